@@ -0,0 +1,59 @@
+// Command import creates the invoices of a CSV file, the same as POST /invoice/v1/import but
+// without the size limit and request timeout of the API. The report is printed as JSON.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	v1 "github.com/Risuii/invoice/src/v1"
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	"github.com/Risuii/invoice/src/app"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "check the file without creating invoices")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		log.Fatal("Missing args. args: [-dry-run] <file.csv>")
+	}
+
+	ctx := context.Background()
+	if err := app.Init(ctx); err != nil {
+		panic(err)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal("Failed to open import file ", err)
+	}
+	defer file.Close()
+
+	request, err := contract.ParseImportCSV(file)
+	if err != nil {
+		log.Fatal("Failed to read import file ", err)
+	}
+	request.DryRun = *dryRun
+
+	deps := v1.Dependencies(ctx)
+
+	res, err := deps.Services.Invoicesvc.Import(ctx, request)
+	if err != nil {
+		log.Fatal("Failed to import invoices ", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(res); err != nil {
+		log.Fatal(err)
+	}
+
+	if res.Failed > 0 || len(res.Errors) > 0 {
+		os.Exit(1)
+	}
+}
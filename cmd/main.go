@@ -38,6 +38,10 @@ func startService(ctx context.Context) {
 	deps := v1.Dependencies(ctx)
 	v1.Router(r, deps)
 
+	go deps.Services.Recurringsvc.Start(ctx, app.Config().RecurringSchedulerInterval)
+	go deps.Services.Invoicesvc.StartOverdueCheck(ctx, app.Config().OverdueCheckInterval, deps.LateFeePolicy)
+	go deps.Services.Dunningsvc.Start(ctx, app.Config().Dunning.CheckInterval)
+
 	err := http.ListenAndServe(address, r)
 	if err != nil {
 		log.Println(err)
@@ -0,0 +1,45 @@
+// Command purge voids the draft invoices that have been in the trash for longer than
+// INVOICE_TRASH_RETENTION, deleting their items for good, and the expired idempotency keys. Run it from cron,
+// -retention overrides the configured period.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	v1 "github.com/Risuii/invoice/src/v1"
+
+	"github.com/Risuii/invoice/src/app"
+)
+
+func main() {
+	retention := flag.Duration("retention", 0, "purge invoices deleted longer ago than this, INVOICE_TRASH_RETENTION when not set")
+	flag.Parse()
+
+	ctx := context.Background()
+	if err := app.Init(ctx); err != nil {
+		panic(err)
+	}
+
+	if *retention <= 0 {
+		*retention = app.Config().InvoiceTrashRetention
+	}
+
+	deps := v1.Dependencies(ctx)
+
+	purged, err := deps.Services.Invoicesvc.Purge(ctx, *retention)
+	if err != nil {
+		log.Fatal("Failed to purge invoices ", err)
+	}
+
+	log.Printf("Voided %d invoices deleted more than %s ago", purged, *retention)
+
+	purged, err = deps.Repositories.IdempotencyRepo.Purge(ctx, time.Now())
+	if err != nil {
+		log.Fatal("Failed to purge idempotency keys ", err)
+	}
+
+	log.Printf("Purged %d expired idempotency keys", purged)
+}
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE payments;
+
+ALTER TABLE invoices DROP COLUMN amount_paid;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,47 @@
+BEGIN;
+
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'PartiallyPaid';
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Overpaid';
+
+ALTER TABLE public.invoices ADD COLUMN amount_paid numeric DEFAULT 0 NOT NULL;
+
+CREATE TABLE public.payments (
+    id bigint NOT NULL,
+    payment_id UUID NOT NULL UNIQUE,
+    invoice_id VARCHAR(10) NOT NULL,
+    amount numeric NOT NULL,
+    payment_date timestamp with time zone NOT NULL,
+    method character varying(50) NOT NULL,
+    reference character varying(255) DEFAULT '' NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.payments_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.payments_id_seq OWNED BY public.payments.id;
+
+ALTER TABLE ONLY public.payments ALTER COLUMN id SET DEFAULT nextval('public.payments_id_seq'::regclass);
+
+ALTER TABLE ONLY public.payments
+    ADD CONSTRAINT payments_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.payments
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+
+CREATE INDEX payments_invoice_id_idx ON public.payments (invoice_id);
+
+-- Invoices already marked as paid get an opening balance payment so the derived status stays the same.
+INSERT INTO public.payments (payment_id, invoice_id, amount, payment_date, method, reference)
+SELECT gen_random_uuid(), invoice_id, grand_total, updated_at, 'migration', 'opening balance'
+FROM public.invoices WHERE status = 'Paid' AND deleted_at IS NULL;
+
+UPDATE public.invoices SET amount_paid = grand_total WHERE status = 'Paid';
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE invoice_status_histories;
+
+ALTER TABLE invoices ALTER COLUMN status DROP DEFAULT;
+
+UPDATE invoices SET status = 'Unpaid' WHERE status IN ('Draft', 'Issued', 'Overdue', 'Void');
+
+COMMIT;
@@ -0,0 +1,47 @@
+BEGIN;
+
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Draft';
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Issued';
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Overdue';
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Void';
+
+COMMIT;
+
+-- new enum values can only be used once the transaction adding them is committed
+BEGIN;
+
+UPDATE public.invoices SET status = 'Issued' WHERE status = 'Unpaid';
+
+ALTER TABLE ONLY public.invoices ALTER COLUMN status SET DEFAULT 'Draft';
+
+CREATE TABLE public.invoice_status_histories (
+    id bigint NOT NULL,
+    invoice_id VARCHAR(10) NOT NULL,
+    from_status status_type NOT NULL,
+    to_status status_type NOT NULL,
+    reason character varying(255) DEFAULT '' NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.invoice_status_histories_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.invoice_status_histories_id_seq OWNED BY public.invoice_status_histories.id;
+
+ALTER TABLE ONLY public.invoice_status_histories ALTER COLUMN id SET DEFAULT nextval('public.invoice_status_histories_id_seq'::regclass);
+
+ALTER TABLE ONLY public.invoice_status_histories
+    ADD CONSTRAINT invoice_status_histories_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.invoice_status_histories
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+
+CREATE INDEX invoice_status_histories_invoice_id_idx ON public.invoice_status_histories (invoice_id);
+
+COMMIT;
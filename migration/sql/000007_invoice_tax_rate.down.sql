@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE invoices DROP COLUMN tax_rate;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,41 @@
+BEGIN;
+
+ALTER TABLE public.invoices ADD COLUMN tax_rate numeric DEFAULT 0 NOT NULL;
+
+-- bring stored invoices in line with the server side calculation:
+-- amount = quantity * unit_price, sub_total = sum(amount), tax = sub_total * tax_rate / 100, grand_total = sub_total + tax
+UPDATE public.items SET amount = ROUND(quantity * unit_price, 2);
+
+UPDATE public.invoices AS i SET sub_total = s.sub_total
+FROM (
+    SELECT invoice_id, COALESCE(SUM(amount), 0) AS sub_total FROM public.items WHERE deleted_at IS NULL GROUP BY invoice_id
+) AS s
+WHERE s.invoice_id = i.invoice_id;
+
+UPDATE public.invoices SET tax_rate = ROUND(tax * 100 / sub_total, 2) WHERE sub_total > 0;
+
+UPDATE public.invoices SET tax = ROUND(sub_total * tax_rate / 100, 2);
+
+UPDATE public.invoices SET grand_total = sub_total + tax;
+
+-- the grand total of an invoice with payments may have moved past or below what was paid, derive its status again
+WITH derived AS (
+    SELECT invoice_id, status AS from_status,
+        CASE
+            WHEN amount_paid < grand_total AND status = 'Overdue' THEN 'Overdue'::status_type
+            WHEN amount_paid < grand_total THEN 'PartiallyPaid'::status_type
+            WHEN amount_paid = grand_total THEN 'Paid'::status_type
+            ELSE 'Overpaid'::status_type
+        END AS to_status
+    FROM public.invoices
+    WHERE amount_paid > 0 AND status IN ('PartiallyPaid', 'Paid', 'Overpaid', 'Overdue')
+), changed AS (
+    UPDATE public.invoices AS i SET status = d.to_status
+    FROM derived AS d
+    WHERE d.invoice_id = i.invoice_id AND d.to_status <> d.from_status
+    RETURNING i.invoice_id, d.from_status, d.to_status
+)
+INSERT INTO public.invoice_status_histories (invoice_id, from_status, to_status, reason)
+SELECT invoice_id, from_status, to_status, 'totals recalculated' FROM changed;
+
+COMMIT;
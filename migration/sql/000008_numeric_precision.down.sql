@@ -0,0 +1,18 @@
+BEGIN;
+
+ALTER TABLE public.payments
+    ALTER COLUMN amount TYPE numeric;
+
+ALTER TABLE public.items
+    ALTER COLUMN quantity TYPE numeric,
+    ALTER COLUMN unit_price TYPE numeric,
+    ALTER COLUMN amount TYPE numeric;
+
+ALTER TABLE public.invoices
+    ALTER COLUMN sub_total TYPE numeric,
+    ALTER COLUMN tax_rate TYPE numeric,
+    ALTER COLUMN tax TYPE numeric,
+    ALTER COLUMN grand_total TYPE numeric,
+    ALTER COLUMN amount_paid TYPE numeric;
+
+COMMIT;
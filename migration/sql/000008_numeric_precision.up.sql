@@ -0,0 +1,19 @@
+BEGIN;
+
+-- money and quantities are handled as fixed point values with 4 fractional digits
+ALTER TABLE public.invoices
+    ALTER COLUMN sub_total TYPE numeric(19,4),
+    ALTER COLUMN tax_rate TYPE numeric(19,4),
+    ALTER COLUMN tax TYPE numeric(19,4),
+    ALTER COLUMN grand_total TYPE numeric(19,4),
+    ALTER COLUMN amount_paid TYPE numeric(19,4);
+
+ALTER TABLE public.items
+    ALTER COLUMN quantity TYPE numeric(19,4),
+    ALTER COLUMN unit_price TYPE numeric(19,4),
+    ALTER COLUMN amount TYPE numeric(19,4);
+
+ALTER TABLE public.payments
+    ALTER COLUMN amount TYPE numeric(19,4);
+
+COMMIT;
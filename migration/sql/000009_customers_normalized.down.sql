@@ -0,0 +1,9 @@
+BEGIN;
+
+-- merged duplicates stay merged, there is no record of which invoice pointed to which copy
+DROP INDEX IF EXISTS customers_normalized_key;
+
+ALTER TABLE customers DROP COLUMN address_normalized;
+ALTER TABLE customers DROP COLUMN name_normalized;
+
+COMMIT;
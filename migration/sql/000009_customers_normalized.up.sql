@@ -0,0 +1,29 @@
+BEGIN;
+
+-- invoices used to insert a new customer every time, the normalized columns let us find the existing one
+ALTER TABLE public.customers ADD COLUMN name_normalized character varying(255) DEFAULT '' NOT NULL;
+ALTER TABLE public.customers ADD COLUMN address_normalized character varying(255) DEFAULT '' NOT NULL;
+
+UPDATE public.customers SET
+    name_normalized = lower(regexp_replace(btrim(COALESCE(name, '')), '\s+', ' ', 'g')),
+    address_normalized = lower(regexp_replace(btrim(COALESCE(address, '')), '\s+', ' ', 'g'));
+
+-- merge the duplicated customers into the oldest row of each name and address pair
+CREATE TEMPORARY TABLE customer_duplicates ON COMMIT DROP AS
+SELECT customer_id, keep_id FROM (
+    SELECT customer_id, first_value(customer_id) OVER (PARTITION BY name_normalized, address_normalized ORDER BY id) AS keep_id
+    FROM public.customers
+    WHERE deleted_at IS NULL
+) AS c
+WHERE c.customer_id <> c.keep_id;
+
+UPDATE public.invoices AS i SET customer_id = d.keep_id
+FROM customer_duplicates AS d
+WHERE i.customer_id = d.customer_id;
+
+UPDATE public.customers SET deleted_at = CURRENT_TIMESTAMP
+WHERE customer_id IN (SELECT customer_id FROM customer_duplicates);
+
+CREATE UNIQUE INDEX customers_normalized_key ON public.customers (name_normalized, address_normalized) WHERE deleted_at IS NULL;
+
+COMMIT;
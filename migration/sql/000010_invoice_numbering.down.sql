@@ -0,0 +1,26 @@
+BEGIN;
+
+DROP TABLE invoice_series_counters;
+
+DROP TABLE invoice_series;
+
+DROP TYPE reset_period_type;
+
+-- fails when longer numbers were issued, they cannot be shortened automatically
+ALTER TABLE ONLY items DROP CONSTRAINT invoice_id;
+ALTER TABLE ONLY payments DROP CONSTRAINT invoice_id;
+ALTER TABLE ONLY invoice_status_histories DROP CONSTRAINT invoice_id;
+
+ALTER TABLE invoices ALTER COLUMN invoice_id TYPE VARCHAR(10);
+ALTER TABLE items ALTER COLUMN invoice_id TYPE VARCHAR(10);
+ALTER TABLE payments ALTER COLUMN invoice_id TYPE VARCHAR(10);
+ALTER TABLE invoice_status_histories ALTER COLUMN invoice_id TYPE VARCHAR(10);
+
+ALTER TABLE ONLY items
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES invoices(invoice_id);
+ALTER TABLE ONLY payments
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES invoices(invoice_id);
+ALTER TABLE ONLY invoice_status_histories
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES invoices(invoice_id);
+
+COMMIT;
@@ -0,0 +1,67 @@
+BEGIN;
+
+-- numbers such as INV/2024/03/00042 do not fit the old VARCHAR(10)
+ALTER TABLE ONLY public.items DROP CONSTRAINT invoice_id;
+ALTER TABLE ONLY public.payments DROP CONSTRAINT invoice_id;
+ALTER TABLE ONLY public.invoice_status_histories DROP CONSTRAINT invoice_id;
+
+ALTER TABLE public.invoices ALTER COLUMN invoice_id TYPE VARCHAR(50);
+ALTER TABLE public.items ALTER COLUMN invoice_id TYPE VARCHAR(50);
+ALTER TABLE public.payments ALTER COLUMN invoice_id TYPE VARCHAR(50);
+ALTER TABLE public.invoice_status_histories ALTER COLUMN invoice_id TYPE VARCHAR(50);
+
+ALTER TABLE ONLY public.items
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+ALTER TABLE ONLY public.payments
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+ALTER TABLE ONLY public.invoice_status_histories
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+
+CREATE TYPE reset_period_type AS ENUM ('never', 'yearly', 'monthly');
+
+CREATE TABLE public.invoice_series (
+    id bigint NOT NULL,
+    code character varying(20) NOT NULL UNIQUE,
+    template character varying(100) NOT NULL,
+    reset_period reset_period_type DEFAULT 'never' NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.invoice_series_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.invoice_series_id_seq OWNED BY public.invoice_series.id;
+
+ALTER TABLE ONLY public.invoice_series ALTER COLUMN id SET DEFAULT nextval('public.invoice_series_id_seq'::regclass);
+
+ALTER TABLE ONLY public.invoice_series
+    ADD CONSTRAINT invoice_series_pkey PRIMARY KEY (id);
+
+-- one row per series and reset period, the row lock taken while incrementing keeps numbers gapless
+CREATE TABLE public.invoice_series_counters (
+    series_code character varying(20) NOT NULL,
+    period_key character varying(10) DEFAULT '' NOT NULL,
+    last_value bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+ALTER TABLE ONLY public.invoice_series_counters
+    ADD CONSTRAINT invoice_series_counters_pkey PRIMARY KEY (series_code, period_key);
+
+ALTER TABLE ONLY public.invoice_series_counters
+    ADD CONSTRAINT series_code FOREIGN KEY (series_code) REFERENCES public.invoice_series(code);
+
+-- the default series keeps the old four digit numbers and continues after the highest one
+INSERT INTO public.invoice_series (code, template, reset_period) VALUES ('INV', '{seq:04}', 'never');
+
+INSERT INTO public.invoice_series_counters (series_code, period_key, last_value)
+SELECT 'INV', '', COALESCE(MAX(invoice_id::bigint), 0) FROM public.invoices WHERE invoice_id ~ '^[0-9]+$';
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS customers_search_vector ON customers;
+DROP TRIGGER IF EXISTS items_search_vector ON items;
+DROP TRIGGER IF EXISTS invoices_search_vector ON invoices;
+
+DROP FUNCTION IF EXISTS customers_search_vector_trigger();
+DROP FUNCTION IF EXISTS items_search_vector_trigger();
+DROP FUNCTION IF EXISTS invoices_search_vector_trigger();
+DROP FUNCTION IF EXISTS invoice_search_vector(character varying, text, uuid);
+
+DROP INDEX IF EXISTS invoices_search_vector_idx;
+
+ALTER TABLE invoices DROP COLUMN search_vector;
+
+COMMIT;
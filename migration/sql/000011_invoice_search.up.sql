@@ -0,0 +1,65 @@
+BEGIN;
+
+-- the keyword search matches invoice number, subject, customer name and item names.
+-- the document is kept on the invoice row so it can be indexed, triggers refresh it when any source changes
+ALTER TABLE public.invoices ADD COLUMN search_vector tsvector DEFAULT ''::tsvector NOT NULL;
+
+-- invoice numbers are split on their separators so INV/2024/03/00042 matches "2024" or "00042"
+CREATE FUNCTION public.invoice_search_vector(p_invoice_id character varying, p_subject text, p_customer_id uuid) RETURNS tsvector
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT
+        setweight(to_tsvector('simple', regexp_replace(COALESCE(p_invoice_id, ''), '[^[:alnum:]]+', ' ', 'g')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(p_subject, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE((SELECT c.name FROM public.customers AS c WHERE c.customer_id = p_customer_id), '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE((SELECT string_agg(i.name, ' ') FROM public.items AS i WHERE i.invoice_id = p_invoice_id AND i.deleted_at IS NULL), '')), 'C')
+$$;
+
+CREATE FUNCTION public.invoices_search_vector_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.search_vector := public.invoice_search_vector(NEW.invoice_id, NEW.subject, NEW.customer_id);
+    RETURN NEW;
+END
+$$;
+
+CREATE FUNCTION public.items_search_vector_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE public.invoices SET search_vector = public.invoice_search_vector(invoice_id, subject, customer_id) WHERE invoice_id = OLD.invoice_id;
+    END IF;
+
+    IF TG_OP <> 'DELETE' THEN
+        UPDATE public.invoices SET search_vector = public.invoice_search_vector(invoice_id, subject, customer_id) WHERE invoice_id = NEW.invoice_id;
+    END IF;
+
+    RETURN NULL;
+END
+$$;
+
+CREATE FUNCTION public.customers_search_vector_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    UPDATE public.invoices SET search_vector = public.invoice_search_vector(invoice_id, subject, customer_id) WHERE customer_id = NEW.customer_id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER invoices_search_vector BEFORE INSERT OR UPDATE OF invoice_id, subject, customer_id ON public.invoices
+    FOR EACH ROW EXECUTE FUNCTION public.invoices_search_vector_trigger();
+
+CREATE TRIGGER items_search_vector AFTER INSERT OR DELETE OR UPDATE OF invoice_id, name, deleted_at ON public.items
+    FOR EACH ROW EXECUTE FUNCTION public.items_search_vector_trigger();
+
+CREATE TRIGGER customers_search_vector AFTER UPDATE OF name ON public.customers
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name) EXECUTE FUNCTION public.customers_search_vector_trigger();
+
+UPDATE public.invoices SET search_vector = public.invoice_search_vector(invoice_id, subject, customer_id);
+
+CREATE INDEX invoices_search_vector_idx ON public.invoices USING GIN (search_vector);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS invoices_created_at_id_idx;
+
+COMMIT;
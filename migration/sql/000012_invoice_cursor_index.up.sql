@@ -0,0 +1,6 @@
+BEGIN;
+
+-- cursor pagination reads the invoices in (created_at, id) order after the last row of the previous page
+CREATE INDEX invoices_created_at_id_idx ON public.invoices (created_at, id) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE customers DROP COLUMN country_code;
+ALTER TABLE customers DROP COLUMN endpoint_scheme;
+ALTER TABLE customers DROP COLUMN endpoint_id;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- the electronic address and country of the buyer on UBL e-invoices
+ALTER TABLE public.customers ADD COLUMN endpoint_id character varying(255) DEFAULT '' NOT NULL;
+ALTER TABLE public.customers ADD COLUMN endpoint_scheme character varying(4) DEFAULT '' NOT NULL;
+ALTER TABLE public.customers ADD COLUMN country_code character varying(2) DEFAULT '' NOT NULL;
+
+COMMIT;
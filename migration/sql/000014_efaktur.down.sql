@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS invoices_tax_invoice_number_key;
+
+ALTER TABLE invoices DROP COLUMN tax_invoice_number;
+ALTER TABLE customers DROP COLUMN npwp;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- NPWP of the customer and the tax invoice serial (NSFP) given by DJP, both stored as digits only
+ALTER TABLE public.customers ADD COLUMN npwp character varying(16) DEFAULT '' NOT NULL;
+ALTER TABLE public.invoices ADD COLUMN tax_invoice_number character varying(13) DEFAULT '' NOT NULL;
+
+CREATE UNIQUE INDEX invoices_tax_invoice_number_key ON public.invoices (tax_invoice_number) WHERE tax_invoice_number <> '' AND deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- responses of create requests sent with an Idempotency-Key header, replayed when the client retries
+CREATE TABLE public.idempotency_keys (
+    key character varying(255) NOT NULL,
+    request_hash character varying(64) NOT NULL,
+    -- 0 while the first request is still being handled
+    status_code integer DEFAULT 0 NOT NULL,
+    content_type character varying(255) DEFAULT '' NOT NULL,
+    response_body bytea,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+ALTER TABLE ONLY public.idempotency_keys
+    ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (key);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON public.idempotency_keys USING btree (expires_at);
+
+COMMIT;
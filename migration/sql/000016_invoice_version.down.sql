@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE invoices DROP COLUMN version;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- bumped on every update, it is the ETag of the invoice
+ALTER TABLE public.invoices ADD COLUMN version bigint DEFAULT 1 NOT NULL;
+
+COMMIT;
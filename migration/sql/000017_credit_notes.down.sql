@@ -0,0 +1,16 @@
+BEGIN;
+
+DELETE FROM invoice_series_counters WHERE series_code = 'CN';
+
+DELETE FROM invoice_series WHERE code = 'CN';
+
+DROP TABLE credit_note_items;
+
+DROP TABLE credit_notes;
+
+-- status_type keeps the Credited value, enum values cannot be dropped
+UPDATE invoices SET status = 'Issued' WHERE status = 'Credited';
+
+ALTER TABLE invoices DROP COLUMN amount_credited;
+
+COMMIT;
@@ -0,0 +1,78 @@
+BEGIN;
+
+-- an issued invoice credited in full without any payment
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Credited';
+
+ALTER TABLE public.invoices ADD COLUMN amount_credited numeric(19,4) DEFAULT 0 NOT NULL;
+
+CREATE TABLE public.credit_notes (
+    id bigint NOT NULL,
+    credit_note_id VARCHAR(50) NOT NULL UNIQUE,
+    invoice_id VARCHAR(50) NOT NULL,
+    issue_date timestamp with time zone NOT NULL,
+    reason character varying(255) NOT NULL,
+    sub_total numeric(19,4) NOT NULL,
+    tax numeric(19,4) NOT NULL,
+    grand_total numeric(19,4) NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.credit_notes_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.credit_notes_id_seq OWNED BY public.credit_notes.id;
+
+ALTER TABLE ONLY public.credit_notes ALTER COLUMN id SET DEFAULT nextval('public.credit_notes_id_seq'::regclass);
+
+ALTER TABLE ONLY public.credit_notes
+    ADD CONSTRAINT credit_notes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.credit_notes
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+
+CREATE INDEX credit_notes_invoice_id_idx ON public.credit_notes (invoice_id);
+
+-- the lines are negative, item_id is the credited item of the original invoice
+CREATE TABLE public.credit_note_items (
+    id bigint NOT NULL,
+    credit_note_id VARCHAR(50) NOT NULL,
+    item_id UUID NOT NULL,
+    name character varying(255),
+    type character varying(255),
+    quantity numeric(19,4) NOT NULL,
+    unit_price numeric(19,4) NOT NULL,
+    amount numeric(19,4) NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.credit_note_items_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.credit_note_items_id_seq OWNED BY public.credit_note_items.id;
+
+ALTER TABLE ONLY public.credit_note_items ALTER COLUMN id SET DEFAULT nextval('public.credit_note_items_id_seq'::regclass);
+
+ALTER TABLE ONLY public.credit_note_items
+    ADD CONSTRAINT credit_note_items_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.credit_note_items
+    ADD CONSTRAINT credit_note_id FOREIGN KEY (credit_note_id) REFERENCES public.credit_notes(credit_note_id);
+
+CREATE INDEX credit_note_items_credit_note_id_idx ON public.credit_note_items (credit_note_id);
+
+-- credit notes are numbered apart from invoices
+INSERT INTO public.invoice_series (code, template, reset_period) VALUES ('CN', 'CN/{YYYY}/{seq:05}', 'yearly');
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP TABLE recurring_invoice_runs;
+
+DROP TABLE recurring_invoice_items;
+
+DROP TABLE recurring_invoices;
+
+DROP TYPE recurring_run_status_type;
+
+DROP TYPE recurring_interval_type;
+
+COMMIT;
@@ -0,0 +1,111 @@
+BEGIN;
+
+CREATE TYPE recurring_interval_type AS ENUM ('weekly', 'monthly', 'quarterly', 'yearly', 'cron');
+
+CREATE TYPE recurring_run_status_type AS ENUM ('pending', 'created', 'failed');
+
+-- a template the scheduler turns into a draft invoice every period
+CREATE TABLE public.recurring_invoices (
+    id bigint NOT NULL,
+    recurring_id UUID NOT NULL UNIQUE,
+    customer_id UUID NOT NULL,
+    subject character varying(255) NOT NULL,
+    tax_rate numeric(19,4) DEFAULT 0 NOT NULL,
+    series VARCHAR(20) DEFAULT '' NOT NULL,
+    "interval" recurring_interval_type NOT NULL,
+    cron character varying(255) DEFAULT '' NOT NULL,
+    due_days integer DEFAULT 0 NOT NULL,
+    start_date timestamp with time zone NOT NULL,
+    end_date timestamp with time zone,
+    -- null once the schedule is past its end date
+    next_run timestamp with time zone,
+    last_run_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.recurring_invoices_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.recurring_invoices_id_seq OWNED BY public.recurring_invoices.id;
+
+ALTER TABLE ONLY public.recurring_invoices ALTER COLUMN id SET DEFAULT nextval('public.recurring_invoices_id_seq'::regclass);
+
+ALTER TABLE ONLY public.recurring_invoices
+    ADD CONSTRAINT recurring_invoices_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.recurring_invoices
+    ADD CONSTRAINT customer_id FOREIGN KEY (customer_id) REFERENCES public.customers(customer_id);
+
+CREATE INDEX recurring_invoices_next_run_idx ON public.recurring_invoices (next_run) WHERE deleted_at IS NULL;
+
+CREATE TABLE public.recurring_invoice_items (
+    id bigint NOT NULL,
+    recurring_id UUID NOT NULL,
+    name character varying(255),
+    type character varying(255),
+    quantity numeric(19,4) NOT NULL,
+    unit_price numeric(19,4) NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.recurring_invoice_items_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.recurring_invoice_items_id_seq OWNED BY public.recurring_invoice_items.id;
+
+ALTER TABLE ONLY public.recurring_invoice_items ALTER COLUMN id SET DEFAULT nextval('public.recurring_invoice_items_id_seq'::regclass);
+
+ALTER TABLE ONLY public.recurring_invoice_items
+    ADD CONSTRAINT recurring_invoice_items_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.recurring_invoice_items
+    ADD CONSTRAINT recurring_id FOREIGN KEY (recurring_id) REFERENCES public.recurring_invoices(recurring_id);
+
+CREATE INDEX recurring_invoice_items_recurring_id_idx ON public.recurring_invoice_items (recurring_id);
+
+-- one row per period, the unique key keeps a period from being invoiced twice
+CREATE TABLE public.recurring_invoice_runs (
+    id bigint NOT NULL,
+    recurring_id UUID NOT NULL,
+    run_at timestamp with time zone NOT NULL,
+    invoice_id VARCHAR(50) DEFAULT '' NOT NULL,
+    status recurring_run_status_type DEFAULT 'pending' NOT NULL,
+    error text DEFAULT '' NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE SEQUENCE public.recurring_invoice_runs_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.recurring_invoice_runs_id_seq OWNED BY public.recurring_invoice_runs.id;
+
+ALTER TABLE ONLY public.recurring_invoice_runs ALTER COLUMN id SET DEFAULT nextval('public.recurring_invoice_runs_id_seq'::regclass);
+
+ALTER TABLE ONLY public.recurring_invoice_runs
+    ADD CONSTRAINT recurring_invoice_runs_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.recurring_invoice_runs
+    ADD CONSTRAINT recurring_id FOREIGN KEY (recurring_id) REFERENCES public.recurring_invoices(recurring_id);
+
+ALTER TABLE ONLY public.recurring_invoice_runs
+    ADD CONSTRAINT recurring_invoice_runs_period_key UNIQUE (recurring_id, run_at);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE invoice_reminders;
+
+DROP TYPE invoice_reminder_status_type;
+
+ALTER TABLE customers DROP COLUMN dunning_paused;
+ALTER TABLE customers DROP COLUMN email;
+
+COMMIT;
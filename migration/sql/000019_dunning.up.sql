@@ -0,0 +1,44 @@
+BEGIN;
+
+-- reminders of unpaid invoices are mailed to the customer unless dunning is paused for it
+ALTER TABLE public.customers ADD COLUMN email character varying(255) DEFAULT '' NOT NULL;
+ALTER TABLE public.customers ADD COLUMN dunning_paused boolean DEFAULT false NOT NULL;
+
+CREATE TYPE invoice_reminder_status_type AS ENUM ('pending', 'sent', 'failed');
+
+-- stage is the number of days from the due date the reminder belongs to, negative before it,
+-- attempts counts the sends of a reminder, a failed one is sent again up to DUNNING_MAX_ATTEMPTS
+CREATE TABLE public.invoice_reminders (
+    id bigint NOT NULL,
+    invoice_id VARCHAR(50) NOT NULL,
+    stage integer NOT NULL,
+    email character varying(255) DEFAULT '' NOT NULL,
+    status invoice_reminder_status_type DEFAULT 'pending' NOT NULL,
+    error text DEFAULT '' NOT NULL,
+    attempts integer DEFAULT 1 NOT NULL,
+    sent_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE SEQUENCE public.invoice_reminders_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.invoice_reminders_id_seq OWNED BY public.invoice_reminders.id;
+
+ALTER TABLE ONLY public.invoice_reminders ALTER COLUMN id SET DEFAULT nextval('public.invoice_reminders_id_seq'::regclass);
+
+ALTER TABLE ONLY public.invoice_reminders
+    ADD CONSTRAINT invoice_reminders_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.invoice_reminders
+    ADD CONSTRAINT invoice_id FOREIGN KEY (invoice_id) REFERENCES public.invoices(invoice_id);
+
+ALTER TABLE ONLY public.invoice_reminders
+    ADD CONSTRAINT invoice_reminders_stage_key UNIQUE (invoice_id, stage);
+
+COMMIT;
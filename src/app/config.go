@@ -36,10 +36,25 @@ type (
 		Postgres    Postgres    `mapstructure:",squash"`
 		Redis       Redis       `mapstructure:",squash"`
 		Translation Translation `mapstructure:",squash"`
+		Document    Document    `mapstructure:",squash"`
+		LateFee     LateFee     `mapstructure:",squash"`
+		SMTP        SMTP        `mapstructure:",squash"`
+		Dunning     Dunning     `mapstructure:",squash"`
 
 		Environment string `mapstructure:"ENV" validate:"required,oneof=development staging production"`
 		BindAddress int    `mapstructure:"BIND_ADDRESS" validate:"required"`
 		LogLevel    int    `mapstructure:"LOG_LEVEL" validate:"required"`
+
+		InvoiceBatchMaxSize int           `mapstructure:"INVOICE_BATCH_MAX_SIZE" validate:"required,min=1"`
+		IdempotencyKeyTTL   time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL" validate:"required"`
+		// IdempotencyLease is how long a request holds its key before a retry may take it over, longer than the request timeout
+		IdempotencyLease time.Duration `mapstructure:"IDEMPOTENCY_LEASE" validate:"required"`
+		// InvoiceTrashRetention is how long a deleted invoice stays in the trash before it is purged
+		InvoiceTrashRetention time.Duration `mapstructure:"INVOICE_TRASH_RETENTION" validate:"required"`
+		// RecurringSchedulerInterval is how often the scheduler looks for recurring invoices due
+		RecurringSchedulerInterval time.Duration `mapstructure:"RECURRING_SCHEDULER_INTERVAL" validate:"required"`
+		// OverdueCheckInterval is how often invoices past their due date are marked overdue
+		OverdueCheckInterval time.Duration `mapstructure:"OVERDUE_CHECK_INTERVAL" validate:"required"`
 	}
 )
 
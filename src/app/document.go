@@ -0,0 +1,21 @@
+package app
+
+type (
+	// Document configures the rendered invoice documents of a deployment.
+	Document struct {
+		TemplateDir    string `mapstructure:"DOCUMENT_TEMPLATE_DIR" validate:"required"`
+		CompanyName    string `mapstructure:"DOCUMENT_COMPANY_NAME"`
+		CompanyAddress string `mapstructure:"DOCUMENT_COMPANY_ADDRESS"`
+		LogoPath       string `mapstructure:"DOCUMENT_LOGO_PATH"` //Optional, no logo when empty
+		BankAccount    string `mapstructure:"DOCUMENT_BANK_ACCOUNT"`
+		FooterNotes    string `mapstructure:"DOCUMENT_FOOTER_NOTES"`
+
+		// the fields below are only used by the UBL e-invoices
+		CountryCode      string `mapstructure:"DOCUMENT_COUNTRY_CODE" validate:"required,len=2"`
+		Currency         string `mapstructure:"DOCUMENT_CURRENCY" validate:"required,len=3"`
+		CompanyTaxID     string `mapstructure:"DOCUMENT_COMPANY_TAX_ID"`     //Optional, no PartyTaxScheme when empty
+		EndpointID       string `mapstructure:"DOCUMENT_ENDPOINT_ID"`        //Optional, the PEPPOL participant id of the company
+		EndpointScheme   string `mapstructure:"DOCUMENT_ENDPOINT_SCHEME"`    //Optional, the EAS code of EndpointID such as 0088
+		PaymentAccountID string `mapstructure:"DOCUMENT_PAYMENT_ACCOUNT_ID"` //Optional, no PayeeFinancialAccount when empty
+	}
+)
@@ -0,0 +1,24 @@
+package app
+
+import "time"
+
+type (
+	// SMTP is the server the payment reminders are sent through.
+	SMTP struct {
+		Host     string `mapstructure:"SMTP_HOST" validate:"required"`
+		Port     int    `mapstructure:"SMTP_PORT" validate:"required"`
+		Username string `mapstructure:"SMTP_USERNAME"` //Optional, no authentication when empty
+		Password string `mapstructure:"SMTP_PASSWORD"`
+		From     string `mapstructure:"SMTP_FROM" validate:"required,email"`
+	}
+
+	// Dunning configures the payment reminders of unpaid invoices.
+	Dunning struct {
+		// Schedule are the days from the due date a reminder is sent on, negative before it
+		Schedule      []int         `mapstructure:"DUNNING_SCHEDULE" validate:"dive,min=-365,max=365"` //Optional, no reminders when empty
+		CheckInterval time.Duration `mapstructure:"DUNNING_CHECK_INTERVAL" validate:"required"`
+		// MaxAttempts is how many times a reminder that keeps failing is sent at most
+		MaxAttempts int    `mapstructure:"DUNNING_MAX_ATTEMPTS" validate:"required,min=1"`
+		Language    string `mapstructure:"DUNNING_LANGUAGE" validate:"required"`
+	}
+)
@@ -0,0 +1,13 @@
+package app
+
+type (
+	// LateFee configures the late fees the overdue check charges on overdue invoices.
+	LateFee struct {
+		Type string `mapstructure:"LATE_FEE_TYPE" validate:"required,oneof=none flat percentage"`
+		// Amount is the fee of one charge, a percent of the outstanding balance for percentage
+		Amount       string `mapstructure:"LATE_FEE_AMOUNT" validate:"required,numeric"`
+		GraceDays    int    `mapstructure:"LATE_FEE_GRACE_DAYS" validate:"min=0"`             //Optional, the first fee is charged the day after the due date
+		IntervalDays int    `mapstructure:"LATE_FEE_INTERVAL_DAYS" validate:"min=0"`          //Optional, the fee is charged once
+		MaxAmount    string `mapstructure:"LATE_FEE_MAX_AMOUNT" validate:"omitempty,numeric"` //Optional, no cap on the late fees of an invoice
+	}
+)
@@ -0,0 +1,434 @@
+// Package decimal provides a fixed point number used for money and quantities.
+// Values are kept as an int64 count of 1/10000 units, which matches numeric(19,4) columns,
+// so adding and comparing amounts never drifts the way float64 does.
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fractional digits every Decimal is stored with.
+const Scale = 4
+
+const unit = 10000
+
+var (
+	ErrInvalid  = errors.New("decimal: invalid number")
+	ErrOverflow = errors.New("decimal: value out of range")
+)
+
+// RoundingMode decides which way a value is rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest neighbour, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest neighbour, ties to the even neighbour.
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Decimal is a signed number with Scale fractional digits. The zero value is 0.
+type Decimal struct {
+	units int64
+}
+
+var Zero = Decimal{}
+
+var (
+	bigUnit  = big.NewInt(unit)
+	maxUnits = big.NewInt(math.MaxInt64)
+	minUnits = big.NewInt(math.MinInt64)
+)
+
+// NewFromInt returns the whole number i.
+func NewFromInt(i int64) Decimal {
+	return Decimal{units: i * unit}
+}
+
+// NewFromFloat converts f rounding half up to Scale digits. It exists for callers that only
+// have a float at hand; money should be parsed from strings.
+func NewFromFloat(f float64) Decimal {
+	d, err := NewFromString(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Zero
+	}
+
+	return d
+}
+
+// NewFromString parses a plain decimal literal such as "-12.5". Digits beyond Scale are
+// rounded half up.
+func NewFromString(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Zero, ErrInvalid
+	}
+
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return Zero, ErrInvalid
+	}
+
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Zero, ErrInvalid
+		}
+	}
+
+	n, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Zero, ErrInvalid
+	}
+
+	if neg {
+		n.Neg(n)
+	}
+
+	exp := len(fracPart) - Scale
+	if exp <= 0 {
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+	} else {
+		n = divRound(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil), RoundHalfUp)
+	}
+
+	return fromBig(n)
+}
+
+// RequireFromString is NewFromString for literals known to be valid. It panics otherwise.
+func RequireFromString(s string) Decimal {
+	d, err := NewFromString(s)
+	if err != nil {
+		panic(fmt.Sprintf("decimal: cannot parse %q: %v", s, err))
+	}
+
+	return d
+}
+
+func fromBig(n *big.Int) (Decimal, error) {
+	if n.Cmp(maxUnits) > 0 || n.Cmp(minUnits) < 0 {
+		return Zero, ErrOverflow
+	}
+
+	return Decimal{units: n.Int64()}, nil
+}
+
+func mustFromBig(n *big.Int) Decimal {
+	return must(fromBig(n))
+}
+
+func must(d Decimal, err error) Decimal {
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// divRound divides n by d and rounds the quotient with mode. d must be positive.
+func divRound(n, d *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	away := false
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	default:
+		half := new(big.Int).Abs(r)
+		half.Mul(half, big.NewInt(2))
+
+		switch half.Cmp(d) {
+		case 1:
+			away = true
+		case 0:
+			away = mode == RoundHalfUp || q.Bit(0) == 1
+		}
+	}
+
+	if away {
+		if n.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q
+}
+
+func (d Decimal) big() *big.Int {
+	return big.NewInt(d.units)
+}
+
+// Add returns d + o. It wraps around when the sum does not fit, AddChecked reports it instead.
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{units: d.units + o.units}
+}
+
+// AddChecked returns d + o, or ErrOverflow when the sum does not fit.
+func (d Decimal) AddChecked(o Decimal) (Decimal, error) {
+	sum := d.units + o.units
+	if (o.units > 0 && sum < d.units) || (o.units < 0 && sum > d.units) {
+		return Zero, ErrOverflow
+	}
+
+	return Decimal{units: sum}, nil
+}
+
+// Sub returns d - o. It wraps around when the difference does not fit.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{units: d.units - o.units}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: -d.units}
+}
+
+// Mul returns d * o rounded half up to Scale digits.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return d.MulRound(o, Scale, RoundHalfUp)
+}
+
+// MulRound returns d * o rounded once to places fractional digits. It panics when the product
+// does not fit, MulRoundChecked reports it instead.
+func (d Decimal) MulRound(o Decimal, places int32, mode RoundingMode) Decimal {
+	return must(d.MulRoundChecked(o, places, mode))
+}
+
+// MulRoundChecked returns d * o like MulRound, or ErrOverflow when the product does not fit.
+func (d Decimal) MulRoundChecked(o Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	places = clampPlaces(places)
+	n := new(big.Int).Mul(d.big(), o.big())
+	return fromBig(scaleDown(n, Scale+Scale-places, mode, places))
+}
+
+// MulPercent returns d * percent / 100 rounded once to places fractional digits. It panics
+// when the result does not fit, MulPercentChecked reports it instead.
+func (d Decimal) MulPercent(percent Decimal, places int32, mode RoundingMode) Decimal {
+	return must(d.MulPercentChecked(percent, places, mode))
+}
+
+// MulPercentChecked returns d * percent / 100 like MulPercent, or ErrOverflow when the result
+// does not fit.
+func (d Decimal) MulPercentChecked(percent Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	places = clampPlaces(places)
+	n := new(big.Int).Mul(d.big(), percent.big())
+	return fromBig(scaleDown(n, Scale+Scale+2-places, mode, places))
+}
+
+// Div returns d / o rounded half up to Scale digits. It panics when o is zero.
+func (d Decimal) Div(o Decimal) Decimal {
+	return d.DivRound(o, Scale, RoundHalfUp)
+}
+
+// DivRound returns d / o rounded once to places fractional digits. It panics when o is zero.
+func (d Decimal) DivRound(o Decimal, places int32, mode RoundingMode) Decimal {
+	if o.units == 0 {
+		panic("decimal: division by zero")
+	}
+
+	places = clampPlaces(places)
+	n := new(big.Int).Mul(d.big(), pow10(places))
+	den := o.big()
+	if den.Sign() < 0 {
+		n.Neg(n)
+		den.Neg(den)
+	}
+
+	return mustFromBig(scaleUp(divRound(n, den, mode), places))
+}
+
+// Round returns d rounded to places fractional digits. places above Scale leave d unchanged.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places >= Scale {
+		return d
+	}
+
+	return mustFromBig(scaleDown(d.big(), Scale-places, mode, places))
+}
+
+// scaleDown drops drop digits from n with mode, then rescales the result from places to Scale.
+func scaleDown(n *big.Int, drop int32, mode RoundingMode, places int32) *big.Int {
+	if drop > 0 {
+		n = divRound(n, pow10(drop), mode)
+	} else if drop < 0 {
+		n = new(big.Int).Mul(n, pow10(-drop))
+	}
+
+	return scaleUp(n, places)
+}
+
+// scaleUp turns a value with places fractional digits into units. places never exceeds Scale.
+func scaleUp(n *big.Int, places int32) *big.Int {
+	return n.Mul(n, pow10(Scale-places))
+}
+
+func clampPlaces(places int32) int32 {
+	switch {
+	case places > Scale:
+		return Scale
+	case places < 0:
+		return 0
+	}
+
+	return places
+}
+
+func pow10(n int32) *big.Int {
+	if n <= 0 {
+		return big.NewInt(1)
+	}
+
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Cmp returns -1, 0 or +1 when d is less than, equal to or greater than o.
+func (d Decimal) Cmp(o Decimal) int {
+	switch {
+	case d.units < o.units:
+		return -1
+	case d.units > o.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) Equal(o Decimal) bool       { return d.units == o.units }
+func (d Decimal) LessThan(o Decimal) bool    { return d.units < o.units }
+func (d Decimal) GreaterThan(o Decimal) bool { return d.units > o.units }
+func (d Decimal) IsZero() bool               { return d.units == 0 }
+func (d Decimal) IsNegative() bool           { return d.units < 0 }
+func (d Decimal) IsPositive() bool           { return d.units > 0 }
+
+// Float64 returns the nearest float. Use it for display or validation only, never for math.
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / unit
+}
+
+// String formats d without trailing fractional zeros, e.g. "12.5" or "-3".
+func (d Decimal) String() string {
+	u := d.units
+	sign := ""
+	if u < 0 {
+		sign = "-"
+	}
+
+	abs := new(big.Int).Abs(d.big())
+	q, r := new(big.Int).QuoRem(abs, bigUnit, new(big.Int))
+	if r.Sign() == 0 {
+		return sign + q.String()
+	}
+
+	frac := strings.TrimRight(fmt.Sprintf("%0*d", Scale, r.Int64()), "0")
+
+	return sign + q.String() + "." + frac
+}
+
+// StringFixed formats d with exactly places fractional digits, rounding half up.
+func (d Decimal) StringFixed(places int32) string {
+	r := d.Round(places, RoundHalfUp)
+	s := r.String()
+	if places <= 0 {
+		return s
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	return intPart + "." + fracPart + strings.Repeat("0", int(places)-len(fracPart))
+}
+
+// MarshalJSON writes d as an exact JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number, a quoted number or null.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*d = Zero
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return ErrInvalid
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	parsed, err := NewFromString(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// Scan reads a numeric column. NULL scans as zero.
+func (d *Decimal) Scan(value interface{}) error {
+	var s string
+
+	switch v := value.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		*d = NewFromInt(v)
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("decimal: cannot scan %T", value)
+	}
+
+	parsed, err := NewFromString(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// Value writes d as text, which postgres converts to numeric without loss.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
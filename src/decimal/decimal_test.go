@@ -0,0 +1,172 @@
+package decimal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-playground/assert"
+)
+
+func TestDecimal_NewFromString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    string
+		expected string
+		err      error
+	}{
+		{name: "integer", given: "300", expected: "300"},
+		{name: "fraction", given: "99.99", expected: "99.99"},
+		{name: "negative", given: "-0.5", expected: "-0.5"},
+		{name: "plus sign", given: "+1.25", expected: "1.25"},
+		{name: "trailing zeros", given: "10.5000", expected: "10.5"},
+		{name: "rounds beyond scale", given: "0.00005", expected: "0.0001"},
+		{name: "rounds negative beyond scale", given: "-0.00005", expected: "-0.0001"},
+		{name: "empty", given: "", err: ErrInvalid},
+		{name: "letters", given: "12a", err: ErrInvalid},
+		{name: "only dot", given: ".", err: ErrInvalid},
+		{name: "overflow", given: "99999999999999999999", err: ErrOverflow},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			d, err := NewFromString(testCase.given)
+
+			assert.Equal(t, testCase.err, err)
+			if err == nil {
+				assert.Equal(t, testCase.expected, d.String())
+			}
+		})
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    string
+		mode     RoundingMode
+		expected string
+	}{
+		{name: "half up tie", given: "2.345", mode: RoundHalfUp, expected: "2.35"},
+		{name: "half up negative tie", given: "-2.345", mode: RoundHalfUp, expected: "-2.35"},
+		{name: "half even tie down", given: "2.345", mode: RoundHalfEven, expected: "2.34"},
+		{name: "half even tie up", given: "2.355", mode: RoundHalfEven, expected: "2.36"},
+		{name: "half even not a tie", given: "2.3451", mode: RoundHalfEven, expected: "2.35"},
+		{name: "down", given: "2.349", mode: RoundDown, expected: "2.34"},
+		{name: "down negative", given: "-2.349", mode: RoundDown, expected: "-2.34"},
+		{name: "up", given: "2.341", mode: RoundUp, expected: "2.35"},
+		{name: "up negative", given: "-2.341", mode: RoundUp, expected: "-2.35"},
+		{name: "exact", given: "2.3", mode: RoundUp, expected: "2.3"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			d := RequireFromString(testCase.given).Round(2, testCase.mode)
+
+			assert.Equal(t, testCase.expected, d.String())
+		})
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := RequireFromString("0.1")
+	b := RequireFromString("0.2")
+
+	assert.Equal(t, true, a.Add(b).Equal(RequireFromString("0.3")))
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "0.5", a.Div(b).String())
+	assert.Equal(t, "0.3333", NewFromInt(1).Div(NewFromInt(3)).String())
+	assert.Equal(t, "-0.33", NewFromInt(1).DivRound(NewFromInt(-3), 2, RoundHalfUp).String())
+
+	// 1.5 * 99.99 is exactly 149.985, which a float product rounds down.
+	assert.Equal(t, "149.99", RequireFromString("1.5").MulRound(RequireFromString("99.99"), 2, RoundHalfUp).String())
+	assert.Equal(t, "149.98", RequireFromString("1.5").MulRound(RequireFromString("99.99"), 2, RoundHalfEven).String())
+
+	assert.Equal(t, "115.5", RequireFromString("1049.99").MulPercent(NewFromInt(11), 2, RoundHalfUp).String())
+	assert.Equal(t, "0.01", RequireFromString("0.05").MulPercent(NewFromInt(10), 2, RoundHalfUp).String())
+
+	large := RequireFromString("99999999999")
+	_, err := large.MulRoundChecked(large, 2, RoundHalfUp)
+	assert.Equal(t, ErrOverflow, err)
+	_, err = large.MulPercentChecked(large, 2, RoundHalfUp)
+	assert.Equal(t, ErrOverflow, err)
+	_, err = NewFromInt(900000000000000).AddChecked(NewFromInt(900000000000000))
+	assert.Equal(t, ErrOverflow, err)
+	_, err = NewFromInt(-900000000000000).AddChecked(NewFromInt(-900000000000000))
+	assert.Equal(t, ErrOverflow, err)
+	sum, err := a.AddChecked(b)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0.3", sum.String())
+
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, 0, a.Cmp(RequireFromString("0.10")))
+	assert.Equal(t, "12.50", RequireFromString("12.5").StringFixed(2))
+	assert.Equal(t, "12", RequireFromString("12.4").StringFixed(0))
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	type payload struct {
+		Amount Decimal `json:"amount"`
+	}
+
+	testCases := []struct {
+		name     string
+		given    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "number", given: `{"amount":12.34}`, expected: "12.34"},
+		{name: "string", given: `{"amount":"12.34"}`, expected: "12.34"},
+		{name: "exponent", given: `{"amount":1.5e2}`, expected: "150"},
+		{name: "null", given: `{"amount":null}`, expected: "0"},
+		{name: "invalid", given: `{"amount":"abc"}`, wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var p payload
+			err := json.Unmarshal([]byte(testCase.given), &p)
+
+			assert.Equal(t, testCase.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, testCase.expected, p.Amount.String())
+			}
+		})
+	}
+
+	out, err := json.Marshal(payload{Amount: RequireFromString("0.30")})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"amount":0.3}`, string(out))
+}
+
+func TestDecimal_ScanValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    interface{}
+		expected string
+		wantErr  bool
+	}{
+		{name: "bytes", given: []byte("1049.99"), expected: "1049.99"},
+		{name: "string", given: "-3.5", expected: "-3.5"},
+		{name: "int64", given: int64(7), expected: "7"},
+		{name: "float64", given: 0.1, expected: "0.1"},
+		{name: "null", given: nil, expected: "0"},
+		{name: "unsupported", given: true, wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var d Decimal
+			err := d.Scan(testCase.given)
+
+			assert.Equal(t, testCase.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, testCase.expected, d.String())
+
+				v, _ := d.Value()
+				assert.Equal(t, testCase.expected, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Package document holds what the rendered invoice documents have in common: the localized
+// labels and the formatting of amounts. The renderers themselves live in the sub packages.
+package document
+
+import (
+	"strings"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	frsI18n "github.com/Risuii/frs-lib/i18n"
+)
+
+// Labels are the captions printed on a document, in the language of the request.
+type Labels struct {
+	Invoice       string
+	InvoiceNumber string
+	IssueDate     string
+	DueDate       string
+	BillTo        string
+	Subject       string
+	Item          string
+	Quantity      string
+	UnitPrice     string
+	Amount        string
+	SubTotal      string
+	Tax           string
+	GrandTotal    string
+	AmountPaid    string
+	Outstanding   string
+
+	// Statuses maps an invoice status to the text of the watermark
+	Statuses map[string]string
+}
+
+// NewLabels translates the labels with the translation files loaded by frsI18n.
+func NewLabels(lang string) Labels {
+	t := func(key string) string {
+		return frsI18n.Translate(lang, key)
+	}
+
+	statuses := map[string]string{}
+	for _, status := range []string{
+		entity.InvoiceStatusDraft,
+		entity.InvoiceStatusIssued,
+		entity.InvoiceStatusPartiallyPaid,
+		entity.InvoiceStatusPaid,
+		entity.InvoiceStatusOverpaid,
+		entity.InvoiceStatusOverdue,
+		entity.InvoiceStatusVoid,
+		entity.InvoiceStatusCredited,
+	} {
+		statuses[status] = t("doc_status_" + strings.ToLower(status))
+	}
+
+	return Labels{
+		Invoice:       t("doc_invoice"),
+		InvoiceNumber: t("doc_invoice_number"),
+		IssueDate:     t("doc_issue_date"),
+		DueDate:       t("doc_due_date"),
+		BillTo:        t("doc_bill_to"),
+		Subject:       t("doc_subject"),
+		Item:          t("doc_item"),
+		Quantity:      t("doc_quantity"),
+		UnitPrice:     t("doc_unit_price"),
+		Amount:        t("doc_amount"),
+		SubTotal:      t("doc_sub_total"),
+		Tax:           t("doc_tax"),
+		GrandTotal:    t("doc_grand_total"),
+		AmountPaid:    t("doc_amount_paid"),
+		Outstanding:   t("doc_outstanding"),
+		Statuses:      statuses,
+	}
+}
+
+// Status returns the label of status, or the status itself when it has no translation.
+func (l Labels) Status(status string) string {
+	if label, ok := l.Statuses[status]; ok && label != "" {
+		return label
+	}
+
+	return strings.ToUpper(status)
+}
+
+// FormatAmount prints an amount with two decimals and grouped thousands, e.g. 1,049.99.
+func FormatAmount(d decimal.Decimal) string {
+	s := d.StringFixed(2)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+
+	return sign + b.String() + "." + fraction
+}
+
+// SampleInvoice is the invoice used to preview templates without touching real data.
+func SampleInvoice() contract.InvoiceResponse {
+	return contract.InvoiceResponse{
+		InvoiceID:       "INV/2024/03/00042",
+		IssueDate:       "07-03-2024",
+		DueDate:         "06-04-2024",
+		Subject:         "website redesign",
+		TotalItem:       2,
+		CustomerName:    "pt maju bersama",
+		CustomerAddress: "Jl. Asia Afrika No. 8, Bandung",
+		Status:          entity.InvoiceStatusPartiallyPaid,
+		Items: []contract.ItemResponse{
+			{Name: "design", Quantity: decimal.NewFromInt(40), UnitPrice: decimal.NewFromInt(250000), Amount: decimal.NewFromInt(10000000)},
+			{Name: "hosting", Quantity: decimal.NewFromInt(12), UnitPrice: decimal.NewFromInt(150000), Amount: decimal.NewFromInt(1800000)},
+		},
+		SubTotal:           decimal.NewFromInt(11800000),
+		TaxRate:            decimal.NewFromInt(11),
+		Tax:                decimal.NewFromInt(1298000),
+		GrandTotal:         decimal.NewFromInt(13098000),
+		AmountPaid:         decimal.NewFromInt(5000000),
+		OutstandingBalance: decimal.NewFromInt(8098000),
+	}
+}
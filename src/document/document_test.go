@@ -0,0 +1,34 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/go-playground/assert"
+)
+
+func TestDocument_FormatAmount(t *testing.T) {
+	testCases := []struct {
+		given    string
+		expected string
+	}{
+		{given: "0", expected: "0.00"},
+		{given: "999.5", expected: "999.50"},
+		{given: "1049.99", expected: "1,049.99"},
+		{given: "1234567.891", expected: "1,234,567.89"},
+		{given: "-100000", expected: "-100,000.00"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.given, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, FormatAmount(decimal.RequireFromString(testCase.given)))
+		})
+	}
+}
+
+func TestDocument_LabelsStatus(t *testing.T) {
+	labels := Labels{Statuses: map[string]string{"Paid": "LUNAS"}}
+
+	assert.Equal(t, "LUNAS", labels.Status("Paid"))
+	assert.Equal(t, "PARTIALLYPAID", labels.Status("PartiallyPaid"))
+}
@@ -0,0 +1,123 @@
+// Package efaktur writes the CSV imported by the DJP e-Faktur desktop application to create
+// the output tax invoices (faktur keluaran) of a period.
+package efaktur
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+)
+
+const (
+	// TransactionCode 01 is a delivery to a buyer that is not a VAT collector
+	TransactionCode = "01"
+	// ReplacementFlag 0 is a normal tax invoice, not the replacement of an earlier one
+	ReplacementFlag = "0"
+
+	// NoNPWP is reported for buyers without an NPWP
+	NoNPWP = "000000000000000"
+
+	dateLayout = "02/01/2006"
+)
+
+var (
+	headerFK = []string{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN", "FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI", "KODE_DOKUMEN_PENDUKUNG"}
+	headerLT = []string{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN", "PROPINSI", "KODE_POS", "NOMOR_TELEPON"}
+	headerOF = []string{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN", "TARIF_PPNBM", "PPNBM"}
+)
+
+// Invoice is one tax invoice with the customer it is billed to and its items.
+type Invoice struct {
+	Invoice  entity.Invoices
+	Customer entity.Customer
+	Items    []*entity.Item
+}
+
+// Write writes the three header rows followed by, for every invoice, its FK row, the LT row of
+// the buyer and one OF row per item. e-Faktur only takes whole rupiah on the FK row, so its
+// DPP and PPN are rounded down there.
+func Write(w io.Writer, invoices []Invoice) error {
+	cw := csv.NewWriter(w)
+
+	for _, header := range [][]string{headerFK, headerLT, headerOF} {
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, invoice := range invoices {
+		if err := writeInvoice(cw, invoice); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeInvoice(cw *csv.Writer, invoice Invoice) error {
+	data := invoice.Invoice
+	npwp := invoice.Customer.NPWP
+	if npwp == "" {
+		npwp = NoNPWP
+	}
+
+	fk := []string{
+		"FK",
+		TransactionCode,
+		ReplacementFlag,
+		data.TaxInvoiceNumber,
+		strconv.Itoa(int(data.IssueDate.Month())),
+		strconv.Itoa(data.IssueDate.Year()),
+		data.IssueDate.Format(dateLayout),
+		npwp,
+		invoice.Customer.Name,
+		invoice.Customer.Address,
+		wholeRupiah(data.SubTotal),
+		wholeRupiah(data.Tax),
+		"0",
+		"",
+		"0",
+		"0",
+		"0",
+		"0",
+		data.InvoiceID,
+		"",
+	}
+	if err := cw.Write(fk); err != nil {
+		return err
+	}
+
+	lt := []string{"LT", npwp, invoice.Customer.Name, invoice.Customer.Address, "", "", "", "", "", "", "", "", "", ""}
+	if err := cw.Write(lt); err != nil {
+		return err
+	}
+
+	for _, item := range invoice.Items {
+		of := []string{
+			"OF",
+			"",
+			item.Name,
+			item.UnitPrice.String(),
+			item.Quantity.String(),
+			item.Amount.String(),
+			"0",
+			item.Amount.String(),
+			item.Amount.MulPercent(data.TaxRate, 2, decimal.RoundHalfUp).String(),
+			"0",
+			"0",
+		}
+		if err := cw.Write(of); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func wholeRupiah(d decimal.Decimal) string {
+	return d.Round(0, decimal.RoundDown).String()
+}
@@ -0,0 +1,80 @@
+package efaktur
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/go-playground/assert"
+)
+
+func TestEFaktur_Write(t *testing.T) {
+	invoices := []Invoice{
+		{
+			Invoice: entity.Invoices{InvoicesData: entity.InvoicesData{
+				InvoiceID:        "INV/2024/03/00042",
+				IssueDate:        time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+				SubTotal:         decimal.RequireFromString("1049.99"),
+				TaxRate:          decimal.NewFromInt(11),
+				Tax:              decimal.RequireFromString("115.5"),
+				TaxInvoiceNumber: "0002400000001",
+			}},
+			Customer: entity.Customer{CustomerData: entity.CustomerData{
+				Name:    "pt maju bersama",
+				Address: "Jl. Asia Afrika No. 8, Bandung",
+				NPWP:    "012345678901000",
+			}},
+			Items: []*entity.Item{
+				{ItemData: entity.ItemData{Name: "design", Quantity: decimal.RequireFromString("1.5"), UnitPrice: decimal.RequireFromString("699.99"), Amount: decimal.RequireFromString("1049.99")}},
+			},
+		},
+		{
+			Invoice: entity.Invoices{InvoicesData: entity.InvoicesData{
+				InvoiceID:        "INV/2024/12/00043",
+				IssueDate:        time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+				SubTotal:         decimal.NewFromInt(300),
+				TaxRate:          decimal.NewFromInt(0),
+				TaxInvoiceNumber: "0002400000002",
+			}},
+			Customer: entity.Customer{CustomerData: entity.CustomerData{
+				Name:    "toko \"sinar\"",
+				Address: "Jl. Braga, Bandung",
+			}},
+			Items: []*entity.Item{
+				{ItemData: entity.ItemData{Name: "hosting", Quantity: decimal.NewFromInt(3), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(300)}},
+				{ItemData: entity.ItemData{Name: "domain", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(0), Amount: decimal.NewFromInt(0)}},
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"FK,KD_JENIS_TRANSAKSI,FG_PENGGANTI,NOMOR_FAKTUR,MASA_PAJAK,TAHUN_PAJAK,TANGGAL_FAKTUR,NPWP,NAMA,ALAMAT_LENGKAP,JUMLAH_DPP,JUMLAH_PPN,JUMLAH_PPNBM,ID_KETERANGAN_TAMBAHAN,FG_UANG_MUKA,UANG_MUKA_DPP,UANG_MUKA_PPN,UANG_MUKA_PPNBM,REFERENSI,KODE_DOKUMEN_PENDUKUNG",
+		"LT,NPWP,NAMA,JALAN,BLOK,NOMOR,RT,RW,KECAMATAN,KELURAHAN,KABUPATEN,PROPINSI,KODE_POS,NOMOR_TELEPON",
+		"OF,KODE_OBJEK,NAMA,HARGA_SATUAN,JUMLAH_BARANG,HARGA_TOTAL,DISKON,DPP,PPN,TARIF_PPNBM,PPNBM",
+		`FK,01,0,0002400000001,3,2024,07/03/2024,012345678901000,pt maju bersama,"Jl. Asia Afrika No. 8, Bandung",1049,115,0,,0,0,0,0,INV/2024/03/00042,`,
+		`LT,012345678901000,pt maju bersama,"Jl. Asia Afrika No. 8, Bandung",,,,,,,,,,`,
+		"OF,,design,699.99,1.5,1049.99,0,1049.99,115.5,0,0",
+		`FK,01,0,0002400000002,12,2024,31/12/2024,000000000000000,"toko ""sinar""","Jl. Braga, Bandung",300,0,0,,0,0,0,0,INV/2024/12/00043,`,
+		`LT,000000000000000,"toko ""sinar""","Jl. Braga, Bandung",,,,,,,,,,`,
+		"OF,,hosting,100,3,300,0,300,0,0,0",
+		"OF,,domain,0,1,0,0,0,0,0,0",
+		"",
+	}, "\n")
+
+	var buf bytes.Buffer
+	err := Write(&buf, invoices)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestEFaktur_WriteEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, nil)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+}
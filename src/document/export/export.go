@@ -0,0 +1,270 @@
+// Package export writes the invoice list as a CSV or XLSX spreadsheet. Rows are written one
+// at a time as they are read from the database, so the size of an export is not bounded by
+// memory.
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	SheetInvoices = "Invoices"
+	SheetItems    = "Items"
+
+	dateLayout = "02-01-2006"
+)
+
+var ErrUnknownFormat = errors.New("export: unknown format")
+
+var (
+	invoiceColumns = []string{"invoice_id", "issue_date", "due_date", "subject", "customer_name", "status", "total_items", "sub_total", "tax_rate", "tax", "grand_total", "amount_paid", "amount_credited"}
+	itemColumns    = []string{"item_id", "item_name", "item_type", "quantity", "unit_price", "amount"}
+)
+
+// Writer receives the rows of an export in order. Item is nil when the items are not exported
+// or the invoice has none; an invoice with several items comes once per item.
+type Writer interface {
+	Write(invoice *entity.Invoices, item *entity.Item) error
+	// Close writes what is still buffered, the writer is not usable afterwards.
+	Close() error
+	// Abort releases the writer after a failed export without completing the document.
+	Abort()
+}
+
+// ContentType returns the media type of format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// NewWriter returns the writer of format. With includeItems a CSV export repeats the invoice
+// columns on one row per item, an XLSX export lists the items on a second sheet.
+func NewWriter(w io.Writer, format string, includeItems bool) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, includeItems)
+	case FormatXLSX:
+		return newXLSXWriter(w, includeItems)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func invoiceValues(invoice *entity.Invoices) []string {
+	return []string{
+		invoice.InvoiceID,
+		invoice.IssueDate.Format(dateLayout),
+		invoice.DueDate.Format(dateLayout),
+		safeText(invoice.Subject),
+		safeText(invoice.CustomerName),
+		invoice.Status,
+		strconv.Itoa(invoice.TotalItems),
+		invoice.SubTotal.String(),
+		invoice.TaxRate.String(),
+		invoice.Tax.String(),
+		invoice.GrandTotal.String(),
+		invoice.AmountPaid.String(),
+		invoice.AmountCredited.String(),
+	}
+}
+
+// safeText keeps a spreadsheet from reading text typed by users as a formula, a leading
+// quote makes it a plain string.
+func safeText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func itemValues(item *entity.Item) []string {
+	if item == nil {
+		return make([]string, len(itemColumns))
+	}
+
+	return []string{
+		item.ItemID.String(),
+		safeText(item.Name),
+		item.Type,
+		item.Quantity.String(),
+		item.UnitPrice.String(),
+		item.Amount.String(),
+	}
+}
+
+type csvWriter struct {
+	w            *csv.Writer
+	includeItems bool
+}
+
+func newCSVWriter(w io.Writer, includeItems bool) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), includeItems: includeItems}
+
+	header := invoiceColumns
+	if includeItems {
+		header = append(append([]string{}, invoiceColumns...), itemColumns...)
+	}
+
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(invoice *entity.Invoices, item *entity.Item) error {
+	row := invoiceValues(invoice)
+	if cw.includeItems {
+		row = append(row, itemValues(item)...)
+	}
+
+	// csv.Writer buffers 4KB at a time, so the rows reach the client while the query runs
+	return cw.w.Write(row)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Abort() {}
+
+// xlsxWriter streams the sheets to temporary files, excelize keeps at most 16MB of each in
+// memory. The workbook is a zip archive, so nothing reaches w before Close.
+type xlsxWriter struct {
+	w             io.Writer
+	file          *excelize.File
+	invoices      *excelize.StreamWriter
+	items         *excelize.StreamWriter
+	invoiceRow    int
+	itemRow       int
+	lastInvoiceID string
+}
+
+func newXLSXWriter(w io.Writer, includeItems bool) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	xw := &xlsxWriter{w: w, file: file, invoiceRow: 1, itemRow: 1}
+
+	if err := file.SetSheetName("Sheet1", SheetInvoices); err != nil {
+		return nil, xw.fail(err)
+	}
+
+	var err error
+	if xw.invoices, err = file.NewStreamWriter(SheetInvoices); err != nil {
+		return nil, xw.fail(err)
+	}
+	if err := xw.invoices.SetRow("A1", cells(invoiceColumns)); err != nil {
+		return nil, xw.fail(err)
+	}
+
+	if includeItems {
+		if _, err := file.NewSheet(SheetItems); err != nil {
+			return nil, xw.fail(err)
+		}
+		if xw.items, err = file.NewStreamWriter(SheetItems); err != nil {
+			return nil, xw.fail(err)
+		}
+		if err := xw.items.SetRow("A1", cells(append([]string{"invoice_id"}, itemColumns...))); err != nil {
+			return nil, xw.fail(err)
+		}
+	}
+
+	return xw, nil
+}
+
+func (xw *xlsxWriter) Write(invoice *entity.Invoices, item *entity.Item) error {
+	if invoice.InvoiceID != xw.lastInvoiceID {
+		xw.lastInvoiceID = invoice.InvoiceID
+		xw.invoiceRow++
+
+		row := []interface{}{
+			invoice.InvoiceID,
+			invoice.IssueDate.Format(dateLayout),
+			invoice.DueDate.Format(dateLayout),
+			safeText(invoice.Subject),
+			safeText(invoice.CustomerName),
+			invoice.Status,
+			invoice.TotalItems,
+			invoice.SubTotal.Float64(),
+			invoice.TaxRate.Float64(),
+			invoice.Tax.Float64(),
+			invoice.GrandTotal.Float64(),
+			invoice.AmountPaid.Float64(),
+			invoice.AmountCredited.Float64(),
+		}
+		if err := xw.invoices.SetRow(cellName(xw.invoiceRow), row); err != nil {
+			return err
+		}
+	}
+
+	if xw.items == nil || item == nil {
+		return nil
+	}
+
+	xw.itemRow++
+	row := []interface{}{
+		invoice.InvoiceID,
+		item.ItemID.String(),
+		safeText(item.Name),
+		item.Type,
+		item.Quantity.Float64(),
+		item.UnitPrice.Float64(),
+		item.Amount.Float64(),
+	}
+
+	return xw.items.SetRow(cellName(xw.itemRow), row)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+
+	if err := xw.invoices.Flush(); err != nil {
+		return err
+	}
+
+	if xw.items != nil {
+		if err := xw.items.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return xw.file.Write(xw.w)
+}
+
+func (xw *xlsxWriter) Abort() {
+	xw.file.Close()
+}
+
+// fail releases the temporary files of a writer that could not be set up.
+func (xw *xlsxWriter) fail(err error) error {
+	xw.file.Close()
+	return err
+}
+
+func cells(values []string) []interface{} {
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+
+	return row
+}
+
+func cellName(row int) string {
+	return "A" + strconv.Itoa(row)
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+type exportRow struct {
+	invoice *entity.Invoices
+	item    *entity.Item
+}
+
+func sampleRows() []exportRow {
+	first := &entity.Invoices{InvoicesData: entity.InvoicesData{
+		InvoiceID:      "0001",
+		IssueDate:      time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+		DueDate:        time.Date(2024, time.April, 6, 0, 0, 0, 0, time.UTC),
+		Subject:        "website, redesign",
+		CustomerName:   "acme",
+		Status:         entity.InvoiceStatusIssued,
+		TotalItems:     2,
+		SubTotal:       decimal.RequireFromString("1049.99"),
+		TaxRate:        decimal.NewFromInt(11),
+		Tax:            decimal.RequireFromString("115.5"),
+		GrandTotal:     decimal.RequireFromString("1165.49"),
+		AmountPaid:     decimal.NewFromInt(500),
+		AmountCredited: decimal.RequireFromString("100.5"),
+	}}
+	second := &entity.Invoices{InvoicesData: entity.InvoicesData{
+		InvoiceID:    "0002",
+		IssueDate:    time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+		DueDate:      time.Date(2024, time.April, 7, 0, 0, 0, 0, time.UTC),
+		Subject:      "=HYPERLINK(\"http://evil\")",
+		CustomerName: "@globex",
+		Status:       entity.InvoiceStatusDraft,
+	}}
+
+	design := &entity.Item{ItemData: entity.ItemData{
+		ItemID:    uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Name:      "design",
+		Type:      "service",
+		Quantity:  decimal.RequireFromString("1.5"),
+		UnitPrice: decimal.RequireFromString("699.99"),
+		Amount:    decimal.RequireFromString("1049.99"),
+	}}
+	support := &entity.Item{ItemData: entity.ItemData{
+		ItemID: uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		Name:   "-support",
+		Type:   "service",
+	}}
+
+	return []exportRow{{first, design}, {first, support}, {second, nil}}
+}
+
+func writeRows(t *testing.T, format string, includeItems bool, rows []exportRow) []byte {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, format, includeItems)
+	assert.Equal(t, nil, err)
+
+	for _, row := range rows {
+		assert.Equal(t, nil, w.Write(row.invoice, row.item))
+	}
+	assert.Equal(t, nil, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestExport_CSV(t *testing.T) {
+	t.Run("invoices only", func(t *testing.T) {
+		rows := sampleRows()
+		got := writeRows(t, FormatCSV, false, []exportRow{{rows[0].invoice, nil}, rows[2]})
+
+		expected := strings.Join([]string{
+			"invoice_id,issue_date,due_date,subject,customer_name,status,total_items,sub_total,tax_rate,tax,grand_total,amount_paid,amount_credited",
+			`0001,07-03-2024,06-04-2024,"website, redesign",acme,Issued,2,1049.99,11,115.5,1165.49,500,100.5`,
+			`0002,08-03-2024,07-04-2024,"'=HYPERLINK(""http://evil"")",'@globex,Draft,0,0,0,0,0,0,0`,
+			"",
+		}, "\n")
+		assert.Equal(t, expected, string(got))
+	})
+
+	t.Run("flattened items", func(t *testing.T) {
+		got := writeRows(t, FormatCSV, true, sampleRows())
+
+		expected := strings.Join([]string{
+			"invoice_id,issue_date,due_date,subject,customer_name,status,total_items,sub_total,tax_rate,tax,grand_total,amount_paid,amount_credited,item_id,item_name,item_type,quantity,unit_price,amount",
+			`0001,07-03-2024,06-04-2024,"website, redesign",acme,Issued,2,1049.99,11,115.5,1165.49,500,100.5,11111111-1111-1111-1111-111111111111,design,service,1.5,699.99,1049.99`,
+			`0001,07-03-2024,06-04-2024,"website, redesign",acme,Issued,2,1049.99,11,115.5,1165.49,500,100.5,22222222-2222-2222-2222-222222222222,'-support,service,0,0,0`,
+			`0002,08-03-2024,07-04-2024,"'=HYPERLINK(""http://evil"")",'@globex,Draft,0,0,0,0,0,0,0,,,,,,`,
+			"",
+		}, "\n")
+		assert.Equal(t, expected, string(got))
+	})
+}
+
+func TestExport_XLSX(t *testing.T) {
+	got := writeRows(t, FormatXLSX, true, sampleRows())
+
+	file, err := excelize.OpenReader(bytes.NewReader(got))
+	assert.Equal(t, nil, err)
+	defer file.Close()
+
+	assert.Equal(t, []string{SheetInvoices, SheetItems}, file.GetSheetList())
+
+	invoices, err := file.GetRows(SheetInvoices)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(invoices))
+	assert.Equal(t, invoiceColumns, invoices[0])
+	assert.Equal(t, []string{"0001", "07-03-2024", "06-04-2024", "website, redesign", "acme", "Issued", "2", "1049.99", "11", "115.5", "1165.49", "500", "100.5"}, invoices[1])
+	assert.Equal(t, []string{"0002", "'=HYPERLINK(\"http://evil\")", "'@globex"}, []string{invoices[2][0], invoices[2][3], invoices[2][4]})
+
+	items, err := file.GetRows(SheetItems)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, []string{"0001", "11111111-1111-1111-1111-111111111111", "design", "service", "1.5", "699.99", "1049.99"}, items[1])
+	assert.Equal(t, "'-support", items[2][2])
+}
+
+func TestExport_UnknownFormat(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, "pdf", false)
+
+	assert.Equal(t, true, errors.Is(err, ErrUnknownFormat))
+	assert.Equal(t, "text/csv; charset=utf-8", ContentType(FormatCSV))
+}
+
+func TestExport_Abort(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, FormatXLSX, true)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, w.Write(sampleRows()[0].invoice, sampleRows()[0].item))
+	w.Abort()
+
+	// an aborted workbook never reaches the client
+	assert.Equal(t, 0, buf.Len())
+}
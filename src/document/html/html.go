@@ -0,0 +1,137 @@
+// Package html renders invoices with the html/template files of a directory, so every
+// deployment can ship its own layout and branding without a rebuild.
+package html
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+// DefaultTemplate is used when the request does not name a template.
+const DefaultTemplate = "default"
+
+// templateExtension is the extension of the template files, the file name without it is the
+// name of the template.
+const templateExtension = ".html"
+
+var ErrUnknownTemplate = errors.New("html: unknown template")
+
+// Branding is the company printed on every document of the deployment.
+type Branding struct {
+	CompanyName    string
+	CompanyAddress string
+	BankAccount    string
+	FooterNotes    string
+	// LogoPath is an image file embedded in the document, no logo is printed when it is empty
+	LogoPath string
+}
+
+// Data is what the templates are executed with.
+type Data struct {
+	Invoice  contract.InvoiceResponse
+	Labels   document.Labels
+	Branding Branding
+	// Logo is the logo as a data URI, empty without a logo
+	Logo template.URL
+}
+
+type Renderer struct {
+	templates map[string]*template.Template
+	branding  Branding
+	logo      template.URL
+}
+
+var funcs = template.FuncMap{
+	"amount": document.FormatAmount,
+}
+
+// NewRenderer parses every template of dir and reads the logo of branding. Templates are
+// parsed once, a broken template fails here instead of on the first request.
+func NewRenderer(dir string, branding Branding) (*Renderer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s templates in %s", templateExtension, dir)
+	}
+
+	templates := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), templateExtension)
+
+		tmpl, err := template.New(filepath.Base(file)).Funcs(funcs).ParseFiles(file)
+		if err != nil {
+			return nil, fmt.Errorf("parse template %s: %w", file, err)
+		}
+
+		templates[name] = tmpl
+	}
+
+	logo, err := logoURL(branding.LogoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		templates: templates,
+		branding:  branding,
+		logo:      logo,
+	}, nil
+}
+
+func logoURL(path string) (template.URL, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	image, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read logo: %w", err)
+	}
+
+	// the logo comes from the deployment configuration, not from the request
+	return template.URL("data:" + http.DetectContentType(image) + ";base64," + base64.StdEncoding.EncodeToString(image)), nil
+}
+
+// Templates returns the template names in alphabetical order.
+func (r *Renderer) Templates() []string {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Render executes the named template for the invoice.
+func (r *Renderer) Render(w io.Writer, name string, invoice contract.InvoiceResponse, labels document.Labels) error {
+	if name == "" {
+		name = DefaultTemplate
+	}
+
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+
+	return tmpl.Execute(w, Data{
+		Invoice:  invoice,
+		Labels:   labels,
+		Branding: r.branding,
+		Logo:     r.logo,
+	})
+}
@@ -0,0 +1,90 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+)
+
+func TestHTML_NewRenderer(t *testing.T) {
+	t.Run("err empty directory", func(t *testing.T) {
+		_, err := NewRenderer(t.TempDir(), Branding{})
+		assert.NotEqual(t, nil, err)
+	})
+
+	t.Run("err broken template", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "broken.html"), []byte("{{.Invoice"), 0o644)
+
+		_, err := NewRenderer(dir, Branding{})
+		assert.NotEqual(t, nil, err)
+	})
+
+	t.Run("err missing logo", func(t *testing.T) {
+		_, err := NewRenderer("../../../templates/html", Branding{LogoPath: filepath.Join(t.TempDir(), "logo.png")})
+		assert.NotEqual(t, nil, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "default.html"), []byte("{{.Invoice.InvoiceID}}"), 0o644)
+		os.WriteFile(filepath.Join(dir, "compact.html"), []byte("{{.Invoice.InvoiceID}}"), 0o644)
+		os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a template"), 0o644)
+
+		renderer, err := NewRenderer(dir, Branding{})
+		assert.Equal(t, nil, err)
+		assert.Equal(t, []string{"compact", "default"}, renderer.Templates())
+	})
+}
+
+func TestHTML_Render(t *testing.T) {
+	dir := t.TempDir()
+	logo := filepath.Join(dir, "logo.png")
+	os.WriteFile(logo, []byte("\x89PNG\r\n\x1a\n"), 0o644)
+
+	renderer, err := NewRenderer("../../../templates/html", Branding{
+		CompanyName: "PT Contoh",
+		BankAccount: "BCA 123",
+		FooterNotes: "Thank you",
+		LogoPath:    logo,
+	})
+	assert.Equal(t, nil, err)
+
+	invoice := document.SampleInvoice()
+	invoice.CustomerName = "<script>alert(1)</script>"
+	labels := document.Labels{Invoice: "INVOICE", Statuses: map[string]string{"PartiallyPaid": "PARTIALLY PAID"}}
+
+	t.Run("default template", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		assert.Equal(t, nil, renderer.Render(&buf, "", invoice, labels))
+
+		out := buf.String()
+		for _, want := range []string{
+			"INV/2024/03/00042",
+			"PT Contoh",
+			"BCA 123",
+			"Thank you",
+			"PARTIALLY PAID",
+			"13,098,000.00",
+			`src="data:image/png;base64,`,
+			"&lt;script&gt;",
+		} {
+			assert.Equal(t, true, strings.Contains(out, want))
+		}
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := renderer.Render(&buf, "fancy", contract.InvoiceResponse{}, labels)
+		assert.Equal(t, true, errors.Is(err, ErrUnknownTemplate))
+	})
+}
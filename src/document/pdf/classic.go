@@ -0,0 +1,113 @@
+package pdf
+
+import (
+	"github.com/Risuii/invoice/src/document"
+	"github.com/jung-kurt/gofpdf"
+)
+
+func init() {
+	Register(DefaultTemplate, Classic{})
+}
+
+// Classic is a plain A4 layout: the header and customer on top, the item table, then the
+// totals on the right. The status is printed diagonally across the page.
+type Classic struct{}
+
+type classicTotal struct {
+	label string
+	value string
+	bold  bool
+}
+
+// column widths of the item table, they add up to the printable width of an A4 page
+var classicColumns = []float64{90, 20, 40, 40}
+
+func (Classic) Render(f *gofpdf.Fpdf, tr func(string) string, data Data) {
+	invoice, labels := data.Invoice, data.Labels
+	left, _, right, _ := f.GetMargins()
+	pageWidth, _ := f.GetPageSize()
+	width := pageWidth - left - right
+
+	classicWatermark(f, tr(labels.Status(invoice.Status)))
+
+	// header
+	f.SetFont("Helvetica", "B", 20)
+	f.CellFormat(width/2, 10, tr(labels.Invoice), "", 0, "L", false, 0, "")
+	f.SetFont("Helvetica", "", 10)
+	f.CellFormat(width/2, 5, tr(labels.InvoiceNumber+": "+invoice.InvoiceID), "", 2, "R", false, 0, "")
+	f.CellFormat(width/2, 5, tr(labels.IssueDate+": "+invoice.IssueDate), "", 2, "R", false, 0, "")
+	f.CellFormat(width/2, 5, tr(labels.DueDate+": "+invoice.DueDate), "", 1, "R", false, 0, "")
+	f.Ln(8)
+
+	// customer and subject
+	f.SetFont("Helvetica", "B", 10)
+	f.CellFormat(width, 5, tr(labels.BillTo), "", 1, "L", false, 0, "")
+	f.SetFont("Helvetica", "", 10)
+	f.MultiCell(width/2, 5, tr(invoice.CustomerName+"\n"+invoice.CustomerAddress), "", "L", false)
+	f.Ln(4)
+	f.MultiCell(width, 5, tr(labels.Subject+": "+invoice.Subject), "", "L", false)
+	f.Ln(4)
+
+	// items
+	f.SetFont("Helvetica", "B", 10)
+	f.SetFillColor(235, 235, 235)
+	for i, header := range []string{labels.Item, labels.Quantity, labels.UnitPrice, labels.Amount} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		f.CellFormat(classicColumns[i], 7, tr(header), "B", 0, align, true, 0, "")
+	}
+	f.Ln(-1)
+
+	f.SetFont("Helvetica", "", 10)
+	for _, item := range invoice.Items {
+		f.CellFormat(classicColumns[0], 6, tr(item.Name), "", 0, "L", false, 0, "")
+		f.CellFormat(classicColumns[1], 6, item.Quantity.String(), "", 0, "R", false, 0, "")
+		f.CellFormat(classicColumns[2], 6, document.FormatAmount(item.UnitPrice), "", 0, "R", false, 0, "")
+		f.CellFormat(classicColumns[3], 6, document.FormatAmount(item.Amount), "", 1, "R", false, 0, "")
+	}
+	f.CellFormat(width, 2, "", "T", 1, "", false, 0, "")
+
+	// totals
+	totals := []classicTotal{
+		{labels.SubTotal, document.FormatAmount(invoice.SubTotal), false},
+		{labels.Tax + " (" + invoice.TaxRate.String() + "%)", document.FormatAmount(invoice.Tax), false},
+		{labels.GrandTotal, document.FormatAmount(invoice.GrandTotal), true},
+	}
+
+	if !invoice.AmountPaid.IsZero() {
+		totals = append(totals,
+			classicTotal{labels.AmountPaid, document.FormatAmount(invoice.AmountPaid), false},
+			classicTotal{labels.Outstanding, document.FormatAmount(invoice.OutstandingBalance), true},
+		)
+	}
+
+	for _, total := range totals {
+		style := ""
+		if total.bold {
+			style = "B"
+		}
+		f.SetFont("Helvetica", style, 10)
+		f.CellFormat(width-classicColumns[3]-classicColumns[2], 6, "", "", 0, "", false, 0, "")
+		f.CellFormat(classicColumns[2], 6, tr(total.label), "", 0, "L", false, 0, "")
+		f.CellFormat(classicColumns[3], 6, total.value, "", 1, "R", false, 0, "")
+	}
+}
+
+func classicWatermark(f *gofpdf.Fpdf, text string) {
+	pageWidth, pageHeight := f.GetPageSize()
+	x, y := f.GetXY()
+
+	f.SetFont("Helvetica", "B", 80)
+	f.SetTextColor(225, 225, 225)
+	textWidth := f.GetStringWidth(text)
+
+	f.TransformBegin()
+	f.TransformRotate(45, pageWidth/2, pageHeight/2)
+	f.Text(pageWidth/2-textWidth/2, pageHeight/2, text)
+	f.TransformEnd()
+
+	f.SetTextColor(0, 0, 0)
+	f.SetXY(x, y)
+}
@@ -0,0 +1,82 @@
+// Package pdf renders invoices to PDF with gofpdf. The layout is chosen by name among the
+// registered templates, so another layout only needs a Template and a call to Register.
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DefaultTemplate is used when the request does not name a template.
+const DefaultTemplate = "classic"
+
+var ErrUnknownTemplate = errors.New("pdf: unknown template")
+
+// Data is what a template gets to draw.
+type Data struct {
+	Invoice contract.InvoiceResponse
+	Labels  document.Labels
+}
+
+// Template draws an invoice on a new document. Tr converts UTF-8 text to the encoding of the
+// core fonts and must wrap every string written to the page.
+type Template interface {
+	Render(f *gofpdf.Fpdf, tr func(string) string, data Data)
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   = map[string]Template{}
+)
+
+// Register makes a template available under name, replacing any template with the same name.
+func Register(name string, template Template) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+
+	templates[name] = template
+}
+
+// Templates returns the registered template names in alphabetical order.
+func Templates() []string {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Render writes the invoice as a PDF drawn by the named template.
+func Render(w io.Writer, name string, data Data) error {
+	if name == "" {
+		name = DefaultTemplate
+	}
+
+	templatesMu.RLock()
+	template, ok := templates[name]
+	templatesMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+
+	f := gofpdf.New("P", "mm", "A4", "")
+	f.SetTitle(data.Labels.Invoice+" "+data.Invoice.InvoiceID, true)
+	f.AddPage()
+
+	template.Render(f, f.UnicodeTranslatorFromDescriptor(""), data)
+
+	return f.Output(w)
+}
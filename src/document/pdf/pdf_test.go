@@ -0,0 +1,72 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/jung-kurt/gofpdf"
+)
+
+type recordingTemplate struct {
+	rendered *Data
+}
+
+func (r recordingTemplate) Render(f *gofpdf.Fpdf, tr func(string) string, data Data) {
+	*r.rendered = data
+	f.Text(10, 10, tr(data.Invoice.InvoiceID))
+}
+
+func TestPDF_Render(t *testing.T) {
+	data := Data{
+		Invoice: contract.InvoiceResponse{
+			InvoiceID:       "INV/2024/00042",
+			IssueDate:       "07-03-2024",
+			DueDate:         "06-04-2024",
+			Subject:         "consulting",
+			CustomerName:    "Café Jakarta",
+			CustomerAddress: "Jl. Sudirman 1\nJakarta",
+			Status:          "PartiallyPaid",
+			Items: []contract.ItemResponse{
+				{Name: "design", Quantity: decimal.RequireFromString("1.5"), UnitPrice: decimal.NewFromInt(1000), Amount: decimal.NewFromInt(1500)},
+			},
+			SubTotal:           decimal.NewFromInt(1500),
+			TaxRate:            decimal.NewFromInt(11),
+			Tax:                decimal.NewFromInt(165),
+			GrandTotal:         decimal.NewFromInt(1665),
+			AmountPaid:         decimal.NewFromInt(665),
+			OutstandingBalance: decimal.NewFromInt(1000),
+		},
+		Labels: document.Labels{Invoice: "INVOICE", Statuses: map[string]string{"PartiallyPaid": "PARTIALLY PAID"}},
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		assert.Equal(t, nil, Render(&buf, "", data))
+		assert.Equal(t, true, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	})
+
+	t.Run("registered template", func(t *testing.T) {
+		var rendered Data
+		Register("recording", recordingTemplate{rendered: &rendered})
+
+		var buf bytes.Buffer
+
+		assert.Equal(t, nil, Render(&buf, "recording", data))
+		assert.Equal(t, data.Invoice.InvoiceID, rendered.Invoice.InvoiceID)
+		assert.Equal(t, []string{"classic", "recording"}, Templates())
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Render(&buf, "fancy", data)
+		assert.Equal(t, true, errors.Is(err, ErrUnknownTemplate))
+		assert.Equal(t, 0, buf.Len())
+	})
+}
@@ -0,0 +1,323 @@
+// Package ubl maps invoices to UBL 2.1 Invoice documents that follow PEPPOL BIS Billing 3.0,
+// the structured e-invoice format some customers require instead of a PDF.
+package ubl
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+)
+
+const (
+	CustomizationID = "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0"
+	ProfileID       = "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+
+	NamespaceInvoice = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	NamespaceCAC     = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	NamespaceCBC     = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+
+	// InvoiceTypeCommercial is the UNCL1001 code of a commercial invoice
+	InvoiceTypeCommercial = "380"
+
+	// UNCL4461 payment means, a credit transfer needs the account of the payee
+	PaymentMeansCreditTransfer = "30"
+	PaymentMeansNotDefined     = "1"
+
+	// UNCL5305 tax categories, the invoices carry a single tax rate
+	TaxCategoryStandard = "S"
+	TaxCategoryZero     = "Z"
+	TaxSchemeVAT        = "VAT"
+
+	// UnitCodeOne is the UN/ECE rec 20 code for "one", item quantities have no unit of measure
+	UnitCodeOne = "C62"
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	ErrSupplierEndpoint = errors.New("ubl: the supplier has no endpoint id")
+	ErrCustomerEndpoint = errors.New("ubl: the customer has no endpoint id")
+)
+
+// Supplier is the company sending the invoices, taken from the document configuration.
+type Supplier struct {
+	Name             string
+	Address          string
+	CountryCode      string
+	Currency         string
+	TaxID            string
+	EndpointID       string
+	EndpointScheme   string
+	PaymentAccountID string
+}
+
+// Invoice is the root element. The element names carry their namespace prefix because
+// encoding/xml can not write prefixed names from namespaces.
+type Invoice struct {
+	XMLName  xml.Name `xml:"Invoice"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	XmlnsCAC string   `xml:"xmlns:cac,attr"`
+	XmlnsCBC string   `xml:"xmlns:cbc,attr"`
+
+	CustomizationID         string        `xml:"cbc:CustomizationID"`
+	ProfileID               string        `xml:"cbc:ProfileID"`
+	ID                      string        `xml:"cbc:ID"`
+	IssueDate               string        `xml:"cbc:IssueDate"`
+	DueDate                 string        `xml:"cbc:DueDate"`
+	InvoiceTypeCode         string        `xml:"cbc:InvoiceTypeCode"`
+	Note                    string        `xml:"cbc:Note,omitempty"`
+	DocumentCurrencyCode    string        `xml:"cbc:DocumentCurrencyCode"`
+	BuyerReference          string        `xml:"cbc:BuyerReference"`
+	AccountingSupplierParty PartyWrapper  `xml:"cac:AccountingSupplierParty"`
+	AccountingCustomerParty PartyWrapper  `xml:"cac:AccountingCustomerParty"`
+	PaymentMeans            PaymentMeans  `xml:"cac:PaymentMeans"`
+	TaxTotal                TaxTotal      `xml:"cac:TaxTotal"`
+	LegalMonetaryTotal      MonetaryTotal `xml:"cac:LegalMonetaryTotal"`
+	InvoiceLines            []InvoiceLine `xml:"cac:InvoiceLine"`
+}
+
+type PartyWrapper struct {
+	Party Party `xml:"cac:Party"`
+}
+
+type Party struct {
+	EndpointID       Identifier      `xml:"cbc:EndpointID"`
+	PostalAddress    Address         `xml:"cac:PostalAddress"`
+	PartyTaxScheme   *PartyTaxScheme `xml:"cac:PartyTaxScheme,omitempty"`
+	PartyLegalEntity LegalEntity     `xml:"cac:PartyLegalEntity"`
+}
+
+type Identifier struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type Address struct {
+	StreetName string  `xml:"cbc:StreetName,omitempty"`
+	Country    Country `xml:"cac:Country"`
+}
+
+type Country struct {
+	IdentificationCode string `xml:"cbc:IdentificationCode"`
+}
+
+type PartyTaxScheme struct {
+	CompanyID string    `xml:"cbc:CompanyID"`
+	TaxScheme TaxScheme `xml:"cac:TaxScheme"`
+}
+
+type LegalEntity struct {
+	RegistrationName string `xml:"cbc:RegistrationName"`
+}
+
+type PaymentMeans struct {
+	PaymentMeansCode      string            `xml:"cbc:PaymentMeansCode"`
+	PaymentID             string            `xml:"cbc:PaymentID"`
+	PayeeFinancialAccount *FinancialAccount `xml:"cac:PayeeFinancialAccount,omitempty"`
+}
+
+type FinancialAccount struct {
+	ID   string `xml:"cbc:ID"`
+	Name string `xml:"cbc:Name,omitempty"`
+}
+
+type TaxTotal struct {
+	TaxAmount    Amount        `xml:"cbc:TaxAmount"`
+	TaxSubtotals []TaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type TaxSubtotal struct {
+	TaxableAmount Amount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     Amount      `xml:"cbc:TaxAmount"`
+	TaxCategory   TaxCategory `xml:"cac:TaxCategory"`
+}
+
+type TaxCategory struct {
+	ID        string    `xml:"cbc:ID"`
+	Percent   string    `xml:"cbc:Percent"`
+	TaxScheme TaxScheme `xml:"cac:TaxScheme"`
+}
+
+type TaxScheme struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type MonetaryTotal struct {
+	LineExtensionAmount Amount  `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount  Amount  `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount  Amount  `xml:"cbc:TaxInclusiveAmount"`
+	PrepaidAmount       *Amount `xml:"cbc:PrepaidAmount,omitempty"`
+	PayableAmount       Amount  `xml:"cbc:PayableAmount"`
+}
+
+type InvoiceLine struct {
+	ID                  string   `xml:"cbc:ID"`
+	InvoicedQuantity    Quantity `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount Amount   `xml:"cbc:LineExtensionAmount"`
+	Item                Item     `xml:"cac:Item"`
+	Price               Price    `xml:"cac:Price"`
+}
+
+type Item struct {
+	Name                  string      `xml:"cbc:Name"`
+	ClassifiedTaxCategory TaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
+
+type Price struct {
+	PriceAmount Amount `xml:"cbc:PriceAmount"`
+}
+
+type Amount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type Quantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// Encoder writes the invoices of one supplier.
+type Encoder struct {
+	supplier Supplier
+}
+
+func NewEncoder(supplier Supplier) *Encoder {
+	return &Encoder{supplier: supplier}
+}
+
+// Encode writes the UBL document of invoice, billed to customer, as indented XML.
+func (e *Encoder) Encode(w io.Writer, invoice entity.Invoices, customer entity.Customer, items []*entity.Item) error {
+	doc, err := Build(e.supplier, invoice, customer, items)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// Build maps the invoice to its UBL document. Both parties need an endpoint id, PEPPOL
+// routes the document with them.
+func Build(supplier Supplier, invoice entity.Invoices, customer entity.Customer, items []*entity.Item) (Invoice, error) {
+	if supplier.EndpointID == "" {
+		return Invoice{}, ErrSupplierEndpoint
+	}
+	if customer.EndpointID == "" {
+		return Invoice{}, ErrCustomerEndpoint
+	}
+
+	amount := func(d decimal.Decimal) Amount {
+		return Amount{CurrencyID: supplier.Currency, Value: d.StringFixed(2)}
+	}
+
+	category := taxCategory(invoice.TaxRate)
+
+	// customers without a country are billed in the country of the supplier
+	customerCountry := customer.CountryCode
+	if customerCountry == "" {
+		customerCountry = supplier.CountryCode
+	}
+
+	paymentMeans := PaymentMeans{
+		PaymentMeansCode: PaymentMeansNotDefined,
+		PaymentID:        invoice.InvoiceID,
+	}
+	if supplier.PaymentAccountID != "" {
+		paymentMeans.PaymentMeansCode = PaymentMeansCreditTransfer
+		paymentMeans.PayeeFinancialAccount = &FinancialAccount{ID: supplier.PaymentAccountID, Name: supplier.Name}
+	}
+
+	monetaryTotal := MonetaryTotal{
+		LineExtensionAmount: amount(invoice.SubTotal),
+		TaxExclusiveAmount:  amount(invoice.SubTotal),
+		TaxInclusiveAmount:  amount(invoice.GrandTotal),
+		PayableAmount:       amount(invoice.GrandTotal.Sub(invoice.AmountPaid)),
+	}
+	if !invoice.AmountPaid.IsZero() {
+		prepaid := amount(invoice.AmountPaid)
+		monetaryTotal.PrepaidAmount = &prepaid
+	}
+
+	lines := make([]InvoiceLine, 0, len(items))
+	for i, item := range items {
+		lines = append(lines, InvoiceLine{
+			ID:                  strconv.Itoa(i + 1),
+			InvoicedQuantity:    Quantity{UnitCode: UnitCodeOne, Value: item.Quantity.String()},
+			LineExtensionAmount: amount(item.Amount),
+			Item:                Item{Name: item.Name, ClassifiedTaxCategory: category},
+			Price:               Price{PriceAmount: amount(item.UnitPrice)},
+		})
+	}
+
+	return Invoice{
+		Xmlns:    NamespaceInvoice,
+		XmlnsCAC: NamespaceCAC,
+		XmlnsCBC: NamespaceCBC,
+
+		CustomizationID:      CustomizationID,
+		ProfileID:            ProfileID,
+		ID:                   invoice.InvoiceID,
+		IssueDate:            invoice.IssueDate.Format(dateLayout),
+		DueDate:              invoice.DueDate.Format(dateLayout),
+		InvoiceTypeCode:      InvoiceTypeCommercial,
+		Note:                 invoice.Subject,
+		DocumentCurrencyCode: supplier.Currency,
+		BuyerReference:       customer.CustomerID.String(),
+		AccountingSupplierParty: PartyWrapper{Party: Party{
+			EndpointID:       Identifier{SchemeID: supplier.EndpointScheme, Value: supplier.EndpointID},
+			PostalAddress:    Address{StreetName: supplier.Address, Country: Country{IdentificationCode: supplier.CountryCode}},
+			PartyTaxScheme:   partyTaxScheme(supplier.TaxID),
+			PartyLegalEntity: LegalEntity{RegistrationName: supplier.Name},
+		}},
+		AccountingCustomerParty: PartyWrapper{Party: Party{
+			EndpointID:       Identifier{SchemeID: customer.EndpointScheme, Value: customer.EndpointID},
+			PostalAddress:    Address{StreetName: customer.Address, Country: Country{IdentificationCode: customerCountry}},
+			PartyTaxScheme:   partyTaxScheme(customer.NPWP),
+			PartyLegalEntity: LegalEntity{RegistrationName: customer.Name},
+		}},
+		PaymentMeans: paymentMeans,
+		TaxTotal: TaxTotal{
+			TaxAmount: amount(invoice.Tax),
+			TaxSubtotals: []TaxSubtotal{{
+				TaxableAmount: amount(invoice.SubTotal),
+				TaxAmount:     amount(invoice.Tax),
+				TaxCategory:   category,
+			}},
+		},
+		LegalMonetaryTotal: monetaryTotal,
+		InvoiceLines:       lines,
+	}, nil
+}
+
+// taxCategory is zero rated for a 0% tax rate and standard rated otherwise.
+func taxCategory(rate decimal.Decimal) TaxCategory {
+	id := TaxCategoryStandard
+	if rate.IsZero() {
+		id = TaxCategoryZero
+	}
+
+	return TaxCategory{ID: id, Percent: rate.String(), TaxScheme: TaxScheme{ID: TaxSchemeVAT}}
+}
+
+func partyTaxScheme(taxID string) *PartyTaxScheme {
+	if taxID == "" {
+		return nil
+	}
+
+	return &PartyTaxScheme{CompanyID: taxID, TaxScheme: TaxScheme{ID: TaxSchemeVAT}}
+}
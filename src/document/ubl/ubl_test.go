@@ -0,0 +1,186 @@
+package ubl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var supplier = Supplier{
+	Name:             "PT Contoh Indonesia",
+	Address:          "Jl. Jend. Sudirman No. 1, Jakarta 10220",
+	CountryCode:      "ID",
+	Currency:         "IDR",
+	TaxID:            "01.234.567.8-901.000",
+	EndpointID:       "0123456789012",
+	EndpointScheme:   "0088",
+	PaymentAccountID: "1234567890",
+}
+
+func sampleInvoice() (entity.Invoices, entity.Customer, []*entity.Item) {
+	invoice := entity.Invoices{InvoicesData: entity.InvoicesData{
+		InvoiceID:  "INV/2024/03/00042",
+		IssueDate:  time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+		DueDate:    time.Date(2024, time.April, 6, 0, 0, 0, 0, time.UTC),
+		Subject:    "website redesign",
+		TotalItems: 2,
+		Status:     entity.InvoiceStatusPartiallyPaid,
+		SubTotal:   decimal.NewFromInt(11800000),
+		TaxRate:    decimal.NewFromInt(11),
+		Tax:        decimal.NewFromInt(1298000),
+		GrandTotal: decimal.NewFromInt(13098000),
+		AmountPaid: decimal.NewFromInt(5000000),
+	}}
+
+	customer := entity.Customer{CustomerData: entity.CustomerData{
+		CustomerID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Name:           "pt maju bersama",
+		Address:        "Jl. Asia Afrika No. 8, Bandung",
+		NPWP:           "098765432109000",
+		EndpointID:     "9876543210987",
+		EndpointScheme: "0088",
+	}}
+
+	items := []*entity.Item{
+		{ItemData: entity.ItemData{Name: "design", Quantity: decimal.NewFromInt(40), UnitPrice: decimal.NewFromInt(250000), Amount: decimal.NewFromInt(10000000)}},
+		{ItemData: entity.ItemData{Name: "hosting", Quantity: decimal.RequireFromString("1.5"), UnitPrice: decimal.NewFromInt(1200000), Amount: decimal.NewFromInt(1800000)}},
+	}
+
+	return invoice, customer, items
+}
+
+func TestUBL_Encode(t *testing.T) {
+	invoice, customer, items := sampleInvoice()
+
+	zeroRated := invoice
+	zeroRated.TaxRate = decimal.Decimal{}
+	zeroRated.Tax = decimal.Decimal{}
+	zeroRated.GrandTotal = zeroRated.SubTotal
+	zeroRated.AmountPaid = decimal.Decimal{}
+
+	foreignCustomer := customer
+	foreignCustomer.CountryCode = "SG"
+	foreignCustomer.NPWP = ""
+
+	bareSupplier := supplier
+	bareSupplier.TaxID = ""
+	bareSupplier.PaymentAccountID = ""
+
+	testCases := []struct {
+		name     string
+		supplier Supplier
+		invoice  entity.Invoices
+		customer entity.Customer
+		golden   string
+	}{
+		{name: "standard rated partially paid", supplier: supplier, invoice: invoice, customer: customer, golden: "invoice.xml"},
+		{name: "zero rated without payment account", supplier: bareSupplier, invoice: zeroRated, customer: foreignCustomer, golden: "invoice_zero_rated.xml"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewEncoder(testCase.supplier).Encode(&buf, testCase.invoice, testCase.customer, items)
+			assert.Equal(t, nil, err)
+
+			golden := filepath.Join("testdata", testCase.golden)
+			if *update {
+				os.WriteFile(golden, buf.Bytes(), 0o644)
+			}
+
+			expected, err := os.ReadFile(golden)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, string(expected), buf.String())
+		})
+	}
+}
+
+// TestUBL_Namespaces decodes the golden files with namespace resolution, so a prefix typo in a
+// struct tag shows up as an element outside the UBL namespaces.
+func TestUBL_Namespaces(t *testing.T) {
+	for _, golden := range []string{"invoice.xml", "invoice_zero_rated.xml"} {
+		t.Run(golden, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", golden))
+			assert.Equal(t, nil, err)
+
+			dec := xml.NewDecoder(bytes.NewReader(data))
+			var root string
+			for {
+				token, err := dec.Token()
+				if err == io.EOF {
+					break
+				}
+				assert.Equal(t, nil, err)
+
+				start, ok := token.(xml.StartElement)
+				if !ok {
+					continue
+				}
+				if root == "" {
+					root = start.Name.Space + " " + start.Name.Local
+				}
+
+				switch start.Name.Space {
+				case NamespaceInvoice, NamespaceCAC, NamespaceCBC:
+				default:
+					t.Errorf("element %s is outside the UBL namespaces", start.Name.Local)
+				}
+			}
+
+			assert.Equal(t, NamespaceInvoice+" Invoice", root)
+		})
+	}
+}
+
+func TestUBL_Build(t *testing.T) {
+	invoice, customer, items := sampleInvoice()
+
+	t.Run("err supplier endpoint", func(t *testing.T) {
+		s := supplier
+		s.EndpointID = ""
+
+		_, err := Build(s, invoice, customer, items)
+		assert.Equal(t, ErrSupplierEndpoint, err)
+	})
+
+	t.Run("err customer endpoint", func(t *testing.T) {
+		c := customer
+		c.EndpointID = ""
+
+		_, err := Build(supplier, invoice, c, items)
+		assert.Equal(t, ErrCustomerEndpoint, err)
+	})
+
+	t.Run("totals add up", func(t *testing.T) {
+		doc, err := Build(supplier, invoice, customer, items)
+		assert.Equal(t, nil, err)
+
+		// BR-CO-10, BR-CO-15 and BR-CO-16 of EN 16931
+		lines := decimal.Decimal{}
+		for _, line := range doc.InvoiceLines {
+			lines = lines.Add(decimal.RequireFromString(line.LineExtensionAmount.Value))
+		}
+		total := doc.LegalMonetaryTotal
+		assert.Equal(t, total.LineExtensionAmount.Value, lines.StringFixed(2))
+
+		exclusive := decimal.RequireFromString(total.TaxExclusiveAmount.Value)
+		tax := decimal.RequireFromString(doc.TaxTotal.TaxAmount.Value)
+		assert.Equal(t, total.TaxInclusiveAmount.Value, exclusive.Add(tax).StringFixed(2))
+
+		inclusive := decimal.RequireFromString(total.TaxInclusiveAmount.Value)
+		prepaid := decimal.RequireFromString(total.PrepaidAmount.Value)
+		assert.Equal(t, total.PayableAmount.Value, inclusive.Sub(prepaid).StringFixed(2))
+	})
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
+
+// CreditNoteSeries numbers credit notes, apart from the invoices they correct.
+const CreditNoteSeries = "CN"
+
+// CreditNote corrects an issued invoice. Its lines and totals are negative.
+type CreditNote struct {
+	ModelID
+	ModelLogTime
+	CreditNoteData
+}
+
+type CreditNoteData struct {
+	CreditNoteID string          `db:"credit_note_id"`
+	InvoiceID    string          `db:"invoice_id"`
+	IssueDate    time.Time       `db:"issue_date"`
+	Reason       string          `db:"reason"`
+	SubTotal     decimal.Decimal `db:"sub_total"`
+	Tax          decimal.Decimal `db:"tax"`
+	GrandTotal   decimal.Decimal `db:"grand_total"`
+}
+
+type CreditNoteItem struct {
+	ModelID
+	ModelLogTime
+	CreditNoteItemData
+}
+
+type CreditNoteItemData struct {
+	CreditNoteID string `db:"credit_note_id"`
+	// ItemID is the credited item of the original invoice
+	ItemID    uuid.UUID       `db:"item_id"`
+	Name      string          `db:"name"`
+	Type      string          `db:"type"`
+	Quantity  decimal.Decimal `db:"quantity"`
+	UnitPrice decimal.Decimal `db:"unit_price"`
+	Amount    decimal.Decimal `db:"amount"`
+}
@@ -12,4 +12,19 @@ type CustomerData struct {
 	CustomerID uuid.UUID `db:"customer_id"`
 	Name       string    `db:"name"`
 	Address    string    `db:"address"`
+	// NPWP is the tax id of the customer, digits only
+	NPWP string `db:"npwp"`
+
+	// electronic address of the customer on the PEPPOL network, EndpointScheme is its EAS code
+	EndpointID     string `db:"endpoint_id"`
+	EndpointScheme string `db:"endpoint_scheme"`
+	CountryCode    string `db:"country_code"`
+
+	// Email receives the payment reminders, DunningPaused stops them
+	Email         string `db:"email"`
+	DunningPaused bool   `db:"dunning_paused"`
+
+	// normalized copies of name and address, used to find an existing customer
+	NameNormalized    string `db:"name_normalized"`
+	AddressNormalized string `db:"address_normalized"`
 }
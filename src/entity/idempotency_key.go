@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// IdempotencyKey is the stored outcome of a request sent with an Idempotency-Key header.
+// StatusCode stays 0 until the first request with the key has been handled.
+type IdempotencyKey struct {
+	Key          string    `db:"key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   int       `db:"status_code"`
+	ContentType  string    `db:"content_type"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
@@ -3,9 +3,21 @@ package entity
 import (
 	"time"
 
+	"github.com/Risuii/invoice/src/decimal"
 	"github.com/google/uuid"
 )
 
+const (
+	InvoiceStatusDraft         = "Draft"
+	InvoiceStatusIssued        = "Issued"
+	InvoiceStatusPartiallyPaid = "PartiallyPaid"
+	InvoiceStatusPaid          = "Paid"
+	InvoiceStatusOverpaid      = "Overpaid"
+	InvoiceStatusOverdue       = "Overdue"
+	InvoiceStatusVoid          = "Void"
+	InvoiceStatusCredited      = "Credited"
+)
+
 type Invoices struct {
 	ModelID
 	ModelLogTime
@@ -13,15 +25,40 @@ type Invoices struct {
 }
 
 type InvoicesData struct {
-	InvoiceID    string    `db:"invoice_id"`
-	IssueDate    time.Time `db:"issue_date"`
-	Subject      string    `db:"subject"`
-	TotalItems   int       `db:"total_items"`
-	CustomerID   uuid.UUID `db:"customer_id"`
-	DueDate      time.Time `db:"due_date"`
-	Status       string    `db:"status"`
-	SubTotal     float64   `db:"sub_total"`
-	Tax          float64   `db:"tax"`
-	GrandTotal   float64   `db:"grand_total"`
-	CustomerName string    `db:"customer_name"`
+	InvoiceID    string          `db:"invoice_id"`
+	IssueDate    time.Time       `db:"issue_date"`
+	Subject      string          `db:"subject"`
+	TotalItems   int             `db:"total_items"`
+	CustomerID   uuid.UUID       `db:"customer_id"`
+	DueDate      time.Time       `db:"due_date"`
+	Status       string          `db:"status"`
+	SubTotal     decimal.Decimal `db:"sub_total"`
+	TaxRate      decimal.Decimal `db:"tax_rate"`
+	Tax          decimal.Decimal `db:"tax"`
+	GrandTotal   decimal.Decimal `db:"grand_total"`
+	AmountPaid   decimal.Decimal `db:"amount_paid"`
+	CustomerName string          `db:"customer_name"`
+
+	// AmountCredited is the total of the credit notes issued against the invoice, a positive amount
+	AmountCredited decimal.Decimal `db:"amount_credited"`
+
+	// TaxInvoiceNumber is the 13 digit serial of the e-Faktur tax invoice, empty until DJP assigns one
+	// to the issued invoice
+	TaxInvoiceNumber string `db:"tax_invoice_number"`
+
+	// Version is bumped on every update, an update of an older version is rejected
+	Version int64 `db:"version"`
+}
+
+type InvoiceStatusHistory struct {
+	ModelID
+	ModelLogTime
+	InvoiceStatusHistoryData
+}
+
+type InvoiceStatusHistoryData struct {
+	InvoiceID  string `db:"invoice_id"`
+	FromStatus string `db:"from_status"`
+	ToStatus   string `db:"to_status"`
+	Reason     string `db:"reason"`
 }
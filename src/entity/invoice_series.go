@@ -0,0 +1,16 @@
+package entity
+
+// DefaultInvoiceSeries numbers invoices created without an explicit series.
+const DefaultInvoiceSeries = "INV"
+
+type InvoiceSeries struct {
+	ModelID
+	ModelLogTime
+	InvoiceSeriesData
+}
+
+type InvoiceSeriesData struct {
+	Code        string `db:"code"`
+	Template    string `db:"template"`
+	ResetPeriod string `db:"reset_period"`
+}
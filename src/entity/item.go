@@ -1,6 +1,12 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
+
+// ItemTypeLateFee is the type of the items added by the overdue job for late fees
+const ItemTypeLateFee = "late_fee"
 
 type Item struct {
 	ModelID
@@ -9,11 +15,11 @@ type Item struct {
 }
 
 type ItemData struct {
-	InvoiceID string    `db:"invoice_id"`
-	ItemID    uuid.UUID `db:"item_id"`
-	Name      string    `db:"name"`
-	Type      string    `db:"type"`
-	Quantity  float64   `db:"quantity"`
-	UnitPrice float64   `db:"unit_price"`
-	Amount    float64   `db:"amount"`
+	InvoiceID string          `db:"invoice_id"`
+	ItemID    uuid.UUID       `db:"item_id"`
+	Name      string          `db:"name"`
+	Type      string          `db:"type"`
+	Quantity  decimal.Decimal `db:"quantity"`
+	UnitPrice decimal.Decimal `db:"unit_price"`
+	Amount    decimal.Decimal `db:"amount"`
 }
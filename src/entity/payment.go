@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
+
+type Payment struct {
+	ModelID
+	ModelLogTime
+	PaymentData
+}
+
+type PaymentData struct {
+	PaymentID   uuid.UUID       `db:"payment_id"`
+	InvoiceID   string          `db:"invoice_id"`
+	Amount      decimal.Decimal `db:"amount"`
+	PaymentDate time.Time       `db:"payment_date"`
+	Method      string          `db:"method"`
+	Reference   string          `db:"reference"`
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
+
+const (
+	RecurringRunPending = "pending"
+	RecurringRunCreated = "created"
+	RecurringRunFailed  = "failed"
+)
+
+// RecurringInvoice is a template the scheduler turns into a draft invoice every period.
+type RecurringInvoice struct {
+	ModelID
+	ModelLogTime
+	RecurringInvoiceData
+}
+
+type RecurringInvoiceData struct {
+	RecurringID uuid.UUID       `db:"recurring_id"`
+	CustomerID  uuid.UUID       `db:"customer_id"`
+	Subject     string          `db:"subject"`
+	TaxRate     decimal.Decimal `db:"tax_rate"`
+	Series      string          `db:"series"`
+	Interval    string          `db:"interval"`
+	Cron        string          `db:"cron"`
+	DueDays     int             `db:"due_days"`
+	StartDate   time.Time       `db:"start_date"`
+	EndDate     *time.Time      `db:"end_date"`
+
+	// NextRun is the period the scheduler invoices next, nil once the schedule has ended
+	NextRun   *time.Time `db:"next_run"`
+	LastRunAt *time.Time `db:"last_run_at"`
+}
+
+type RecurringInvoiceItem struct {
+	ModelID
+	ModelLogTime
+	RecurringInvoiceItemData
+}
+
+type RecurringInvoiceItemData struct {
+	RecurringID uuid.UUID       `db:"recurring_id"`
+	Name        string          `db:"name"`
+	Type        string          `db:"type"`
+	Quantity    decimal.Decimal `db:"quantity"`
+	UnitPrice   decimal.Decimal `db:"unit_price"`
+}
+
+// RecurringInvoiceRun records the invoice created for one period of a schedule.
+type RecurringInvoiceRun struct {
+	ModelID
+	ModelLogTime
+	RecurringInvoiceRunData
+}
+
+type RecurringInvoiceRunData struct {
+	RecurringID uuid.UUID `db:"recurring_id"`
+	RunAt       time.Time `db:"run_at"`
+	InvoiceID   string    `db:"invoice_id"`
+	Status      string    `db:"status"`
+	Error       string    `db:"error"`
+}
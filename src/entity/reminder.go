@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+const (
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	ReminderFailed  = "failed"
+)
+
+// InvoiceReminder records the reminder mailed for one dunning stage of an invoice.
+type InvoiceReminder struct {
+	ModelID
+	ModelLogTime
+	InvoiceReminderData
+}
+
+type InvoiceReminderData struct {
+	InvoiceID string `db:"invoice_id"`
+	// Stage is the number of days from the due date, negative before it
+	Stage  int        `db:"stage"`
+	Email  string     `db:"email"`
+	Status string     `db:"status"`
+	Error  string     `db:"error"`
+	SentAt *time.Time `db:"sent_at"`
+	// Attempts is how many times the reminder was sent, a failed one is sent again
+	Attempts int `db:"attempts"`
+}
+
+// DunningInvoice is an unpaid invoice with what a reminder needs of its customer.
+type DunningInvoice struct {
+	InvoicesData
+	CustomerEmail string `db:"customer_email"`
+	// LastStage is the latest stage a reminder was recorded for and is not sent again, nil when
+	// there is none
+	LastStage *int `db:"last_stage"`
+}
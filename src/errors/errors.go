@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"github.com/Risuii/invoice/src/decimal"
+
 	i18n_err "github.com/Risuii/frs-lib/i18n/errors"
 )
 
@@ -8,4 +10,60 @@ var (
 	ErrDuplicateInvoices  = i18n_err.NewI18nError("err_Invoices_duplicate")
 	ErrCustomerIdNotFound = i18n_err.NewI18nError("err_customer_id_not_found")
 	ErrInvoiceIdNotFound  = i18n_err.NewI18nError("err_invoice_id_not_found")
+
+	ErrInvoiceNotEditable             = i18n_err.NewI18nError("err_invoice_not_editable")
+	ErrInvoiceNotPayable              = i18n_err.NewI18nError("err_invoice_not_payable")
+	ErrInvoiceInvalidStatusTransition = i18n_err.NewI18nError("err_invoice_invalid_status_transition")
+	ErrInvoiceHasPayments             = i18n_err.NewI18nError("err_invoice_has_payments")
+	ErrInvoiceNotDeletable            = i18n_err.NewI18nError("err_invoice_not_deletable")
+
+	ErrInvoiceNotCreditable       = i18n_err.NewI18nError("err_invoice_not_creditable")
+	ErrInvoiceFullyCredited       = i18n_err.NewI18nError("err_invoice_fully_credited")
+	ErrInvoiceHasCreditNotes      = i18n_err.NewI18nError("err_invoice_has_credit_notes")
+	ErrCreditNoteQuantityExceeded = i18n_err.NewI18nError("err_credit_note_quantity_exceeded")
+
+	ErrInvoiceIfMatchRequired = i18n_err.NewI18nError("err_invoice_if_match_required")
+	ErrInvoiceVersionMismatch = i18n_err.NewI18nError("err_invoice_version_mismatch")
+
+	ErrInvoiceItemNotFound       = i18n_err.NewI18nError("err_invoice_item_not_found")
+	ErrInvoicePatchInvalid       = i18n_err.NewI18nError("err_invoice_patch_invalid")
+	ErrUnsupportedPatchMediaType = i18n_err.NewI18nError("err_unsupported_patch_media_type")
+	ErrInvoiceAmountOutOfRange   = i18n_err.NewI18nError("err_invoice_amount_out_of_range")
+
+	ErrInvoiceSeriesNotFound = i18n_err.NewI18nError("err_invoice_series_not_found")
+
+	ErrInvoiceBatchTooLarge = i18n_err.NewI18nError("err_invoice_batch_too_large")
+	ErrInvoiceBatchAborted  = i18n_err.NewI18nError("err_invoice_batch_aborted")
+
+	ErrDuplicateTaxInvoiceNumber     = i18n_err.NewI18nError("err_tax_invoice_number_duplicate")
+	ErrInvoiceTaxNumberNotAssignable = i18n_err.NewI18nError("err_invoice_tax_number_not_assignable")
+
+	ErrDocumentTemplateNotFound = i18n_err.NewI18nError("err_document_template_not_found")
+	ErrInvoiceNotExportable     = i18n_err.NewI18nError("err_invoice_not_exportable")
+	ErrCustomerEndpointMissing  = i18n_err.NewI18nError("err_customer_endpoint_missing")
+
+	ErrIdempotencyKeyReused     = i18n_err.NewI18nError("err_idempotency_key_reused")
+	ErrIdempotencyKeyInProgress = i18n_err.NewI18nError("err_idempotency_key_in_progress")
+
+	ErrDuplicateCustomer   = i18n_err.NewI18nError("err_customer_duplicate")
+	ErrCustomerHasInvoices = i18n_err.NewI18nError("err_customer_has_invoices")
+
+	ErrRecurringIdNotFound = i18n_err.NewI18nError("err_recurring_id_not_found")
 )
+
+// TotalMismatch describes a client supplied total that differs from the server side calculation.
+type TotalMismatch struct {
+	Field    string          `json:"field"`
+	Expected decimal.Decimal `json:"expected"`
+	Actual   decimal.Decimal `json:"actual"`
+}
+
+// TotalsMismatchError is returned when the invoice totals sent by the client do not add up.
+// It carries every mismatching field so the client can see what to fix.
+type TotalsMismatchError struct {
+	Mismatches []TotalMismatch
+}
+
+func (e *TotalsMismatchError) Error() string {
+	return "err_invoice_totals_mismatch"
+}
@@ -0,0 +1,142 @@
+// Package mailer sends plain text emails. SMTPMailer delivers them through an SMTP server, the
+// services only depend on Message so another transport can be plugged in.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Message is a plain text email to one recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// SMTPConfig is where and as who SMTPMailer sends. Username may be empty for a server without
+// authentication, such as a local relay.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPMailer struct {
+	cfg SMTPConfig
+	// now stamps the Date header
+	now func() time.Time
+}
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Send delivers msg in one SMTP session. STARTTLS is used whenever the server offers it, the
+// credentials are only sent over TLS or to localhost. ctx bounds the whole session.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp server: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		return fmt.Errorf("smtp greeting: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err = client.Mail(m.cfg.From); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+
+	if err = client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	data, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("smtp end of data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// build writes msg as a MIME message, the subject is Q encoded and the body quoted printable
+// so any UTF-8 text goes through.
+func (m *SMTPMailer) build(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := []struct{ key, value string }{
+		{"From", (&mail.Address{Address: m.cfg.From}).String()},
+		{"To", (&mail.Address{Address: msg.To}).String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", m.now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h.key, h.value)
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(msg.Body)); err != nil {
+		return nil, fmt.Errorf("encode body: %w", err)
+	}
+
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("encode body: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
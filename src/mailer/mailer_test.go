@@ -0,0 +1,186 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert"
+)
+
+// fakeSMTP is a local SMTP server accepting one message per session, enough to see what
+// SMTPMailer sends.
+type fakeSMTP struct {
+	listener net.Listener
+	// rejectRcpt is answered 550 to RCPT TO
+	rejectRcpt string
+
+	auth     chan string
+	from     chan string
+	rcpt     chan string
+	messages chan string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeSMTP{
+		listener: listener,
+		auth:     make(chan string, 1),
+		from:     make(chan string, 1),
+		rcpt:     make(chan string, 1),
+		messages: make(chan string, 1),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		io.WriteString(conn, line+"\r\n")
+	}
+
+	reply("220 localhost fake smtp")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch {
+		case verb == "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case verb == "AUTH":
+			credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, "AUTH PLAIN "))
+			s.auth <- string(credentials)
+			reply("235 authenticated")
+		case verb == "MAIL":
+			s.from <- line
+			reply("250 ok")
+		case verb == "RCPT":
+			if s.rejectRcpt != "" && strings.Contains(line, s.rejectRcpt) {
+				reply("550 no such user")
+				continue
+			}
+			s.rcpt <- line
+			reply("250 ok")
+		case verb == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.messages <- data.String()
+			reply("250 queued")
+		case verb == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestMailer_Send(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	m := NewSMTPMailer(SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "billing",
+		Password: "secret",
+		From:     "billing@example.com",
+	})
+	m.now = func() time.Time {
+		return time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := m.Send(ctx, Message{
+		To:      "finance@customer.co.id",
+		Subject: "Pengingat tagihan INV/2024/03/00042 jatuh tempo",
+		Body:    "Yth. pt maju bersama,\n\nTagihan sebesar Rp 8.098.000 jatuh tempo pada 14-03-2024.",
+	})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "\x00billing\x00secret", <-server.auth)
+	assert.Equal(t, "MAIL FROM:<billing@example.com>", <-server.from)
+	assert.Equal(t, "RCPT TO:<finance@customer.co.id>", <-server.rcpt)
+
+	msg, err := mail.ReadMessage(strings.NewReader(<-server.messages))
+	assert.Equal(t, nil, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Pengingat tagihan INV/2024/03/00042 jatuh tempo", subject)
+	assert.Equal(t, "<billing@example.com>", msg.Header.Get("From"))
+	assert.Equal(t, "<finance@customer.co.id>", msg.Header.Get("To"))
+	assert.Equal(t, "Mon, 11 Mar 2024 09:00:00 +0000", msg.Header.Get("Date"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Yth. pt maju bersama,\r\n\r\nTagihan sebesar Rp 8.098.000 jatuh tempo pada 14-03-2024.\r\n", string(body))
+}
+
+func TestMailer_SendErr(t *testing.T) {
+	t.Run("err rejected recipient", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		server.rejectRcpt = "unknown@customer.co.id"
+
+		m := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "billing@example.com"})
+
+		err := m.Send(context.Background(), Message{To: "unknown@customer.co.id", Subject: "reminder", Body: "reminder"})
+		assert.NotEqual(t, nil, err)
+		assert.Equal(t, true, strings.Contains(err.Error(), "550"))
+	})
+
+	t.Run("err server down", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		port := server.port()
+		server.listener.Close()
+
+		m := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "billing@example.com"})
+
+		err := m.Send(context.Background(), Message{To: "finance@customer.co.id", Subject: "reminder", Body: "reminder"})
+		assert.NotEqual(t, nil, err)
+		assert.Equal(t, true, strings.HasPrefix(err.Error(), "dial smtp server: "))
+	})
+}
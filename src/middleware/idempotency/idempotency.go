@@ -0,0 +1,196 @@
+// Package idempotency makes retried requests safe. A request sent with an Idempotency-Key header
+// is handled once, later requests with the same key get the stored response back.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/middleware/response"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+const (
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on a response that was stored by an earlier request.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	// MaxKeyLength is the size of the key column.
+	MaxKeyLength = 255
+)
+
+type Store interface {
+	// Reserve returns the time of the reservation, the lease Complete and Release need.
+	Reserve(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (time.Time, bool, error)
+	Get(ctx context.Context, key string) (entity.IdempotencyKey, error)
+	// Complete returns sql.ErrNoRows when the lease of data.CreatedAt was lost.
+	Complete(ctx context.Context, data *entity.IdempotencyKey) error
+	Release(ctx context.Context, key string, reservedAt time.Time) error
+}
+
+// Middleware handles a request with an Idempotency-Key once and keeps its response for ttl.
+// A retry with the same method, URL and body replays that response, a key reused for another
+// request is rejected with 422 and a retry while the first request is still running with 409.
+// A first request running for longer than lease is taken to have died with its process and
+// the retry handles it again, lease has to be longer than any request may take. Server errors
+// are not kept, the client may retry them. Requests without the header pass through.
+func Middleware(store Store, ttl, lease time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > MaxKeyLength {
+				response.JSONBadRequestResponse(r.Context(), w)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Println("read request body err: ", err)
+				response.JSONBadRequestResponse(r.Context(), w)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			requestHash := hashRequest(r, body)
+
+			now := time.Now()
+			reservedAt, reserved, err := store.Reserve(r.Context(), key, requestHash, now.Add(ttl), now.Add(-lease))
+			if err != nil {
+				log.Println("reserve idempotency key err: ", err)
+				response.JSONInternalErrorResponse(r.Context(), w)
+				return
+			}
+
+			if !reserved {
+				replay(w, r, store, key, requestHash)
+				return
+			}
+
+			// the key is settled even when the request was canceled or timed out
+			ctx := context.WithoutCancel(r.Context())
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			defer func() {
+				if v := recover(); v != nil {
+					release(ctx, store, key, reservedAt)
+					panic(v)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode() >= http.StatusInternalServerError {
+				release(ctx, store, key, reservedAt)
+				return
+			}
+
+			err = store.Complete(ctx, &entity.IdempotencyKey{
+				Key:          key,
+				StatusCode:   recorder.statusCode(),
+				ContentType:  recorder.Header().Get("Content-Type"),
+				ResponseBody: recorder.body.Bytes(),
+				CreatedAt:    reservedAt,
+			})
+			if err == sql.ErrNoRows {
+				// the request ran past its lease and a retry took the key over, that one keeps its response
+				log.Printf("idempotency key %s lease lost, response not kept", key)
+				return
+			}
+			if err != nil {
+				// retries get 409 until the lease runs out and then handle the request again
+				log.Printf("complete idempotency key %s err: %v", key, err)
+			}
+		})
+	}
+}
+
+// release gives the key up so the client can retry, a key that could not be released is taken
+// over once its lease runs out.
+func release(ctx context.Context, store Store, key string, reservedAt time.Time) {
+	if err := store.Release(ctx, key, reservedAt); err != nil {
+		log.Printf("release idempotency key %s err: %v", key, err)
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, store Store, key, requestHash string) {
+	data, err := store.Get(r.Context(), key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// expired between the reservation and now, as good as still running
+			response.JSONError(r.Context(), w, http.StatusConflict, errorss.ErrIdempotencyKeyInProgress)
+			return
+		}
+		log.Println("get idempotency key err: ", err)
+		response.JSONInternalErrorResponse(r.Context(), w)
+		return
+	}
+
+	if data.RequestHash != requestHash {
+		response.JSONUnprocessableEntity(r.Context(), w, errorss.ErrIdempotencyKeyReused)
+		return
+	}
+
+	if data.StatusCode == 0 {
+		response.JSONError(r.Context(), w, http.StatusConflict, errorss.ErrIdempotencyKeyInProgress)
+		return
+	}
+
+	if data.ContentType != "" {
+		w.Header().Set("Content-Type", data.ContentType)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(data.StatusCode)
+	w.Write(data.ResponseBody)
+}
+
+// hashRequest identifies a request by its method, URL and body.
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes the response through and keeps a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.status == 0 {
+		rr.status = statusCode
+	}
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) statusCode() int {
+	if rr.status == 0 {
+		return http.StatusOK
+	}
+	return rr.status
+}
@@ -0,0 +1,356 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/go-playground/assert"
+)
+
+type memoryStore struct {
+	keys       map[string]entity.IdempotencyKey
+	reserveErr error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: map[string]entity.IdempotencyKey{}}
+}
+
+func (s *memoryStore) Reserve(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (time.Time, bool, error) {
+	if s.reserveErr != nil {
+		return time.Time{}, false, s.reserveErr
+	}
+
+	if data, ok := s.keys[key]; ok {
+		stale := data.StatusCode == 0 && data.RequestHash == requestHash && data.CreatedAt.Before(staleBefore)
+		if !stale {
+			return time.Time{}, false, nil
+		}
+	}
+
+	reservedAt := time.Now()
+	s.keys[key] = entity.IdempotencyKey{Key: key, RequestHash: requestHash, CreatedAt: reservedAt, ExpiresAt: expiresAt}
+	return reservedAt, true, nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (entity.IdempotencyKey, error) {
+	data, ok := s.keys[key]
+	if !ok {
+		return data, sql.ErrNoRows
+	}
+
+	return data, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, data *entity.IdempotencyKey) error {
+	stored, ok := s.keys[data.Key]
+	if !ok || stored.StatusCode != 0 || !stored.CreatedAt.Equal(data.CreatedAt) {
+		return sql.ErrNoRows
+	}
+
+	stored.StatusCode = data.StatusCode
+	stored.ContentType = data.ContentType
+	stored.ResponseBody = data.ResponseBody
+	s.keys[data.Key] = stored
+
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, key string, reservedAt time.Time) error {
+	if data := s.keys[key]; data.StatusCode == 0 && data.CreatedAt.Equal(reservedAt) {
+		delete(s.keys, key)
+	}
+
+	return nil
+}
+
+func TestIdempotency_Middleware(t *testing.T) {
+	type request struct {
+		key  string
+		body string
+	}
+
+	type expected struct {
+		statusCode int
+		body       string
+		replayed   bool
+	}
+
+	testCases := []struct {
+		name        string
+		reserveErr  error
+		statusCodes []int
+		requests    []request
+		expected    []expected
+		calls       int
+	}{
+		{
+			name:        "without key",
+			statusCodes: []int{http.StatusOK, http.StatusOK},
+			requests:    []request{{body: `{"a":1}`}, {body: `{"a":1}`}},
+			expected: []expected{
+				{statusCode: http.StatusOK, body: "call 1"},
+				{statusCode: http.StatusOK, body: "call 2"},
+			},
+			calls: 2,
+		},
+		{
+			name:     "key too long",
+			requests: []request{{key: strings.Repeat("k", MaxKeyLength+1), body: `{"a":1}`}},
+			expected: []expected{{statusCode: http.StatusBadRequest}},
+		},
+		{
+			name:       "err reserve",
+			reserveErr: errors.New("error internal server"),
+			requests:   []request{{key: "key-1", body: `{"a":1}`}},
+			expected:   []expected{{statusCode: http.StatusInternalServerError}},
+		},
+		{
+			name:        "replays the response",
+			statusCodes: []int{http.StatusCreated},
+			requests:    []request{{key: "key-1", body: `{"a":1}`}, {key: "key-1", body: `{"a":1}`}},
+			expected: []expected{
+				{statusCode: http.StatusCreated, body: "call 1"},
+				{statusCode: http.StatusCreated, body: "call 1", replayed: true},
+			},
+			calls: 1,
+		},
+		{
+			name:        "keeps client errors",
+			statusCodes: []int{http.StatusUnprocessableEntity},
+			requests:    []request{{key: "key-1", body: `{"a":1}`}, {key: "key-1", body: `{"a":1}`}},
+			expected: []expected{
+				{statusCode: http.StatusUnprocessableEntity, body: "call 1"},
+				{statusCode: http.StatusUnprocessableEntity, body: "call 1", replayed: true},
+			},
+			calls: 1,
+		},
+		{
+			name:        "rejects a reused key",
+			statusCodes: []int{http.StatusCreated},
+			requests:    []request{{key: "key-1", body: `{"a":1}`}, {key: "key-1", body: `{"a":2}`}},
+			expected: []expected{
+				{statusCode: http.StatusCreated, body: "call 1"},
+				{statusCode: http.StatusUnprocessableEntity},
+			},
+			calls: 1,
+		},
+		{
+			name:        "retries server errors",
+			statusCodes: []int{http.StatusInternalServerError, http.StatusCreated},
+			requests:    []request{{key: "key-1", body: `{"a":1}`}, {key: "key-1", body: `{"a":1}`}},
+			expected: []expected{
+				{statusCode: http.StatusInternalServerError, body: "call 1"},
+				{statusCode: http.StatusCreated, body: "call 2"},
+			},
+			calls: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := newMemoryStore()
+			store.reserveErr = testCase.reserveErr
+
+			calls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// the handler still reads the whole body
+				io.ReadAll(r.Body)
+
+				calls++
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(testCase.statusCodes[calls-1])
+				w.Write([]byte("call " + strconv.Itoa(calls)))
+			})
+
+			hf := Middleware(store, time.Hour, time.Minute)(next)
+
+			for i, req := range testCase.requests {
+				r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(req.body))
+				if req.key != "" {
+					r.Header.Set(HeaderKey, req.key)
+				}
+				w := httptest.NewRecorder()
+
+				hf.ServeHTTP(w, r)
+
+				res := w.Result()
+				data, _ := io.ReadAll(res.Body)
+
+				assert.Equal(t, testCase.expected[i].statusCode, res.StatusCode)
+				if testCase.expected[i].body != "" {
+					assert.Equal(t, testCase.expected[i].body, string(data))
+					assert.Equal(t, "text/plain", res.Header.Get("Content-Type"))
+				}
+				assert.Equal(t, testCase.expected[i].replayed, res.Header.Get(HeaderReplayed) == "true")
+			}
+
+			assert.Equal(t, testCase.calls, calls)
+		})
+	}
+}
+
+func TestIdempotency_MiddlewareInProgress(t *testing.T) {
+	store := newMemoryStore()
+
+	var inner *httptest.ResponseRecorder
+	var hf http.Handler
+	hf = Middleware(store, time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a retry arrives while the first request is still being handled
+		retry := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(`{"a":1}`))
+		retry.Header.Set(HeaderKey, "key-1")
+		inner = httptest.NewRecorder()
+		hf.ServeHTTP(inner, retry)
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(`{"a":1}`))
+	r.Header.Set(HeaderKey, "key-1")
+	w := httptest.NewRecorder()
+	hf.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	assert.Equal(t, http.StatusConflict, inner.Result().StatusCode)
+}
+
+func TestIdempotency_MiddlewarePanic(t *testing.T) {
+	store := newMemoryStore()
+
+	hf := Middleware(store, time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(`{"a":1}`))
+	r.Header.Set(HeaderKey, "key-1")
+
+	func() {
+		defer func() {
+			assert.Equal(t, "boom", recover())
+		}()
+		hf.ServeHTTP(httptest.NewRecorder(), r)
+	}()
+
+	_, ok := store.keys["key-1"]
+	assert.Equal(t, false, ok)
+}
+
+func TestIdempotency_MiddlewareStaleLease(t *testing.T) {
+	body := `{"a":1}`
+	newRequest := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(body))
+		r.Header.Set(HeaderKey, "key-1")
+		return r
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		startedAt  time.Time
+		statusCode int
+		calls      int
+	}{
+		{
+			name:       "still running",
+			body:       body,
+			startedAt:  time.Now(),
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "takes over a dead request",
+			body:       body,
+			startedAt:  time.Now().Add(-time.Hour),
+			statusCode: http.StatusCreated,
+			calls:      1,
+		},
+		{
+			name:       "rejects a reused key of a dead request",
+			body:       `{"a":2}`,
+			startedAt:  time.Now().Add(-time.Hour),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := newMemoryStore()
+
+			// the first request reserved the key and its process died before completing it
+			store.keys["key-1"] = entity.IdempotencyKey{
+				Key:         "key-1",
+				RequestHash: hashRequest(newRequest(body), []byte(body)),
+				CreatedAt:   testCase.startedAt,
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}
+
+			calls := 0
+			hf := Middleware(store, time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			w := httptest.NewRecorder()
+			hf.ServeHTTP(w, newRequest(testCase.body))
+
+			assert.Equal(t, testCase.statusCode, w.Result().StatusCode)
+			assert.Equal(t, testCase.calls, calls)
+		})
+	}
+}
+
+func TestIdempotency_MiddlewareLostLease(t *testing.T) {
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(`{"a":1}`))
+		r.Header.Set(HeaderKey, "key-1")
+		return r
+	}
+
+	testCases := []struct {
+		name       string
+		statusCode int
+	}{
+		{
+			name:       "does not complete a key taken over",
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "does not release a key taken over",
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := newMemoryStore()
+			takenOverAt := time.Now().Add(time.Minute)
+
+			hf := Middleware(store, time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// the request ran past its lease and a retry reserved the key again
+				data := store.keys["key-1"]
+				data.CreatedAt = takenOverAt
+				store.keys["key-1"] = data
+
+				w.WriteHeader(testCase.statusCode)
+			}))
+
+			w := httptest.NewRecorder()
+			hf.ServeHTTP(w, newRequest())
+
+			assert.Equal(t, testCase.statusCode, w.Result().StatusCode)
+
+			data, ok := store.keys["key-1"]
+			assert.Equal(t, true, ok)
+			assert.Equal(t, 0, data.StatusCode)
+			assert.Equal(t, true, data.CreatedAt.Equal(takenOverAt))
+		})
+	}
+}
@@ -31,3 +31,19 @@ func JSONUnprocessableEntity(ctx context.Context, w http.ResponseWriter, err i18
 	JSONResponse(ctx, w, createErrorResponse(err, request.GetRequestID(ctx), request.GetLanguage(ctx)),
 		http.StatusUnprocessableEntity)
 }
+
+func JSONUnprocessableEntityWithDetails(ctx context.Context, w http.ResponseWriter, err i18n_err.I18nError, details interface{}) {
+	resp := createErrorResponse(err, request.GetRequestID(ctx), request.GetLanguage(ctx))
+	resp.Error.Details = details
+
+	JSONResponse(ctx, w, resp, http.StatusUnprocessableEntity)
+}
+
+// NewError describes err in the language of the request, for responses that carry errors next
+// to data, such as the results of a batch.
+func NewError(ctx context.Context, err i18n_err.I18nError, details interface{}) *Error {
+	resp := createErrorResponse(err, request.GetRequestID(ctx), request.GetLanguage(ctx))
+	resp.Error.Details = details
+
+	return resp.Error
+}
@@ -22,10 +22,11 @@ type Meta struct {
 }
 
 type Error struct {
-	Code     string `json:"code"`
-	Title    string `json:"message_title"`
-	Message  string `json:"message"`
-	Severity string `json:"message_severity"`
+	Code     string      `json:"code"`
+	Title    string      `json:"message_title"`
+	Message  string      `json:"message"`
+	Severity string      `json:"message_severity"`
+	Details  interface{} `json:"details,omitempty"`
 }
 
 func JSONResponse(ctx context.Context, w http.ResponseWriter, data Response, statusCode int) {
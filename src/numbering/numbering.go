@@ -0,0 +1,108 @@
+// Package numbering formats document numbers from series templates such as
+// "INV/{YYYY}/{MM}/{seq:05}". Allocating the sequence value itself is the job of the
+// series repository, which does it inside the transaction creating the document.
+package numbering
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ResetNever   = "never"
+	ResetYearly  = "yearly"
+	ResetMonthly = "monthly"
+
+	// MaxLength is the size of the invoice_id column.
+	MaxLength = 50
+)
+
+var (
+	ErrInvalidTemplate = errors.New("numbering: invalid template")
+	ErrTooLong         = errors.New("numbering: number longer than the invoice id column")
+)
+
+// PeriodKey returns the counter bucket for t. Counters with the same key share one sequence,
+// so a yearly series starts again at 1 when the key changes.
+func PeriodKey(reset string, t time.Time) string {
+	switch reset {
+	case ResetYearly:
+		return t.Format("2006")
+	case ResetMonthly:
+		return t.Format("2006-01")
+	default:
+		return ""
+	}
+}
+
+// Format expands template for the document dated t with sequence value seq.
+// Supported placeholders are {YYYY}, {YY}, {MM}, {DD}, {seq} and {seq:0N} for a zero padded
+// sequence of N digits. Everything else is copied as is.
+func Format(template string, t time.Time, seq int64) (string, error) {
+	var b strings.Builder
+	hasSeq := false
+
+	for rest := template; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unclosed placeholder in %q", ErrInvalidTemplate, template)
+		}
+		end += start
+
+		b.WriteString(rest[:start])
+
+		value, isSeq, err := expand(rest[start+1:end], t, seq)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v in %q", ErrInvalidTemplate, err, template)
+		}
+
+		hasSeq = hasSeq || isSeq
+		b.WriteString(value)
+		rest = rest[end+1:]
+	}
+
+	if !hasSeq {
+		return "", fmt.Errorf("%w: %q has no {seq} placeholder", ErrInvalidTemplate, template)
+	}
+
+	if b.Len() > MaxLength {
+		return "", ErrTooLong
+	}
+
+	return b.String(), nil
+}
+
+func expand(placeholder string, t time.Time, seq int64) (string, bool, error) {
+	switch placeholder {
+	case "YYYY":
+		return t.Format("2006"), false, nil
+	case "YY":
+		return t.Format("06"), false, nil
+	case "MM":
+		return t.Format("01"), false, nil
+	case "DD":
+		return t.Format("02"), false, nil
+	case "seq":
+		return strconv.FormatInt(seq, 10), true, nil
+	}
+
+	if width, ok := strings.CutPrefix(placeholder, "seq:"); ok {
+		n, err := strconv.Atoi(width)
+		if err != nil || n < 1 || n > 20 {
+			return "", false, fmt.Errorf("bad sequence width %q", width)
+		}
+
+		return fmt.Sprintf("%0*d", n, seq), true, nil
+	}
+
+	return "", false, fmt.Errorf("unknown placeholder {%s}", placeholder)
+}
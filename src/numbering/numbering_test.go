@@ -0,0 +1,49 @@
+package numbering
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert"
+)
+
+func TestNumbering_Format(t *testing.T) {
+	date := time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		template string
+		seq      int64
+		expected string
+		err      error
+	}{
+		{name: "legacy four digits", template: "{seq:04}", seq: 12, expected: "0012"},
+		{name: "yearly and monthly", template: "INV/{YYYY}/{MM}/{seq:05}", seq: 42, expected: "INV/2024/03/00042"},
+		{name: "short year and day", template: "{YY}{MM}{DD}-{seq}", seq: 7, expected: "240307-7"},
+		{name: "sequence wider than padding", template: "{seq:02}", seq: 1234, expected: "1234"},
+		{name: "missing sequence", template: "INV/{YYYY}", err: ErrInvalidTemplate},
+		{name: "unknown placeholder", template: "{QQ}-{seq}", err: ErrInvalidTemplate},
+		{name: "bad width", template: "{seq:x}", err: ErrInvalidTemplate},
+		{name: "unclosed placeholder", template: "INV-{seq", err: ErrInvalidTemplate},
+		{name: "too long", template: strings.Repeat("X", MaxLength) + "{seq}", seq: 1, err: ErrTooLong},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Format(testCase.template, date, testCase.seq)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, true, errors.Is(err, testCase.err))
+		})
+	}
+}
+
+func TestNumbering_PeriodKey(t *testing.T) {
+	date := time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "", PeriodKey(ResetNever, date))
+	assert.Equal(t, "2024", PeriodKey(ResetYearly, date))
+	assert.Equal(t, "2024-03", PeriodKey(ResetMonthly, date))
+}
@@ -0,0 +1,84 @@
+package creditnotes
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Risuii/invoice/src/entity"
+)
+
+func (c *CreditNotesRepository) Create(ctx context.Context, data *entity.CreditNote) error {
+	namedStmt, err := c.getNamedStatement(ctx, InsertCreditNote)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	_, err = namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("create credit note err: ", err)
+		return err
+	}
+
+	redisErr := c.redis.DelWithPattern(ctx, DeleteCreditNoteRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+func (c *CreditNotesRepository) CreateItems(ctx context.Context, data []*entity.CreditNoteItem) error {
+	namedStmt, err := c.getNamedStatement(ctx, InsertCreditNoteItems)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	for _, v := range data {
+		_, err = namedStmt.ExecContext(ctx, v)
+		if err != nil {
+			log.Println("create credit note items err: ", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *CreditNotesRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*entity.CreditNote, error) {
+	var CreditNote []*entity.CreditNote
+	err := c.redis.WithCache(ctx, fmt.Sprintf(GetCreditNotesByInvoiceIDRedisKey, invID), &CreditNote, func() (interface{}, error) {
+		var creditNoteData []*entity.CreditNote
+		err := c.masterStmts[GetByInvoiceID].SelectContext(ctx, &creditNoteData, invID)
+		return creditNoteData, err
+	})
+
+	if err != nil {
+		log.Println(err)
+		return CreditNote, err
+	}
+
+	return CreditNote, nil
+}
+
+// GetItemsByInvoiceID returns the lines of every credit note of the invoice. Inside an atomic
+// session it sees the lines inserted by the same transaction.
+func (c *CreditNotesRepository) GetItemsByInvoiceID(ctx context.Context, invID string) ([]*entity.CreditNoteItem, error) {
+	var items []*entity.CreditNoteItem
+
+	stmt, err := c.getStatement(ctx, GetItemsByInvoiceID)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return items, err
+	}
+
+	err = stmt.SelectContext(ctx, &items, invID)
+	if err != nil {
+		log.Println("get credit note items err: ", err)
+		return items, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,100 @@
+package creditnotes
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	atomicSqlx "github.com/Risuii/frs-lib/atomic/sqlx"
+	frsRedis "github.com/Risuii/frs-lib/redis"
+	sqlxUtils "github.com/Risuii/frs-lib/sqlx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	AllFields     = `id, credit_note_id, invoice_id, issue_date, reason, sub_total, tax, grand_total, created_at, updated_at`
+	AllItemFields = `i.id, i.credit_note_id, i.item_id, i.name, i.type, i.quantity, i.unit_price, i.amount`
+
+	GetByInvoiceID = iota + 100
+	GetItemsByInvoiceID
+
+	InsertCreditNote = iota + 200
+	InsertCreditNoteItems
+
+	// Redis Key
+
+	GetCreditNotesByInvoiceIDRedisKey = "invoice:creditnotes:invoiceid:%s"
+	DeleteCreditNoteRedisKey          = "invoice:creditnotes:*"
+)
+
+var (
+	masterQueries = []string{
+		GetByInvoiceID: fmt.Sprintf("SELECT %s FROM credit_notes WHERE invoice_id = $1 AND deleted_at IS NULL ORDER BY issue_date, id", AllFields),
+		GetItemsByInvoiceID: fmt.Sprintf(`SELECT %s FROM credit_note_items AS i INNER JOIN credit_notes AS c ON c.credit_note_id = i.credit_note_id
+			WHERE c.invoice_id = $1 AND c.deleted_at IS NULL AND i.deleted_at IS NULL ORDER BY i.id`, AllItemFields),
+	}
+
+	masterNamedQueries = []string{
+		InsertCreditNote:      `INSERT INTO credit_notes (credit_note_id, invoice_id, issue_date, reason, sub_total, tax, grand_total) VALUES (:credit_note_id, :invoice_id, :issue_date, :reason, :sub_total, :tax, :grand_total)`,
+		InsertCreditNoteItems: `INSERT INTO credit_note_items (credit_note_id, item_id, name, type, quantity, unit_price, amount) VALUES (:credit_note_id, :item_id, :name, :type, :quantity, :unit_price, :amount)`,
+	}
+)
+
+type CreditNotesRepository struct {
+	db                *sqlx.DB
+	masterStmts       []*sqlx.Stmt
+	masterNamedStmpts []*sqlx.NamedStmt
+	redis             frsRedis.Redis
+}
+
+func InitCreditNotesRepository(ctx context.Context, db *sqlx.DB, redis frsRedis.Redis) (*CreditNotesRepository, error) {
+	stmpts, err := sqlxUtils.PrepareQueries(db, masterQueries)
+	if err != nil {
+		log.Println("PrepareQueries err:", err)
+		return nil, err
+	}
+
+	namedStmpts, err := sqlxUtils.PrepareNamedQueries(db, masterNamedQueries)
+	if err != nil {
+		log.Println("PrepareNamedQueries err:", err)
+		return nil, err
+	}
+
+	return &CreditNotesRepository{
+		db:                db,
+		masterStmts:       stmpts,
+		masterNamedStmpts: namedStmpts,
+		redis:             redis,
+	}, nil
+}
+
+func (r *CreditNotesRepository) getStatement(ctx context.Context, queryId int) (*sqlx.Stmt, error) {
+	var err error
+	var statement *sqlx.Stmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			statement, err = atomicSession.Tx().PreparexContext(ctx, masterQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		statement = r.masterStmts[queryId]
+	}
+	return statement, err
+}
+
+func (r *CreditNotesRepository) getNamedStatement(ctx context.Context, queryId int) (*sqlx.NamedStmt, error) {
+	var err error
+	var namedStmt *sqlx.NamedStmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			namedStmt, err = atomicSession.Tx().PrepareNamedContext(ctx, masterNamedQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		namedStmt = r.masterNamedStmpts[queryId]
+	}
+	return namedStmt, err
+}
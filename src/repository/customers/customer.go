@@ -3,13 +3,49 @@ package customers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/lib/pq"
+
+	errorss "github.com/Risuii/invoice/src/errors"
 )
 
+// NormalizeKey lower cases s and collapses its whitespace, the same way the migration filled
+// the normalized columns.
+func NormalizeKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func normalize(data *entity.Customer) {
+	data.NameNormalized = NormalizeKey(data.Name)
+	data.AddressNormalized = NormalizeKey(data.Address)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+func BuildFilter(query string, params contract.GetListCustomerParam) (string, contract.GetListCustomerParam) {
+
+	if params.Keyword != "" {
+		params.Keyword = "%" + likeEscaper.Replace(NormalizeKey(params.Keyword)) + "%"
+		query += ` AND (name_normalized LIKE :keyword OR address_normalized LIKE :keyword)`
+	}
+
+	return query, params
+}
+
 func (c *CustomersRepository) Create(ctx context.Context, data *entity.Customer) error {
+	normalize(data)
 
 	namedStmt, err := c.getNamedStatement(ctx, InsertCustomer)
 	if err != nil {
@@ -20,6 +56,9 @@ func (c *CustomersRepository) Create(ctx context.Context, data *entity.Customer)
 	_, err = namedStmt.ExecContext(ctx, data)
 	if err != nil {
 		log.Println("create customer err: ", err)
+		if isUniqueViolation(err) {
+			return errorss.ErrDuplicateCustomer
+		}
 		return err
 	}
 
@@ -51,6 +90,8 @@ func (c *CustomersRepository) Get(ctx context.Context, id string) (entity.Custom
 func (c *CustomersRepository) Update(ctx context.Context, data *entity.Customer) error {
 	var rowsAffected int64
 
+	normalize(data)
+
 	namedStmt, err := c.getNamedStatement(ctx, UpdateCustomer)
 	if err != nil {
 		log.Println("get named statement err: ", err)
@@ -60,6 +101,9 @@ func (c *CustomersRepository) Update(ctx context.Context, data *entity.Customer)
 	res, err := namedStmt.ExecContext(ctx, data)
 	if err != nil {
 		log.Println("exec err: ", err)
+		if isUniqueViolation(err) {
+			return errorss.ErrDuplicateCustomer
+		}
 		return err
 	}
 
@@ -79,5 +123,192 @@ func (c *CustomersRepository) Update(ctx context.Context, data *entity.Customer)
 		log.Println(redisErr)
 	}
 
+	redisErr = c.redis.DelWithPattern(ctx, DeleteInvoiceListRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// UpdateDunningPaused stops or resumes the payment reminders of a customer.
+func (c *CustomersRepository) UpdateDunningPaused(ctx context.Context, id string, paused bool) error {
+	stmt, err := c.getStatement(ctx, UpdateDunningPaused)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, id, paused)
+	if err != nil {
+		log.Println("exec err: ", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := c.redis.DelWithPattern(ctx, DeleteCustomerRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// FindOrCreate returns the customer with the same normalized name and address as data,
+// inserting data when there is none yet.
+func (c *CustomersRepository) FindOrCreate(ctx context.Context, data *entity.Customer) (entity.Customer, error) {
+	var customer entity.Customer
+
+	normalize(data)
+
+	namedStmt, err := c.getNamedStatement(ctx, FindOrCreateCustomer)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return customer, err
+	}
+
+	if err = namedStmt.GetContext(ctx, &customer, data); err != nil {
+		log.Println("find or create customer err: ", err)
+		return customer, err
+	}
+
+	redisErr := c.redis.DelWithPattern(ctx, DeleteCustomerRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return customer, nil
+}
+
+func (c *CustomersRepository) GetList(ctx context.Context, params contract.GetListCustomerParam) ([]*entity.Customer, error) {
+	var customers []*entity.Customer
+
+	query, params := BuildFilter(masterQueries[GetList], params)
+	query += " ORDER BY name_normalized, id LIMIT :limit OFFSET :offset"
+
+	param, err := json.Marshal(params)
+	if err != nil {
+		log.Println("marshal err: ", err)
+		return nil, err
+	}
+
+	err = c.redis.WithCache(ctx, fmt.Sprintf(GetListCustomersRedisKey, param), &customers, func() (interface{}, error) {
+		rows, err := c.db.NamedQueryContext(ctx, query, params)
+		if err != nil {
+			log.Println("named query err: ", err)
+			return nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var customerData entity.Customer
+			err = rows.StructScan(&customerData)
+			if err != nil {
+				return nil, err
+			}
+
+			customers = append(customers, &customerData)
+		}
+
+		return customers, nil
+	})
+
+	if err != nil {
+		log.Println("GetCustomersList err: ", err)
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (c *CustomersRepository) GetCustomersCount(ctx context.Context, params contract.GetListCustomerParam) (int64, error) {
+	var count int64
+
+	query, params := BuildFilter(masterQueries[GetCountList], params)
+
+	param, err := json.Marshal(params)
+	if err != nil {
+		log.Println("marshal err: ", err)
+		return 0, err
+	}
+
+	err = c.redis.WithCache(ctx, fmt.Sprintf(GetCustomersCountRedisKey, param), &count, func() (interface{}, error) {
+		var countData int64
+
+		namedStmt, err := c.db.PrepareNamedContext(ctx, query)
+		if err != nil {
+			return countData, err
+		}
+		defer namedStmt.Close()
+
+		err = namedStmt.GetContext(ctx, &countData, params)
+		return countData, err
+	})
+
+	if err != nil {
+		log.Println("GetCustomersCount err: ", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountInvoices returns how many invoices, trashed ones included, and recurring invoices are billed to the customer.
+func (c *CustomersRepository) CountInvoices(ctx context.Context, id string) (int64, error) {
+	var count int64
+
+	stmt, err := c.getStatement(ctx, GetCountInvoices)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return count, err
+	}
+
+	if err = stmt.GetContext(ctx, &count, id); err != nil {
+		log.Println("count invoices err: ", err)
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (c *CustomersRepository) Delete(ctx context.Context, data *entity.Customer) error {
+	namedStmt, err := c.getNamedStatement(ctx, DeleteCustomer)
+	if err != nil {
+		log.Println("get named statement err: ", err)
+		return err
+	}
+
+	res, err := namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("exec err: ", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := c.redis.DelWithPattern(ctx, DeleteCustomerRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
 	return nil
 }
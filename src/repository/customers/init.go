@@ -13,27 +13,51 @@ import (
 )
 
 const (
-	AllFields = `id, customer_id, name, address, created_at, updated_at`
+	AllFields = `id, customer_id, name, address, npwp, endpoint_id, endpoint_scheme, country_code, email, dunning_paused, created_at, updated_at`
 
 	GetByID = iota + 100
+	GetList
+	GetCountList
+	GetCountInvoices
+	UpdateDunningPaused
 
 	InsertCustomer = iota + 200
 	UpdateCustomer
+	FindOrCreateCustomer
+	DeleteCustomer
 
 	// Redis Key
 
+	GetListCustomersRedisKey   = "invoice:customers:getlist:%s"
+	GetCustomersCountRedisKey  = "invoice:customers:getcount:%s"
 	GetDetailCustomersRedisKey = "invoice:customers:getdetail:%s"
 	DeleteCustomerRedisKey     = "invoice:customers:*"
+	// cached invoices, their lists and counts show or filter by the customer name, so they go stale when a customer is renamed
+	DeleteInvoiceListRedisKey = "invoice:invoices:*"
+
+	// uniqueViolation is the postgres error code raised by the normalized name and address index
+	uniqueViolation = "23505"
 )
 
 var (
 	masterQueries = []string{
-		GetByID: fmt.Sprintf("SELECT %s FROM customers WHERE customer_id = $1 AND deleted_at IS NULL", AllFields),
+		GetByID:      fmt.Sprintf("SELECT %s FROM customers WHERE customer_id = $1 AND deleted_at IS NULL", AllFields),
+		GetList:      fmt.Sprintf("SELECT %s FROM customers WHERE deleted_at IS NULL", AllFields),
+		GetCountList: `SELECT COUNT(*) FROM customers WHERE deleted_at IS NULL`,
+		// trashed invoices count too, they can still be restored to the customer, purged ones are void and can't
+		GetCountInvoices: `SELECT (SELECT COUNT(*) FROM invoices WHERE customer_id = $1 AND (deleted_at IS NULL OR status <> 'Void')) + (SELECT COUNT(*) FROM recurring_invoices WHERE customer_id = $1 AND deleted_at IS NULL)`,
+		// written on its own so a concurrent edit of the customer does not undo it
+		UpdateDunningPaused: `UPDATE customers SET dunning_paused = $2 WHERE customer_id = $1 AND deleted_at IS NULL`,
 	}
 
 	masterNamedQueries = []string{
-		InsertCustomer: `INSERT INTO customers (customer_id, name, address) VALUES (:customer_id, :name, :address)`,
-		UpdateCustomer: `UPDATE customers SET (customer_id, name, address) = (:customer_id, :name, :address) WHERE customer_id = :customer_id`,
+		InsertCustomer: `INSERT INTO customers (customer_id, name, address, name_normalized, address_normalized, npwp, endpoint_id, endpoint_scheme, country_code, email) VALUES (:customer_id, :name, :address, :name_normalized, :address_normalized, :npwp, :endpoint_id, :endpoint_scheme, :country_code, :email)`,
+		UpdateCustomer: `UPDATE customers SET (name, address, name_normalized, address_normalized, npwp, endpoint_id, endpoint_scheme, country_code, email) = (:name, :address, :name_normalized, :address_normalized, :npwp, :endpoint_id, :endpoint_scheme, :country_code, :email) WHERE customer_id = :customer_id AND deleted_at IS NULL`,
+		// the no-op update makes RETURNING yield the existing row when the customer is already known
+		FindOrCreateCustomer: fmt.Sprintf(`INSERT INTO customers (customer_id, name, address, name_normalized, address_normalized, npwp, endpoint_id, endpoint_scheme, country_code) VALUES (:customer_id, :name, :address, :name_normalized, :address_normalized, :npwp, :endpoint_id, :endpoint_scheme, :country_code)
+			ON CONFLICT (name_normalized, address_normalized) WHERE deleted_at IS NULL DO UPDATE SET name_normalized = EXCLUDED.name_normalized
+			RETURNING %s`, AllFields),
+		DeleteCustomer: `UPDATE customers SET deleted_at = CURRENT_TIMESTAMP WHERE customer_id = :customer_id AND deleted_at IS NULL`,
 	}
 )
 
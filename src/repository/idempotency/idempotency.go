@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+)
+
+// Reserve claims key for the request hashed as requestHash until expiresAt and returns the time
+// of the reservation, the lease Complete and Release are given. It returns false when a live
+// request already holds the key. The same request still running since before staleBefore is
+// taken over, its process is assumed to be gone.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (time.Time, bool, error) {
+	var reservedAt time.Time
+
+	err := r.masterStmts[Reserve].GetContext(ctx, &reservedAt, key, requestHash, expiresAt, staleBefore)
+	if err == sql.ErrNoRows {
+		return reservedAt, false, nil
+	}
+	if err != nil {
+		log.Println("reserve idempotency key err: ", err)
+		return reservedAt, false, err
+	}
+
+	return reservedAt, true, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key string) (entity.IdempotencyKey, error) {
+	var data entity.IdempotencyKey
+
+	if err := r.masterStmts[GetByKey].GetContext(ctx, &data, key); err != nil {
+		log.Println("get idempotency key err: ", err)
+		return data, err
+	}
+
+	return data, nil
+}
+
+// Complete stores the response of the request holding key since data.CreatedAt. It returns
+// sql.ErrNoRows when the key was taken over in the meantime, the lease is lost.
+func (r *IdempotencyRepository) Complete(ctx context.Context, data *entity.IdempotencyKey) error {
+	res, err := r.masterStmts[Complete].ExecContext(ctx, data.Key, data.StatusCode, data.ContentType, data.ResponseBody, data.CreatedAt)
+	if err != nil {
+		log.Println("complete idempotency key err: ", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("idempotency lease lost err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Release gives up a key reserved at reservedAt whose request did not complete, so the client
+// can retry it. A key taken over by another request since is left alone.
+func (r *IdempotencyRepository) Release(ctx context.Context, key string, reservedAt time.Time) error {
+	_, err := r.masterStmts[Release].ExecContext(ctx, key, reservedAt)
+	if err != nil {
+		log.Println("release idempotency key err: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge deletes the keys that expired before before and returns how many there were.
+func (r *IdempotencyRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.masterStmts[Purge].ExecContext(ctx, before)
+	if err != nil {
+		log.Println("purge idempotency keys err: ", err)
+		return 0, err
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return 0, err
+	}
+
+	return purged, nil
+}
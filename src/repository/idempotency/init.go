@@ -0,0 +1,56 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	sqlxUtils "github.com/Risuii/frs-lib/sqlx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	AllFields = `key, request_hash, status_code, content_type, response_body, created_at, expires_at`
+
+	Reserve = iota + 100
+	GetByKey
+	Complete
+	Release
+	Purge
+)
+
+var (
+	masterQueries = []string{
+		// an expired key is taken over, and so is the same request still running since before $4, its
+		// process is gone. A live one is left alone and nothing is returned. created_at is the lease of
+		// the request holding the key, the ones it was taken from can no longer complete or release it
+		Reserve: `INSERT INTO idempotency_keys (key, request_hash, expires_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status_code = 0, content_type = '',
+				response_body = NULL, created_at = CURRENT_TIMESTAMP, expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at < CURRENT_TIMESTAMP
+				OR (idempotency_keys.status_code = 0 AND idempotency_keys.request_hash = EXCLUDED.request_hash AND idempotency_keys.created_at < $4)
+			RETURNING created_at`,
+		GetByKey: fmt.Sprintf("SELECT %s FROM idempotency_keys WHERE key = $1 AND expires_at >= CURRENT_TIMESTAMP", AllFields),
+		Complete: "UPDATE idempotency_keys SET status_code = $2, content_type = $3, response_body = $4 WHERE key = $1 AND created_at = $5 AND status_code = 0",
+		Release:  "DELETE FROM idempotency_keys WHERE key = $1 AND created_at = $2 AND status_code = 0",
+		Purge:    "DELETE FROM idempotency_keys WHERE expires_at < $1",
+	}
+)
+
+type IdempotencyRepository struct {
+	db          *sqlx.DB
+	masterStmts []*sqlx.Stmt
+}
+
+func InitIdempotencyRepository(ctx context.Context, db *sqlx.DB) (*IdempotencyRepository, error) {
+	stmpts, err := sqlxUtils.PrepareQueries(db, masterQueries)
+	if err != nil {
+		log.Println("PrepareQueries err:", err)
+		return nil, err
+	}
+
+	return &IdempotencyRepository{
+		db:          db,
+		masterStmts: stmpts,
+	}, nil
+}
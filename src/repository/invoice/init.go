@@ -14,18 +14,38 @@ import (
 )
 
 const (
-	AllFields           = `id, invoice_id, issue_date, subject, total_items, customer_id, due_date, status, sub_total, tax, grand_total, created_at, updated_at`
-	AllFieldsForGetList = `t.id, t.invoice_id, t.issue_date, t.subject, t.total_items, c.name AS customer_name, t.due_date, t.status, t.sub_total, t.tax, t.grand_total, t.created_at, t.updated_at`
+	AllFields           = `id, invoice_id, issue_date, subject, total_items, customer_id, due_date, status, sub_total, tax_rate, tax, grand_total, amount_paid, amount_credited, tax_invoice_number, version, created_at, updated_at`
+	AllFieldsForGetList = `t.id, t.invoice_id, t.issue_date, t.subject, t.total_items, c.name AS customer_name, t.due_date, t.status, t.sub_total, t.tax_rate, t.tax, t.grand_total, t.amount_paid, t.amount_credited, t.created_at, t.updated_at`
+	// ListSource is shared by the list and the count so both see the same rows for the same filters
+	ListSource = `FROM Invoices as t INNER JOIN customers as c ON t.customer_id = c.customer_id WHERE t.deleted_at IS NULL`
+	// ExportItemsSource is ListSource with the items of every invoice, an invoice without items still has one row
+	ExportItemsSource = `FROM Invoices as t INNER JOIN customers as c ON t.customer_id = c.customer_id
+		LEFT JOIN items as i ON i.invoice_id = t.invoice_id AND i.deleted_at IS NULL WHERE t.deleted_at IS NULL`
+	// TrashSource is ListSource of the deleted invoices, the void ones were purged and only keep their number
+	TrashSource      = `FROM Invoices as t INNER JOIN customers as c ON t.customer_id = c.customer_id WHERE t.deleted_at IS NOT NULL AND t.status <> 'Void'`
+	ExportItemFields = `i.item_id, COALESCE(i.name, '') AS item_name, COALESCE(i.type, '') AS item_type, i.quantity AS item_quantity, i.unit_price AS item_unit_price, i.amount AS item_amount`
 
 	BaseQuery = iota + 100
 	GetByID
 	GetByInvoiceID
 	GetList
 	GetCountList
-	GetLatestInvoiceID
+	GetByIDForUpdate
+	GetForTaxExport
+	GetExportListWithItems
+	GetDeletedByIDForUpdate
+	GetTrashList
+	GetTrashCount
+	DeleteInvoice
+	RestoreInvoice
+	PurgeInvoices
+	GetPastDue
 
 	InsertInvoice = iota + 200
 	UpdateInvoice
+	UpdateStatus
+	InsertStatusHistory
+	UpdateTaxInvoiceNumber
 
 	// Redis Key
 
@@ -33,21 +53,54 @@ const (
 	GetDetailInvoicesRedisKey = "invoice:invoices:getdetail:%s"
 	GetInvoicesCountRedisKey  = "invoice:invoices:getcount:%s"
 	DeleteInvoiceRedisKey     = "invoice:invoices:*"
+
+	// taxInvoiceNumberKey is the unique index of the e-Faktur serials
+	taxInvoiceNumberKey = "invoices_tax_invoice_number_key"
+	uniqueViolation     = "23505"
 )
 
 var (
 	masterQueries = []string{
-		BaseQuery:          fmt.Sprintf("SELECT %s FROM Invoices", AllFields),
-		GetByID:            fmt.Sprintf("SELECT %s FROM Invoices WHERE invoice_id = $1 AND deleted_at IS NULL", AllFields),
-		GetByInvoiceID:     fmt.Sprintf("SELECT %s FROM Invoices WHERE invoice_id = $1 And deleted_at IS NULL", AllFields),
-		GetList:            fmt.Sprintf(`SELECT %s FROM Invoices as t INNER JOIN customers as c ON t.customer_id = c.customer_id  WHERE t.deleted_at IS NULL`, AllFieldsForGetList),
-		GetCountList:       `SELECT COUNT(*) FROM Invoices WHERE deleted_at IS NULL`,
-		GetLatestInvoiceID: `SELECT MAX(invoice_id) FROM invoices`,
+		BaseQuery:              fmt.Sprintf("SELECT %s FROM Invoices", AllFields),
+		GetByID:                fmt.Sprintf("SELECT %s FROM Invoices WHERE invoice_id = $1 AND deleted_at IS NULL", AllFields),
+		GetByInvoiceID:         fmt.Sprintf("SELECT %s FROM Invoices WHERE invoice_id = $1 And deleted_at IS NULL", AllFields),
+		GetList:                fmt.Sprintf("SELECT %s %s", AllFieldsForGetList, ListSource),
+		GetCountList:           fmt.Sprintf("SELECT COUNT(*) %s", ListSource),
+		GetByIDForUpdate:       fmt.Sprintf("SELECT %s FROM Invoices WHERE invoice_id = $1 AND deleted_at IS NULL FOR UPDATE", AllFields),
+		GetExportListWithItems: fmt.Sprintf("SELECT %s, %s %s", AllFieldsForGetList, ExportItemFields, ExportItemsSource),
+		// drafts are not final and void invoices are cancelled, neither is reported to DJP
+		GetForTaxExport: fmt.Sprintf(`SELECT %s FROM Invoices WHERE deleted_at IS NULL AND tax_invoice_number <> '' AND status NOT IN ('Draft', 'Void')
+			AND issue_date >= $1 AND issue_date < CAST($2 AS timestamptz) + interval '1 day' ORDER BY issue_date, id`, AllFields),
+		GetDeletedByIDForUpdate: fmt.Sprintf("SELECT %s, deleted_at FROM Invoices WHERE invoice_id = $1 AND deleted_at IS NOT NULL AND status <> 'Void' FOR UPDATE", AllFields),
+		GetTrashList:            fmt.Sprintf("SELECT %s, t.deleted_at %s ORDER BY t.deleted_at DESC, t.id DESC LIMIT $1 OFFSET $2", AllFieldsForGetList, TrashSource),
+		GetTrashCount:           fmt.Sprintf("SELECT COUNT(*) %s", TrashSource),
+		DeleteInvoice:           `UPDATE invoices SET deleted_at = $2 WHERE invoice_id = $1 AND deleted_at IS NULL`,
+		RestoreInvoice:          `UPDATE invoices SET (deleted_at, version) = (NULL, version + 1) WHERE invoice_id = $1 AND deleted_at IS NOT NULL`,
+		// numbers are gapless, so a purged draft keeps its row as a void invoice without items.
+		// Invoices that were ever issued are part of the books and stay in the trash.
+		PurgeInvoices: `WITH purged AS (
+				SELECT invoice_id FROM invoices AS i WHERE deleted_at < $1 AND status = 'Draft'
+					AND NOT EXISTS (SELECT 1 FROM payments AS p WHERE p.invoice_id = i.invoice_id)
+					AND NOT EXISTS (SELECT 1 FROM invoice_status_histories AS h WHERE h.invoice_id = i.invoice_id AND h.to_status = 'Issued')
+			), purged_items AS (
+				DELETE FROM items WHERE invoice_id IN (SELECT invoice_id FROM purged)
+			), purged_reminders AS (
+				DELETE FROM invoice_reminders WHERE invoice_id IN (SELECT invoice_id FROM purged)
+			), purged_histories AS (
+				INSERT INTO invoice_status_histories (invoice_id, from_status, to_status, reason) SELECT invoice_id, 'Draft', 'Void', 'purged' FROM purged
+			)
+			UPDATE invoices SET (status, total_items, sub_total, tax, grand_total, version) = ('Void', 0, 0, 0, 0, version + 1) WHERE invoice_id IN (SELECT invoice_id FROM purged)`,
+		// unpaid issued invoices due before $1, with the ones already overdue when $2 is set
+		GetPastDue: fmt.Sprintf(`SELECT %s FROM Invoices WHERE deleted_at IS NULL AND due_date < $1
+			AND (status IN ('Issued', 'PartiallyPaid') OR ($2 AND status = 'Overdue')) ORDER BY due_date, id`, AllFields),
 	}
 
 	masterNamedQueries = []string{
-		InsertInvoice: `INSERT INTO invoices (invoice_id, issue_date, subject, total_items, customer_id, due_date, status, sub_total, tax, grand_total) VALUES (:invoice_id, :issue_date, :subject, :total_items, :customer_id, :due_date, :status, :sub_total, :tax, :grand_total) RETURNING invoice_id, customer_id`,
-		UpdateInvoice: `UPDATE invoices SET (issue_date, subject, total_items, due_date, sub_total, tax, grand_total) = (:issue_date, :subject, :total_items, :due_date, :sub_total, :tax, :grand_total) WHERE invoice_id = :invoice_id`,
+		InsertInvoice:          `INSERT INTO invoices (invoice_id, issue_date, subject, total_items, customer_id, due_date, status, sub_total, tax_rate, tax, grand_total, tax_invoice_number) VALUES (:invoice_id, :issue_date, :subject, :total_items, :customer_id, :due_date, :status, :sub_total, :tax_rate, :tax, :grand_total, :tax_invoice_number) RETURNING invoice_id, customer_id`,
+		UpdateInvoice:          `UPDATE invoices SET (issue_date, subject, total_items, customer_id, due_date, status, sub_total, tax_rate, tax, grand_total, tax_invoice_number, version) = (:issue_date, :subject, :total_items, :customer_id, :due_date, :status, :sub_total, :tax_rate, :tax, :grand_total, :tax_invoice_number, version + 1) WHERE invoice_id = :invoice_id AND version = :version`,
+		UpdateStatus:           `UPDATE invoices SET (status, amount_paid, amount_credited, version) = (:status, :amount_paid, :amount_credited, version + 1) WHERE invoice_id = :invoice_id`,
+		InsertStatusHistory:    `INSERT INTO invoice_status_histories (invoice_id, from_status, to_status, reason) VALUES (:invoice_id, :from_status, :to_status, :reason)`,
+		UpdateTaxInvoiceNumber: `UPDATE invoices SET (tax_invoice_number, version) = (:tax_invoice_number, version + 1) WHERE invoice_id = :invoice_id`,
 	}
 )
 
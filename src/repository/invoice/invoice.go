@@ -4,29 +4,123 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
+	"unicode"
 
+	"github.com/Risuii/invoice/src/decimal"
 	"github.com/Risuii/invoice/src/entity"
 	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	errorss "github.com/Risuii/invoice/src/errors"
 )
 
-func BuildFilter(query string, params contract.GetListParam) (string, contract.GetListParam) {
+// SearchQuery turns a free text keyword into a prefix tsquery, so "acme cons" finds
+// "Acme Consulting". Only letters and digits are kept, the rest of the input separates words
+// and can never break the tsquery syntax. It returns an empty string when nothing is left.
+func SearchQuery(keyword string) string {
+	words := strings.FieldsFunc(strings.ToLower(keyword), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// sortColumns maps the sort fields of contract.InvoiceSortFields to their columns. Only these
+// columns ever reach the ORDER BY clause.
+var sortColumns = map[string]string{
+	"invoice_id":  "t.invoice_id",
+	"issue_date":  "t.issue_date",
+	"due_date":    "t.due_date",
+	"subject":     "t.subject",
+	"total_items": "t.total_items",
+	"customer":    "c.name",
+	"status":      "t.status",
+	"grand_total": "t.grand_total",
+	"amount_paid": "t.amount_paid",
+	"created_at":  "t.created_at",
+}
+
+// exportRow is a row of the export with items. The item columns are null for an invoice
+// without items.
+type exportRow struct {
+	entity.Invoices
+	ItemID        uuid.NullUUID   `db:"item_id"`
+	ItemName      string          `db:"item_name"`
+	ItemType      string          `db:"item_type"`
+	ItemQuantity  decimal.Decimal `db:"item_quantity"`
+	ItemUnitPrice decimal.Decimal `db:"item_unit_price"`
+	ItemAmount    decimal.Decimal `db:"item_amount"`
+}
+
+func (r exportRow) item() *entity.Item {
+	if !r.ItemID.Valid {
+		return nil
+	}
+
+	return &entity.Item{
+		ItemData: entity.ItemData{
+			InvoiceID: r.InvoiceID,
+			ItemID:    r.ItemID.UUID,
+			Name:      r.ItemName,
+			Type:      r.ItemType,
+			Quantity:  r.ItemQuantity,
+			UnitPrice: r.ItemUnitPrice,
+			Amount:    r.ItemAmount,
+		},
+	}
+}
+
+// ListArgs are the named arguments of the list query. Statuses is bound as a Postgres array
+// so the status filter stays a single parameter whatever the number of values.
+type ListArgs struct {
+	contract.GetListParam
+	Statuses       pq.StringArray `json:"-" db:"statuses"`
+	AfterCreatedAt time.Time      `json:"-" db:"after_created_at"`
+	AfterID        int64          `json:"-" db:"after_id"`
+}
+
+// BuildWhere returns the conditions appended to ListSource for the filters in params, along
+// with the named arguments they use. The list and the count both go through it.
+func BuildWhere(params contract.GetListParam) (string, ListArgs) {
+	var query string
+
+	params.Keyword = SearchQuery(params.Keyword)
+	if params.Keyword != "" {
+		query += ` AND t.search_vector @@ to_tsquery('simple', :keyword)`
+	}
 
 	if params.InvoiceID != "" {
-		query += ` AND invoice_id = :invoice_id`
+		query += ` AND t.invoice_id = :invoice_id`
 	}
 
 	if params.IssueDate != "" {
-		query += ` AND issue_date = :issue_date`
+		query += ` AND t.issue_date = :issue_date`
+	}
+
+	if !params.IssueDateFrom.IsZero() {
+		query += ` AND t.issue_date >= :issue_date_from`
+	}
+
+	if !params.IssueDateTo.IsZero() {
+		query += ` AND t.issue_date < CAST(:issue_date_to AS timestamptz) + interval '1 day'`
 	}
 
 	if params.Subject != "" {
-		query += ` AND subject = :subject`
+		query += ` AND t.subject = :subject`
 	}
 
 	if params.TotalItem > 0 {
-		query += ` AND total_items = :total_item`
+		query += ` AND t.total_items = :total_item`
 	}
 
 	if params.Customer != "" {
@@ -34,18 +128,95 @@ func BuildFilter(query string, params contract.GetListParam) (string, contract.G
 	}
 
 	if params.DueDate != "" {
-		query += ` AND due_date = :due_date`
+		query += ` AND t.due_date = :due_date`
+	}
+
+	if !params.DueDateFrom.IsZero() {
+		query += ` AND t.due_date >= :due_date_from`
 	}
 
-	if params.Status != "" {
-		query += ` AND status = :status`
+	if !params.DueDateTo.IsZero() {
+		query += ` AND t.due_date < CAST(:due_date_to AS timestamptz) + interval '1 day'`
 	}
 
-	if params.Offset != 0 || params.Page == 1 {
-		query += " LIMIT :limit OFFSET :offset"
+	if params.GrandTotalMin != nil {
+		query += ` AND t.grand_total >= :grand_total_min`
 	}
 
-	return query, params
+	if params.GrandTotalMax != nil {
+		query += ` AND t.grand_total <= :grand_total_max`
+	}
+
+	if len(params.Statuses) > 0 {
+		query += ` AND t.status = ANY(CAST(:statuses AS status_type[]))`
+	}
+
+	return query, ListArgs{GetListParam: params, Statuses: params.Statuses}
+}
+
+// BuildFilter appends the filters, the order and the page to the list query.
+// In cursor mode the page is the rows after the cursor in (created_at, id) order, which
+// stays stable while invoices are added and is served by an index instead of an offset scan.
+func BuildFilter(query string, params contract.GetListParam) (string, ListArgs) {
+	where, args := BuildWhere(params)
+	query += where
+
+	if params.CursorMode {
+		if !params.After.CreatedAt.IsZero() || params.After.ID != 0 {
+			args.AfterCreatedAt, args.AfterID = params.After.CreatedAt, params.After.ID
+			query += ` AND (t.created_at, t.id) > (:after_created_at, :after_id)`
+		}
+
+		return query + " ORDER BY t.created_at, t.id LIMIT :limit", args
+	}
+
+	query += " ORDER BY " + orderBy(args.GetListParam)
+	query += " LIMIT :limit OFFSET :offset"
+
+	return query, args
+}
+
+// BuildExport appends the filters and the order of the list to an export query. There is no
+// page, an export has every matching invoice. With items the rows of an invoice follow each
+// other in the order the items were added.
+func BuildExport(query string, params contract.GetListParam, includeItems bool) (string, ListArgs) {
+	where, args := BuildWhere(params)
+	query += where + " ORDER BY " + orderBy(args.GetListParam)
+
+	if includeItems {
+		query += ", i.id"
+	}
+
+	return query, args
+}
+
+// orderBy applies the requested sort first, then the search rank when there is a keyword.
+// t.id always comes last so rows with equal values keep a stable order between pages.
+func orderBy(params contract.GetListParam) string {
+	var order []string
+
+	for _, sort := range params.Sort {
+		column, ok := sortColumns[sort.Field]
+		if !ok {
+			continue
+		}
+
+		if sort.Desc {
+			column += " DESC"
+		}
+		order = append(order, column)
+	}
+
+	if params.Keyword != "" {
+		order = append(order, "ts_rank(t.search_vector, to_tsquery('simple', :keyword)) DESC")
+	}
+
+	return strings.Join(append(order, "t.id DESC"), ", ")
+}
+
+func isDuplicateTaxInvoiceNumber(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == taxInvoiceNumberKey
 }
 
 func (t *InvoicesRepository) Create(ctx context.Context, data *entity.Invoices) (contract.InvoiceResponseDB, error) {
@@ -59,6 +230,9 @@ func (t *InvoicesRepository) Create(ctx context.Context, data *entity.Invoices)
 
 	if err = namedStmt.GetContext(ctx, &res, data); err != nil {
 		log.Println("get invoice err: ", err)
+		if isDuplicateTaxInvoiceNumber(err) {
+			return res, errorss.ErrDuplicateTaxInvoiceNumber
+		}
 		return res, err
 	}
 
@@ -74,20 +248,21 @@ func (t *InvoicesRepository) GetList(ctx context.Context, params contract.GetLis
 	var Invoices []*entity.Invoices
 
 	stringQuery := masterQueries[GetList]
-	query, params := BuildFilter(stringQuery, params)
+	query, args := BuildFilter(stringQuery, params)
 
-	param, err := json.Marshal(params)
+	param, err := json.Marshal(args)
 	if err != nil {
 		log.Println("marshal err: ", err)
 		return nil, err
 	}
 
 	err = t.redis.WithCache(ctx, fmt.Sprintf(GetListInvoicesRedisKey, param), &Invoices, func() (interface{}, error) {
-		rows, err := t.db.NamedQueryContext(ctx, query, params)
+		rows, err := t.db.NamedQueryContext(ctx, query, args)
 		if err != nil {
 			log.Println("named query err: ", err)
 			return nil, err
 		}
+		defer rows.Close()
 
 		for rows.Next() {
 			var dataInvoices entity.Invoices
@@ -110,10 +285,50 @@ func (t *InvoicesRepository) GetList(ctx context.Context, params contract.GetLis
 	return Invoices, nil
 }
 
+// ExportList calls fn for every invoice matching the filters of params, once per item with
+// includeItems. Rows are read as fn consumes them and never go through the cache, so an
+// export does not hold the whole result in memory. It stops at the first error of fn.
+func (t *InvoicesRepository) ExportList(ctx context.Context, params contract.GetListParam, includeItems bool, fn func(invoice *entity.Invoices, item *entity.Item) error) error {
+	stringQuery := masterQueries[GetList]
+	if includeItems {
+		stringQuery = masterQueries[GetExportListWithItems]
+	}
+	query, args := BuildExport(stringQuery, params, includeItems)
+
+	rows, err := t.db.NamedQueryContext(ctx, query, args)
+	if err != nil {
+		log.Println("named query err: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row exportRow
+		if includeItems {
+			err = rows.StructScan(&row)
+		} else {
+			err = rows.StructScan(&row.Invoices)
+		}
+		if err != nil {
+			log.Println("struct scan err: ", err)
+			return err
+		}
+
+		if err = fn(&row.Invoices, row.item()); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (t *InvoicesRepository) GetInvoicesCount(ctx context.Context, param contract.GetListParam) (int64, error) {
 	var count int64
 
-	params, err := json.Marshal(param)
+	where, args := BuildWhere(param)
+	query := masterQueries[GetCountList] + where
+
+	params, err := json.Marshal(args)
 	if err != nil {
 		log.Println("marshal err: ", err)
 		return 0, err
@@ -121,7 +336,14 @@ func (t *InvoicesRepository) GetInvoicesCount(ctx context.Context, param contrac
 
 	err = t.redis.WithCache(ctx, fmt.Sprintf(GetInvoicesCountRedisKey, params), &count, func() (interface{}, error) {
 		var countData int64
-		err := t.masterStmts[GetCountList].Get(&countData)
+
+		boundQuery, boundArgs, err := t.db.BindNamed(query, args)
+		if err != nil {
+			log.Println("bind named err: ", err)
+			return countData, err
+		}
+
+		err = t.db.GetContext(ctx, &countData, boundQuery, boundArgs...)
 		return countData, err
 	})
 
@@ -149,22 +371,100 @@ func (t *InvoicesRepository) Get(ctx context.Context, id string) (entity.Invoice
 	return Invoices, nil
 }
 
-func (t *InvoicesRepository) GetLatestInvoiceID(ctx context.Context) (string, error) {
-	var res string
+func (t InvoicesRepository) Update(ctx context.Context, data *entity.Invoices) error {
+	var rowsAffected int64
 
-	err := t.masterStmts[GetLatestInvoiceID].GetContext(ctx, &res)
+	namedStmt, err := t.getNamedStatement(ctx, UpdateInvoice)
 	if err != nil {
-		log.Println("get latest invoice id err: ", err)
-		return res, err
+		log.Println("get named statement err: ", err)
+		return err
 	}
 
-	return res, nil
+	res, err := namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("exec err: ", err)
+		if isDuplicateTaxInvoiceNumber(err) {
+			return errorss.ErrDuplicateTaxInvoiceNumber
+		}
+		return err
+	}
+
+	rowsAffected, err = res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := t.redis.DelWithPattern(ctx, DeleteInvoiceRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
 }
 
-func (t InvoicesRepository) Update(ctx context.Context, data *entity.Invoices) error {
+func (t *InvoicesRepository) GetForUpdate(ctx context.Context, id string) (entity.Invoices, error) {
+	var Invoices entity.Invoices
+
+	stmt, err := t.getStatement(ctx, GetByIDForUpdate)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return Invoices, err
+	}
+
+	err = stmt.GetContext(ctx, &Invoices, id)
+	if err != nil {
+		log.Println("get invoice for update err: ", err)
+		return Invoices, err
+	}
+
+	return Invoices, nil
+}
+
+func (t *InvoicesRepository) UpdateStatus(ctx context.Context, data *entity.Invoices) error {
 	var rowsAffected int64
 
-	namedStmt, err := t.getNamedStatement(ctx, UpdateInvoice)
+	namedStmt, err := t.getNamedStatement(ctx, UpdateStatus)
+	if err != nil {
+		log.Println("get named statement err: ", err)
+		return err
+	}
+
+	res, err := namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("exec err: ", err)
+		return err
+	}
+
+	rowsAffected, err = res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := t.redis.DelWithPattern(ctx, DeleteInvoiceRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// UpdateTaxInvoiceNumber records the tax invoice number DJP assigned to an invoice.
+func (t *InvoicesRepository) UpdateTaxInvoiceNumber(ctx context.Context, data *entity.Invoices) error {
+	var rowsAffected int64
+
+	namedStmt, err := t.getNamedStatement(ctx, UpdateTaxInvoiceNumber)
 	if err != nil {
 		log.Println("get named statement err: ", err)
 		return err
@@ -173,6 +473,9 @@ func (t InvoicesRepository) Update(ctx context.Context, data *entity.Invoices) e
 	res, err := namedStmt.ExecContext(ctx, data)
 	if err != nil {
 		log.Println("exec err: ", err)
+		if isDuplicateTaxInvoiceNumber(err) {
+			return errorss.ErrDuplicateTaxInvoiceNumber
+		}
 		return err
 	}
 
@@ -194,3 +497,172 @@ func (t InvoicesRepository) Update(ctx context.Context, data *entity.Invoices) e
 
 	return nil
 }
+
+func (t *InvoicesRepository) CreateStatusHistory(ctx context.Context, data *entity.InvoiceStatusHistory) error {
+	namedStmt, err := t.getNamedStatement(ctx, InsertStatusHistory)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	_, err = namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("create invoice status history err: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetForTaxExport returns the invoices with a tax invoice number issued between from and to,
+// both inclusive. It reads the database directly, an export has to see every change.
+func (t *InvoicesRepository) GetForTaxExport(ctx context.Context, from, to time.Time) ([]*entity.Invoices, error) {
+	var Invoices []*entity.Invoices
+
+	err := t.masterStmts[GetForTaxExport].SelectContext(ctx, &Invoices, from, to)
+	if err != nil {
+		log.Println("get invoices for tax export err: ", err)
+		return nil, err
+	}
+
+	return Invoices, nil
+}
+
+// GetPastDue returns the unpaid issued invoices due before dueBefore, including the overdue
+// ones when includeOverdue is set. It is read by the overdue job and never cached.
+func (t *InvoicesRepository) GetPastDue(ctx context.Context, dueBefore time.Time, includeOverdue bool) ([]*entity.Invoices, error) {
+	var Invoices []*entity.Invoices
+
+	err := t.masterStmts[GetPastDue].SelectContext(ctx, &Invoices, dueBefore, includeOverdue)
+	if err != nil {
+		log.Println("get past due invoices err: ", err)
+		return nil, err
+	}
+
+	return Invoices, nil
+}
+
+// Delete moves an invoice to the trash.
+func (t *InvoicesRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	stmt, err := t.getStatement(ctx, DeleteInvoice)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, id, deletedAt)
+	if err != nil {
+		log.Println("exec err: ", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := t.redis.DelWithPattern(ctx, DeleteInvoiceRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// GetDeletedForUpdate returns an invoice of the trash and locks it until the transaction ends.
+func (t *InvoicesRepository) GetDeletedForUpdate(ctx context.Context, id string) (entity.Invoices, error) {
+	var Invoices entity.Invoices
+
+	stmt, err := t.getStatement(ctx, GetDeletedByIDForUpdate)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return Invoices, err
+	}
+
+	err = stmt.GetContext(ctx, &Invoices, id)
+	if err != nil {
+		log.Println("get deleted invoice for update err: ", err)
+		return Invoices, err
+	}
+
+	return Invoices, nil
+}
+
+// Restore takes an invoice out of the trash.
+func (t *InvoicesRepository) Restore(ctx context.Context, id string) error {
+	stmt, err := t.getStatement(ctx, RestoreInvoice)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		log.Println("exec err: ", err)
+		if isDuplicateTaxInvoiceNumber(err) {
+			return errorss.ErrDuplicateTaxInvoiceNumber
+		}
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := t.redis.DelWithPattern(ctx, DeleteInvoiceRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// GetTrash returns a page of the trash, the last deleted invoice first.
+func (t *InvoicesRepository) GetTrash(ctx context.Context, params contract.GetTrashListParam) ([]*entity.Invoices, error) {
+	var Invoices []*entity.Invoices
+
+	err := t.masterStmts[GetTrashList].SelectContext(ctx, &Invoices, params.Limit, params.Offset)
+	if err != nil {
+		log.Println("get trash err: ", err)
+		return nil, err
+	}
+
+	return Invoices, nil
+}
+
+func (t *InvoicesRepository) GetTrashCount(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := t.masterStmts[GetTrashCount].GetContext(ctx, &count)
+	if err != nil {
+		log.Println("get trash count err: ", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Purge voids the draft invoices deleted before the given time and deletes their items for good,
+// the invoice rows stay to keep their numbers. It returns how many invoices were purged.
+func (t *InvoicesRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := t.masterStmts[PurgeInvoices].ExecContext(ctx, before)
+	if err != nil {
+		log.Println("purge invoices err: ", err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
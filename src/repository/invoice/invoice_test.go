@@ -0,0 +1,224 @@
+package Invoices
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+func TestInvoicesRepository_SearchQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keyword  string
+		expected string
+	}{
+		{name: "empty", keyword: "", expected: ""},
+		{name: "single word", keyword: "Acme", expected: "acme:*"},
+		{name: "several words", keyword: "  acme   cons ", expected: "acme:* & cons:*"},
+		{name: "invoice number", keyword: "INV/2024/00042", expected: "inv:* & 2024:* & 00042:*"},
+		{name: "tsquery operators", keyword: "a & !b | (c):*", expected: "a:* & b:* & c:*"},
+		{name: "only symbols", keyword: "'&|!", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, SearchQuery(testCase.keyword))
+		})
+	}
+}
+
+func TestInvoicesRepository_BuildFilterOrder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   contract.GetListParam
+		expected string
+	}{
+		{
+			name:     "default order",
+			params:   contract.GetListParam{Page: 1},
+			expected: " ORDER BY t.id DESC LIMIT :limit OFFSET :offset",
+		},
+		{
+			name: "requested sort",
+			params: contract.GetListParam{
+				Page: 1,
+				Sort: []contract.SortField{{Field: "due_date", Desc: true}, {Field: "customer"}},
+			},
+			expected: " ORDER BY t.due_date DESC, c.name, t.id DESC LIMIT :limit OFFSET :offset",
+		},
+		{
+			name: "unknown sort field is dropped",
+			params: contract.GetListParam{
+				Page: 1,
+				Sort: []contract.SortField{{Field: "id; DROP TABLE invoices"}},
+			},
+			expected: " ORDER BY t.id DESC LIMIT :limit OFFSET :offset",
+		},
+		{
+			name: "keyword ranks after the requested sort",
+			params: contract.GetListParam{
+				Page:    1,
+				Keyword: "acme",
+				Sort:    []contract.SortField{{Field: "grand_total"}},
+			},
+			expected: ` AND t.search_vector @@ to_tsquery('simple', :keyword) ORDER BY t.grand_total, ts_rank(t.search_vector, to_tsquery('simple', :keyword)) DESC, t.id DESC LIMIT :limit OFFSET :offset`,
+		},
+		{
+			name: "later page",
+			params: contract.GetListParam{
+				Page:   3,
+				Limit:  10,
+				Offset: 20,
+			},
+			expected: " ORDER BY t.id DESC LIMIT :limit OFFSET :offset",
+		},
+		{
+			name: "first page in cursor mode",
+			params: contract.GetListParam{
+				CursorMode: true,
+				Limit:      10,
+			},
+			expected: " ORDER BY t.created_at, t.id LIMIT :limit",
+		},
+		{
+			name: "cursor mode ignores sort and rank",
+			params: contract.GetListParam{
+				CursorMode: true,
+				Keyword:    "acme",
+				Sort:       []contract.SortField{{Field: "grand_total"}},
+				After:      contract.Cursor{CreatedAt: time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC), ID: 42},
+			},
+			expected: ` AND t.search_vector @@ to_tsquery('simple', :keyword) AND (t.created_at, t.id) > (:after_created_at, :after_id) ORDER BY t.created_at, t.id LIMIT :limit`,
+		},
+		{
+			name: "statuses are bound as one array",
+			params: contract.GetListParam{
+				Page:     1,
+				Statuses: []string{"Paid", "Issued"},
+			},
+			expected: " AND t.status = ANY(CAST(:statuses AS status_type[])) ORDER BY t.id DESC LIMIT :limit OFFSET :offset",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query, args := BuildFilter("", testCase.params)
+
+			assert.Equal(t, testCase.expected, query)
+			assert.Equal(t, pq.StringArray(testCase.params.Statuses), args.Statuses)
+			assert.Equal(t, testCase.params.After.ID, args.AfterID)
+		})
+	}
+}
+
+func TestInvoicesRepository_BuildExport(t *testing.T) {
+	testCases := []struct {
+		name         string
+		params       contract.GetListParam
+		includeItems bool
+		expected     string
+	}{
+		{
+			name:     "no page",
+			params:   contract.GetListParam{Page: 3, Limit: 10, Offset: 20},
+			expected: " ORDER BY t.id DESC",
+		},
+		{
+			name: "filters and sort of the list",
+			params: contract.GetListParam{
+				Keyword:  "acme",
+				Statuses: []string{"Paid"},
+				Sort:     []contract.SortField{{Field: "issue_date", Desc: true}},
+			},
+			expected: ` AND t.search_vector @@ to_tsquery('simple', :keyword) AND t.status = ANY(CAST(:statuses AS status_type[])) ORDER BY t.issue_date DESC, ts_rank(t.search_vector, to_tsquery('simple', :keyword)) DESC, t.id DESC`,
+		},
+		{
+			name:         "items follow their invoice",
+			params:       contract.GetListParam{Sort: []contract.SortField{{Field: "customer"}}},
+			includeItems: true,
+			expected:     " ORDER BY c.name, t.id DESC, i.id",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query, args := BuildExport("", testCase.params, testCase.includeItems)
+
+			assert.Equal(t, testCase.expected, query)
+			assert.Equal(t, pq.StringArray(testCase.params.Statuses), args.Statuses)
+		})
+	}
+}
+
+func TestInvoicesRepository_BuildWhere(t *testing.T) {
+	date := time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)
+	amount := decimal.NewFromInt(100)
+
+	// filters are listed in the order BuildWhere appends them
+	filters := []struct {
+		apply    func(*contract.GetListParam)
+		fragment string
+	}{
+		{func(p *contract.GetListParam) { p.Keyword = "acme" }, ` AND t.search_vector @@ to_tsquery('simple', :keyword)`},
+		{func(p *contract.GetListParam) { p.InvoiceID = "0001" }, ` AND t.invoice_id = :invoice_id`},
+		{func(p *contract.GetListParam) { p.IssueDate = "2024-03-07" }, ` AND t.issue_date = :issue_date`},
+		{func(p *contract.GetListParam) { p.IssueDateFrom = date }, ` AND t.issue_date >= :issue_date_from`},
+		{func(p *contract.GetListParam) { p.IssueDateTo = date }, ` AND t.issue_date < CAST(:issue_date_to AS timestamptz) + interval '1 day'`},
+		{func(p *contract.GetListParam) { p.Subject = "subject" }, ` AND t.subject = :subject`},
+		{func(p *contract.GetListParam) { p.TotalItem = 2 }, ` AND t.total_items = :total_item`},
+		{func(p *contract.GetListParam) { p.Customer = "acme" }, ` AND c.name = :customer`},
+		{func(p *contract.GetListParam) { p.DueDate = "2024-03-07" }, ` AND t.due_date = :due_date`},
+		{func(p *contract.GetListParam) { p.DueDateFrom = date }, ` AND t.due_date >= :due_date_from`},
+		{func(p *contract.GetListParam) { p.DueDateTo = date }, ` AND t.due_date < CAST(:due_date_to AS timestamptz) + interval '1 day'`},
+		{func(p *contract.GetListParam) { p.GrandTotalMin = &amount }, ` AND t.grand_total >= :grand_total_min`},
+		{func(p *contract.GetListParam) { p.GrandTotalMax = &amount }, ` AND t.grand_total <= :grand_total_max`},
+		{func(p *contract.GetListParam) { p.Statuses = []string{"Paid", "Issued"} }, ` AND t.status = ANY(CAST(:statuses AS status_type[]))`},
+	}
+
+	// every combination of filters, the count must bind the same arguments as the list
+	for mask := 0; mask < 1<<len(filters); mask++ {
+		params := contract.GetListParam{Page: 1, Limit: 10}
+		var expected string
+
+		for i, filter := range filters {
+			if mask&(1<<i) != 0 {
+				filter.apply(&params)
+				expected += filter.fragment
+			}
+		}
+
+		where, args := BuildWhere(params)
+		if where != expected {
+			t.Fatalf("mask %b: expected %q, got %q", mask, expected, where)
+		}
+
+		countQuery, countArgs, err := sqlx.Named(masterQueries[GetCountList]+where, args)
+		if err != nil {
+			t.Fatalf("mask %b: bind count err: %v", mask, err)
+		}
+
+		listQuery, listArgs := BuildFilter(masterQueries[GetList], params)
+		listQuery, listBound, err := sqlx.Named(listQuery, listArgs)
+		if err != nil {
+			t.Fatalf("mask %b: bind list err: %v", mask, err)
+		}
+
+		countWhere := strings.TrimPrefix(countQuery, "SELECT COUNT(*) ")
+		if !strings.Contains(listQuery, countWhere) {
+			t.Fatalf("mask %b: list %q does not share the count filters %q", mask, listQuery, countWhere)
+		}
+
+		// the list binds the count arguments, the rank when searching, then limit and offset
+		extra := 2
+		if params.Keyword != "" {
+			extra++
+		}
+		assert.Equal(t, len(countArgs)+extra, len(listBound))
+	}
+}
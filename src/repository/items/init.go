@@ -16,7 +16,10 @@ const (
 	AllFields = `id, invoice_id, item_id, name, type, quantity, unit_price, amount`
 
 	GetByInvoiceID = iota + 100
+	GetByInvoiceIDForUpdate
 	DeleteItemByItemID
+	DeleteItemsByInvoiceID
+	RestoreItemsByInvoiceID
 
 	InsertItems = iota + 200
 	UpdateItems
@@ -29,8 +32,12 @@ const (
 
 var (
 	masterQueries = []string{
-		GetByInvoiceID:     fmt.Sprintf("SELECT %s FROM items WHERE invoice_id = $1 AND deleted_at IS NULL", AllFields),
-		DeleteItemByItemID: `UPDATE items SET deleted_at = now() WHERE item_id = $1 and deleted_at IS NULL`,
+		GetByInvoiceID:          fmt.Sprintf("SELECT %s FROM items WHERE invoice_id = $1 AND deleted_at IS NULL", AllFields),
+		GetByInvoiceIDForUpdate: fmt.Sprintf("SELECT %s FROM items WHERE invoice_id = $1 AND deleted_at IS NULL ORDER BY id FOR UPDATE", AllFields),
+		DeleteItemByItemID:      `UPDATE items SET deleted_at = now() WHERE item_id = $1 and deleted_at IS NULL`,
+		// items deleted with their invoice share its deleted_at, a restore leaves the ones removed before
+		DeleteItemsByInvoiceID:  `UPDATE items SET deleted_at = $2 WHERE invoice_id = $1 AND deleted_at IS NULL`,
+		RestoreItemsByInvoiceID: `UPDATE items SET deleted_at = NULL WHERE invoice_id = $1 AND deleted_at = $2`,
 	}
 
 	masterNamedQueries = []string{
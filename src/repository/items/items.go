@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/Risuii/invoice/src/entity"
 	"github.com/google/uuid"
@@ -60,6 +61,26 @@ func (i *ItemsRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*
 	return Item, nil
 }
 
+// GetByInvoiceIDForUpdate returns the items of an invoice and locks them until the transaction
+// ends. It is read in the transaction and never cached, for changes made from what it returns.
+func (i *ItemsRepository) GetByInvoiceIDForUpdate(ctx context.Context, invID string) ([]*entity.Item, error) {
+	var Item []*entity.Item
+
+	stmt, err := i.getStatement(ctx, GetByInvoiceIDForUpdate)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return nil, err
+	}
+
+	err = stmt.SelectContext(ctx, &Item, invID)
+	if err != nil {
+		log.Println("get items for update err: ", err)
+		return nil, err
+	}
+
+	return Item, nil
+}
+
 func (i *ItemsRepository) Update(ctx context.Context, data []*entity.Item) error {
 
 	namedStmt, err := i.getNamedStatement(ctx, UpdateItems)
@@ -98,8 +119,14 @@ func (i *ItemsRepository) Update(ctx context.Context, data []*entity.Item) error
 }
 
 func (i *ItemsRepository) Delete(ctx context.Context, ids []uuid.UUID) error {
+	stmt, err := i.getStatement(ctx, DeleteItemByItemID)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
 	for _, v := range ids {
-		_, err := i.masterStmts[DeleteItemByItemID].ExecContext(ctx, v)
+		_, err := stmt.ExecContext(ctx, v)
 		if err != nil {
 			log.Println("DeleteProduct err: ", err)
 			return err
@@ -113,3 +140,47 @@ func (i *ItemsRepository) Delete(ctx context.Context, ids []uuid.UUID) error {
 
 	return nil
 }
+
+// DeleteByInvoiceID deletes the items of an invoice deleted at deletedAt.
+func (i *ItemsRepository) DeleteByInvoiceID(ctx context.Context, invID string, deletedAt time.Time) error {
+	stmt, err := i.getStatement(ctx, DeleteItemsByInvoiceID)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, invID, deletedAt)
+	if err != nil {
+		log.Println("delete items err: ", err)
+		return err
+	}
+
+	redisErr := i.redis.DelWithPattern(ctx, DeleteItemRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// RestoreByInvoiceID restores the items deleted with their invoice at deletedAt.
+func (i *ItemsRepository) RestoreByInvoiceID(ctx context.Context, invID string, deletedAt time.Time) error {
+	stmt, err := i.getStatement(ctx, RestoreItemsByInvoiceID)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, invID, deletedAt)
+	if err != nil {
+		log.Println("restore items err: ", err)
+		return err
+	}
+
+	redisErr := i.redis.DelWithPattern(ctx, DeleteItemRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
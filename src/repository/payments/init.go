@@ -0,0 +1,96 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	atomicSqlx "github.com/Risuii/frs-lib/atomic/sqlx"
+	frsRedis "github.com/Risuii/frs-lib/redis"
+	sqlxUtils "github.com/Risuii/frs-lib/sqlx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	AllFields = `id, payment_id, invoice_id, amount, payment_date, method, reference, created_at, updated_at`
+
+	GetByInvoiceID = iota + 100
+	GetTotalByInvoiceID
+
+	InsertPayment = iota + 200
+
+	// Redis Key
+
+	GetPaymentsByInvoiceIDRedisKey = "invoice:payments:invoiceid:%s"
+	DeletePaymentRedisKey          = "invoice:payments:*"
+)
+
+var (
+	masterQueries = []string{
+		GetByInvoiceID:      fmt.Sprintf("SELECT %s FROM payments WHERE invoice_id = $1 AND deleted_at IS NULL ORDER BY payment_date, id", AllFields),
+		GetTotalByInvoiceID: `SELECT COALESCE(SUM(amount), 0) FROM payments WHERE invoice_id = $1 AND deleted_at IS NULL`,
+	}
+
+	masterNamedQueries = []string{
+		InsertPayment: `INSERT INTO payments (payment_id, invoice_id, amount, payment_date, method, reference) VALUES (:payment_id, :invoice_id, :amount, :payment_date, :method, :reference)`,
+	}
+)
+
+type PaymentsRepository struct {
+	db                *sqlx.DB
+	masterStmts       []*sqlx.Stmt
+	masterNamedStmpts []*sqlx.NamedStmt
+	redis             frsRedis.Redis
+}
+
+func InitPaymentsRepository(ctx context.Context, db *sqlx.DB, redis frsRedis.Redis) (*PaymentsRepository, error) {
+	stmpts, err := sqlxUtils.PrepareQueries(db, masterQueries)
+	if err != nil {
+		log.Println("PrepareQueries err:", err)
+		return nil, err
+	}
+
+	namedStmpts, err := sqlxUtils.PrepareNamedQueries(db, masterNamedQueries)
+	if err != nil {
+		log.Println("PrepareNamedQueries err:", err)
+		return nil, err
+	}
+
+	return &PaymentsRepository{
+		db:                db,
+		masterStmts:       stmpts,
+		masterNamedStmpts: namedStmpts,
+		redis:             redis,
+	}, nil
+}
+
+func (r *PaymentsRepository) getStatement(ctx context.Context, queryId int) (*sqlx.Stmt, error) {
+	var err error
+	var statement *sqlx.Stmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			statement, err = atomicSession.Tx().PreparexContext(ctx, masterQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		statement = r.masterStmts[queryId]
+	}
+	return statement, err
+}
+
+func (r *PaymentsRepository) getNamedStatement(ctx context.Context, queryId int) (*sqlx.NamedStmt, error) {
+	var err error
+	var namedStmt *sqlx.NamedStmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			namedStmt, err = atomicSession.Tx().PrepareNamedContext(ctx, masterNamedQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		namedStmt = r.masterNamedStmpts[queryId]
+	}
+	return namedStmt, err
+}
@@ -0,0 +1,67 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+)
+
+func (p *PaymentsRepository) Create(ctx context.Context, data *entity.Payment) error {
+	namedStmt, err := p.getNamedStatement(ctx, InsertPayment)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	_, err = namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("create payment err: ", err)
+		return err
+	}
+
+	redisErr := p.redis.DelWithPattern(ctx, DeletePaymentRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+func (p *PaymentsRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*entity.Payment, error) {
+	var Payment []*entity.Payment
+	err := p.redis.WithCache(ctx, fmt.Sprintf(GetPaymentsByInvoiceIDRedisKey, invID), &Payment, func() (interface{}, error) {
+		var paymentData []*entity.Payment
+		err := p.masterStmts[GetByInvoiceID].SelectContext(ctx, &paymentData, invID)
+		return paymentData, err
+	})
+
+	if err != nil {
+		log.Println(err)
+		return Payment, err
+	}
+
+	return Payment, nil
+}
+
+// GetTotalByInvoiceID sums every payment recorded for the invoice. Inside an atomic session
+// the sum includes payments inserted by the same transaction.
+func (p *PaymentsRepository) GetTotalByInvoiceID(ctx context.Context, invID string) (decimal.Decimal, error) {
+	var total decimal.Decimal
+
+	stmt, err := p.getStatement(ctx, GetTotalByInvoiceID)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return total, err
+	}
+
+	err = stmt.GetContext(ctx, &total, invID)
+	if err != nil {
+		log.Println("get total payment err: ", err)
+		return total, err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,136 @@
+package recurring
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	atomicSqlx "github.com/Risuii/frs-lib/atomic/sqlx"
+	frsRedis "github.com/Risuii/frs-lib/redis"
+	sqlxUtils "github.com/Risuii/frs-lib/sqlx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	AllFields     = `id, recurring_id, customer_id, subject, tax_rate, series, "interval", cron, due_days, start_date, end_date, next_run, last_run_at, created_at, updated_at`
+	AllItemFields = `id, recurring_id, name, type, quantity, unit_price, created_at, updated_at`
+	AllRunFields  = `id, recurring_id, run_at, invoice_id, status, error, created_at, updated_at`
+
+	GetByID = iota + 100
+	GetForUpdate
+	GetList
+	GetCountList
+	GetDue
+	GetItems
+	GetRuns
+	Advance
+
+	InsertRecurring = iota + 200
+	UpdateRecurring
+	DeleteRecurring
+	InsertRecurringItems
+	DeleteRecurringItems
+	InsertRecurringRun
+
+	// Redis Key
+
+	GetListRecurringRedisKey   = "invoice:recurring_invoices:getlist:%s"
+	GetRecurringCountRedisKey  = "invoice:recurring_invoices:getcount"
+	GetDetailRecurringRedisKey = "invoice:recurring_invoices:getdetail:%s"
+	GetRecurringItemsRedisKey  = "invoice:recurring_invoices:items:%s"
+	DeleteRecurringRedisKey    = "invoice:recurring_invoices:*"
+
+	// schedulerLockKey is the postgres advisory lock held by the instance running the scheduler
+	schedulerLockKey int64 = 7_002_301
+
+	tryLockQuery = `SELECT pg_try_advisory_lock($1)`
+	unlockQuery  = `SELECT pg_advisory_unlock($1)`
+)
+
+var (
+	masterQueries = []string{
+		GetByID:      fmt.Sprintf("SELECT %s FROM recurring_invoices WHERE recurring_id = $1 AND deleted_at IS NULL", AllFields),
+		GetForUpdate: fmt.Sprintf("SELECT %s FROM recurring_invoices WHERE recurring_id = $1 AND deleted_at IS NULL FOR UPDATE", AllFields),
+		GetList:      fmt.Sprintf("SELECT %s FROM recurring_invoices WHERE deleted_at IS NULL ORDER BY id DESC LIMIT $1 OFFSET $2", AllFields),
+		GetCountList: `SELECT COUNT(*) FROM recurring_invoices WHERE deleted_at IS NULL`,
+		GetDue:       fmt.Sprintf("SELECT %s FROM recurring_invoices WHERE next_run <= $1 AND deleted_at IS NULL ORDER BY next_run, id", AllFields),
+		GetItems:     fmt.Sprintf("SELECT %s FROM recurring_invoice_items WHERE recurring_id = $1 AND deleted_at IS NULL ORDER BY id", AllItemFields),
+		GetRuns:      fmt.Sprintf("SELECT %s FROM recurring_invoice_runs WHERE recurring_id = $1 AND deleted_at IS NULL ORDER BY run_at DESC LIMIT $2", AllRunFields),
+		// only moves a schedule still waiting for run_at, so a period is claimed once
+		Advance: `UPDATE recurring_invoices SET next_run = $3, last_run_at = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE recurring_id = $1 AND next_run = $2 AND deleted_at IS NULL`,
+	}
+
+	masterNamedQueries = []string{
+		InsertRecurring: `INSERT INTO recurring_invoices (recurring_id, customer_id, subject, tax_rate, series, "interval", cron, due_days, start_date, end_date, next_run)
+			VALUES (:recurring_id, :customer_id, :subject, :tax_rate, :series, :interval, :cron, :due_days, :start_date, :end_date, :next_run)`,
+		UpdateRecurring: `UPDATE recurring_invoices SET (customer_id, subject, tax_rate, series, "interval", cron, due_days, start_date, end_date, next_run, updated_at)
+			= (:customer_id, :subject, :tax_rate, :series, :interval, :cron, :due_days, :start_date, :end_date, :next_run, CURRENT_TIMESTAMP)
+			WHERE recurring_id = :recurring_id AND deleted_at IS NULL`,
+		DeleteRecurring:      `UPDATE recurring_invoices SET deleted_at = CURRENT_TIMESTAMP WHERE recurring_id = :recurring_id AND deleted_at IS NULL`,
+		InsertRecurringItems: `INSERT INTO recurring_invoice_items (recurring_id, name, type, quantity, unit_price) VALUES (:recurring_id, :name, :type, :quantity, :unit_price)`,
+		DeleteRecurringItems: `UPDATE recurring_invoice_items SET deleted_at = CURRENT_TIMESTAMP WHERE recurring_id = :recurring_id AND deleted_at IS NULL`,
+		// a period tried again replaces the failed run recorded before
+		InsertRecurringRun: `INSERT INTO recurring_invoice_runs (recurring_id, run_at, invoice_id, status, error) VALUES (:recurring_id, :run_at, :invoice_id, :status, :error)
+			ON CONFLICT (recurring_id, run_at) DO UPDATE SET (invoice_id, status, error, updated_at) = (EXCLUDED.invoice_id, EXCLUDED.status, EXCLUDED.error, CURRENT_TIMESTAMP)`,
+	}
+)
+
+type RecurringRepository struct {
+	db                *sqlx.DB
+	masterStmts       []*sqlx.Stmt
+	masterNamedStmpts []*sqlx.NamedStmt
+	redis             frsRedis.Redis
+}
+
+func InitRecurringRepository(ctx context.Context, db *sqlx.DB, redis frsRedis.Redis) (*RecurringRepository, error) {
+	stmpts, err := sqlxUtils.PrepareQueries(db, masterQueries)
+	if err != nil {
+		log.Println("PrepareQueries err:", err)
+		return nil, err
+	}
+
+	namedStmpts, err := sqlxUtils.PrepareNamedQueries(db, masterNamedQueries)
+	if err != nil {
+		log.Println("PrepareNamedQueries err:", err)
+		return nil, err
+	}
+
+	return &RecurringRepository{
+		db:                db,
+		masterStmts:       stmpts,
+		masterNamedStmpts: namedStmpts,
+		redis:             redis,
+	}, nil
+}
+
+func (r *RecurringRepository) getStatement(ctx context.Context, queryId int) (*sqlx.Stmt, error) {
+	var err error
+	var statement *sqlx.Stmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			statement, err = atomicSession.Tx().PreparexContext(ctx, masterQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		statement = r.masterStmts[queryId]
+	}
+	return statement, err
+}
+
+func (r *RecurringRepository) getNamedStatement(ctx context.Context, queryId int) (*sqlx.NamedStmt, error) {
+	var err error
+	var namedStmt *sqlx.NamedStmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			namedStmt, err = atomicSession.Tx().PrepareNamedContext(ctx, masterNamedQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		namedStmt = r.masterNamedStmpts[queryId]
+	}
+	return namedStmt, err
+}
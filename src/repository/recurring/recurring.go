@@ -0,0 +1,318 @@
+package recurring
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+)
+
+func (r *RecurringRepository) Create(ctx context.Context, data *entity.RecurringInvoice) error {
+	namedStmt, err := r.getNamedStatement(ctx, InsertRecurring)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	_, err = namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("create recurring invoice err: ", err)
+		return err
+	}
+
+	redisErr := r.redis.DelWithPattern(ctx, DeleteRecurringRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+func (r *RecurringRepository) CreateItems(ctx context.Context, data []*entity.RecurringInvoiceItem) error {
+	namedStmt, err := r.getNamedStatement(ctx, InsertRecurringItems)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	for _, v := range data {
+		_, err = namedStmt.ExecContext(ctx, v)
+		if err != nil {
+			log.Println("create recurring invoice items err: ", err)
+			return err
+		}
+	}
+
+	redisErr := r.redis.DelWithPattern(ctx, DeleteRecurringRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+func (r *RecurringRepository) Get(ctx context.Context, id string) (entity.RecurringInvoice, error) {
+	var recurring entity.RecurringInvoice
+
+	err := r.redis.WithCache(ctx, fmt.Sprintf(GetDetailRecurringRedisKey, id), &recurring, func() (interface{}, error) {
+		var recurringData entity.RecurringInvoice
+		err := r.masterStmts[GetByID].GetContext(ctx, &recurringData, id)
+		return recurringData, err
+	})
+
+	if err != nil {
+		log.Println(err)
+		return recurring, err
+	}
+
+	return recurring, nil
+}
+
+// GetForUpdate locks the schedule until the surrounding transaction ends.
+func (r *RecurringRepository) GetForUpdate(ctx context.Context, id string) (entity.RecurringInvoice, error) {
+	var recurring entity.RecurringInvoice
+
+	stmt, err := r.getStatement(ctx, GetForUpdate)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return recurring, err
+	}
+
+	if err = stmt.GetContext(ctx, &recurring, id); err != nil {
+		log.Println("get recurring invoice for update err: ", err)
+		return recurring, err
+	}
+
+	return recurring, nil
+}
+
+func (r *RecurringRepository) GetItems(ctx context.Context, id string) ([]*entity.RecurringInvoiceItem, error) {
+	var items []*entity.RecurringInvoiceItem
+
+	err := r.redis.WithCache(ctx, fmt.Sprintf(GetRecurringItemsRedisKey, id), &items, func() (interface{}, error) {
+		var itemsData []*entity.RecurringInvoiceItem
+		err := r.masterStmts[GetItems].SelectContext(ctx, &itemsData, id)
+		return itemsData, err
+	})
+
+	if err != nil {
+		log.Println(err)
+		return items, err
+	}
+
+	return items, nil
+}
+
+// GetRuns returns the latest runs of a schedule, newest first.
+func (r *RecurringRepository) GetRuns(ctx context.Context, id string, limit int) ([]*entity.RecurringInvoiceRun, error) {
+	var runs []*entity.RecurringInvoiceRun
+
+	err := r.masterStmts[GetRuns].SelectContext(ctx, &runs, id, limit)
+	if err != nil {
+		log.Println("get recurring invoice runs err: ", err)
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (r *RecurringRepository) GetList(ctx context.Context, params contract.GetListRecurringParam) ([]*entity.RecurringInvoice, error) {
+	var recurring []*entity.RecurringInvoice
+
+	err := r.redis.WithCache(ctx, fmt.Sprintf(GetListRecurringRedisKey, fmt.Sprintf("%d:%d", params.Limit, params.Offset)), &recurring, func() (interface{}, error) {
+		var recurringData []*entity.RecurringInvoice
+		err := r.masterStmts[GetList].SelectContext(ctx, &recurringData, params.Limit, params.Offset)
+		return recurringData, err
+	})
+
+	if err != nil {
+		log.Println("GetRecurringList err: ", err)
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+func (r *RecurringRepository) GetRecurringCount(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := r.redis.WithCache(ctx, GetRecurringCountRedisKey, &count, func() (interface{}, error) {
+		var countData int64
+		err := r.masterStmts[GetCountList].GetContext(ctx, &countData)
+		return countData, err
+	})
+
+	if err != nil {
+		log.Println("GetRecurringCount err: ", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetDue returns the schedules whose next run is at or before now. It is read by the
+// scheduler and never cached.
+func (r *RecurringRepository) GetDue(ctx context.Context, now time.Time) ([]*entity.RecurringInvoice, error) {
+	var recurring []*entity.RecurringInvoice
+
+	err := r.masterStmts[GetDue].SelectContext(ctx, &recurring, now)
+	if err != nil {
+		log.Println("get due recurring invoices err: ", err)
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+func (r *RecurringRepository) Update(ctx context.Context, data *entity.RecurringInvoice) error {
+	return r.execNamed(ctx, UpdateRecurring, data)
+}
+
+func (r *RecurringRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.execNamed(ctx, DeleteRecurring, entity.RecurringInvoiceData{RecurringID: id})
+}
+
+// DeleteItems removes the items of a schedule, an update replaces them.
+func (r *RecurringRepository) DeleteItems(ctx context.Context, id uuid.UUID) error {
+	namedStmt, err := r.getNamedStatement(ctx, DeleteRecurringItems)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	_, err = namedStmt.ExecContext(ctx, entity.RecurringInvoiceItemData{RecurringID: id})
+	if err != nil {
+		log.Println("delete recurring invoice items err: ", err)
+		return err
+	}
+
+	redisErr := r.redis.DelWithPattern(ctx, DeleteRecurringRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+// Advance moves the schedule from the run at runAt to nextRun, nil when the schedule has
+// ended. It returns sql.ErrNoRows when the schedule is no longer waiting for runAt, another
+// run claimed the period or the schedule was changed.
+func (r *RecurringRepository) Advance(ctx context.Context, id uuid.UUID, runAt time.Time, nextRun *time.Time) error {
+	stmt, err := r.getStatement(ctx, Advance)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, id, runAt, nextRun)
+	if err != nil {
+		log.Println("advance recurring invoice err: ", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	redisErr := r.redis.DelWithPattern(ctx, DeleteRecurringRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
+
+func (r *RecurringRepository) CreateRun(ctx context.Context, data *entity.RecurringInvoiceRun) error {
+	namedStmt, err := r.getNamedStatement(ctx, InsertRecurringRun)
+	if err != nil {
+		log.Println("getNamedStatement err: ", err)
+		return err
+	}
+
+	_, err = namedStmt.ExecContext(ctx, data)
+	if err != nil {
+		log.Println("create recurring invoice run err: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// RunLocked runs fn while holding the scheduler advisory lock. The lock is taken on a
+// connection of its own, kept out of the pool until it is released. It returns false
+// without running fn when another instance holds the lock.
+func (r *RecurringRepository) RunLocked(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		log.Println("get connection err: ", err)
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err = conn.QueryRowxContext(ctx, tryLockQuery, schedulerLockKey).Scan(&locked); err != nil {
+		log.Println("try advisory lock err: ", err)
+		return false, err
+	}
+
+	if !locked {
+		return false, nil
+	}
+
+	defer func() {
+		// the lock belongs to the session, a connection that failed to release it must not go
+		// back to the pool
+		if _, err := conn.ExecContext(context.Background(), unlockQuery, schedulerLockKey); err != nil {
+			log.Println("advisory unlock err: ", err)
+			conn.Raw(func(driverConn interface{}) error {
+				return driver.ErrBadConn
+			})
+		}
+	}()
+
+	return true, fn(ctx)
+}
+
+func (r *RecurringRepository) execNamed(ctx context.Context, queryId int, arg interface{}) error {
+	namedStmt, err := r.getNamedStatement(ctx, queryId)
+	if err != nil {
+		log.Println("get named statement err: ", err)
+		return err
+	}
+
+	res, err := namedStmt.ExecContext(ctx, arg)
+	if err != nil {
+		log.Println("exec err: ", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		log.Println("ID not exist err: ", sql.ErrNoRows)
+		return sql.ErrNoRows
+	}
+
+	redisErr := r.redis.DelWithPattern(ctx, DeleteRecurringRedisKey)
+	if redisErr != nil {
+		log.Println(redisErr)
+	}
+
+	return nil
+}
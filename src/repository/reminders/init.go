@@ -0,0 +1,70 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	sqlxUtils "github.com/Risuii/frs-lib/sqlx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	AllFields = `id, invoice_id, stage, email, status, error, sent_at, attempts, created_at, updated_at`
+	// DunningFields are the fields of an unpaid invoice a reminder is written from, a failed
+	// reminder with attempts left does not count as reached
+	DunningFields = `t.invoice_id, t.issue_date, t.subject, t.customer_id, t.due_date, t.status, t.grand_total, t.amount_paid, t.amount_credited,
+		c.name AS customer_name, c.email AS customer_email,
+		(SELECT MAX(r.stage) FROM invoice_reminders AS r WHERE r.invoice_id = t.invoice_id AND (r.status <> 'failed' OR r.attempts >= $2)) AS last_stage`
+
+	GetDue = iota + 100
+	GetByInvoiceID
+	InsertReminder
+
+	UpdateReminder = iota + 200
+)
+
+var (
+	masterQueries = []string{
+		GetDue: fmt.Sprintf(`SELECT %s FROM invoices AS t INNER JOIN customers AS c ON t.customer_id = c.customer_id
+			WHERE t.deleted_at IS NULL AND NOT c.dunning_paused AND c.email <> '' AND t.status IN ('Issued', 'PartiallyPaid', 'Overdue') AND t.due_date <= $1
+			ORDER BY t.due_date, t.id`, DunningFields),
+		GetByInvoiceID: fmt.Sprintf("SELECT %s FROM invoice_reminders WHERE invoice_id = $1 ORDER BY stage", AllFields),
+		// a stage already recorded is left alone unless it failed with attempts left below $5, so
+		// its reminder is sent once
+		InsertReminder: `INSERT INTO invoice_reminders (invoice_id, stage, email, status) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (invoice_id, stage) DO UPDATE SET (email, status, error, attempts, updated_at) = (EXCLUDED.email, EXCLUDED.status, '', invoice_reminders.attempts + 1, CURRENT_TIMESTAMP)
+			WHERE invoice_reminders.status = 'failed' AND invoice_reminders.attempts < $5`,
+	}
+
+	masterNamedQueries = []string{
+		UpdateReminder: `UPDATE invoice_reminders SET (status, error, sent_at, updated_at) = (:status, :error, :sent_at, CURRENT_TIMESTAMP)
+			WHERE invoice_id = :invoice_id AND stage = :stage`,
+	}
+)
+
+type RemindersRepository struct {
+	db                *sqlx.DB
+	masterStmts       []*sqlx.Stmt
+	masterNamedStmpts []*sqlx.NamedStmt
+}
+
+func InitRemindersRepository(ctx context.Context, db *sqlx.DB) (*RemindersRepository, error) {
+	stmpts, err := sqlxUtils.PrepareQueries(db, masterQueries)
+	if err != nil {
+		log.Println("PrepareQueries err:", err)
+		return nil, err
+	}
+
+	namedStmpts, err := sqlxUtils.PrepareNamedQueries(db, masterNamedQueries)
+	if err != nil {
+		log.Println("PrepareNamedQueries err:", err)
+		return nil, err
+	}
+
+	return &RemindersRepository{
+		db:                db,
+		masterStmts:       stmpts,
+		masterNamedStmpts: namedStmpts,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package reminders
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+)
+
+// GetDue returns the unpaid issued invoices due on or before dueBefore whose customer has an
+// email and did not pause dunning, with the latest stage reminded. A failed stage sent fewer
+// than maxAttempts times is not counted. It is read by the dunning job and never cached.
+func (r *RemindersRepository) GetDue(ctx context.Context, dueBefore time.Time, maxAttempts int) ([]*entity.DunningInvoice, error) {
+	var invoices []*entity.DunningInvoice
+
+	err := r.masterStmts[GetDue].SelectContext(ctx, &invoices, dueBefore, maxAttempts)
+	if err != nil {
+		log.Println("get dunning invoices err: ", err)
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+func (r *RemindersRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*entity.InvoiceReminder, error) {
+	var reminders []*entity.InvoiceReminder
+
+	err := r.masterStmts[GetByInvoiceID].SelectContext(ctx, &reminders, invID)
+	if err != nil {
+		log.Println("get invoice reminders err: ", err)
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+// Create records the reminder of a stage before it is sent, or takes over a failed one sent
+// fewer than maxAttempts times. It returns false when the stage was already recorded, by an
+// earlier run or another instance.
+func (r *RemindersRepository) Create(ctx context.Context, data *entity.InvoiceReminder, maxAttempts int) (bool, error) {
+	res, err := r.masterStmts[InsertReminder].ExecContext(ctx, data.InvoiceID, data.Stage, data.Email, data.Status, maxAttempts)
+	if err != nil {
+		log.Println("create invoice reminder err: ", err)
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		log.Println("Get rows affected err: ", err)
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *RemindersRepository) Update(ctx context.Context, data *entity.InvoiceReminder) error {
+	_, err := r.masterNamedStmpts[UpdateReminder].ExecContext(ctx, data)
+	if err != nil {
+		log.Println("update invoice reminder err: ", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	atomicSqlx "github.com/Risuii/frs-lib/atomic/sqlx"
+	frsRedis "github.com/Risuii/frs-lib/redis"
+	sqlxUtils "github.com/Risuii/frs-lib/sqlx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	AllFields = `id, code, template, reset_period, created_at, updated_at`
+
+	GetByCode = iota + 100
+	NextValue
+
+	// Redis Key
+
+	GetDetailSeriesRedisKey = "invoice:invoice_series:getdetail:%s"
+)
+
+// ErrNoTransaction is returned when a number is requested outside of an atomic session.
+// The counter must be incremented by the transaction that stores the number, otherwise a
+// failed insert would leave a gap.
+var ErrNoTransaction = errors.New("invoice numbers must be allocated inside a transaction")
+
+var (
+	masterQueries = []string{
+		GetByCode: fmt.Sprintf("SELECT %s FROM invoice_series WHERE code = $1 AND deleted_at IS NULL", AllFields),
+		// the upsert locks the counter row until the surrounding transaction ends
+		NextValue: `INSERT INTO invoice_series_counters (series_code, period_key, last_value) VALUES ($1, $2, 1)
+			ON CONFLICT (series_code, period_key) DO UPDATE SET last_value = invoice_series_counters.last_value + 1, updated_at = CURRENT_TIMESTAMP
+			RETURNING last_value`,
+	}
+)
+
+type SeriesRepository struct {
+	db          *sqlx.DB
+	masterStmts []*sqlx.Stmt
+	redis       frsRedis.Redis
+}
+
+func InitSeriesRepository(ctx context.Context, db *sqlx.DB, redis frsRedis.Redis) (*SeriesRepository, error) {
+	stmpts, err := sqlxUtils.PrepareQueries(db, masterQueries)
+	if err != nil {
+		log.Println("PrepareQueries err:", err)
+		return nil, err
+	}
+
+	return &SeriesRepository{
+		db:          db,
+		masterStmts: stmpts,
+		redis:       redis,
+	}, nil
+}
+
+func (r *SeriesRepository) getStatement(ctx context.Context, queryId int) (*sqlx.Stmt, error) {
+	var err error
+	var statement *sqlx.Stmt
+	if atomicSessionCtx, ok := ctx.(*frsAtomic.AtomicSessionContext); ok {
+		if atomicSession, ok := atomicSessionCtx.AtomicSession.(*atomicSqlx.SqlxAtomicSession); ok {
+			statement, err = atomicSession.Tx().PreparexContext(ctx, masterQueries[queryId])
+		} else {
+			err = frsAtomic.InvalidAtomicSessionProvider
+		}
+	} else {
+		statement = r.masterStmts[queryId]
+	}
+	return statement, err
+}
@@ -0,0 +1,51 @@
+package series
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Risuii/invoice/src/entity"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+)
+
+func (r *SeriesRepository) Get(ctx context.Context, code string) (entity.InvoiceSeries, error) {
+	var series entity.InvoiceSeries
+
+	err := r.redis.WithCache(ctx, fmt.Sprintf(GetDetailSeriesRedisKey, code), &series, func() (interface{}, error) {
+		var seriesData entity.InvoiceSeries
+		err := r.masterStmts[GetByCode].GetContext(ctx, &seriesData, code)
+		return seriesData, err
+	})
+
+	if err != nil {
+		log.Println(err)
+		return series, err
+	}
+
+	return series, nil
+}
+
+// NextValue increments and returns the counter of the series for the given period.
+func (r *SeriesRepository) NextValue(ctx context.Context, code, periodKey string) (int64, error) {
+	var value int64
+
+	if _, ok := ctx.(*frsAtomic.AtomicSessionContext); !ok {
+		log.Println("next value err: ", ErrNoTransaction)
+		return value, ErrNoTransaction
+	}
+
+	stmt, err := r.getStatement(ctx, NextValue)
+	if err != nil {
+		log.Println("getStatement err: ", err)
+		return value, err
+	}
+
+	if err = stmt.GetContext(ctx, &value, code, periodKey); err != nil {
+		log.Println("next value err: ", err)
+		return value, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCron = errors.New("schedule: invalid cron expression")
+
+// searchLimit bounds the search for the next occurrence, an expression such as "0 0 30 2 *"
+// never matches.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Cron is a parsed five field cron expression: minute, hour, day of month, month and day of
+// week. Each field takes *, a value, a range a-b, a list a,b,c and a step such as */15 or
+// 1-5/2. Day of week runs from 0 to 7, both 0 and 7 are Sunday. Like the standard cron, when
+// both the day of month and the day of week are restricted a day matching either one runs.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record a day field given as *
+	domAny, dowAny bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// ParseCron parses a five field cron expression.
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%w: %q needs %d fields", ErrInvalidCron, expr, len(cronFields))
+	}
+
+	var bits [5]uint64
+	for i, f := range cronFields {
+		b, err := parseCronField(fields[i], f)
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	// 7 is another name for Sunday
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Cron{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+		rangePart, step := part, 1
+
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%w: bad step %q in %s", ErrInvalidCron, part, f.name)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = cronValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%w: bad range %q in %s", ErrInvalidCron, part, f.name)
+			}
+		default:
+			v, err := cronValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// a single value runs once unless it has a step, 5/15 means 5-max/15
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func cronValue(s string, f cronField) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: bad value %q in %s", ErrInvalidCron, s, f.name)
+	}
+	return v, nil
+}
+
+// Next returns the first minute matching the expression after the given time.
+func (c *Cron) Next(after time.Time) (time.Time, error) {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+
+	return time.Time{}, ErrNoOccurrence
+}
+
+func (c *Cron) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,112 @@
+// Package schedule computes when a recurring invoice runs next. Weekly, monthly, quarterly
+// and yearly schedules repeat from their start date, cron schedules follow a five field cron
+// expression. All times are evaluated in UTC.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	IntervalWeekly    = "weekly"
+	IntervalMonthly   = "monthly"
+	IntervalQuarterly = "quarterly"
+	IntervalYearly    = "yearly"
+	IntervalCron      = "cron"
+)
+
+var (
+	ErrInvalidInterval = errors.New("schedule: invalid interval")
+	ErrNoOccurrence    = errors.New("schedule: no occurrence")
+)
+
+// ValidInterval reports whether interval is one of the supported intervals.
+func ValidInterval(interval string) bool {
+	switch interval {
+	case IntervalWeekly, IntervalMonthly, IntervalQuarterly, IntervalYearly, IntervalCron:
+		return true
+	default:
+		return false
+	}
+}
+
+// First returns the first occurrence of the schedule, on or after start.
+func First(interval, cron string, start time.Time) (time.Time, error) {
+	return Next(interval, cron, start, start.Add(-time.Nanosecond))
+}
+
+// Next returns the first occurrence of the schedule after the given time. Occurrences before
+// start are skipped. Monthly, quarterly and yearly schedules keep the day of month of start,
+// clamped to the end of shorter months, so a schedule starting on the 31st runs on the 30th
+// in April and on the 31st again in May.
+func Next(interval, cron string, start, after time.Time) (time.Time, error) {
+	start, after = start.UTC(), after.UTC()
+
+	switch interval {
+	case IntervalWeekly:
+		return nextWeekly(start, after), nil
+	case IntervalMonthly:
+		return nextMonthly(start, after, 1), nil
+	case IntervalQuarterly:
+		return nextMonthly(start, after, 3), nil
+	case IntervalYearly:
+		return nextMonthly(start, after, 12), nil
+	case IntervalCron:
+		c, err := ParseCron(cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if after.Before(start) {
+			after = start.Add(-time.Nanosecond)
+		}
+		return c.Next(after)
+	default:
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidInterval, interval)
+	}
+}
+
+func nextWeekly(start, after time.Time) time.Time {
+	const week = 7 * 24 * time.Hour
+
+	if after.Before(start) {
+		return start
+	}
+
+	return start.Add((after.Sub(start)/week + 1) * week)
+}
+
+// nextMonthly returns the first occurrence every step months from start that is after the
+// given time.
+func nextMonthly(start, after time.Time, step int) time.Time {
+	if after.Before(start) {
+		return start
+	}
+
+	months := (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+	k := months/step - 1
+	if k < 0 {
+		k = 0
+	}
+
+	for {
+		occurrence := addMonths(start, k*step)
+		if occurrence.After(after) {
+			return occurrence
+		}
+		k++
+	}
+}
+
+// addMonths adds n months to t, clamping the day to the last day of the resulting month.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	day := t.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
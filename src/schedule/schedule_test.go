@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert"
+)
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSchedule_Next(t *testing.T) {
+	start := date(2024, time.January, 31, 0, 0)
+
+	testCases := []struct {
+		name     string
+		interval string
+		cron     string
+		start    time.Time
+		after    time.Time
+		expected time.Time
+		err      error
+	}{
+		{name: "before start", interval: IntervalMonthly, start: start, after: date(2023, time.December, 1, 0, 0), expected: start},
+		{name: "monthly clamped to leap february", interval: IntervalMonthly, start: start, after: start, expected: date(2024, time.February, 29, 0, 0)},
+		{name: "monthly back to the 31st", interval: IntervalMonthly, start: start, after: date(2024, time.April, 30, 0, 0), expected: date(2024, time.May, 31, 0, 0)},
+		{name: "monthly on the occurrence", interval: IntervalMonthly, start: start, after: date(2024, time.March, 31, 0, 0), expected: date(2024, time.April, 30, 0, 0)},
+		{name: "quarterly", interval: IntervalQuarterly, start: start, after: date(2024, time.February, 1, 0, 0), expected: date(2024, time.April, 30, 0, 0)},
+		{name: "yearly across years", interval: IntervalYearly, start: date(2024, time.February, 29, 0, 0), after: date(2024, time.March, 1, 0, 0), expected: date(2025, time.February, 28, 0, 0)},
+		{name: "weekly", interval: IntervalWeekly, start: date(2024, time.March, 4, 0, 0), after: date(2024, time.March, 11, 0, 0), expected: date(2024, time.March, 18, 0, 0)},
+		{name: "cron first of the month", interval: IntervalCron, cron: "0 9 1 * *", start: start, after: start, expected: date(2024, time.February, 1, 9, 0)},
+		{name: "cron not before start", interval: IntervalCron, cron: "0 9 * * *", start: start, after: date(2023, time.June, 1, 0, 0), expected: date(2024, time.January, 31, 9, 0)},
+		{name: "cron bad expression", interval: IntervalCron, cron: "0 9 1 *", start: start, after: start, err: ErrInvalidCron},
+		{name: "unknown interval", interval: "daily", start: start, after: start, err: ErrInvalidInterval},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Next(testCase.interval, testCase.cron, testCase.start, testCase.after)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, true, errors.Is(err, testCase.err))
+		})
+	}
+}
+
+func TestSchedule_First(t *testing.T) {
+	start := date(2024, time.March, 1, 0, 0)
+
+	got, err := First(IntervalMonthly, "", start)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, start, got)
+}
+
+func TestSchedule_CronNext(t *testing.T) {
+	// a Thursday
+	after := date(2024, time.March, 7, 10, 30)
+
+	testCases := []struct {
+		name     string
+		expr     string
+		expected time.Time
+		err      error
+	}{
+		{name: "every minute", expr: "* * * * *", expected: date(2024, time.March, 7, 10, 31)},
+		{name: "step", expr: "*/15 * * * *", expected: date(2024, time.March, 7, 10, 45)},
+		{name: "list and range", expr: "0 8-9,17 * * *", expected: date(2024, time.March, 7, 17, 0)},
+		{name: "weekdays", expr: "0 9 * * 1-5", expected: date(2024, time.March, 8, 9, 0)},
+		{name: "sunday as 7", expr: "0 9 * * 7", expected: date(2024, time.March, 10, 9, 0)},
+		{name: "day of month or day of week", expr: "0 0 15 * 6", expected: date(2024, time.March, 9, 0, 0)},
+		{name: "next year", expr: "0 0 1 1 *", expected: date(2025, time.January, 1, 0, 0)},
+		{name: "leap day", expr: "0 0 29 2 *", expected: date(2028, time.February, 29, 0, 0)},
+		{name: "never", expr: "0 0 30 2 *", err: ErrNoOccurrence},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c, err := ParseCron(testCase.expr)
+			assert.Equal(t, nil, err)
+
+			got, err := c.Next(after)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, err)
+		})
+	}
+}
+
+func TestSchedule_ParseCron(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 9 * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+
+			assert.Equal(t, true, errors.Is(err, ErrInvalidCron))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+type BatchInvoiceRequest struct {
+	// Atomic creates every invoice or none of them
+	Atomic  bool
+	Entries []BatchEntry
+}
+
+// BatchEntry is one invoice of a batch. Err is set when the entry is not a valid invoice
+// request, the entry is then reported instead of created.
+type BatchEntry struct {
+	Request InvoiceRequest
+	Err     error
+}
+
+// InvalidEntryError wraps why a batch entry is not a valid invoice request.
+type InvalidEntryError struct {
+	Err error
+}
+
+func (e *InvalidEntryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidEntryError) Unwrap() error {
+	return e.Err
+}
+
+// BatchResult is the outcome of one batch entry, in the order of the request.
+type BatchResult struct {
+	InvoiceID string
+	Err       error
+}
+
+type BatchInvoiceResponse struct {
+	Atomic  bool                 `json:"atomic"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Results []BatchEntryResponse `json:"results"`
+}
+
+type BatchEntryResponse struct {
+	Index     int         `json:"index"`
+	InvoiceID string      `json:"invoice_id,omitempty"`
+	Error     interface{} `json:"error,omitempty"`
+}
+
+// BuildAndValidateBatchRequest reads a JSON array of invoice requests and the optional atomic
+// flag. Every entry is validated like BuildAndValidateInvoiceRequest; an invalid entry does not
+// fail the request, it is returned with its error.
+func BuildAndValidateBatchRequest(r *http.Request, maxSize int) (BatchInvoiceRequest, error) {
+	var payload BatchInvoiceRequest
+
+	if atomic := r.URL.Query().Get("atomic"); atomic != "" {
+		var err error
+		if payload.Atomic, err = strconv.ParseBool(atomic); err != nil {
+			return payload, err
+		}
+	}
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(bodyByte, &entries); err != nil {
+		log.Println("unmarshal request body err: ", err)
+		return payload, err
+	}
+
+	if len(entries) == 0 {
+		return payload, errors.New("batch is empty")
+	}
+
+	if len(entries) > maxSize {
+		return payload, errorss.ErrInvoiceBatchTooLarge
+	}
+
+	payload.Entries = make([]BatchEntry, len(entries))
+	for i, entry := range entries {
+		if err := json.Unmarshal(entry, &payload.Entries[i].Request); err != nil {
+			payload.Entries[i].Err = &InvalidEntryError{Err: err}
+			continue
+		}
+
+		if err := validateInvoiceRequest(&payload.Entries[i].Request); err != nil {
+			payload.Entries[i].Err = &InvalidEntryError{Err: err}
+		}
+	}
+
+	return payload, nil
+}
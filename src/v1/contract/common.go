@@ -1,12 +1,70 @@
 package contract
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
 	"github.com/go-chi/chi/v5"
 )
 
+// ListDateLayout is the date format of the list filters, the same one used by invoice requests.
+const ListDateLayout = "02-01-2006"
+
+// InvoiceSortFields are the fields accepted by the sort query parameter.
+var InvoiceSortFields = []string{"invoice_id", "issue_date", "due_date", "subject", "total_items", "customer", "status", "grand_total", "amount_paid", "created_at"}
+
+var invoiceStatuses = []string{
+	entity.InvoiceStatusDraft,
+	entity.InvoiceStatusIssued,
+	entity.InvoiceStatusPartiallyPaid,
+	entity.InvoiceStatusPaid,
+	entity.InvoiceStatusOverpaid,
+	entity.InvoiceStatusOverdue,
+	entity.InvoiceStatusVoid,
+	entity.InvoiceStatusCredited,
+}
+
+// Cursor points at the last invoice of a page in cursor mode. Clients get it back as an
+// opaque string and must not build it themselves.
+type Cursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+
+	return cursor, nil
+}
+
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
 type GetListParam struct {
 	Page      int    `json:"page" db:"page"`
 	Limit     int    `json:"limit" db:"limit"`
@@ -18,7 +76,22 @@ type GetListParam struct {
 	TotalItem int    `json:"total_item" db:"total_item"`
 	Customer  string `json:"customer" db:"customer"`
 	DueDate   string `json:"due_date" db:"due_date"`
-	Status    string `json:"status" db:"status"`
+
+	// ranges are inclusive, a zero value means the bound is not set
+	IssueDateFrom time.Time        `json:"issue_date_from" db:"issue_date_from"`
+	IssueDateTo   time.Time        `json:"issue_date_to" db:"issue_date_to"`
+	DueDateFrom   time.Time        `json:"due_date_from" db:"due_date_from"`
+	DueDateTo     time.Time        `json:"due_date_to" db:"due_date_to"`
+	GrandTotalMin *decimal.Decimal `json:"grand_total_min" db:"grand_total_min"`
+	GrandTotalMax *decimal.Decimal `json:"grand_total_max" db:"grand_total_max"`
+
+	Statuses []string    `json:"statuses" db:"-"`
+	Sort     []SortField `json:"sort" db:"-"`
+
+	// CursorMode pages by (created_at, id) after the After cursor instead of page and offset.
+	// It is set by the after query parameter, an empty value starts from the first invoice.
+	CursorMode bool   `json:"cursor_mode" db:"-"`
+	After      Cursor `json:"after" db:"-"`
 }
 
 // ValidateQuery return common converted parameter from query parameter for get list data
@@ -27,6 +100,9 @@ type GetListParam struct {
 // limit is limit data loaded per page, offset is number data skiped when loaded data
 // data page and limit from query parameter is always number in string
 // its need to converted to int, it will return error if page and limit is not a number
+// dates use the dd-mm-yyyy layout, status takes a comma separated list and sort a comma
+// separated list of fields where a leading minus sorts descending, e.g. sort=-due_date,grand_total
+// after switches to cursor pagination, which always orders by creation and cannot be sorted
 func ValidateAndBuildRequest(r *http.Request) (getListParam *GetListParam, err error) {
 	// default value for page and limit
 	page, limit := 1, 10
@@ -43,7 +119,6 @@ func ValidateAndBuildRequest(r *http.Request) (getListParam *GetListParam, err e
 	TotalItem := queryParams.Get("total_item")
 	Customer := queryParams.Get("customer")
 	DueDate := queryParams.Get("due_date")
-	Status := queryParams.Get("status")
 
 	// query param validation
 	if pageQuery != "" {
@@ -60,6 +135,11 @@ func ValidateAndBuildRequest(r *http.Request) (getListParam *GetListParam, err e
 		}
 	}
 
+	if page < 1 || limit < 1 {
+		err = errors.New("page and limit must be positive")
+		return
+	}
+
 	if TotalItem != "" {
 		item, err = strconv.Atoi(TotalItem)
 		if err != nil {
@@ -67,6 +147,66 @@ func ValidateAndBuildRequest(r *http.Request) (getListParam *GetListParam, err e
 		}
 	}
 
+	if IssueDate, err = normalizeListDate(IssueDate); err != nil {
+		return
+	}
+
+	if DueDate, err = normalizeListDate(DueDate); err != nil {
+		return
+	}
+
+	var issueDateFrom, issueDateTo, dueDateFrom, dueDateTo time.Time
+	for _, date := range []struct {
+		query string
+		dest  *time.Time
+	}{
+		{"issue_date_from", &issueDateFrom},
+		{"issue_date_to", &issueDateTo},
+		{"due_date_from", &dueDateFrom},
+		{"due_date_to", &dueDateTo},
+	} {
+		if value := queryParams.Get(date.query); value != "" {
+			if *date.dest, err = time.Parse(ListDateLayout, value); err != nil {
+				return
+			}
+		}
+	}
+
+	grandTotalMin, err := parseListDecimal(queryParams.Get("grand_total_min"))
+	if err != nil {
+		return
+	}
+
+	grandTotalMax, err := parseListDecimal(queryParams.Get("grand_total_max"))
+	if err != nil {
+		return
+	}
+
+	statuses, err := parseListStatuses(queryParams.Get("status"))
+	if err != nil {
+		return
+	}
+
+	sort, err := parseListSort(queryParams.Get("sort"))
+	if err != nil {
+		return
+	}
+
+	var after Cursor
+	cursorMode := queryParams.Has("after")
+	if cursorMode {
+		if len(sort) > 0 {
+			err = errors.New("sort is not supported with cursor pagination")
+			return
+		}
+
+		if value := queryParams.Get("after"); value != "" {
+			if after, err = DecodeCursor(value); err != nil {
+				return
+			}
+		}
+	}
+
 	// offset for OFFSET in get list query
 	offset := (page - 1) * limit
 	getListParam = &GetListParam{
@@ -80,14 +220,112 @@ func ValidateAndBuildRequest(r *http.Request) (getListParam *GetListParam, err e
 		TotalItem: item,
 		Customer:  Customer,
 		DueDate:   DueDate,
-		Status:    Status,
+
+		IssueDateFrom: issueDateFrom,
+		IssueDateTo:   issueDateTo,
+		DueDateFrom:   dueDateFrom,
+		DueDateTo:     dueDateTo,
+		GrandTotalMin: grandTotalMin,
+		GrandTotalMax: grandTotalMax,
+
+		Statuses: statuses,
+		Sort:     sort,
+
+		CursorMode: cursorMode,
+		After:      after,
 	}
 
 	return
 }
 
+// normalizeListDate rewrites a dd-mm-yyyy date to yyyy-mm-dd, which the database reads
+// the same way whatever its DateStyle is.
+func normalizeListDate(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	date, err := time.Parse(ListDateLayout, value)
+	if err != nil {
+		return "", err
+	}
+
+	return date.Format("2006-01-02"), nil
+}
+
+func parseListDecimal(value string) (*decimal.Decimal, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func parseListStatuses(value string) ([]string, error) {
+	statuses := splitList(value)
+
+	for i, status := range statuses {
+		known := false
+		for _, s := range invoiceStatuses {
+			if strings.EqualFold(status, s) {
+				statuses[i] = s
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return nil, fmt.Errorf("unknown status %q", status)
+		}
+	}
+
+	return statuses, nil
+}
+
+func parseListSort(value string) ([]SortField, error) {
+	var sort []SortField
+
+	for _, field := range splitList(value) {
+		sortField := SortField{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+
+		known := false
+		for _, f := range InvoiceSortFields {
+			if sortField.Field == f {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return nil, fmt.Errorf("unknown sort field %q", sortField.Field)
+		}
+
+		sort = append(sort, sortField)
+	}
+
+	return sort, nil
+}
+
+// ValidateIDParamRequest returns the invoice id from the path. Numbers from series templates
+// may contain slashes, which clients send escaped as %2F.
 func ValidateIDParamRequest(r *http.Request) (id string, err error) {
 	idParam := chi.URLParam(r, "id")
 
-	return idParam, nil
+	return url.PathUnescape(idParam)
 }
@@ -0,0 +1,85 @@
+package contract
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
+
+// CreditNoteRequest credits an issued invoice. Without items every quantity not credited yet is
+// credited, a full credit note. Otherwise each item credits part of a line of the invoice.
+type CreditNoteRequest struct {
+	IssueDate string                  `json:"issue_date" validate:"required"`
+	Reason    string                  `json:"reason" validate:"required,max=255"`
+	Items     []CreditNoteItemRequest `json:"items" validate:"dive"`
+}
+
+type CreditNoteItemRequest struct {
+	// ItemID is the item of the invoice the quantity is credited from
+	ItemID   uuid.UUID       `json:"item_id" validate:"required"`
+	Quantity decimal.Decimal `json:"quantity" validate:"gt=0"`
+}
+
+type CreditNoteItemResponse struct {
+	ItemID    uuid.UUID       `json:"item_id"`
+	Name      string          `json:"name"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+type CreditNoteResponse struct {
+	CreditNoteID string                   `json:"credit_note_id"`
+	InvoiceID    string                   `json:"invoice_id"`
+	IssueDate    string                   `json:"issue_date"`
+	Reason       string                   `json:"reason"`
+	Items        []CreditNoteItemResponse `json:"items"`
+	SubTotal     decimal.Decimal          `json:"sub_total"`
+	Tax          decimal.Decimal          `json:"tax"`
+	GrandTotal   decimal.Decimal          `json:"grand_total"`
+}
+
+type InvoiceCreditNoteResponse struct {
+	InvoiceID          string          `json:"invoice_id"`
+	CreditNoteID       string          `json:"credit_note_id"`
+	Status             string          `json:"status"`
+	AmountCredited     decimal.Decimal `json:"amount_credited"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+}
+
+func BuildAndValidateCreditNoteRequest(r *http.Request) (CreditNoteRequest, error) {
+	var payload CreditNoteRequest
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	if err := json.Unmarshal(bodyByte, &payload); err != nil {
+		log.Println("unmarshal request body err: ", err)
+		return payload, err
+	}
+
+	payload.Reason = strings.TrimSpace(payload.Reason)
+
+	validator := newValidator()
+
+	if err := validator.Struct(payload); err != nil {
+		log.Println("validate request body err: ", err)
+		return payload, err
+	}
+
+	if _, err := time.Parse("02-01-2006", payload.IssueDate); err != nil {
+		log.Println("parse issue date err: ", err)
+		return payload, err
+	}
+
+	return payload, nil
+}
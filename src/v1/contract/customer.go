@@ -1,6 +1,139 @@
 package contract
 
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	frsUtils "github.com/Risuii/frs-lib/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var taxNumberSeparators = strings.NewReplacer(".", "", "-", "", " ", "")
+
 type CustomerRequest struct {
-	CustomerName string `json:"customer_name" validate:"required"`
-	Address      string `json:"address" validate:"required"`
+	CustomerName string `json:"customer_name" validate:"required,max=255"`
+	Address      string `json:"address" validate:"required,max=255"`
+	// optional, the 15 digit NPWP or the 16 digit NIK used as NPWP
+	NPWP string `json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
+	// optional, needed to send the customer UBL e-invoices
+	EndpointID     string `json:"endpoint_id" validate:"max=255"`
+	EndpointScheme string `json:"endpoint_scheme" validate:"required_with=EndpointID,omitempty,len=4,numeric"`
+	CountryCode    string `json:"country_code" validate:"omitempty,len=2,alpha"`
+	// optional, payment reminders are mailed to it
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type CustomerResponse struct {
+	CustomerID     uuid.UUID `json:"customer_id"`
+	Name           string    `json:"name"`
+	Address        string    `json:"address"`
+	NPWP           string    `json:"npwp"`
+	EndpointID     string    `json:"endpoint_id"`
+	EndpointScheme string    `json:"endpoint_scheme"`
+	CountryCode    string    `json:"country_code"`
+	Email          string    `json:"email"`
+	DunningPaused  bool      `json:"dunning_paused"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ListCustomerResponse struct {
+	Data       []*CustomerResponse
+	Pagination *frsUtils.Pagination
+}
+
+type GetListCustomerParam struct {
+	Page    int    `json:"page" db:"page"`
+	Limit   int    `json:"limit" db:"limit"`
+	Offset  int    `json:"offset" db:"offset"`
+	Keyword string `json:"keyword" db:"keyword"`
+}
+
+func BuildAndValidateCustomerRequest(r *http.Request) (CustomerRequest, error) {
+	var payload CustomerRequest
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	if err := json.Unmarshal(bodyByte, &payload); err != nil {
+		log.Println("unmarshal request body err: ", err)
+		return payload, err
+	}
+
+	payload.CustomerName = strings.ToLower(strings.TrimSpace(payload.CustomerName))
+	payload.Address = strings.TrimSpace(payload.Address)
+	payload.NPWP = TaxNumberDigits(payload.NPWP)
+	payload.EndpointID = strings.TrimSpace(payload.EndpointID)
+	payload.EndpointScheme = strings.TrimSpace(payload.EndpointScheme)
+	payload.CountryCode = strings.ToUpper(strings.TrimSpace(payload.CountryCode))
+	payload.Email = strings.ToLower(strings.TrimSpace(payload.Email))
+
+	validator := newValidator()
+
+	if err := validator.Struct(payload); err != nil {
+		log.Println("validate request body err: ", err)
+		return payload, err
+	}
+
+	return payload, nil
+}
+
+// ValidateAndBuildCustomerListRequest reads page, limit and the keyword matched against
+// customer name and address.
+func ValidateAndBuildCustomerListRequest(r *http.Request) (getListParam *GetListCustomerParam, err error) {
+	// default value for page and limit
+	page, limit := 1, 10
+
+	queryParams := r.URL.Query()
+	limitQuery := queryParams.Get("limit")
+	pageQuery := queryParams.Get("page")
+	keyword := strings.TrimSpace(queryParams.Get("keyword"))
+
+	if pageQuery != "" {
+		page, err = strconv.Atoi(pageQuery)
+		if err != nil {
+			return
+		}
+	}
+
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil {
+			return
+		}
+	}
+
+	if page < 1 || limit < 1 {
+		err = errors.New("page and limit must be positive")
+		return
+	}
+
+	getListParam = &GetListCustomerParam{
+		Page:    page,
+		Limit:   limit,
+		Offset:  (page - 1) * limit,
+		Keyword: keyword,
+	}
+
+	return
+}
+
+// TaxNumberDigits strips the separators of a formatted tax number such as
+// 01.234.567.8-901.000, DJP files take the digits only.
+func TaxNumberDigits(s string) string {
+	return taxNumberSeparators.Replace(strings.TrimSpace(s))
+}
+
+func ValidateCustomerIDParamRequest(r *http.Request) (uuid.UUID, error) {
+	return uuid.Parse(chi.URLParam(r, "id"))
 }
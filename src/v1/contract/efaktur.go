@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// EFakturParam is the issue date range of an e-Faktur export, both days included.
+type EFakturParam struct {
+	From time.Time
+	To   time.Time
+}
+
+// ValidateAndBuildEFakturRequest reads the required from and to query parameters, in the
+// ListDateLayout format.
+func ValidateAndBuildEFakturRequest(r *http.Request) (params EFakturParam, err error) {
+	queryParams := r.URL.Query()
+
+	if params.From, err = time.Parse(ListDateLayout, queryParams.Get("from")); err != nil {
+		return
+	}
+
+	if params.To, err = time.Parse(ListDateLayout, queryParams.Get("to")); err != nil {
+		return
+	}
+
+	if params.To.Before(params.From) {
+		err = errors.New("to must not be before from")
+		return
+	}
+
+	return
+}
+
+// TaxInvoiceNumberRequest records the tax invoice number DJP assigned to an issued invoice.
+type TaxInvoiceNumberRequest struct {
+	TaxInvoiceNumber string `json:"tax_invoice_number" validate:"required,numeric,len=13"`
+}
+
+type TaxInvoiceNumberResponse struct {
+	InvoiceID        string `json:"invoice_id"`
+	TaxInvoiceNumber string `json:"tax_invoice_number"`
+}
+
+func BuildAndValidateTaxInvoiceNumberRequest(r *http.Request) (TaxInvoiceNumberRequest, error) {
+	var payload TaxInvoiceNumberRequest
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	if err := json.Unmarshal(bodyByte, &payload); err != nil {
+		log.Println("unmarshal request body err: ", err)
+		return payload, err
+	}
+
+	payload.TaxInvoiceNumber = TaxNumberDigits(payload.TaxInvoiceNumber)
+
+	validator := newValidator()
+
+	if err := validator.Struct(payload); err != nil {
+		log.Println("validate request body err: ", err)
+		return payload, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,44 @@
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// AnyVersion is the version of an If-Match: * header, it matches whatever version is stored.
+const AnyVersion int64 = 0
+
+// InvoiceETag returns the entity tag of an invoice version.
+func InvoiceETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ValidateIfMatchRequest returns the invoice version sent in the If-Match header, AnyVersion for
+// "*". The header is required so an update is never applied over changes the client has not
+// seen. If-Match compares strongly, a weak tag is rejected like any other malformed value.
+func ValidateIfMatchRequest(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, errorss.ErrInvoiceIfMatchRequired
+	}
+
+	if value == "*" {
+		return AnyVersion, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, fmt.Errorf("invalid If-Match %q", value)
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid If-Match %q", value)
+	}
+
+	return version, nil
+}
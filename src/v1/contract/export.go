@@ -0,0 +1,41 @@
+package contract
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// ExportParam is how the invoice list is exported.
+type ExportParam struct {
+	Format       string
+	IncludeItems bool
+}
+
+// ValidateAndBuildExportRequest reads the list filters the same way as the invoice list,
+// along with the required format and the optional include_items flag. page, limit and after
+// do not apply, an export has every matching invoice.
+func ValidateAndBuildExportRequest(r *http.Request) (getListParam *GetListParam, exportParam ExportParam, err error) {
+	getListParam, err = ValidateAndBuildRequest(r)
+	if err != nil {
+		return
+	}
+
+	queryParams := r.URL.Query()
+
+	exportParam.Format = queryParams.Get("format")
+	switch exportParam.Format {
+	case "csv", "xlsx":
+	default:
+		err = errors.New("format must be csv or xlsx")
+		return
+	}
+
+	if includeItems := queryParams.Get("include_items"); includeItems != "" {
+		if exportParam.IncludeItems, err = strconv.ParseBool(includeItems); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,330 @@
+package contract
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// MaxImportSize is the largest import file accepted over HTTP, bigger migrations go through
+// the import command.
+const MaxImportSize = 10 << 20
+
+// importColumns are the columns of an import file. The file has one row per line item, the
+// rows of an invoice share its reference and only the first of them needs the invoice columns.
+var importColumns = []string{
+	"reference", "subject", "issue_date", "due_date", "tax_rate", "series", "tax_invoice_number",
+	"customer_id", "customer_name", "customer_address", "customer_npwp",
+	"item_name", "item_type", "quantity", "unit_price",
+}
+
+var requiredImportColumns = []string{"reference", "subject", "issue_date", "due_date", "quantity", "unit_price"}
+
+// invoiceImportColumns must agree on every row of an invoice.
+var invoiceImportColumns = []string{
+	"subject", "issue_date", "due_date", "tax_rate", "series", "tax_invoice_number",
+	"customer_id", "customer_name", "customer_address", "customer_npwp",
+}
+
+// importFieldColumns names the column behind a field of InvoiceRequest in validation errors.
+var importFieldColumns = map[string]string{
+	"Subject":          "subject",
+	"IssueDate":        "issue_date",
+	"DueDate":          "due_date",
+	"TaxRate":          "tax_rate",
+	"Series":           "series",
+	"TaxInvoiceNumber": "tax_invoice_number",
+	"CustomerRequest":  "customer_name",
+	"CustomerName":     "customer_name",
+	"Address":          "customer_address",
+	"NPWP":             "customer_npwp",
+	"Name":             "item_name",
+	"Type":             "item_type",
+	"Quantity":         "quantity",
+	"UnitPrice":        "unit_price",
+}
+
+type ImportRequest struct {
+	DryRun   bool
+	Invoices []ImportInvoice
+	// Errors are the rows that do not belong to any invoice
+	Errors []ImportError
+}
+
+// ImportInvoice is one invoice of an import file. An invoice with errors is not imported.
+type ImportInvoice struct {
+	Reference string
+	Rows      []int
+	Request   InvoiceRequest
+	Errors    []ImportError
+}
+
+type ImportError struct {
+	Row       int    `json:"row"`
+	Reference string `json:"reference"`
+	Message   string `json:"message"`
+}
+
+type ImportResult struct {
+	Reference string `json:"reference"`
+	Rows      []int  `json:"rows"`
+	// InvoiceID is empty on a dry run
+	InvoiceID string `json:"invoice_id"`
+}
+
+type ImportResponse struct {
+	DryRun bool `json:"dry_run"`
+	Total  int  `json:"total"`
+	// Imported counts the invoices created, or the ones that would be on a dry run
+	Imported int            `json:"imported"`
+	Failed   int            `json:"failed"`
+	Invoices []ImportResult `json:"invoices"`
+	Errors   []ImportError  `json:"errors"`
+}
+
+// BuildAndValidateImportRequest reads the CSV body of an import and the optional dry_run flag.
+func BuildAndValidateImportRequest(w http.ResponseWriter, r *http.Request) (ImportRequest, error) {
+	var dryRun bool
+
+	if dryRunQuery := r.URL.Query().Get("dry_run"); dryRunQuery != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(dryRunQuery); err != nil {
+			return ImportRequest{}, err
+		}
+	}
+
+	request, err := ParseImportCSV(http.MaxBytesReader(w, r.Body, MaxImportSize))
+	if err != nil {
+		log.Println("parse import file err: ", err)
+		return request, err
+	}
+
+	request.DryRun = dryRun
+
+	return request, nil
+}
+
+// ParseImportCSV groups the rows of an import file into invoices, in the order their references
+// first appear, and validates each of them with the rules of BuildAndValidateInvoiceRequest.
+// Problems with single rows are reported on the invoice, the error is only for a file that
+// cannot be read at all.
+func ParseImportCSV(r io.Reader) (ImportRequest, error) {
+	var request ImportRequest
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return request, fmt.Errorf("read import header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// spreadsheets often save CSV with a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return request, fmt.Errorf("import file has no %s column", name)
+		}
+	}
+
+	byReference := make(map[string]int)
+	firstValues := make(map[string]map[string]string)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return request, fmt.Errorf("read import file: %w", err)
+		}
+
+		row, _ := reader.FieldPos(0)
+
+		values := make(map[string]string, len(importColumns))
+		for _, name := range importColumns {
+			if i, ok := columns[name]; ok && i < len(record) {
+				values[name] = strings.TrimSpace(record[i])
+			}
+		}
+
+		reference := values["reference"]
+		if reference == "" {
+			request.Errors = append(request.Errors, ImportError{Row: row, Message: "reference is required"})
+			continue
+		}
+
+		index, ok := byReference[reference]
+		if !ok {
+			index = len(request.Invoices)
+			byReference[reference] = index
+			firstValues[reference] = values
+
+			invoice := ImportInvoice{Reference: reference}
+			if err := buildImportInvoice(&invoice.Request, values); err != nil {
+				invoice.Errors = append(invoice.Errors, ImportError{Row: row, Reference: reference, Message: err.Error()})
+			}
+
+			request.Invoices = append(request.Invoices, invoice)
+		}
+
+		invoice := &request.Invoices[index]
+		invoice.Rows = append(invoice.Rows, row)
+
+		if ok {
+			if err := checkImportInvoiceColumns(firstValues[reference], values); err != nil {
+				invoice.Errors = append(invoice.Errors, ImportError{Row: row, Reference: reference, Message: err.Error()})
+				continue
+			}
+		}
+
+		item, err := buildImportItem(values)
+		if err != nil {
+			invoice.Errors = append(invoice.Errors, ImportError{Row: row, Reference: reference, Message: err.Error()})
+			continue
+		}
+
+		invoice.Request.ItemRequest = append(invoice.Request.ItemRequest, item)
+	}
+
+	for i := range request.Invoices {
+		invoice := &request.Invoices[i]
+		if len(invoice.Errors) > 0 {
+			continue
+		}
+
+		if err := validateInvoiceRequest(&invoice.Request); err != nil {
+			invoice.Errors = append(invoice.Errors, ImportError{Row: invoice.Rows[0], Reference: invoice.Reference, Message: describeImportError(err)})
+		}
+	}
+
+	return request, nil
+}
+
+func buildImportInvoice(payload *InvoiceRequest, values map[string]string) error {
+	payload.Subject = values["subject"]
+	payload.IssueDate = values["issue_date"]
+	payload.DueDate = values["due_date"]
+	payload.Series = values["series"]
+	payload.TaxInvoiceNumber = values["tax_invoice_number"]
+
+	// the JSON body does not check the dates, an import of old invoices should not guess them
+	for _, name := range []string{"issue_date", "due_date"} {
+		if values[name] == "" {
+			continue
+		}
+		if _, err := time.Parse(ListDateLayout, values[name]); err != nil {
+			return fmt.Errorf("%s: want a dd-mm-yyyy date", name)
+		}
+	}
+
+	if taxRate := values["tax_rate"]; taxRate != "" {
+		d, err := decimal.NewFromString(taxRate)
+		if err != nil {
+			return fmt.Errorf("tax_rate: %w", err)
+		}
+		payload.TaxRate = d
+	}
+
+	if customerID := values["customer_id"]; customerID != "" {
+		id, err := uuid.Parse(customerID)
+		if err != nil {
+			return fmt.Errorf("customer_id: %w", err)
+		}
+		payload.CustomerID = id
+		return nil
+	}
+
+	if values["customer_name"] != "" || values["customer_address"] != "" || values["customer_npwp"] != "" {
+		payload.CustomerRequest = &CustomerRequest{
+			CustomerName: values["customer_name"],
+			Address:      values["customer_address"],
+			NPWP:         values["customer_npwp"],
+		}
+	}
+
+	return nil
+}
+
+func buildImportItem(values map[string]string) (ItemRequest, error) {
+	item := ItemRequest{
+		Name: values["item_name"],
+		Type: values["item_type"],
+	}
+
+	quantity, err := decimal.NewFromString(values["quantity"])
+	if err != nil {
+		return item, fmt.Errorf("quantity: %w", err)
+	}
+	item.Quantity = quantity
+
+	unitPrice, err := decimal.NewFromString(values["unit_price"])
+	if err != nil {
+		return item, fmt.Errorf("unit_price: %w", err)
+	}
+	item.UnitPrice = unitPrice
+
+	validator := newValidator()
+
+	if err := validator.Struct(item); err != nil {
+		return item, errors.New(describeImportError(err))
+	}
+
+	return item, nil
+}
+
+// checkImportInvoiceColumns rejects a row whose invoice columns contradict the first row of
+// its invoice. Leaving them empty is fine.
+func checkImportInvoiceColumns(first, values map[string]string) error {
+	for _, name := range invoiceImportColumns {
+		if values[name] != "" && values[name] != first[name] {
+			return fmt.Errorf("%s differs from the first row of the invoice", name)
+		}
+	}
+
+	return nil
+}
+
+// describeImportError names the columns of the fields failing validation, e.g.
+// "quantity: gt=0".
+func describeImportError(err error) string {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err.Error()
+	}
+
+	messages := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		column, ok := importFieldColumns[fieldErr.StructField()]
+		if !ok {
+			column = fieldErr.Namespace()
+		}
+
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+
+		messages = append(messages, column+": "+rule)
+	}
+
+	return strings.Join(messages, ", ")
+}
@@ -11,56 +11,76 @@ import (
 	"unicode"
 
 	frsUtils "github.com/Risuii/frs-lib/utils"
-	"github.com/go-playground/validator/v10"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
 )
 
 type Invoice struct {
-	InvoiceID    string    `json:"invoice_id"`
-	IssueDate    string    `json:"issue_date"`
-	Subject      string    `json:"subject"`
-	TotalItem    int       `json:"total_item"`
-	CustomerName string    `json:"customer_name"`
-	DueDate      string    `json:"due_date"`
-	Status       string    `json:"status"`
-	SubTotal     float64   `json:"sub_total"`
-	Tax          float64   `json:"tax"`
-	GrandTotal   float64   `json:"grand_total"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	InvoiceID    string          `json:"invoice_id"`
+	IssueDate    string          `json:"issue_date"`
+	Subject      string          `json:"subject"`
+	TotalItem    int             `json:"total_item"`
+	CustomerName string          `json:"customer_name"`
+	DueDate      string          `json:"due_date"`
+	Status       string          `json:"status"`
+	SubTotal     decimal.Decimal `json:"sub_total"`
+	Tax          decimal.Decimal `json:"tax"`
+	GrandTotal   decimal.Decimal `json:"grand_total"`
+	DaysOverdue  int             `json:"days_overdue"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
 }
 
 type ListInvoiceResponse struct {
 	Data       []*Invoice
 	Pagination *frsUtils.Pagination
+	// NextCursor is only set in cursor mode, when there are more invoices after this page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type InvoiceResponse struct {
-	InvoiceID    string         `json:"invoice_id"`
-	IssueDate    string         `json:"issue_date"`
-	Subject      string         `json:"subject"`
-	TotalItem    int            `json:"total_item"`
-	Items        []ItemResponse `json:"item"`
-	CustomerName string         `json:"customer_name"`
-	DueDate      string         `json:"due_date"`
-	Status       string         `json:"status"`
-	SubTotal     float64        `json:"sub_total"`
-	Tax          float64        `json:"tax"`
-	GrandTotal   float64        `json:"grand_total"`
+	InvoiceID          string            `json:"invoice_id"`
+	IssueDate          string            `json:"issue_date"`
+	Subject            string            `json:"subject"`
+	TotalItem          int               `json:"total_item"`
+	Items              []ItemResponse    `json:"item"`
+	CustomerName       string            `json:"customer_name"`
+	CustomerAddress    string            `json:"customer_address"`
+	DueDate            string            `json:"due_date"`
+	Status             string            `json:"status"`
+	DaysOverdue        int               `json:"days_overdue"`
+	SubTotal           decimal.Decimal   `json:"sub_total"`
+	TaxRate            decimal.Decimal   `json:"tax_rate"`
+	Tax                decimal.Decimal   `json:"tax"`
+	GrandTotal         decimal.Decimal   `json:"grand_total"`
+	AmountCredited     decimal.Decimal   `json:"amount_credited"`
+	AmountPaid         decimal.Decimal   `json:"amount_paid"`
+	OutstandingBalance decimal.Decimal   `json:"outstanding_balance"`
+	Payments           []PaymentResponse `json:"payments"`
+	TaxInvoiceNumber   string            `json:"tax_invoice_number"`
+	Version            int64             `json:"version"`
 }
 
 type InvoiceRequest struct {
-	Subject         string          `json:"subject" validate:"required"`
-	IssueDate       string          `json:"issue_date" validate:"required"`
-	DueDate         string          `json:"due_date" validate:"required"`
-	SubTotal        float64         `json:"sub_total"`
-	Tax             float64         `json:"tax"`
-	GrandTotal      float64         `json:"grand_total"`
-	CustomerRequest CustomerRequest `json:"customer_request"`
-	ItemRequest     []ItemRequest   `json:"item_request"`
+	Subject    string          `json:"subject" validate:"required"`
+	IssueDate  string          `json:"issue_date" validate:"required"`
+	DueDate    string          `json:"due_date" validate:"required"`
+	SubTotal   decimal.Decimal `json:"sub_total"`
+	TaxRate    decimal.Decimal `json:"tax_rate" validate:"gte=0,lte=100"`
+	Tax        decimal.Decimal `json:"tax"`
+	GrandTotal decimal.Decimal `json:"grand_total"`
+	Series     string          `json:"series" validate:"max=20"`
+	// optional, the e-Faktur serial given by DJP
+	TaxInvoiceNumber string           `json:"tax_invoice_number" validate:"omitempty,numeric,len=13"`
+	CustomerID       uuid.UUID        `json:"customer_id"`
+	CustomerRequest  *CustomerRequest `json:"customer_request" validate:"required_without=CustomerID"`
+	ItemRequest      []ItemRequest    `json:"item_request" validate:"dive"`
 }
 
 type InvcResponse struct {
 	InvoiceID string `json:"invoice_id"`
+	// Version is the version after an update, the ETag of the updated invoice
+	Version int64 `json:"version,omitempty"`
 }
 
 type InvoiceResponseDB struct {
@@ -91,15 +111,70 @@ func BuildAndValidateInvoiceRequest(r *http.Request) (InvoiceRequest, error) {
 		return payload, err
 	}
 
+	if err := validateInvoiceRequest(&payload); err != nil {
+		log.Println("validate request body err: ", err)
+		return payload, err
+	}
+
+	return payload, nil
+}
+
+// validateInvoiceRequest normalizes and validates an invoice however it was sent, the JSON body
+// and the import file go through the same rules.
+func validateInvoiceRequest(payload *InvoiceRequest) error {
 	payload.Subject = strings.ToLower(payload.Subject)
-	payload.CustomerRequest.CustomerName = strings.ToLower(payload.CustomerRequest.CustomerName)
+	payload.Series = strings.ToUpper(strings.TrimSpace(payload.Series))
+	payload.TaxInvoiceNumber = TaxNumberDigits(payload.TaxInvoiceNumber)
+	if payload.CustomerRequest != nil {
+		payload.CustomerRequest.CustomerName = strings.ToLower(payload.CustomerRequest.CustomerName)
+		payload.CustomerRequest.CountryCode = strings.ToUpper(strings.TrimSpace(payload.CustomerRequest.CountryCode))
+		payload.CustomerRequest.NPWP = TaxNumberDigits(payload.CustomerRequest.NPWP)
+	}
 
 	isSpecial := checkSpecialCharacter(payload.Subject)
 	if isSpecial {
-		return payload, errors.New("there is special characters")
+		return errors.New("there is special characters")
+	}
+
+	validator := newValidator()
+
+	return validator.Struct(payload)
+}
+
+type InvoiceTransitionRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type InvoiceStatusResponse struct {
+	InvoiceID string `json:"invoice_id"`
+	Status    string `json:"status"`
+}
+
+// BuildAndValidateTransitionRequest reads the optional transition body. Voiding and reopening
+// an invoice must be justified, so those callers set reasonRequired.
+func BuildAndValidateTransitionRequest(r *http.Request, reasonRequired bool) (InvoiceTransitionRequest, error) {
+	var payload InvoiceTransitionRequest
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	if len(bodyByte) > 0 {
+		if err := json.Unmarshal(bodyByte, &payload); err != nil {
+			log.Println("unmarshal request body err: ", err)
+			return payload, err
+		}
+	}
+
+	payload.Reason = strings.TrimSpace(payload.Reason)
+
+	if reasonRequired && payload.Reason == "" {
+		return payload, errors.New("reason is required")
 	}
 
-	validator := validator.New()
+	validator := newValidator()
 
 	if err := validator.Struct(payload); err != nil {
 		log.Println("validate request body err: ", err)
@@ -1,30 +1,33 @@
 package contract
 
-import "github.com/google/uuid"
+import (
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
 
 type Item struct {
-	InvoiceID string    `json:"invoice_id"`
-	ItemID    uuid.UUID `json:"item_id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"`
-	Quantity  float64   `json:"quantity"`
-	UnitPrice float64   `json:"unit_price"`
-	Amount    float64   `json:"amount"`
+	InvoiceID string          `json:"invoice_id"`
+	ItemID    uuid.UUID       `json:"item_id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+	Amount    decimal.Decimal `json:"amount"`
 }
 
 type ItemResponse struct {
-	ItemID    uuid.UUID `json:"item_id"`
-	Name      string    `json:"name"`
-	Quantity  float64   `json:"quantity"`
-	UnitPrice float64   `json:"unit_price"`
-	Amount    float64   `json:"amount"`
+	ItemID    uuid.UUID       `json:"item_id"`
+	Name      string          `json:"name"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+	Amount    decimal.Decimal `json:"amount"`
 }
 
 type ItemRequest struct {
-	ItemID    uuid.UUID `json:"item_id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"`
-	Quantity  float64   `json:"quantity"`
-	UnitPrice float64   `json:"unit_price"`
-	Amount    float64   `json:"amount"`
+	ItemID    uuid.UUID       `json:"item_id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Quantity  decimal.Decimal `json:"quantity" validate:"gt=0"`
+	UnitPrice decimal.Decimal `json:"unit_price" validate:"gte=0"`
+	Amount    decimal.Decimal `json:"amount"`
 }
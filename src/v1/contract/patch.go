@@ -0,0 +1,215 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/google/uuid"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// MergePatchContentType is the media type of an RFC 7386 merge patch.
+const MergePatchContentType = "application/merge-patch+json"
+
+// InvoicePatchDocument is the invoice a merge patch is applied to. Items are keyed by item_id so
+// a patch changes or removes (null) one item without resending the others, an item under any
+// other key is added. Totals are not part of it, they are calculated from the patched invoice.
+type InvoicePatchDocument struct {
+	Subject          string               `json:"subject"`
+	IssueDate        string               `json:"issue_date"`
+	DueDate          string               `json:"due_date"`
+	TaxRate          decimal.Decimal      `json:"tax_rate"`
+	TaxInvoiceNumber string               `json:"tax_invoice_number"`
+	CustomerID       uuid.UUID            `json:"customer_id"`
+	CustomerRequest  *CustomerRequest     `json:"customer_request,omitempty"`
+	Items            map[string]ItemPatch `json:"items"`
+}
+
+type ItemPatch struct {
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+}
+
+// BuildAndValidateInvoicePatchRequest reads a merge patch. The body must be a JSON object sent
+// as application/merge-patch+json, plain application/json is accepted too.
+func BuildAndValidateInvoicePatchRequest(r *http.Request) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != MergePatchContentType && mediaType != "application/json") {
+		return nil, errorss.ErrUnsupportedPatchMediaType
+	}
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return nil, err
+	}
+
+	// a patch that is not an object would replace the whole invoice
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(bodyByte, &patch); err != nil || patch == nil {
+		log.Println("unmarshal request body err: ", err)
+		return nil, errors.New("patch is not a JSON object")
+	}
+
+	return bodyByte, nil
+}
+
+// ApplyInvoicePatch applies a merge patch to a stored invoice and returns the result as a full
+// invoice request, validated like BuildAndValidateInvoiceRequest. Kept items keep their item_id
+// and their order, added items follow ordered by their key. A customer_request bills the
+// invoice to that customer instead of the stored one, sending a customer_id with it is
+// ErrInvoicePatchInvalid. A result that is not a valid invoice is ErrInvoicePatchInvalid too.
+func ApplyInvoicePatch(invoice entity.InvoicesData, items []*entity.Item, patch []byte) (InvoiceRequest, error) {
+	var request InvoiceRequest
+
+	document := InvoicePatchDocument{
+		Subject:          invoice.Subject,
+		IssueDate:        invoice.IssueDate.Format(ListDateLayout),
+		DueDate:          invoice.DueDate.Format(ListDateLayout),
+		TaxRate:          invoice.TaxRate,
+		TaxInvoiceNumber: invoice.TaxInvoiceNumber,
+		CustomerID:       invoice.CustomerID,
+		Items:            make(map[string]ItemPatch, len(items)),
+	}
+	for _, v := range items {
+		document.Items[v.ItemID.String()] = ItemPatch{
+			Name:      v.Name,
+			Type:      v.Type,
+			Quantity:  v.Quantity,
+			UnitPrice: v.UnitPrice,
+		}
+	}
+
+	documentByte, err := json.Marshal(document)
+	if err != nil {
+		return request, err
+	}
+
+	target, err := decodeJSON(documentByte)
+	if err != nil {
+		return request, err
+	}
+
+	patchValue, err := decodeJSON(patch)
+	if err != nil {
+		log.Println("decode patch err: ", err)
+		return request, errorss.ErrInvoicePatchInvalid
+	}
+
+	// a customer_id is used before a customer_request, the stored one would hide the new customer
+	if patchObject, ok := patchValue.(map[string]interface{}); ok && patchObject["customer_request"] != nil {
+		if patchObject["customer_id"] != nil {
+			log.Println("patch customer err: both customer_id and customer_request")
+			return request, errorss.ErrInvoicePatchInvalid
+		}
+		delete(target.(map[string]interface{}), "customer_id")
+	}
+
+	patchedByte, err := json.Marshal(mergePatch(target, patchValue))
+	if err != nil {
+		return request, err
+	}
+
+	var patched InvoicePatchDocument
+	decoder := json.NewDecoder(bytes.NewReader(patchedByte))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		log.Println("decode patched invoice err: ", err)
+		return request, errorss.ErrInvoicePatchInvalid
+	}
+
+	request = InvoiceRequest{
+		Subject:          patched.Subject,
+		IssueDate:        patched.IssueDate,
+		DueDate:          patched.DueDate,
+		TaxRate:          patched.TaxRate,
+		TaxInvoiceNumber: patched.TaxInvoiceNumber,
+		CustomerID:       patched.CustomerID,
+		CustomerRequest:  patched.CustomerRequest,
+		ItemRequest:      []ItemRequest{},
+	}
+
+	for _, v := range items {
+		key := v.ItemID.String()
+		if item, ok := patched.Items[key]; ok {
+			request.ItemRequest = append(request.ItemRequest, item.request(v.ItemID))
+			delete(patched.Items, key)
+		}
+	}
+
+	added := make([]string, 0, len(patched.Items))
+	for key := range patched.Items {
+		added = append(added, key)
+	}
+	sort.Strings(added)
+
+	for _, key := range added {
+		request.ItemRequest = append(request.ItemRequest, patched.Items[key].request(uuid.Nil))
+	}
+
+	if err := validateInvoiceRequest(&request); err != nil {
+		log.Println("validate patched invoice err: ", err)
+		return request, errorss.ErrInvoicePatchInvalid
+	}
+
+	return request, nil
+}
+
+func (p ItemPatch) request(itemID uuid.UUID) ItemRequest {
+	return ItemRequest{
+		ItemID:    itemID,
+		Name:      p.Name,
+		Type:      p.Type,
+		Quantity:  p.Quantity,
+		UnitPrice: p.UnitPrice,
+	}
+}
+
+// decodeJSON keeps numbers as written, a float64 would round amounts.
+func decodeJSON(data []byte) (interface{}, error) {
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// mergePatch applies patch to target as described in RFC 7386: members of a patch object are
+// merged recursively, null removes a member and any other value replaces it.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/google/uuid"
+)
+
+type PaymentRequest struct {
+	Amount      decimal.Decimal `json:"amount" validate:"gt=0"`
+	PaymentDate string          `json:"payment_date" validate:"required"`
+	Method      string          `json:"method" validate:"required,max=50"`
+	Reference   string          `json:"reference" validate:"max=255"`
+}
+
+type PaymentResponse struct {
+	PaymentID   uuid.UUID       `json:"payment_id"`
+	Amount      decimal.Decimal `json:"amount"`
+	PaymentDate string          `json:"payment_date"`
+	Method      string          `json:"method"`
+	Reference   string          `json:"reference"`
+}
+
+type InvoicePaymentResponse struct {
+	InvoiceID          string          `json:"invoice_id"`
+	PaymentID          uuid.UUID       `json:"payment_id"`
+	Status             string          `json:"status"`
+	AmountPaid         decimal.Decimal `json:"amount_paid"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+}
+
+func BuildAndValidatePaymentRequest(r *http.Request) (PaymentRequest, error) {
+	var payload PaymentRequest
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	if err := json.Unmarshal(bodyByte, &payload); err != nil {
+		log.Println("unmarshal request body err: ", err)
+		return payload, err
+	}
+
+	payload.Method = strings.ToLower(strings.TrimSpace(payload.Method))
+	payload.Reference = strings.TrimSpace(payload.Reference)
+
+	validator := newValidator()
+
+	if err := validator.Struct(payload); err != nil {
+		log.Println("validate request body err: ", err)
+		return payload, err
+	}
+
+	if _, err := time.Parse("02-01-2006", payload.PaymentDate); err != nil {
+		log.Println("parse payment date err: ", err)
+		return payload, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,191 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/schedule"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	frsUtils "github.com/Risuii/frs-lib/utils"
+)
+
+// RecurringInvoiceRequest is the template of the draft invoices created every period. The
+// invoice of a period is issued on the run date and due DueDays later.
+type RecurringInvoiceRequest struct {
+	CustomerID uuid.UUID       `json:"customer_id" validate:"required"`
+	Subject    string          `json:"subject" validate:"required,max=255"`
+	TaxRate    decimal.Decimal `json:"tax_rate" validate:"gte=0,lte=100"`
+	Series     string          `json:"series" validate:"max=20"`
+	// Interval is weekly, monthly, quarterly, yearly or cron
+	Interval string `json:"interval" validate:"required,oneof=weekly monthly quarterly yearly cron"`
+	// Cron is a five field cron expression evaluated in UTC, required by the cron interval
+	Cron      string `json:"cron" validate:"required_if=Interval cron,max=255"`
+	DueDays   int    `json:"due_days" validate:"gte=0"`
+	StartDate string `json:"start_date" validate:"required"`
+	// optional, no run is made after the end date
+	EndDate string                 `json:"end_date"`
+	Items   []RecurringItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type RecurringItemRequest struct {
+	Name      string          `json:"name" validate:"required,max=255"`
+	Type      string          `json:"type" validate:"max=255"`
+	Quantity  decimal.Decimal `json:"quantity" validate:"gt=0"`
+	UnitPrice decimal.Decimal `json:"unit_price" validate:"gte=0"`
+}
+
+type RecurringItemResponse struct {
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+}
+
+type RecurringRunResponse struct {
+	RunAt     time.Time `json:"run_at"`
+	InvoiceID string    `json:"invoice_id"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error"`
+}
+
+type RecurringInvoiceResponse struct {
+	RecurringID uuid.UUID       `json:"recurring_id"`
+	CustomerID  uuid.UUID       `json:"customer_id"`
+	Subject     string          `json:"subject"`
+	TaxRate     decimal.Decimal `json:"tax_rate"`
+	Series      string          `json:"series"`
+	Interval    string          `json:"interval"`
+	Cron        string          `json:"cron"`
+	DueDays     int             `json:"due_days"`
+	StartDate   string          `json:"start_date"`
+	EndDate     string          `json:"end_date"`
+	NextRun     *time.Time      `json:"next_run"`
+	LastRunAt   *time.Time      `json:"last_run_at"`
+	// Items and the latest Runs are left out of lists
+	Items     []RecurringItemResponse `json:"items,omitempty"`
+	Runs      []RecurringRunResponse  `json:"runs,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+type ListRecurringInvoiceResponse struct {
+	Data       []*RecurringInvoiceResponse
+	Pagination *frsUtils.Pagination
+}
+
+type GetListRecurringParam struct {
+	Page   int `json:"page"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+func BuildAndValidateRecurringInvoiceRequest(r *http.Request) (RecurringInvoiceRequest, error) {
+	var payload RecurringInvoiceRequest
+
+	bodyByte, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("read request body err: ", err)
+		return payload, err
+	}
+
+	if err := json.Unmarshal(bodyByte, &payload); err != nil {
+		log.Println("unmarshal request body err: ", err)
+		return payload, err
+	}
+
+	payload.Subject = strings.TrimSpace(payload.Subject)
+	payload.Series = strings.ToUpper(strings.TrimSpace(payload.Series))
+	payload.Interval = strings.ToLower(strings.TrimSpace(payload.Interval))
+	payload.Cron = strings.Join(strings.Fields(payload.Cron), " ")
+	payload.EndDate = strings.TrimSpace(payload.EndDate)
+	for i := range payload.Items {
+		payload.Items[i].Name = strings.TrimSpace(payload.Items[i].Name)
+		payload.Items[i].Type = strings.TrimSpace(payload.Items[i].Type)
+	}
+
+	validator := newValidator()
+
+	if err := validator.Struct(payload); err != nil {
+		log.Println("validate request body err: ", err)
+		return payload, err
+	}
+
+	layout := "02-01-2006"
+	startDate, err := time.Parse(layout, payload.StartDate)
+	if err != nil {
+		log.Println("parse start date err: ", err)
+		return payload, err
+	}
+
+	if payload.EndDate != "" {
+		endDate, err := time.Parse(layout, payload.EndDate)
+		if err != nil {
+			log.Println("parse end date err: ", err)
+			return payload, err
+		}
+
+		if endDate.Before(startDate) {
+			err = errors.New("end date before start date")
+			log.Println("validate end date err: ", err)
+			return payload, err
+		}
+	}
+
+	if payload.Interval != schedule.IntervalCron {
+		payload.Cron = ""
+	} else if _, err := schedule.First(payload.Interval, payload.Cron, startDate); err != nil {
+		log.Println("validate cron err: ", err)
+		return payload, err
+	}
+
+	return payload, nil
+}
+
+func ValidateAndBuildRecurringListRequest(r *http.Request) (getListParam *GetListRecurringParam, err error) {
+	// default value for page and limit
+	page, limit := 1, 10
+
+	queryParams := r.URL.Query()
+	limitQuery := queryParams.Get("limit")
+	pageQuery := queryParams.Get("page")
+
+	if pageQuery != "" {
+		page, err = strconv.Atoi(pageQuery)
+		if err != nil {
+			return
+		}
+	}
+
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil {
+			return
+		}
+	}
+
+	if page < 1 || limit < 1 {
+		err = errors.New("page and limit must be positive")
+		return
+	}
+
+	getListParam = &GetListRecurringParam{
+		Page:   page,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	return
+}
+
+func ValidateRecurringIDParamRequest(r *http.Request) (uuid.UUID, error) {
+	return uuid.Parse(chi.URLParam(r, "id"))
+}
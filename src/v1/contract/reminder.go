@@ -0,0 +1,15 @@
+package contract
+
+import "time"
+
+// ReminderResponse is one entry of the reminder log of an invoice.
+type ReminderResponse struct {
+	// Stage is the number of days from the due date the reminder belongs to, negative before it
+	Stage     int        `json:"stage"`
+	Email     string     `json:"email"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error"`
+	SentAt    *time.Time `json:"sent_at"`
+	Attempts  int        `json:"attempts"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,62 @@
+package contract
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	frsUtils "github.com/Risuii/frs-lib/utils"
+)
+
+type GetTrashListParam struct {
+	Page   int `json:"page" db:"page"`
+	Limit  int `json:"limit" db:"limit"`
+	Offset int `json:"offset" db:"offset"`
+}
+
+type TrashedInvoice struct {
+	Invoice
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type ListTrashResponse struct {
+	Data       []*TrashedInvoice
+	Pagination *frsUtils.Pagination
+}
+
+func ValidateAndBuildTrashListRequest(r *http.Request) (getListParam *GetTrashListParam, err error) {
+	// default value for page and limit
+	page, limit := 1, 10
+
+	queryParams := r.URL.Query()
+	limitQuery := queryParams.Get("limit")
+	pageQuery := queryParams.Get("page")
+
+	if pageQuery != "" {
+		page, err = strconv.Atoi(pageQuery)
+		if err != nil {
+			return
+		}
+	}
+
+	if limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil {
+			return
+		}
+	}
+
+	if page < 1 || limit < 1 {
+		err = errors.New("page and limit must be positive")
+		return
+	}
+
+	getListParam = &GetTrashListParam{
+		Page:   page,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	return
+}
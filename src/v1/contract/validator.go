@@ -0,0 +1,24 @@
+package contract
+
+import (
+	"reflect"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/go-playground/validator/v10"
+)
+
+// newValidator returns a validator that understands decimal fields, so tags such as gt=0
+// can be used on amounts and quantities.
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if d, ok := field.Interface().(decimal.Decimal); ok {
+			return d.Float64()
+		}
+
+		return nil
+	}, decimal.Decimal{})
+
+	return v
+}
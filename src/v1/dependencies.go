@@ -5,13 +5,26 @@ import (
 	"log"
 
 	"github.com/Risuii/invoice/src/app"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/mailer"
 	"github.com/google/uuid"
 
 	frsAtomicSQLX "github.com/Risuii/frs-lib/atomic/sqlx"
+	htmlDocument "github.com/Risuii/invoice/src/document/html"
+	ublDocument "github.com/Risuii/invoice/src/document/ubl"
+	creditNotesRepo "github.com/Risuii/invoice/src/repository/creditnotes"
 	customerRepo "github.com/Risuii/invoice/src/repository/customers"
+	idempotencyRepo "github.com/Risuii/invoice/src/repository/idempotency"
 	InvoicesRepo "github.com/Risuii/invoice/src/repository/invoice"
 	itemsRepo "github.com/Risuii/invoice/src/repository/items"
+	paymentsRepo "github.com/Risuii/invoice/src/repository/payments"
+	recurringRepo "github.com/Risuii/invoice/src/repository/recurring"
+	remindersRepo "github.com/Risuii/invoice/src/repository/reminders"
+	seriesRepo "github.com/Risuii/invoice/src/repository/series"
+	Customersvc "github.com/Risuii/invoice/src/v1/service/customer"
+	Dunningsvc "github.com/Risuii/invoice/src/v1/service/dunning"
 	Invoicesvc "github.com/Risuii/invoice/src/v1/service/invoice"
+	Recurringsvc "github.com/Risuii/invoice/src/v1/service/recurring"
 )
 
 type repositories struct {
@@ -19,15 +32,30 @@ type repositories struct {
 	InvoicesRepo          *InvoicesRepo.InvoicesRepository
 	CustomersRepo         *customerRepo.CustomersRepository
 	ItemsRepo             *itemsRepo.ItemsRepository
+	PaymentsRepo          *paymentsRepo.PaymentsRepository
+	CreditNotesRepo       *creditNotesRepo.CreditNotesRepository
+	RecurringRepo         *recurringRepo.RecurringRepository
+	RemindersRepo         *remindersRepo.RemindersRepository
+	SeriesRepo            *seriesRepo.SeriesRepository
+	IdempotencyRepo       *idempotencyRepo.IdempotencyRepository
+}
+
+type documents struct {
+	HTMLRenderer *htmlDocument.Renderer
+	UBLEncoder   *ublDocument.Encoder
 }
 
 type services struct {
-	Invoicesvc *Invoicesvc.Invoiceservice
+	Invoicesvc   *Invoicesvc.Invoiceservice
+	Customersvc  *Customersvc.Customerservice
+	Recurringsvc *Recurringsvc.Recurringservice
+	Dunningsvc   *Dunningsvc.Dunningservice
 }
 
 type Dependency struct {
-	Repositories *repositories
-	Services     *services
+	Repositories  *repositories
+	Services      *services
+	LateFeePolicy Invoicesvc.LateFeePolicy
 }
 
 type UUIDGeneratorImplementation struct{}
@@ -57,24 +85,129 @@ func initRepositories(ctx context.Context) *repositories {
 		log.Fatal("init items repo err: ", err)
 	}
 
+	r.PaymentsRepo, err = paymentsRepo.InitPaymentsRepository(ctx, app.DB(), app.Cache())
+	if err != nil {
+		log.Fatal("init payments repo err: ", err)
+	}
+
+	r.CreditNotesRepo, err = creditNotesRepo.InitCreditNotesRepository(ctx, app.DB(), app.Cache())
+	if err != nil {
+		log.Fatal("init credit notes repo err: ", err)
+	}
+
+	r.RecurringRepo, err = recurringRepo.InitRecurringRepository(ctx, app.DB(), app.Cache())
+	if err != nil {
+		log.Fatal("init recurring repo err: ", err)
+	}
+
+	r.RemindersRepo, err = remindersRepo.InitRemindersRepository(ctx, app.DB())
+	if err != nil {
+		log.Fatal("init reminders repo err: ", err)
+	}
+
+	r.SeriesRepo, err = seriesRepo.InitSeriesRepository(ctx, app.DB(), app.Cache())
+	if err != nil {
+		log.Fatal("init series repo err: ", err)
+	}
+
+	r.IdempotencyRepo, err = idempotencyRepo.InitIdempotencyRepository(ctx, app.DB())
+	if err != nil {
+		log.Fatal("init idempotency repo err: ", err)
+	}
+
 	return &r
 }
 
-func initServices(ctx context.Context, r *repositories) *services {
+func initDocuments(ctx context.Context) *documents {
+	var d documents
+	var err error
+
+	cfg := app.Config().Document
+
+	d.HTMLRenderer, err = htmlDocument.NewRenderer(cfg.TemplateDir, htmlDocument.Branding{
+		CompanyName:    cfg.CompanyName,
+		CompanyAddress: cfg.CompanyAddress,
+		BankAccount:    cfg.BankAccount,
+		FooterNotes:    cfg.FooterNotes,
+		LogoPath:       cfg.LogoPath,
+	})
+	if err != nil {
+		log.Fatal("init html renderer err: ", err)
+	}
+
+	d.UBLEncoder = ublDocument.NewEncoder(ublDocument.Supplier{
+		Name:             cfg.CompanyName,
+		Address:          cfg.CompanyAddress,
+		CountryCode:      cfg.CountryCode,
+		Currency:         cfg.Currency,
+		TaxID:            cfg.CompanyTaxID,
+		EndpointID:       cfg.EndpointID,
+		EndpointScheme:   cfg.EndpointScheme,
+		PaymentAccountID: cfg.PaymentAccountID,
+	})
+
+	return &d
+}
+
+func initServices(ctx context.Context, r *repositories, d *documents) *services {
 
 	uuidGen := UUIDGeneratorImplementation{}
 
+	invoicesvc := Invoicesvc.InitInvoiceservice(r.InvoicesRepo, r.CustomersRepo, r.ItemsRepo, r.PaymentsRepo, r.CreditNotesRepo, r.SeriesRepo, d.HTMLRenderer, d.UBLEncoder, &r.AtomicSessionProvider, uuidGen)
+
+	smtpCfg := app.Config().SMTP
+	smtpMailer := mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     smtpCfg.Host,
+		Port:     smtpCfg.Port,
+		Username: smtpCfg.Username,
+		Password: smtpCfg.Password,
+		From:     smtpCfg.From,
+	})
+
+	dunningCfg := app.Config().Dunning
+
 	return &services{
-		Invoicesvc: Invoicesvc.InitInvoiceservice(r.InvoicesRepo, r.CustomersRepo, r.ItemsRepo, &r.AtomicSessionProvider, uuidGen),
+		Invoicesvc:   invoicesvc,
+		Customersvc:  Customersvc.InitCustomerservice(r.CustomersRepo, uuidGen),
+		Recurringsvc: Recurringsvc.InitRecurringservice(r.RecurringRepo, r.CustomersRepo, r.SeriesRepo, invoicesvc, &r.AtomicSessionProvider, uuidGen),
+		Dunningsvc:   Dunningsvc.InitDunningservice(r.RemindersRepo, r.InvoicesRepo, smtpMailer, dunningCfg.Schedule, dunningCfg.MaxAttempts, dunningCfg.Language),
+	}
+}
+
+// initLateFeePolicy builds the late fee policy of the overdue check from the config.
+func initLateFeePolicy() Invoicesvc.LateFeePolicy {
+	cfg := app.Config().LateFee
+
+	amount, err := decimal.NewFromString(cfg.Amount)
+	if err != nil {
+		log.Fatal("parse late fee amount err: ", err)
+	}
+
+	var maxAmount decimal.Decimal
+	if cfg.MaxAmount != "" {
+		maxAmount, err = decimal.NewFromString(cfg.MaxAmount)
+		if err != nil {
+			log.Fatal("parse late fee max amount err: ", err)
+		}
+	}
+
+	return Invoicesvc.LateFeePolicy{
+		Type:         cfg.Type,
+		Amount:       amount,
+		GraceDays:    cfg.GraceDays,
+		IntervalDays: cfg.IntervalDays,
+		MaxAmount:    maxAmount,
 	}
 }
 
 func Dependencies(ctx context.Context) *Dependency {
 	repositories := initRepositories(ctx)
-	services := initServices(ctx, repositories)
+	documents := initDocuments(ctx)
+	services := initServices(ctx, repositories, documents)
 
 	return &Dependency{
-		Repositories: repositories,
-		Services:     services,
+		Repositories:  repositories,
+		Services:      services,
+		LateFeePolicy: initLateFeePolicy(),
 	}
 }
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/middleware/response"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+func CreateCreditNoteHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		creditNoteRequest, err := contract.BuildAndValidateCreditNoteRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.CreateCreditNote(r.Context(), creditNoteRequest, id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrInvoiceNotCreditable,
+				errors.ErrInvoiceFullyCredited,
+				errors.ErrInvoiceItemNotFound,
+				errors.ErrCreditNoteQuantityExceeded,
+				errors.ErrInvoiceSeriesNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+func GetCreditNotesHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetCreditNotes(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/middleware/response"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+func CreateCustomerHandler(svc CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		customerRequest, err := contract.BuildAndValidateCustomerRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Create(r.Context(), customerRequest)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrDuplicateCustomer:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+func GetListCustomersHandler(svc CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := contract.ValidateAndBuildCustomerListRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetList(r.Context(), *params)
+		if err != nil {
+			log.Println(err)
+			response.JSONInternalErrorResponse(r.Context(), w)
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
+
+func GetDetailCustomerHandler(svc CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateCustomerIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetDetail(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrCustomerIdNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
+
+func UpdateCustomerHandler(svc CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateCustomerIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		customerRequest, err := contract.BuildAndValidateCustomerRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Update(r.Context(), customerRequest, id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrCustomerIdNotFound,
+				errors.ErrDuplicateCustomer:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+func DeleteCustomerHandler(svc CustomerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateCustomerIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		err = svc.Delete(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrCustomerIdNotFound,
+				errors.ErrCustomerHasInvoices:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, nil)
+	}
+}
+
+func PauseDunningHandler(svc CustomerService) http.HandlerFunc {
+	return setDunningPausedHandler(svc, true)
+}
+
+func ResumeDunningHandler(svc CustomerService) http.HandlerFunc {
+	return setDunningPausedHandler(svc, false)
+}
+
+func setDunningPausedHandler(svc CustomerService, paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateCustomerIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.SetDunningPaused(r.Context(), id, paused)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrCustomerIdNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_handler "github.com/Risuii/invoice/src/v1/handler/mock"
+)
+
+func TestHandler_CreateCustomer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			request      *contract.CustomerRequest
+			statusCode   int
+			responseBody string
+		}
+
+		given struct {
+			payload      string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	request := contract.CustomerRequest{
+		CustomerName: "test-name",
+		Address:      "test-address",
+	}
+
+	payload := `{
+		"customer_name": " Test-Name ",
+		"address": "test-address"
+	}`
+
+	testCases := []testCase{
+		{
+			name: "err bad request",
+			given: given{
+				payload: `{"customer_name": "test-name"}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err endpoint id without scheme",
+			given: given{
+				payload: `{"customer_name": "test-name", "address": "test-address", "endpoint_id": "9876543210987"}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err bad npwp",
+			given: given{
+				payload: `{"customer_name": "test-name", "address": "test-address", "npwp": "01.234.567"}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err bad country code",
+			given: given{
+				payload: `{"customer_name": "test-name", "address": "test-address", "country_code": "IDN"}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success with endpoint",
+			given: given{
+				payload: `{"customer_name": "test-name", "address": "test-address", "npwp": "01.234.567.8-901.000", "endpoint_id": " 9876543210987 ", "endpoint_scheme": "0088", "country_code": "sg"}`,
+			},
+			expected: expected{
+				request: &contract.CustomerRequest{
+					CustomerName:   "test-name",
+					Address:        "test-address",
+					NPWP:           "012345678901000",
+					EndpointID:     "9876543210987",
+					EndpointScheme: "0088",
+					CountryCode:    "SG",
+				},
+				statusCode:   200,
+				responseBody: `{"data":{"customer_id":"00000000-0000-0000-0000-000000000000","name":"","address":"","npwp":"","endpoint_id":"","endpoint_scheme":"","country_code":"","email":"","dunning_paused":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err duplicate customer",
+			given: given{
+				payload:      payload,
+				svcErrReturn: errorss.ErrDuplicateCustomer,
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_customer_duplicate","message_title":"Customer Already Exists","message":"A customer with the same name and address already exists.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err internal server",
+			given: given{
+				payload:      payload,
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				payload: payload,
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   200,
+				responseBody: `{"data":{"customer_id":"00000000-0000-0000-0000-000000000000","name":"","address":"","npwp":"","endpoint_id":"","endpoint_scheme":"","country_code":"","email":"","dunning_paused":false,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(testCase.given.payload))
+			w := httptest.NewRecorder()
+
+			mockCustomerSvc := mock_handler.NewMockCustomerService(mockCtrl)
+
+			if testCase.expected.request != nil {
+				mockCustomerSvc.EXPECT().Create(gomock.Any(), *testCase.expected.request).
+					Return(contract.CustomerResponse{}, testCase.given.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(CreateCustomerHandler(mockCustomerSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_DeleteCustomer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	testCases := []struct {
+		name         string
+		id           string
+		callSvc      bool
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad request id",
+			id:           "not-a-uuid",
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err customer has invoices",
+			id:           id.String(),
+			callSvc:      true,
+			svcErrReturn: errorss.ErrCustomerHasInvoices,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_customer_has_invoices","message_title":"Customer Has Invoices","message":"Customers with invoices, trashed ones included, or recurring invoices cannot be deleted.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			id:           id.String(),
+			callSvc:      true,
+			statusCode:   200,
+			responseBody: `{"data":null,"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodDelete, "/just/for/testing", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testCase.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			mockCustomerSvc := mock_handler.NewMockCustomerService(mockCtrl)
+
+			if testCase.callSvc {
+				mockCustomerSvc.EXPECT().Delete(gomock.Any(), id).
+					Return(testCase.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(DeleteCustomerHandler(mockCustomerSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_PauseDunning(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	testCases := []struct {
+		name         string
+		id           string
+		callSvc      bool
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad request id",
+			id:           "not-a-uuid",
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err customer id not found",
+			id:           id.String(),
+			callSvc:      true,
+			svcErrReturn: errorss.ErrCustomerIdNotFound,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_customer_id_not_found","message_title":"err_customer_id_not_found_title","message":"err_customer_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			id:           id.String(),
+			callSvc:      true,
+			svcErrReturn: errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			id:           id.String(),
+			callSvc:      true,
+			statusCode:   200,
+			responseBody: `{"data":{"customer_id":"11111111-1111-1111-1111-111111111111","name":"","address":"","npwp":"","endpoint_id":"","endpoint_scheme":"","country_code":"","email":"","dunning_paused":true,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testCase.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			mockCustomerSvc := mock_handler.NewMockCustomerService(mockCtrl)
+
+			if testCase.callSvc {
+				res := contract.CustomerResponse{}
+				if testCase.svcErrReturn == nil {
+					res = contract.CustomerResponse{CustomerID: id, DunningPaused: true}
+				}
+
+				mockCustomerSvc.EXPECT().SetDunningPaused(gomock.Any(), id, true).
+					Return(res, testCase.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(PauseDunningHandler(mockCustomerSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
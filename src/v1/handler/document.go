@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/document/export"
+	"github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/middleware/request"
+	"github.com/Risuii/invoice/src/middleware/response"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-chi/chi/v5"
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// documentFileName turns an invoice number such as INV/2024/00042 into a file name.
+func documentFileName(id, extension string) string {
+	return "invoice-" + unsafeFileNameChars.ReplaceAllString(id, "-") + "." + extension
+}
+
+func writeDocument(w http.ResponseWriter, contentType, fileName string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func GetInvoicePDFHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		labels := document.NewLabels(request.GetLanguage(r.Context()))
+
+		data, err := svc.RenderPDF(r.Context(), id, r.URL.Query().Get("template"), labels)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrCustomerIdNotFound,
+				errors.ErrDocumentTemplateNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		writeDocument(w, "application/pdf", documentFileName(id, "pdf"), data)
+	}
+}
+
+func GetInvoiceHTMLHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		labels := document.NewLabels(request.GetLanguage(r.Context()))
+
+		data, err := svc.RenderHTML(r.Context(), id, r.URL.Query().Get("template"), labels)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrCustomerIdNotFound,
+				errors.ErrDocumentTemplateNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		writeDocument(w, "text/html; charset=utf-8", documentFileName(id, "html"), data)
+	}
+}
+
+// GetInvoiceUBLHandler serves the invoice as a UBL 2.1 / PEPPOL BIS Billing 3.0 e-invoice.
+func GetInvoiceUBLHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.ExportUBL(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrCustomerIdNotFound,
+				errors.ErrInvoiceNotExportable,
+				errors.ErrCustomerEndpointMissing:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		writeDocument(w, "application/xml", documentFileName(id, "xml"), data)
+	}
+}
+
+// ExportEFakturHandler serves the e-Faktur import CSV of the invoices issued between the from
+// and to query parameters.
+func ExportEFakturHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := contract.ValidateAndBuildEFakturRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.ExportEFaktur(r.Context(), params)
+		if err != nil {
+			log.Println(err)
+			response.JSONInternalErrorResponse(r.Context(), w)
+			return
+		}
+
+		fileName := fmt.Sprintf("efaktur-%s-%s.csv", params.From.Format("20060102"), params.To.Format("20060102"))
+		writeDocument(w, "text/csv; charset=utf-8", fileName, data)
+	}
+}
+
+// SetTaxInvoiceNumberHandler records the tax invoice number DJP assigned to an issued invoice.
+func SetTaxInvoiceNumberHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		request, err := contract.BuildAndValidateTaxInvoiceNumberRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.SetTaxInvoiceNumber(r.Context(), request, id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrInvoiceTaxNumberNotAssignable,
+				errors.ErrDuplicateTaxInvoiceNumber:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+// exportWriter sends the headers of the export with its first bytes, so an export that fails
+// before writing anything can still answer with a JSON error.
+type exportWriter struct {
+	http.ResponseWriter
+	contentType string
+	fileName    string
+	written     bool
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	if !ew.written {
+		ew.written = true
+		ew.Header().Set("Content-Type", ew.contentType)
+		ew.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ew.fileName))
+		ew.WriteHeader(http.StatusOK)
+	}
+
+	return ew.ResponseWriter.Write(p)
+}
+
+// ExportInvoicesHandler streams the invoice list, filtered like GetListInvoicesHandler, as a
+// CSV or XLSX file.
+func ExportInvoicesHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, exportParam, err := contract.ValidateAndBuildExportRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		ew := &exportWriter{
+			ResponseWriter: w,
+			contentType:    export.ContentType(exportParam.Format),
+			fileName:       "invoices." + exportParam.Format,
+		}
+
+		if err := svc.Export(r.Context(), *params, exportParam, ew); err != nil {
+			log.Println(err)
+			// once rows are sent the status is already 200, the client gets a truncated file
+			if !ew.written {
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+	}
+}
+
+func PreviewInvoiceTemplateHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		labels := document.NewLabels(request.GetLanguage(r.Context()))
+
+		data, err := svc.PreviewHTML(r.Context(), name, labels)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrDocumentTemplateNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		writeDocument(w, "text/html; charset=utf-8", "preview-"+unsafeFileNameChars.ReplaceAllString(name, "-")+".html", data)
+	}
+}
@@ -0,0 +1,572 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/assert"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_handler "github.com/Risuii/invoice/src/v1/handler/mock"
+)
+
+func TestHandler_GetInvoicePDF(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			statusCode         int
+			contentType        string
+			contentDisposition string
+			responseBody       string
+		}
+
+		given struct {
+			template     string
+			svcData      []byte
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	testCases := []testCase{
+		{
+			name: "err internal server",
+			given: given{
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				statusCode:   500,
+				contentType:  "application/json",
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+			},
+		},
+		{
+			name: "err invoice id not found",
+			given: given{
+				svcErrReturn: errorss.ErrInvoiceIdNotFound,
+			},
+			expected: expected{
+				statusCode:   422,
+				contentType:  "application/json",
+				responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+			},
+		},
+		{
+			name: "err unknown template",
+			given: given{
+				template:     "fancy",
+				svcErrReturn: errorss.ErrDocumentTemplateNotFound,
+			},
+			expected: expected{
+				statusCode:   422,
+				contentType:  "application/json",
+				responseBody: `{"data":null,"error":{"code":"err_document_template_not_found","message_title":"Unknown Template","message":"The requested document template does not exist.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				template: "classic",
+				svcData:  []byte("%PDF-1.3"),
+			},
+			expected: expected{
+				statusCode:         200,
+				contentType:        "application/pdf",
+				contentDisposition: `inline; filename="invoice-INV-2024-00042.pdf"`,
+				responseBody:       "%PDF-1.3",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/just/for/testing/pdf?template=%s", testCase.given.template), nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", "INV%2F2024%2F00042")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+			w := httptest.NewRecorder()
+
+			mockInvoice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			mockInvoice.EXPECT().RenderPDF(gomock.Any(), "INV/2024/00042", testCase.given.template, gomock.Any()).
+				Return(testCase.given.svcData, testCase.given.svcErrReturn).
+				Times(1)
+
+			hf := http.HandlerFunc(GetInvoicePDFHandler(mockInvoice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, testCase.expected.contentType, res.Header.Get("Content-Type"))
+			assert.Equal(t, testCase.expected.contentDisposition, res.Header.Get("Content-Disposition"))
+			assert.Equal(t, testCase.expected.responseBody, string(data))
+		})
+	}
+}
+
+func TestHandler_GetInvoiceHTML(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		svcData      []byte
+		svcErr       error
+		statusCode   int
+		contentType  string
+		responseBody string
+	}{
+		{
+			name:         "err unknown template",
+			svcErr:       errorss.ErrDocumentTemplateNotFound,
+			statusCode:   422,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_document_template_not_found","message_title":"Unknown Template","message":"The requested document template does not exist.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:         "success",
+			svcData:      []byte("<html></html>"),
+			statusCode:   200,
+			contentType:  "text/html; charset=utf-8",
+			responseBody: "<html></html>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/just/for/testing/html?template=default", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", "0001")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+			w := httptest.NewRecorder()
+
+			mockInvoice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			mockInvoice.EXPECT().RenderHTML(gomock.Any(), "0001", "default", gomock.Any()).
+				Return(testCase.svcData, testCase.svcErr).
+				Times(1)
+
+			hf := http.HandlerFunc(GetInvoiceHTMLHandler(mockInvoice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, testCase.contentType, res.Header.Get("Content-Type"))
+			assert.Equal(t, testCase.responseBody, string(data))
+		})
+	}
+}
+
+func TestHandler_GetInvoiceUBL(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name               string
+		svcData            []byte
+		svcErr             error
+		statusCode         int
+		contentType        string
+		contentDisposition string
+		responseBody       string
+	}{
+		{
+			name:         "err internal server",
+			svcErr:       errors.New("ubl: the supplier has no endpoint id"),
+			statusCode:   500,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:         "err invoice not exportable",
+			svcErr:       errorss.ErrInvoiceNotExportable,
+			statusCode:   422,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_invoice_not_exportable","message_title":"Invoice Not Exportable","message":"Draft and void invoices can not be exported as e-invoices.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:         "err customer endpoint missing",
+			svcErr:       errorss.ErrCustomerEndpointMissing,
+			statusCode:   422,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_customer_endpoint_missing","message_title":"Customer Endpoint Missing","message":"Set the endpoint id and scheme of the customer before exporting an e-invoice.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:               "success",
+			svcData:            []byte("<Invoice/>"),
+			statusCode:         200,
+			contentType:        "application/xml",
+			contentDisposition: `inline; filename="invoice-INV-2024-00042.xml"`,
+			responseBody:       "<Invoice/>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/just/for/testing/ubl", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", "INV%2F2024%2F00042")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+			w := httptest.NewRecorder()
+
+			mockInvoice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			mockInvoice.EXPECT().ExportUBL(gomock.Any(), "INV/2024/00042").
+				Return(testCase.svcData, testCase.svcErr).
+				Times(1)
+
+			hf := http.HandlerFunc(GetInvoiceUBLHandler(mockInvoice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, testCase.contentType, res.Header.Get("Content-Type"))
+			assert.Equal(t, testCase.contentDisposition, res.Header.Get("Content-Disposition"))
+			assert.Equal(t, testCase.responseBody, string(data))
+		})
+	}
+}
+
+func TestHandler_ExportEFaktur(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name               string
+		target             string
+		svcData            []byte
+		svcErr             error
+		statusCode         int
+		contentType        string
+		contentDisposition string
+		responseBody       string
+	}{
+		{
+			name:         "err missing range",
+			target:       "/just/for/testing/efaktur?from=01-03-2024",
+			statusCode:   400,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:         "err reversed range",
+			target:       "/just/for/testing/efaktur?from=31-03-2024&to=01-03-2024",
+			statusCode:   400,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:         "err internal server",
+			target:       "/just/for/testing/efaktur?from=01-03-2024&to=31-03-2024",
+			svcErr:       errors.New("error internal server"),
+			statusCode:   500,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:               "success",
+			target:             "/just/for/testing/efaktur?from=01-03-2024&to=31-03-2024",
+			svcData:            []byte("FK,KD_JENIS_TRANSAKSI\n"),
+			statusCode:         200,
+			contentType:        "text/csv; charset=utf-8",
+			contentDisposition: `inline; filename="efaktur-20240301-20240331.csv"`,
+			responseBody:       "FK,KD_JENIS_TRANSAKSI\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, testCase.target, nil)
+			w := httptest.NewRecorder()
+
+			mockInvoice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.statusCode != 400 {
+				mockInvoice.EXPECT().ExportEFaktur(gomock.Any(), contract.EFakturParam{
+					From: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+					To:   time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+				}).
+					Return(testCase.svcData, testCase.svcErr).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(ExportEFakturHandler(mockInvoice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, testCase.contentType, res.Header.Get("Content-Type"))
+			assert.Equal(t, testCase.contentDisposition, res.Header.Get("Content-Disposition"))
+			assert.Equal(t, testCase.responseBody, string(data))
+		})
+	}
+}
+
+func TestHandler_SetTaxInvoiceNumber(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		payload      string
+		callSvc      bool
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad request",
+			payload:      `{"tax_invoice_number": "010.240"}`,
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err duplicate tax invoice number",
+			payload:      `{"tax_invoice_number": "002-24.00000042"}`,
+			callSvc:      true,
+			svcErrReturn: errorss.ErrDuplicateTaxInvoiceNumber,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_tax_invoice_number_duplicate","message_title":"Tax Invoice Number Already Used","message":"Another invoice already has this tax invoice number.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err invoice not issued",
+			payload:      `{"tax_invoice_number": "002-24.00000042"}`,
+			callSvc:      true,
+			svcErrReturn: errorss.ErrInvoiceTaxNumberNotAssignable,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_invoice_tax_number_not_assignable","message_title":"Tax Invoice Number Rejected","message":"A tax invoice number can only be recorded on issued invoices that are not void.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			payload:      `{"tax_invoice_number": "002-24.00000042"}`,
+			callSvc:      true,
+			svcErrReturn: errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			payload:      `{"tax_invoice_number": "002-24.00000042"}`,
+			callSvc:      true,
+			statusCode:   200,
+			responseBody: `{"data":{"invoice_id":"0001","tax_invoice_number":"0022400000042"},"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/just/for/testing", strings.NewReader(testCase.payload))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "0001")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.callSvc {
+				var res contract.TaxInvoiceNumberResponse
+				if testCase.svcErrReturn == nil {
+					res = contract.TaxInvoiceNumberResponse{InvoiceID: "0001", TaxInvoiceNumber: "0022400000042"}
+				}
+
+				mockInvoiceSvc.EXPECT().SetTaxInvoiceNumber(gomock.Any(), contract.TaxInvoiceNumberRequest{TaxInvoiceNumber: "0022400000042"}, "0001").
+					Return(res, testCase.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(SetTaxInvoiceNumberHandler(mockInvoiceSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_ExportInvoices(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	badRequest := `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n"
+
+	testCases := []struct {
+		name               string
+		target             string
+		exportParam        contract.ExportParam
+		svcData            string
+		svcErr             error
+		statusCode         int
+		contentType        string
+		contentDisposition string
+		responseBody       string
+	}{
+		{
+			name:         "err missing format",
+			target:       "/just/for/testing/export",
+			statusCode:   400,
+			contentType:  "application/json",
+			responseBody: badRequest,
+		},
+		{
+			name:         "err unknown format",
+			target:       "/just/for/testing/export?format=pdf",
+			statusCode:   400,
+			contentType:  "application/json",
+			responseBody: badRequest,
+		},
+		{
+			name:         "err bad include items",
+			target:       "/just/for/testing/export?format=csv&include_items=maybe",
+			statusCode:   400,
+			contentType:  "application/json",
+			responseBody: badRequest,
+		},
+		{
+			name:         "err before the first row",
+			target:       "/just/for/testing/export?format=csv&status=paid",
+			exportParam:  contract.ExportParam{Format: "csv"},
+			svcErr:       errors.New("error internal server"),
+			statusCode:   500,
+			contentType:  "application/json",
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:               "err after the first row",
+			target:             "/just/for/testing/export?format=csv&status=paid",
+			exportParam:        contract.ExportParam{Format: "csv"},
+			svcData:            "invoice_id\n",
+			svcErr:             errors.New("error internal server"),
+			statusCode:         200,
+			contentType:        "text/csv; charset=utf-8",
+			contentDisposition: `attachment; filename="invoices.csv"`,
+			responseBody:       "invoice_id\n",
+		},
+		{
+			name:               "success xlsx with items",
+			target:             "/just/for/testing/export?format=xlsx&include_items=true&status=paid",
+			exportParam:        contract.ExportParam{Format: "xlsx", IncludeItems: true},
+			svcData:            "PK",
+			statusCode:         200,
+			contentType:        "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			contentDisposition: `attachment; filename="invoices.xlsx"`,
+			responseBody:       "PK",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, testCase.target, nil)
+			w := httptest.NewRecorder()
+
+			mockInvoice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.statusCode != 400 {
+				mockInvoice.EXPECT().Export(gomock.Any(), gomock.Any(), testCase.exportParam, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params contract.GetListParam, exportParam contract.ExportParam, w io.Writer) error {
+						assert.Equal(t, []string{"Paid"}, params.Statuses)
+						if testCase.svcData != "" {
+							w.Write([]byte(testCase.svcData))
+						}
+						return testCase.svcErr
+					}).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(ExportInvoicesHandler(mockInvoice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, testCase.contentType, res.Header.Get("Content-Type"))
+			assert.Equal(t, testCase.contentDisposition, res.Header.Get("Content-Disposition"))
+			assert.Equal(t, testCase.responseBody, string(data))
+		})
+	}
+}
+
+func TestHandler_PreviewInvoiceTemplate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		svcData      []byte
+		svcErr       error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err internal server",
+			svcErr:       errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}` + "\n",
+		},
+		{
+			name:         "success",
+			svcData:      []byte("<html>preview</html>"),
+			statusCode:   200,
+			responseBody: "<html>preview</html>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/just/for/testing/templates/compact/preview", nil)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("name", "compact")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+			w := httptest.NewRecorder()
+
+			mockInvoice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			mockInvoice.EXPECT().PreviewHTML(gomock.Any(), "compact", gomock.Any()).
+				Return(testCase.svcData, testCase.svcErr).
+				Times(1)
+
+			hf := http.HandlerFunc(PreviewInvoiceTemplateHandler(mockInvoice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, testCase.responseBody, string(data))
+		})
+	}
+}
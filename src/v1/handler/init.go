@@ -2,13 +2,57 @@ package handler
 
 import (
 	"context"
+	"io"
 
+	"github.com/Risuii/invoice/src/document"
 	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
 )
 
 type InvoiceService interface {
 	GetList(ctx context.Context, params contract.GetListParam) (contract.ListInvoiceResponse, error)
 	GetDetail(ctx context.Context, id string) (contract.InvoiceResponse, error)
 	Create(ctx context.Context, request contract.InvoiceRequest) (contract.InvcResponse, error)
-	Update(ctx context.Context, request contract.InvoiceRequest, id string) (contract.InvcResponse, error)
+	Update(ctx context.Context, request contract.InvoiceRequest, id string, version int64) (contract.InvcResponse, error)
+	Patch(ctx context.Context, patch []byte, id string, version int64) (contract.InvcResponse, error)
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (contract.InvcResponse, error)
+	GetTrash(ctx context.Context, params contract.GetTrashListParam) (contract.ListTrashResponse, error)
+	CreateBatch(ctx context.Context, request contract.BatchInvoiceRequest) ([]contract.BatchResult, error)
+	Import(ctx context.Context, request contract.ImportRequest) (contract.ImportResponse, error)
+	CreatePayment(ctx context.Context, request contract.PaymentRequest, id string) (contract.InvoicePaymentResponse, error)
+	GetPayments(ctx context.Context, id string) ([]contract.PaymentResponse, error)
+	CreateCreditNote(ctx context.Context, request contract.CreditNoteRequest, id string) (contract.InvoiceCreditNoteResponse, error)
+	GetCreditNotes(ctx context.Context, id string) ([]contract.CreditNoteResponse, error)
+	Issue(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error)
+	Void(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error)
+	Reopen(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error)
+	RenderPDF(ctx context.Context, id, templateName string, labels document.Labels) ([]byte, error)
+	RenderHTML(ctx context.Context, id, templateName string, labels document.Labels) ([]byte, error)
+	PreviewHTML(ctx context.Context, templateName string, labels document.Labels) ([]byte, error)
+	ExportUBL(ctx context.Context, id string) ([]byte, error)
+	ExportEFaktur(ctx context.Context, params contract.EFakturParam) ([]byte, error)
+	SetTaxInvoiceNumber(ctx context.Context, request contract.TaxInvoiceNumberRequest, id string) (contract.TaxInvoiceNumberResponse, error)
+	Export(ctx context.Context, params contract.GetListParam, exportParam contract.ExportParam, w io.Writer) error
+}
+
+type CustomerService interface {
+	Create(ctx context.Context, request contract.CustomerRequest) (contract.CustomerResponse, error)
+	GetList(ctx context.Context, params contract.GetListCustomerParam) (contract.ListCustomerResponse, error)
+	GetDetail(ctx context.Context, id uuid.UUID) (contract.CustomerResponse, error)
+	Update(ctx context.Context, request contract.CustomerRequest, id uuid.UUID) (contract.CustomerResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	SetDunningPaused(ctx context.Context, id uuid.UUID, paused bool) (contract.CustomerResponse, error)
+}
+
+type DunningService interface {
+	GetReminders(ctx context.Context, id string) ([]contract.ReminderResponse, error)
+}
+
+type RecurringService interface {
+	Create(ctx context.Context, request contract.RecurringInvoiceRequest) (contract.RecurringInvoiceResponse, error)
+	GetList(ctx context.Context, params contract.GetListRecurringParam) (contract.ListRecurringInvoiceResponse, error)
+	GetDetail(ctx context.Context, id uuid.UUID) (contract.RecurringInvoiceResponse, error)
+	Update(ctx context.Context, request contract.RecurringInvoiceRequest, id uuid.UUID) (contract.RecurringInvoiceResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"context"
 	"log"
 	"net/http"
 
 	"github.com/Risuii/invoice/src/errors"
 	"github.com/Risuii/invoice/src/middleware/response"
 	"github.com/Risuii/invoice/src/v1/contract"
+
+	i18n_err "github.com/Risuii/frs-lib/i18n/errors"
 )
 
 func CreateInvoiceHandler(svc InvoiceService) http.HandlerFunc {
@@ -19,16 +22,114 @@ func CreateInvoiceHandler(svc InvoiceService) http.HandlerFunc {
 		}
 
 		res, err := svc.Create(r.Context(), invoiceRequest)
+		if err != nil {
+			log.Println(err)
+			if e, ok := err.(*errors.TotalsMismatchError); ok {
+				response.JSONUnprocessableEntityWithDetails(r.Context(), w, e, e.Mismatches)
+				return
+			}
+
+			switch err {
+			case errors.ErrCustomerIdNotFound,
+				errors.ErrDuplicateTaxInvoiceNumber,
+				errors.ErrInvoiceSeriesNotFound,
+				errors.ErrInvoiceAmountOutOfRange:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+// CreateInvoiceBatchHandler creates the invoices of a JSON array, at most maxSize of them, and
+// reports the outcome of every entry. With atomic=true either all of them are created or none.
+func CreateInvoiceBatchHandler(svc InvoiceService, maxSize int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		batchRequest, err := contract.BuildAndValidateBatchRequest(r, maxSize)
+		if err != nil {
+			if err == errors.ErrInvoiceBatchTooLarge {
+				response.JSONError(r.Context(), w, http.StatusRequestEntityTooLarge, errors.ErrInvoiceBatchTooLarge)
+				return
+			}
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		results, err := svc.CreateBatch(r.Context(), batchRequest)
 		if err != nil {
 			log.Println(err)
 			response.JSONInternalErrorResponse(r.Context(), w)
 			return
 		}
 
+		res := contract.BatchInvoiceResponse{
+			Atomic:  batchRequest.Atomic,
+			Results: make([]contract.BatchEntryResponse, len(results)),
+		}
+
+		for i, result := range results {
+			res.Results[i] = contract.BatchEntryResponse{Index: i, InvoiceID: result.InvoiceID}
+			if result.Err == nil {
+				res.Created++
+				continue
+			}
+
+			res.Failed++
+			res.Results[i].Error = batchEntryError(r.Context(), result.Err)
+		}
+
 		response.JSONSuccessResponse(r.Context(), w, res)
 	}
 }
 
+// batchEntryError describes the error of a batch entry the way the single create endpoint would.
+func batchEntryError(ctx context.Context, err error) *response.Error {
+	if _, ok := err.(*contract.InvalidEntryError); ok {
+		return response.NewError(ctx, i18n_err.ErrBadRequest, nil)
+	}
+
+	if e, ok := err.(*errors.TotalsMismatchError); ok {
+		return response.NewError(ctx, e, e.Mismatches)
+	}
+
+	switch err {
+	case errors.ErrCustomerIdNotFound,
+		errors.ErrDuplicateTaxInvoiceNumber,
+		errors.ErrInvoiceSeriesNotFound,
+		errors.ErrInvoiceAmountOutOfRange,
+		errors.ErrInvoiceBatchAborted:
+		return response.NewError(ctx, err.(i18n_err.I18nError), nil)
+	default:
+		return response.NewError(ctx, i18n_err.ErrInternalServer, nil)
+	}
+}
+
+// ImportInvoicesHandler creates the invoices of the CSV body and reports the rows that failed.
+// With dry_run=true nothing is created.
+func ImportInvoicesHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		importRequest, err := contract.BuildAndValidateImportRequest(w, r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Import(r.Context(), importRequest)
+		if err != nil {
+			log.Println(err)
+			response.JSONInternalErrorResponse(r.Context(), w)
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+// UpdateInvoiceHandler replaces a draft invoice, the body is a full invoice request.
 func UpdateInvoiceHandler(svc InvoiceService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := contract.ValidateIDParamRequest(r)
@@ -38,18 +139,115 @@ func UpdateInvoiceHandler(svc InvoiceService) http.HandlerFunc {
 			return
 		}
 
+		version, ok := validateIfMatch(w, r)
+		if !ok {
+			return
+		}
+
 		invoiceRequest, err := contract.BuildAndValidateInvoiceRequest(r)
 		if err != nil {
 			response.JSONBadRequestResponse(r.Context(), w)
 			return
 		}
 
-		res, err := svc.Update(r.Context(), invoiceRequest, id)
+		res, err := svc.Update(r.Context(), invoiceRequest, id, version)
+		writeInvoiceUpdateResponse(w, r, res, err)
+	}
+}
+
+// PatchInvoiceHandler changes a draft invoice with a JSON merge patch.
+func PatchInvoiceHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		version, ok := validateIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		patch, err := contract.BuildAndValidateInvoicePatchRequest(r)
+		if err != nil {
+			log.Println(err)
+			if err == errors.ErrUnsupportedPatchMediaType {
+				w.Header().Set("Accept-Patch", contract.MergePatchContentType)
+				response.JSONError(r.Context(), w, http.StatusUnsupportedMediaType, errors.ErrUnsupportedPatchMediaType)
+				return
+			}
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Patch(r.Context(), patch, id, version)
+		writeInvoiceUpdateResponse(w, r, res, err)
+	}
+}
+
+// validateIfMatch reads the invoice version of the If-Match header and writes the error response
+// when there is none.
+func validateIfMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	version, err := contract.ValidateIfMatchRequest(r)
+	if err != nil {
+		log.Println(err)
+		if err == errors.ErrInvoiceIfMatchRequired {
+			response.JSONError(r.Context(), w, http.StatusPreconditionRequired, errors.ErrInvoiceIfMatchRequired)
+			return 0, false
+		}
+		response.JSONBadRequestResponse(r.Context(), w)
+		return 0, false
+	}
+
+	return version, true
+}
+
+func writeInvoiceUpdateResponse(w http.ResponseWriter, r *http.Request, res contract.InvcResponse, err error) {
+	if err != nil {
+		log.Println(err)
+		if e, ok := err.(*errors.TotalsMismatchError); ok {
+			response.JSONUnprocessableEntityWithDetails(r.Context(), w, e, e.Mismatches)
+			return
+		}
+
+		switch err {
+		case errors.ErrInvoiceVersionMismatch:
+			response.JSONError(r.Context(), w, http.StatusPreconditionFailed, errors.ErrInvoiceVersionMismatch)
+		case errors.ErrInvoiceIdNotFound,
+			errors.ErrCustomerIdNotFound,
+			errors.ErrInvoiceNotEditable,
+			errors.ErrInvoiceItemNotFound,
+			errors.ErrInvoicePatchInvalid,
+			errors.ErrInvoiceAmountOutOfRange,
+			errors.ErrDuplicateTaxInvoiceNumber:
+			response.JSONUnprocessableEntity(r.Context(), w, err)
+		default:
+			response.JSONInternalErrorResponse(r.Context(), w)
+		}
+		return
+	}
+
+	w.Header().Set("ETag", contract.InvoiceETag(res.Version))
+	response.JSONSuccessResponse(r.Context(), w, res)
+}
+
+func DeleteInvoiceHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		err = svc.Delete(r.Context(), id)
 		if err != nil {
 			log.Println(err)
 			switch err {
 			case errors.ErrInvoiceIdNotFound,
-				errors.ErrCustomerIdNotFound:
+				errors.ErrInvoiceNotDeletable:
 				response.JSONUnprocessableEntity(r.Context(), w, err)
 			default:
 				response.JSONInternalErrorResponse(r.Context(), w)
@@ -57,10 +255,58 @@ func UpdateInvoiceHandler(svc InvoiceService) http.HandlerFunc {
 			return
 		}
 
+		response.JSONSuccessResponse(r.Context(), w, nil)
+	}
+}
+
+func RestoreInvoiceHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Restore(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrCustomerIdNotFound,
+				errors.ErrDuplicateTaxInvoiceNumber:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		w.Header().Set("ETag", contract.InvoiceETag(res.Version))
 		response.JSONSuccessResponse(r.Context(), w, res)
 	}
 }
 
+func GetTrashInvoicesHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := contract.ValidateAndBuildTrashListRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetTrash(r.Context(), *params)
+		if err != nil {
+			log.Println(err)
+			response.JSONInternalErrorResponse(r.Context(), w)
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
+
 func GetListInvoicesHandler(svc InvoiceService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params, err := contract.ValidateAndBuildRequest(r)
@@ -103,6 +349,7 @@ func GetDetailInvoicesHandler(svc InvoiceService) http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set("ETag", contract.InvoiceETag(data.Version))
 		response.JSONSuccessResponse(r.Context(), w, data)
 	}
 }
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/middleware/response"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+type transitionFunc func(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error)
+
+func IssueInvoiceHandler(svc InvoiceService) http.HandlerFunc {
+	return transitionInvoiceHandler(svc.Issue, false)
+}
+
+func VoidInvoiceHandler(svc InvoiceService) http.HandlerFunc {
+	return transitionInvoiceHandler(svc.Void, true)
+}
+
+func ReopenInvoiceHandler(svc InvoiceService) http.HandlerFunc {
+	return transitionInvoiceHandler(svc.Reopen, true)
+}
+
+func transitionInvoiceHandler(transition transitionFunc, reasonRequired bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		transitionRequest, err := contract.BuildAndValidateTransitionRequest(r, reasonRequired)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := transition(r.Context(), transitionRequest, id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrInvoiceInvalidStatusTransition,
+				errors.ErrInvoiceHasPayments,
+				errors.ErrInvoiceHasCreditNotes:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_handler "github.com/Risuii/invoice/src/v1/handler/mock"
+)
+
+func TestHandler_TransitionInvoice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			request      *contract.InvoiceTransitionRequest
+			statusCode   int
+			responseBody string
+		}
+
+		given struct {
+			action       string
+			payload      string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	testCases := []testCase{
+		{
+			name: "success issue without body",
+			given: given{
+				action: "issue",
+			},
+			expected: expected{
+				request:      &contract.InvoiceTransitionRequest{},
+				statusCode:   200,
+				responseBody: `{"data":{"invoice_id":"","status":""},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err void without reason",
+			given: given{
+				action:  "void",
+				payload: `{"reason": "  "}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err void invalid transition",
+			given: given{
+				action:       "void",
+				payload:      `{"reason": "duplicate invoice"}`,
+				svcErrReturn: errorss.ErrInvoiceInvalidStatusTransition,
+			},
+			expected: expected{
+				request:      &contract.InvoiceTransitionRequest{Reason: "duplicate invoice"},
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_invalid_status_transition","message_title":"Invalid Invoice Status","message":"The invoice cannot be moved to the requested status.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err reopen internal server",
+			given: given{
+				action:       "reopen",
+				payload:      `{"reason": "wrong customer"}`,
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				request:      &contract.InvoiceTransitionRequest{Reason: "wrong customer"},
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(testCase.given.payload))
+			w := httptest.NewRecorder()
+
+			dataFromService := contract.InvoiceStatusResponse{}
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			var hf http.HandlerFunc
+			switch testCase.given.action {
+			case "issue":
+				hf = IssueInvoiceHandler(mockInvoiceSvc)
+				if testCase.expected.request != nil {
+					mockInvoiceSvc.EXPECT().Issue(gomock.Any(), *testCase.expected.request, "").
+						Return(dataFromService, testCase.given.svcErrReturn).
+						Times(1)
+				}
+			case "void":
+				hf = VoidInvoiceHandler(mockInvoiceSvc)
+				if testCase.expected.request != nil {
+					mockInvoiceSvc.EXPECT().Void(gomock.Any(), *testCase.expected.request, "").
+						Return(dataFromService, testCase.given.svcErrReturn).
+						Times(1)
+				}
+			case "reopen":
+				hf = ReopenInvoiceHandler(mockInvoiceSvc)
+				if testCase.expected.request != nil {
+					mockInvoiceSvc.EXPECT().Reopen(gomock.Any(), *testCase.expected.request, "").
+						Return(dataFromService, testCase.given.svcErrReturn).
+						Times(1)
+				}
+			}
+
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -8,7 +9,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Risuii/invoice/src/decimal"
 	"github.com/Risuii/invoice/src/v1/contract"
 	"github.com/go-playground/assert"
 	"go.uber.org/mock/gomock"
@@ -28,6 +31,7 @@ func TestHandler_GetListInovice(t *testing.T) {
 		}
 
 		given struct {
+			target       string
 			param        contract.GetListParam
 			svcErrReturn error
 		}
@@ -39,7 +43,108 @@ func TestHandler_GetListInovice(t *testing.T) {
 		}
 	)
 
+	grandTotalMin := decimal.NewFromInt(100)
+	mockCursor := contract.Cursor{CreatedAt: time.Date(2024, time.March, 7, 10, 30, 0, 123456000, time.UTC), ID: 42}
+
 	testCases := []testCase{
+		{
+			name: "err unknown status",
+			given: given{
+				target: "/just/for/testing?status=Paid,Lost",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err unknown sort field",
+			given: given{
+				target: "/just/for/testing?sort=-deleted_at",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err invalid date range",
+			given: given{
+				target: "/just/for/testing?due_date_from=2024-03-01",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err page zero",
+			given: given{
+				target: "/just/for/testing?page=0",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err malformed cursor",
+			given: given{
+				target: "/just/for/testing?after=not-a-cursor",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err sort in cursor mode",
+			given: given{
+				target: "/just/for/testing?after=&sort=grand_total",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success cursor mode",
+			given: given{
+				target: "/just/for/testing?limit=500&after=" + contract.EncodeCursor(mockCursor),
+				param: contract.GetListParam{
+					Page:       1,
+					Limit:      500,
+					CursorMode: true,
+					After:      mockCursor,
+				},
+			},
+			expected: expected{
+				statusCode:   200,
+				responseBody: `{"data":{"Data":null,"Pagination":null},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success with ranges, statuses and sort",
+			given: given{
+				target: "/just/for/testing?issue_date_from=01-03-2024&issue_date_to=31-03-2024&grand_total_min=100&status=paid,%20Issued&sort=-due_date,grand_total",
+				param: contract.GetListParam{
+					Page:          1,
+					Limit:         10,
+					IssueDateFrom: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+					IssueDateTo:   time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+					GrandTotalMin: &grandTotalMin,
+					Statuses:      []string{"Paid", "Issued"},
+					Sort: []contract.SortField{
+						{Field: "due_date", Desc: true},
+						{Field: "grand_total"},
+					},
+				},
+			},
+			expected: expected{
+				statusCode:   200,
+				responseBody: `{"data":{"Data":null,"Pagination":null},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
 		{
 			name: "err",
 			given: given{
@@ -72,15 +177,22 @@ func TestHandler_GetListInovice(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			r := httptest.NewRequest(http.MethodGet, "/just/for/testing", nil)
+			target := testCase.given.target
+			if target == "" {
+				target = "/just/for/testing"
+			}
+
+			r := httptest.NewRequest(http.MethodGet, target, nil)
 			w := httptest.NewRecorder()
 
 			dataFromService := contract.ListInvoiceResponse{}
 			InoviceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
 
-			InoviceSvc.EXPECT().GetList(gomock.Any(), testCase.given.param).
-				Return(dataFromService, testCase.given.svcErrReturn).
-				Times(1)
+			if testCase.expected.statusCode != http.StatusBadRequest {
+				InoviceSvc.EXPECT().GetList(gomock.Any(), testCase.given.param).
+					Return(dataFromService, testCase.given.svcErrReturn).
+					Times(1)
+			}
 
 			hf := http.HandlerFunc(GetListInvoicesHandler(InoviceSvc))
 			hf.ServeHTTP(w, r)
@@ -105,6 +217,7 @@ func TestHandler_GetInovice(t *testing.T) {
 		expected struct {
 			statusCode   int
 			responseBody string
+			etag         string
 		}
 
 		given struct {
@@ -161,7 +274,8 @@ func TestHandler_GetInovice(t *testing.T) {
 			},
 			expected: expected{
 				statusCode:   200,
-				responseBody: `{"data":{"invoice_id":"","issue_date":"","subject":"","total_item":0,"item":null,"customer_name":"","due_date":"","status":"","sub_total":0,"tax":0,"grand_total":0},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				responseBody: `{"data":{"invoice_id":"","issue_date":"","subject":"","total_item":0,"item":null,"customer_name":"","customer_address":"","due_date":"","status":"","days_overdue":0,"sub_total":0,"tax_rate":0,"tax":0,"grand_total":0,"amount_credited":0,"amount_paid":0,"outstanding_balance":0,"payments":null,"tax_invoice_number":"","version":2},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				etag:         `"2"`,
 			},
 		},
 	}
@@ -171,7 +285,7 @@ func TestHandler_GetInovice(t *testing.T) {
 			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/just/for/testing/%s", testCase.given.id), nil)
 			w := httptest.NewRecorder()
 
-			dataFromService := contract.InvoiceResponse{}
+			dataFromService := contract.InvoiceResponse{Version: 2}
 			mockInovice := mock_handler.NewMockInvoiceService(mockCtrl)
 
 			mockInovice.EXPECT().GetDetail(gomock.Any(), testCase.given.id).
@@ -189,6 +303,7 @@ func TestHandler_GetInovice(t *testing.T) {
 
 			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
 			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+			assert.Equal(t, testCase.expected.etag, res.Header.Get("ETag"))
 		})
 	}
 }
@@ -307,10 +422,10 @@ func TestHandler_CreateInvoice(t *testing.T) {
 					Subject:    "test-subject-1",
 					IssueDate:  "23-01-2023",
 					DueDate:    "23-01-2024",
-					SubTotal:   300,
-					Tax:        10,
-					GrandTotal: 200,
-					CustomerRequest: contract.CustomerRequest{
+					SubTotal:   decimal.NewFromInt(300),
+					Tax:        decimal.NewFromInt(10),
+					GrandTotal: decimal.NewFromInt(200),
+					CustomerRequest: &contract.CustomerRequest{
 						CustomerName: "test-customer-name-1",
 						Address:      "test-address-1",
 					},
@@ -318,23 +433,23 @@ func TestHandler_CreateInvoice(t *testing.T) {
 						{
 							Name:      "test-1",
 							Type:      "test-type",
-							Quantity:  1,
-							UnitPrice: 1,
-							Amount:    1,
+							Quantity:  decimal.NewFromInt(1),
+							UnitPrice: decimal.NewFromInt(1),
+							Amount:    decimal.NewFromInt(1),
 						},
 						{
 							Name:      "test-2",
 							Type:      "test-type",
-							Quantity:  2,
-							UnitPrice: 2,
-							Amount:    2,
+							Quantity:  decimal.NewFromInt(2),
+							UnitPrice: decimal.NewFromInt(2),
+							Amount:    decimal.NewFromInt(2),
 						},
 						{
 							Name:      "test-3",
 							Type:      "test-type",
-							Quantity:  3,
-							UnitPrice: 3,
-							Amount:    3,
+							Quantity:  decimal.NewFromInt(3),
+							UnitPrice: decimal.NewFromInt(3),
+							Amount:    decimal.NewFromInt(3),
 						},
 					},
 				},
@@ -342,6 +457,98 @@ func TestHandler_CreateInvoice(t *testing.T) {
 				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
 			},
 		},
+		{
+			name: "err invoice series not found",
+			given: given{
+				payload: `{
+					"subject": "test-subject-1",
+					"issue_date": "23-01-2023",
+					"due_date": "23-01-2024",
+					"series": "exp",
+					"customer_request": {
+						"customer_name": "test-customer-name-1",
+						"address": "test-address-1"
+					},
+					"item_request": [
+						{
+							"name": "test-1",
+							"type": "test-type",
+							"quantity": 1,
+							"unit_price": 1,
+							"amount": 1
+						}
+					]
+				}`,
+				svcErrReturn: errorss.ErrInvoiceSeriesNotFound,
+			},
+			expected: expected{
+				request: &contract.InvoiceRequest{
+					Subject:   "test-subject-1",
+					IssueDate: "23-01-2023",
+					DueDate:   "23-01-2024",
+					Series:    "EXP",
+					CustomerRequest: &contract.CustomerRequest{
+						CustomerName: "test-customer-name-1",
+						Address:      "test-address-1",
+					},
+					ItemRequest: []contract.ItemRequest{
+						{
+							Name:      "test-1",
+							Type:      "test-type",
+							Quantity:  decimal.NewFromInt(1),
+							UnitPrice: decimal.NewFromInt(1),
+							Amount:    decimal.NewFromInt(1),
+						},
+					},
+				},
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_series_not_found","message_title":"Unknown Invoice Series","message":"The requested invoice number series does not exist.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err invoice amount out of range",
+			given: given{
+				payload: `{
+					"subject": "test-subject-1",
+					"issue_date": "23-01-2023",
+					"due_date": "23-01-2024",
+					"customer_request": {
+						"customer_name": "test-customer-name-1",
+						"address": "test-address-1"
+					},
+					"item_request": [
+						{
+							"name": "test-1",
+							"type": "test-type",
+							"quantity": 99999999999,
+							"unit_price": 99999999999
+						}
+					]
+				}`,
+				svcErrReturn: errorss.ErrInvoiceAmountOutOfRange,
+			},
+			expected: expected{
+				request: &contract.InvoiceRequest{
+					Subject:   "test-subject-1",
+					IssueDate: "23-01-2023",
+					DueDate:   "23-01-2024",
+					CustomerRequest: &contract.CustomerRequest{
+						CustomerName: "test-customer-name-1",
+						Address:      "test-address-1",
+					},
+					ItemRequest: []contract.ItemRequest{
+						{
+							Name:      "test-1",
+							Type:      "test-type",
+							Quantity:  decimal.NewFromInt(99999999999),
+							UnitPrice: decimal.NewFromInt(99999999999),
+						},
+					},
+				},
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_amount_out_of_range","message_title":"Amount Out of Range","message":"The quantities and unit prices give an amount too large to store","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
 		{
 			name: "success",
 			given: given{
@@ -387,10 +594,10 @@ func TestHandler_CreateInvoice(t *testing.T) {
 					Subject:    "test-subject-1",
 					IssueDate:  "23-01-2023",
 					DueDate:    "23-01-2024",
-					SubTotal:   300,
-					Tax:        10,
-					GrandTotal: 200,
-					CustomerRequest: contract.CustomerRequest{
+					SubTotal:   decimal.NewFromInt(300),
+					Tax:        decimal.NewFromInt(10),
+					GrandTotal: decimal.NewFromInt(200),
+					CustomerRequest: &contract.CustomerRequest{
 						CustomerName: "test-customer-name-1",
 						Address:      "test-address-1",
 					},
@@ -398,23 +605,23 @@ func TestHandler_CreateInvoice(t *testing.T) {
 						{
 							Name:      "test-1",
 							Type:      "test-type",
-							Quantity:  1,
-							UnitPrice: 1,
-							Amount:    1,
+							Quantity:  decimal.NewFromInt(1),
+							UnitPrice: decimal.NewFromInt(1),
+							Amount:    decimal.NewFromInt(1),
 						},
 						{
 							Name:      "test-2",
 							Type:      "test-type",
-							Quantity:  2,
-							UnitPrice: 2,
-							Amount:    2,
+							Quantity:  decimal.NewFromInt(2),
+							UnitPrice: decimal.NewFromInt(2),
+							Amount:    decimal.NewFromInt(2),
 						},
 						{
 							Name:      "test-3",
 							Type:      "test-type",
-							Quantity:  3,
-							UnitPrice: 3,
-							Amount:    3,
+							Quantity:  decimal.NewFromInt(3),
+							UnitPrice: decimal.NewFromInt(3),
+							Amount:    decimal.NewFromInt(3),
 						},
 					},
 				},
@@ -454,6 +661,274 @@ func TestHandler_CreateInvoice(t *testing.T) {
 	}
 }
 
+func TestHandler_CreateInvoiceBatch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	validEntry := `{"subject":"Design","issue_date":"07-03-2024","due_date":"06-04-2024","customer_request":{"customer_name":"Acme","address":"Jakarta"},"item_request":[{"name":"logo","quantity":1,"unit_price":100}]}`
+	invalidEntry := `{"subject":"","issue_date":"07-03-2024","due_date":"06-04-2024"}`
+
+	badRequest := `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`
+
+	validRequest := contract.InvoiceRequest{
+		Subject:   "design",
+		IssueDate: "07-03-2024",
+		DueDate:   "06-04-2024",
+		CustomerRequest: &contract.CustomerRequest{
+			CustomerName: "acme",
+			Address:      "Jakarta",
+		},
+		ItemRequest: []contract.ItemRequest{
+			{Name: "logo", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		target       string
+		payload      string
+		atomic       bool
+		svcResults   []contract.BatchResult
+		svcErr       error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad atomic",
+			target:       "/just/for/testing/batch?atomic=maybe",
+			payload:      "[" + validEntry + "]",
+			statusCode:   400,
+			responseBody: badRequest,
+		},
+		{
+			name:         "err not an array",
+			target:       "/just/for/testing/batch",
+			payload:      validEntry,
+			statusCode:   400,
+			responseBody: badRequest,
+		},
+		{
+			name:         "err empty batch",
+			target:       "/just/for/testing/batch",
+			payload:      "[]",
+			statusCode:   400,
+			responseBody: badRequest,
+		},
+		{
+			name:         "err batch too large",
+			target:       "/just/for/testing/batch",
+			payload:      "[" + validEntry + "," + validEntry + "," + validEntry + "]",
+			statusCode:   413,
+			responseBody: `{"data":null,"error":{"code":"err_invoice_batch_too_large","message_title":"Batch Too Large","message":"The batch has more invoices than allowed, split it into smaller batches.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			target:       "/just/for/testing/batch?atomic=true",
+			payload:      "[" + validEntry + "]",
+			atomic:       true,
+			svcErr:       errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:    "success partial",
+			target:  "/just/for/testing/batch",
+			payload: "[" + validEntry + "," + invalidEntry + "]",
+			svcResults: []contract.BatchResult{
+				{InvoiceID: "0001"},
+				{Err: &contract.InvalidEntryError{Err: errors.New("subject is required")}},
+			},
+			statusCode:   200,
+			responseBody: `{"data":{"atomic":false,"created":1,"failed":1,"results":[{"index":0,"invoice_id":"0001"},{"index":1,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"}}]},"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:    "success atomic aborted",
+			target:  "/just/for/testing/batch?atomic=true",
+			payload: "[" + validEntry + "," + validEntry + "]",
+			atomic:  true,
+			svcResults: []contract.BatchResult{
+				{Err: errorss.ErrInvoiceBatchAborted},
+				{Err: errorss.ErrCustomerIdNotFound},
+			},
+			statusCode:   200,
+			responseBody: `{"data":{"atomic":true,"created":0,"failed":2,"results":[{"index":0,"error":{"code":"err_invoice_batch_aborted","message_title":"Batch Aborted","message":"The invoice was not created because another invoice of the batch failed.","message_severity":"error"}},{"index":1,"error":{"code":"err_customer_id_not_found","message_title":"err_customer_id_not_found_title","message":"err_customer_id_not_found_message","message_severity":"error"}}]},"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, testCase.target, strings.NewReader(testCase.payload))
+			w := httptest.NewRecorder()
+
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.statusCode != 400 && testCase.statusCode != 413 {
+				mockInvoiceSvc.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, request contract.BatchInvoiceRequest) ([]contract.BatchResult, error) {
+						assert.Equal(t, testCase.atomic, request.Atomic)
+						assert.Equal(t, validRequest, request.Entries[0].Request)
+						assert.Equal(t, nil, request.Entries[0].Err)
+						if len(request.Entries) > 1 && testCase.svcResults[1].Err != errorss.ErrCustomerIdNotFound {
+							_, invalid := request.Entries[1].Err.(*contract.InvalidEntryError)
+							assert.Equal(t, true, invalid)
+						}
+
+						return testCase.svcResults, testCase.svcErr
+					}).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(CreateInvoiceBatchHandler(mockInvoiceSvc, 2))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_ImportInvoices(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	payload := "\ufeffreference,subject,issue_date,due_date,tax_rate,customer_name,customer_address,item_name,quantity,unit_price\n" +
+		"A-1,Design,07-03-2024,06-04-2024,11,Acme,Jakarta,logo,1,100\n" +
+		"A-1,,,,,,,banner,2,50.5\n" +
+		"B-1,Design!,07-03-2024,06-04-2024,,Acme,Jakarta,logo,1,100\n" +
+		",Design,07-03-2024,06-04-2024,,Acme,Jakarta,logo,1,100\n" +
+		"C-1,Web,07-03-2024,06-04-2024,,Acme,Jakarta,site,0,100\n" +
+		"D-1,Web,2024-03-07,06-04-2024,,Acme,Jakarta,site,1,100\n" +
+		"E-1,Web,07-03-2024,06-04-2024,,Acme,Jakarta,site,1,100\n" +
+		"E-1,Hosting,,,,,,server,1,100\n"
+
+	importRequest := contract.ImportRequest{
+		DryRun: true,
+		Invoices: []contract.ImportInvoice{
+			{
+				Reference: "A-1",
+				Rows:      []int{2, 3},
+				Request: contract.InvoiceRequest{
+					Subject:   "design",
+					IssueDate: "07-03-2024",
+					DueDate:   "06-04-2024",
+					TaxRate:   decimal.NewFromInt(11),
+					CustomerRequest: &contract.CustomerRequest{
+						CustomerName: "acme",
+						Address:      "Jakarta",
+					},
+					ItemRequest: []contract.ItemRequest{
+						{Name: "logo", Quantity: decimal.RequireFromString("1"), UnitPrice: decimal.RequireFromString("100")},
+						{Name: "banner", Quantity: decimal.RequireFromString("2"), UnitPrice: decimal.RequireFromString("50.5")},
+					},
+				},
+			},
+		},
+		Errors: []contract.ImportError{{Row: 5, Message: "reference is required"}},
+	}
+
+	// the invoices failing validation only matter by their errors
+	failed := map[string][]contract.ImportError{
+		"B-1": {{Row: 4, Reference: "B-1", Message: "there is special characters"}},
+		"C-1": {{Row: 6, Reference: "C-1", Message: "quantity: gt=0"}},
+		"D-1": {{Row: 7, Reference: "D-1", Message: "issue_date: want a dd-mm-yyyy date"}},
+		"E-1": {{Row: 9, Reference: "E-1", Message: "subject differs from the first row of the invoice"}},
+	}
+
+	testCases := []struct {
+		name         string
+		target       string
+		payload      string
+		svcErr       error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad dry run",
+			target:       "/just/for/testing/import?dry_run=maybe",
+			payload:      payload,
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err missing column",
+			target:       "/just/for/testing/import?dry_run=true",
+			payload:      "reference,subject\nA-1,Design\n",
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			target:       "/just/for/testing/import?dry_run=true",
+			payload:      payload,
+			svcErr:       errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			target:       "/just/for/testing/import?dry_run=true",
+			payload:      payload,
+			statusCode:   200,
+			responseBody: `{"data":{"dry_run":true,"total":5,"imported":1,"failed":4,"invoices":[{"reference":"A-1","rows":[2,3],"invoice_id":""}],"errors":[]},"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, testCase.target, strings.NewReader(testCase.payload))
+			w := httptest.NewRecorder()
+
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.statusCode != 400 {
+				mockInvoiceSvc.EXPECT().Import(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, request contract.ImportRequest) (contract.ImportResponse, error) {
+						assert.Equal(t, len(importRequest.Invoices)+len(failed), len(request.Invoices))
+
+						valid := request
+						valid.Invoices = nil
+						for _, invoice := range request.Invoices {
+							if errs, ok := failed[invoice.Reference]; ok {
+								assert.Equal(t, errs, invoice.Errors)
+								continue
+							}
+							valid.Invoices = append(valid.Invoices, invoice)
+						}
+						assert.Equal(t, importRequest, valid)
+
+						return contract.ImportResponse{
+							DryRun:   true,
+							Total:    5,
+							Imported: 1,
+							Failed:   4,
+							Invoices: []contract.ImportResult{{Reference: "A-1", Rows: []int{2, 3}}},
+							Errors:   []contract.ImportError{},
+						}, testCase.svcErr
+					}).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(ImportInvoicesHandler(mockInvoiceSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
+
 func TestHandler_UpdateInvoice(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -461,13 +936,17 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 	type (
 		expected struct {
 			request      *contract.InvoiceRequest
+			version      int64
 			statusCode   int
 			responseBody string
+			etag         string
 		}
 
 		given struct {
 			id           string
+			ifMatch      string
 			payload      string
+			newVersion   int64
 			svcErrReturn error
 		}
 
@@ -482,10 +961,10 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 		Subject:    "test-subject-2",
 		IssueDate:  "23-01-2023",
 		DueDate:    "23-01-2024",
-		SubTotal:   300,
-		Tax:        10,
-		GrandTotal: 200,
-		CustomerRequest: contract.CustomerRequest{
+		SubTotal:   decimal.NewFromInt(300),
+		Tax:        decimal.NewFromInt(10),
+		GrandTotal: decimal.NewFromInt(200),
+		CustomerRequest: &contract.CustomerRequest{
 			CustomerName: "test-customer-name-2",
 			Address:      "test-address-2",
 		},
@@ -493,16 +972,16 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 			{
 				Name:      "test-1",
 				Type:      "test-type",
-				Quantity:  1,
-				UnitPrice: 1,
-				Amount:    1,
+				Quantity:  decimal.NewFromInt(1),
+				UnitPrice: decimal.NewFromInt(1),
+				Amount:    decimal.NewFromInt(1),
 			},
 			{
 				Name:      "test-2",
 				Type:      "test-type",
-				Quantity:  2,
-				UnitPrice: 2,
-				Amount:    2,
+				Quantity:  decimal.NewFromInt(2),
+				UnitPrice: decimal.NewFromInt(2),
+				Amount:    decimal.NewFromInt(2),
 			},
 		},
 	}
@@ -510,7 +989,7 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 	testCases := []testCase{
 		{
 			name:  "err bad request",
-			given: given{},
+			given: given{ifMatch: `"3"`},
 			expected: expected{
 				statusCode:   400,
 				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
@@ -519,7 +998,8 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 		{
 			name: "err invoice id not found",
 			given: given{
-				id: "",
+				id:      "",
+				ifMatch: `"3"`,
 				payload: `{
 					"subject": "test-subject-2",
 					"issue_date": "23-01-2023",
@@ -552,6 +1032,7 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 			},
 			expected: expected{
 				request:      &request,
+				version:      3,
 				statusCode:   422,
 				responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
 			},
@@ -559,7 +1040,8 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 		{
 			name: "err customer id not found",
 			given: given{
-				id: "",
+				id:      "",
+				ifMatch: `"3"`,
 				payload: `{
 					"subject": "test-subject-2",
 					"issue_date": "23-01-2023",
@@ -592,6 +1074,7 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 			},
 			expected: expected{
 				request:      &request,
+				version:      3,
 				statusCode:   422,
 				responseBody: `{"data":null,"error":{"code":"err_customer_id_not_found","message_title":"err_customer_id_not_found_title","message":"err_customer_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
 			},
@@ -599,7 +1082,8 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 		{
 			name: "err internal server",
 			given: given{
-				id: "",
+				id:      "",
+				ifMatch: `"3"`,
 				payload: `{
 					"subject": "test-subject-2",
 					"issue_date": "23-01-2023",
@@ -632,6 +1116,7 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 			},
 			expected: expected{
 				request:      &request,
+				version:      3,
 				statusCode:   500,
 				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
 			},
@@ -639,7 +1124,8 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 		{
 			name: "err internal server",
 			given: given{
-				id: "",
+				id:      "",
+				ifMatch: `"3"`,
 				payload: `{
 					"subject": "test-subject-2",
 					"issue_date": "23-01-2023",
@@ -668,27 +1154,192 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 						}
 					]
 				}`,
+				newVersion:   4,
 				svcErrReturn: nil,
 			},
 			expected: expected{
 				request:      &request,
+				version:      3,
 				statusCode:   200,
-				responseBody: `{"data":{"invoice_id":""},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				responseBody: `{"data":{"invoice_id":"","version":4},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				etag:         `"4"`,
 			},
 		},
-	}
-
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(testCase.given.payload))
-			w := httptest.NewRecorder()
-
-			dataFromService := contract.InvcResponse{}
-
+		{
+			name: "err if match required",
+			given: given{
+				payload: `{
+					"subject": "test-subject-2",
+					"issue_date": "23-01-2023",
+					"due_date": "23-01-2024",
+					"sub_total": 300,
+					"tax": 10,
+					"grand_total": 200,
+					"customer_request": {
+						"customer_name": "test-customer-name-2",
+						"address": "test-address-2"
+					},
+					"item_request": [
+						{
+							"name": "test-1",
+							"type": "test-type",
+							"quantity": 1,
+							"unit_price": 1,
+							"amount": 1
+						},
+						{
+							"name": "test-2",
+							"type": "test-type",
+							"quantity": 2,
+							"unit_price": 2,
+							"amount": 2
+						}
+					]
+				}`,
+			},
+			expected: expected{
+				statusCode:   428,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_if_match_required","message_title":"Precondition Required","message":"Send the ETag of the invoice in the If-Match header","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err weak if match",
+			given: given{
+				ifMatch: `W/"3"`,
+				payload: `{
+					"subject": "test-subject-2",
+					"issue_date": "23-01-2023",
+					"due_date": "23-01-2024",
+					"sub_total": 300,
+					"tax": 10,
+					"grand_total": 200,
+					"customer_request": {
+						"customer_name": "test-customer-name-2",
+						"address": "test-address-2"
+					},
+					"item_request": [
+						{
+							"name": "test-1",
+							"type": "test-type",
+							"quantity": 1,
+							"unit_price": 1,
+							"amount": 1
+						},
+						{
+							"name": "test-2",
+							"type": "test-type",
+							"quantity": 2,
+							"unit_price": 2,
+							"amount": 2
+						}
+					]
+				}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err version mismatch",
+			given: given{
+				ifMatch: `"3"`,
+				payload: `{
+					"subject": "test-subject-2",
+					"issue_date": "23-01-2023",
+					"due_date": "23-01-2024",
+					"sub_total": 300,
+					"tax": 10,
+					"grand_total": 200,
+					"customer_request": {
+						"customer_name": "test-customer-name-2",
+						"address": "test-address-2"
+					},
+					"item_request": [
+						{
+							"name": "test-1",
+							"type": "test-type",
+							"quantity": 1,
+							"unit_price": 1,
+							"amount": 1
+						},
+						{
+							"name": "test-2",
+							"type": "test-type",
+							"quantity": 2,
+							"unit_price": 2,
+							"amount": 2
+						}
+					]
+				}`,
+				svcErrReturn: errorss.ErrInvoiceVersionMismatch,
+			},
+			expected: expected{
+				request:      &request,
+				version:      3,
+				statusCode:   412,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_version_mismatch","message_title":"Invoice Changed","message":"The invoice was changed by someone else, reload it and try again","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success any version",
+			given: given{
+				ifMatch: "*",
+				payload: `{
+					"subject": "test-subject-2",
+					"issue_date": "23-01-2023",
+					"due_date": "23-01-2024",
+					"sub_total": 300,
+					"tax": 10,
+					"grand_total": 200,
+					"customer_request": {
+						"customer_name": "test-customer-name-2",
+						"address": "test-address-2"
+					},
+					"item_request": [
+						{
+							"name": "test-1",
+							"type": "test-type",
+							"quantity": 1,
+							"unit_price": 1,
+							"amount": 1
+						},
+						{
+							"name": "test-2",
+							"type": "test-type",
+							"quantity": 2,
+							"unit_price": 2,
+							"amount": 2
+						}
+					]
+				}`,
+				newVersion: 8,
+			},
+			expected: expected{
+				request:      &request,
+				version:      contract.AnyVersion,
+				statusCode:   200,
+				responseBody: `{"data":{"invoice_id":"","version":8},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				etag:         `"8"`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(testCase.given.payload))
+			w := httptest.NewRecorder()
+
+			if testCase.given.ifMatch != "" {
+				r.Header.Set("If-Match", testCase.given.ifMatch)
+			}
+
+			dataFromService := contract.InvcResponse{Version: testCase.given.newVersion}
+
 			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
 
 			if testCase.expected.request != nil {
-				mockInvoiceSvc.EXPECT().Update(gomock.Any(), *testCase.expected.request, testCase.given.id).
+				mockInvoiceSvc.EXPECT().Update(gomock.Any(), *testCase.expected.request, testCase.given.id, testCase.expected.version).
 					Return(dataFromService, testCase.given.svcErrReturn).
 					Times(1)
 			}
@@ -702,6 +1353,443 @@ func TestHandler_UpdateInvoice(t *testing.T) {
 				t.Errorf("expected error to be nil got %v", err)
 			}
 
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+			assert.Equal(t, testCase.expected.etag, res.Header.Get("ETag"))
+		})
+	}
+}
+
+func TestHandler_PatchInvoice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			callSvc      bool
+			statusCode   int
+			responseBody string
+			etag         string
+			acceptPatch  string
+		}
+
+		given struct {
+			ifMatch      string
+			contentType  string
+			payload      string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	testCases := []testCase{
+		{
+			name: "err if match required",
+			given: given{
+				contentType: "application/merge-patch+json",
+				payload:     `{"due_date":"30-04-2024"}`,
+			},
+			expected: expected{
+				statusCode:   428,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_if_match_required","message_title":"Precondition Required","message":"Send the ETag of the invoice in the If-Match header","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err unsupported media type",
+			given: given{
+				ifMatch:     `"3"`,
+				contentType: "text/plain",
+				payload:     `{"due_date":"30-04-2024"}`,
+			},
+			expected: expected{
+				statusCode:   415,
+				responseBody: `{"data":null,"error":{"code":"err_unsupported_patch_media_type","message_title":"Unsupported Media Type","message":"Send the patch as application/merge-patch+json","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+				acceptPatch:  "application/merge-patch+json",
+			},
+		},
+		{
+			name: "err patch is not an object",
+			given: given{
+				ifMatch:     `"3"`,
+				contentType: "application/merge-patch+json",
+				payload:     `["due_date"]`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err patched invoice invalid",
+			given: given{
+				ifMatch:      `"3"`,
+				contentType:  "application/merge-patch+json",
+				payload:      `{"subject":null}`,
+				svcErrReturn: errorss.ErrInvoicePatchInvalid,
+			},
+			expected: expected{
+				callSvc:      true,
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_patch_invalid","message_title":"Invalid Patch","message":"The patched invoice is not a valid invoice","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err version mismatch",
+			given: given{
+				ifMatch:      `"3"`,
+				contentType:  "application/merge-patch+json",
+				payload:      `{"due_date":"30-04-2024"}`,
+				svcErrReturn: errorss.ErrInvoiceVersionMismatch,
+			},
+			expected: expected{
+				callSvc:      true,
+				statusCode:   412,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_version_mismatch","message_title":"Invoice Changed","message":"The invoice was changed by someone else, reload it and try again","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				ifMatch:     `"3"`,
+				contentType: "application/merge-patch+json",
+				payload:     `{"due_date":"30-04-2024"}`,
+			},
+			expected: expected{
+				callSvc:      true,
+				statusCode:   200,
+				responseBody: `{"data":{"invoice_id":"0001","version":4},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				etag:         `"4"`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/just/for/testing", strings.NewReader(testCase.given.payload))
+			r.Header.Set("Content-Type", testCase.given.contentType)
+			if testCase.given.ifMatch != "" {
+				r.Header.Set("If-Match", testCase.given.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.expected.callSvc {
+				dataFromService := contract.InvcResponse{}
+				if testCase.given.svcErrReturn == nil {
+					dataFromService = contract.InvcResponse{InvoiceID: "0001", Version: 4}
+				}
+
+				mockInvoiceSvc.EXPECT().Patch(gomock.Any(), []byte(testCase.given.payload), "", int64(3)).
+					Return(dataFromService, testCase.given.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(PatchInvoiceHandler(mockInvoiceSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+			assert.Equal(t, testCase.expected.etag, res.Header.Get("ETag"))
+			assert.Equal(t, testCase.expected.acceptPatch, res.Header.Get("Accept-Patch"))
+		})
+	}
+}
+
+func TestHandler_DeleteInvoice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			statusCode   int
+			responseBody string
+		}
+
+		given struct {
+			id           string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	testCases := []testCase{
+		{
+			name: "err internal server",
+			given: given{
+				id:           "",
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err invoice id not found",
+			given: given{
+				id:           "",
+				svcErrReturn: errorss.ErrInvoiceIdNotFound,
+			},
+			expected: expected{
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err invoice not deletable",
+			given: given{
+				id:           "",
+				svcErrReturn: errorss.ErrInvoiceNotDeletable,
+			},
+			expected: expected{
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_not_deletable","message_title":"Invoice Not Deletable","message":"Only draft invoices can be deleted","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				id:           "",
+				svcErrReturn: nil,
+			},
+			expected: expected{
+				statusCode:   200,
+				responseBody: `{"data":null,"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/just/for/testing/%s", testCase.given.id), nil)
+			w := httptest.NewRecorder()
+
+			mockInovice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			mockInovice.EXPECT().Delete(gomock.Any(), testCase.given.id).
+				Return(testCase.given.svcErrReturn).
+				Times(1)
+
+			hf := http.HandlerFunc(DeleteInvoiceHandler(mockInovice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_RestoreInvoice(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			statusCode   int
+			responseBody string
+			etag         string
+		}
+
+		given struct {
+			id           string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	testCases := []testCase{
+		{
+			name: "err internal server",
+			given: given{
+				id:           "",
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err invoice not in trash",
+			given: given{
+				id:           "",
+				svcErrReturn: errorss.ErrInvoiceIdNotFound,
+			},
+			expected: expected{
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err customer id not found",
+			given: given{
+				id:           "",
+				svcErrReturn: errorss.ErrCustomerIdNotFound,
+			},
+			expected: expected{
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_customer_id_not_found","message_title":"err_customer_id_not_found_title","message":"err_customer_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				id:           "",
+				svcErrReturn: nil,
+			},
+			expected: expected{
+				statusCode:   200,
+				responseBody: `{"data":{"invoice_id":"0001","version":3},"error":null,"success":true,"metadata":{"request_id":""}}`,
+				etag:         `"3"`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/just/for/testing/%s/restore", testCase.given.id), nil)
+			w := httptest.NewRecorder()
+
+			dataFromService := contract.InvcResponse{}
+			if testCase.given.svcErrReturn == nil {
+				dataFromService = contract.InvcResponse{InvoiceID: "0001", Version: 3}
+			}
+
+			mockInovice := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			mockInovice.EXPECT().Restore(gomock.Any(), testCase.given.id).
+				Return(dataFromService, testCase.given.svcErrReturn).
+				Times(1)
+
+			hf := http.HandlerFunc(RestoreInvoiceHandler(mockInovice))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+			assert.Equal(t, testCase.expected.etag, res.Header.Get("ETag"))
+		})
+	}
+}
+
+func TestHandler_GetTrashInvoices(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			statusCode   int
+			responseBody string
+		}
+
+		given struct {
+			target       string
+			param        contract.GetTrashListParam
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	testCases := []testCase{
+		{
+			name: "err invalid page",
+			given: given{
+				target: "/just/for/testing?page=0",
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err internal server",
+			given: given{
+				param:        contract.GetTrashListParam{Page: 1, Limit: 10},
+				svcErrReturn: errors.New("error"),
+			},
+			expected: expected{
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				target: "/just/for/testing?page=2&limit=5",
+				param:  contract.GetTrashListParam{Page: 2, Limit: 5, Offset: 5},
+			},
+			expected: expected{
+				statusCode:   200,
+				responseBody: `{"data":{"Data":null,"Pagination":null},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			target := testCase.given.target
+			if target == "" {
+				target = "/just/for/testing"
+			}
+
+			r := httptest.NewRequest(http.MethodGet, target, nil)
+			w := httptest.NewRecorder()
+
+			InoviceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.expected.statusCode != http.StatusBadRequest {
+				InoviceSvc.EXPECT().GetTrash(gomock.Any(), testCase.given.param).
+					Return(contract.ListTrashResponse{}, testCase.given.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(GetTrashInvoicesHandler(InoviceSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
 			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
 			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
 		})
@@ -10,9 +10,12 @@ package mock_handler
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
+	document "github.com/Risuii/invoice/src/document"
 	contract "github.com/Risuii/invoice/src/v1/contract"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,6 +57,124 @@ func (mr *MockInvoiceServiceMockRecorder) Create(ctx, request any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvoiceService)(nil).Create), ctx, request)
 }
 
+// CreateBatch mocks base method.
+func (m *MockInvoiceService) CreateBatch(ctx context.Context, request contract.BatchInvoiceRequest) ([]contract.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, request)
+	ret0, _ := ret[0].([]contract.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockInvoiceServiceMockRecorder) CreateBatch(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockInvoiceService)(nil).CreateBatch), ctx, request)
+}
+
+// CreateCreditNote mocks base method.
+func (m *MockInvoiceService) CreateCreditNote(ctx context.Context, request contract.CreditNoteRequest, id string) (contract.InvoiceCreditNoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditNote", ctx, request, id)
+	ret0, _ := ret[0].(contract.InvoiceCreditNoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditNote indicates an expected call of CreateCreditNote.
+func (mr *MockInvoiceServiceMockRecorder) CreateCreditNote(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditNote", reflect.TypeOf((*MockInvoiceService)(nil).CreateCreditNote), ctx, request, id)
+}
+
+// CreatePayment mocks base method.
+func (m *MockInvoiceService) CreatePayment(ctx context.Context, request contract.PaymentRequest, id string) (contract.InvoicePaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, request, id)
+	ret0, _ := ret[0].(contract.InvoicePaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockInvoiceServiceMockRecorder) CreatePayment(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockInvoiceService)(nil).CreatePayment), ctx, request, id)
+}
+
+// Delete mocks base method.
+func (m *MockInvoiceService) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockInvoiceServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInvoiceService)(nil).Delete), ctx, id)
+}
+
+// Export mocks base method.
+func (m *MockInvoiceService) Export(ctx context.Context, params contract.GetListParam, exportParam contract.ExportParam, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, params, exportParam, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockInvoiceServiceMockRecorder) Export(ctx, params, exportParam, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockInvoiceService)(nil).Export), ctx, params, exportParam, w)
+}
+
+// ExportEFaktur mocks base method.
+func (m *MockInvoiceService) ExportEFaktur(ctx context.Context, params contract.EFakturParam) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportEFaktur", ctx, params)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportEFaktur indicates an expected call of ExportEFaktur.
+func (mr *MockInvoiceServiceMockRecorder) ExportEFaktur(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportEFaktur", reflect.TypeOf((*MockInvoiceService)(nil).ExportEFaktur), ctx, params)
+}
+
+// ExportUBL mocks base method.
+func (m *MockInvoiceService) ExportUBL(ctx context.Context, id string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUBL", ctx, id)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportUBL indicates an expected call of ExportUBL.
+func (mr *MockInvoiceServiceMockRecorder) ExportUBL(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUBL", reflect.TypeOf((*MockInvoiceService)(nil).ExportUBL), ctx, id)
+}
+
+// GetCreditNotes mocks base method.
+func (m *MockInvoiceService) GetCreditNotes(ctx context.Context, id string) ([]contract.CreditNoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreditNotes", ctx, id)
+	ret0, _ := ret[0].([]contract.CreditNoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreditNotes indicates an expected call of GetCreditNotes.
+func (mr *MockInvoiceServiceMockRecorder) GetCreditNotes(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditNotes", reflect.TypeOf((*MockInvoiceService)(nil).GetCreditNotes), ctx, id)
+}
+
 // GetDetail mocks base method.
 func (m *MockInvoiceService) GetDetail(ctx context.Context, id string) (contract.InvoiceResponse, error) {
 	m.ctrl.T.Helper()
@@ -84,17 +205,444 @@ func (mr *MockInvoiceServiceMockRecorder) GetList(ctx, params any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInvoiceService)(nil).GetList), ctx, params)
 }
 
+// GetPayments mocks base method.
+func (m *MockInvoiceService) GetPayments(ctx context.Context, id string) ([]contract.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayments", ctx, id)
+	ret0, _ := ret[0].([]contract.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayments indicates an expected call of GetPayments.
+func (mr *MockInvoiceServiceMockRecorder) GetPayments(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayments", reflect.TypeOf((*MockInvoiceService)(nil).GetPayments), ctx, id)
+}
+
+// GetTrash mocks base method.
+func (m *MockInvoiceService) GetTrash(ctx context.Context, params contract.GetTrashListParam) (contract.ListTrashResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, params)
+	ret0, _ := ret[0].(contract.ListTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockInvoiceServiceMockRecorder) GetTrash(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockInvoiceService)(nil).GetTrash), ctx, params)
+}
+
+// Import mocks base method.
+func (m *MockInvoiceService) Import(ctx context.Context, request contract.ImportRequest) (contract.ImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, request)
+	ret0, _ := ret[0].(contract.ImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockInvoiceServiceMockRecorder) Import(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockInvoiceService)(nil).Import), ctx, request)
+}
+
+// Issue mocks base method.
+func (m *MockInvoiceService) Issue(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", ctx, request, id)
+	ret0, _ := ret[0].(contract.InvoiceStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockInvoiceServiceMockRecorder) Issue(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockInvoiceService)(nil).Issue), ctx, request, id)
+}
+
+// Patch mocks base method.
+func (m *MockInvoiceService) Patch(ctx context.Context, patch []byte, id string, version int64) (contract.InvcResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, patch, id, version)
+	ret0, _ := ret[0].(contract.InvcResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockInvoiceServiceMockRecorder) Patch(ctx, patch, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockInvoiceService)(nil).Patch), ctx, patch, id, version)
+}
+
+// PreviewHTML mocks base method.
+func (m *MockInvoiceService) PreviewHTML(ctx context.Context, templateName string, labels document.Labels) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewHTML", ctx, templateName, labels)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewHTML indicates an expected call of PreviewHTML.
+func (mr *MockInvoiceServiceMockRecorder) PreviewHTML(ctx, templateName, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewHTML", reflect.TypeOf((*MockInvoiceService)(nil).PreviewHTML), ctx, templateName, labels)
+}
+
+// RenderHTML mocks base method.
+func (m *MockInvoiceService) RenderHTML(ctx context.Context, id, templateName string, labels document.Labels) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderHTML", ctx, id, templateName, labels)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderHTML indicates an expected call of RenderHTML.
+func (mr *MockInvoiceServiceMockRecorder) RenderHTML(ctx, id, templateName, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderHTML", reflect.TypeOf((*MockInvoiceService)(nil).RenderHTML), ctx, id, templateName, labels)
+}
+
+// RenderPDF mocks base method.
+func (m *MockInvoiceService) RenderPDF(ctx context.Context, id, templateName string, labels document.Labels) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderPDF", ctx, id, templateName, labels)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderPDF indicates an expected call of RenderPDF.
+func (mr *MockInvoiceServiceMockRecorder) RenderPDF(ctx, id, templateName, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderPDF", reflect.TypeOf((*MockInvoiceService)(nil).RenderPDF), ctx, id, templateName, labels)
+}
+
+// Reopen mocks base method.
+func (m *MockInvoiceService) Reopen(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reopen", ctx, request, id)
+	ret0, _ := ret[0].(contract.InvoiceStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reopen indicates an expected call of Reopen.
+func (mr *MockInvoiceServiceMockRecorder) Reopen(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reopen", reflect.TypeOf((*MockInvoiceService)(nil).Reopen), ctx, request, id)
+}
+
+// Restore mocks base method.
+func (m *MockInvoiceService) Restore(ctx context.Context, id string) (contract.InvcResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(contract.InvcResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockInvoiceServiceMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockInvoiceService)(nil).Restore), ctx, id)
+}
+
+// SetTaxInvoiceNumber mocks base method.
+func (m *MockInvoiceService) SetTaxInvoiceNumber(ctx context.Context, request contract.TaxInvoiceNumberRequest, id string) (contract.TaxInvoiceNumberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaxInvoiceNumber", ctx, request, id)
+	ret0, _ := ret[0].(contract.TaxInvoiceNumberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTaxInvoiceNumber indicates an expected call of SetTaxInvoiceNumber.
+func (mr *MockInvoiceServiceMockRecorder) SetTaxInvoiceNumber(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaxInvoiceNumber", reflect.TypeOf((*MockInvoiceService)(nil).SetTaxInvoiceNumber), ctx, request, id)
+}
+
 // Update mocks base method.
-func (m *MockInvoiceService) Update(ctx context.Context, request contract.InvoiceRequest, id string) (contract.InvcResponse, error) {
+func (m *MockInvoiceService) Update(ctx context.Context, request contract.InvoiceRequest, id string, version int64) (contract.InvcResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, request, id)
+	ret := m.ctrl.Call(m, "Update", ctx, request, id, version)
 	ret0, _ := ret[0].(contract.InvcResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockInvoiceServiceMockRecorder) Update(ctx, request, id any) *gomock.Call {
+func (mr *MockInvoiceServiceMockRecorder) Update(ctx, request, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInvoiceService)(nil).Update), ctx, request, id, version)
+}
+
+// Void mocks base method.
+func (m *MockInvoiceService) Void(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, request, id)
+	ret0, _ := ret[0].(contract.InvoiceStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockInvoiceServiceMockRecorder) Void(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockInvoiceService)(nil).Void), ctx, request, id)
+}
+
+// MockCustomerService is a mock of CustomerService interface.
+type MockCustomerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerServiceMockRecorder
+}
+
+// MockCustomerServiceMockRecorder is the mock recorder for MockCustomerService.
+type MockCustomerServiceMockRecorder struct {
+	mock *MockCustomerService
+}
+
+// NewMockCustomerService creates a new mock instance.
+func NewMockCustomerService(ctrl *gomock.Controller) *MockCustomerService {
+	mock := &MockCustomerService{ctrl: ctrl}
+	mock.recorder = &MockCustomerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerService) EXPECT() *MockCustomerServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCustomerService) Create(ctx context.Context, request contract.CustomerRequest) (contract.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(contract.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCustomerServiceMockRecorder) Create(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerService)(nil).Create), ctx, request)
+}
+
+// Delete mocks base method.
+func (m *MockCustomerService) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCustomerServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerService)(nil).Delete), ctx, id)
+}
+
+// GetDetail mocks base method.
+func (m *MockCustomerService) GetDetail(ctx context.Context, id uuid.UUID) (contract.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, id)
+	ret0, _ := ret[0].(contract.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockCustomerServiceMockRecorder) GetDetail(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockCustomerService)(nil).GetDetail), ctx, id)
+}
+
+// GetList mocks base method.
+func (m *MockCustomerService) GetList(ctx context.Context, params contract.GetListCustomerParam) (contract.ListCustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, params)
+	ret0, _ := ret[0].(contract.ListCustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockCustomerServiceMockRecorder) GetList(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockCustomerService)(nil).GetList), ctx, params)
+}
+
+// SetDunningPaused mocks base method.
+func (m *MockCustomerService) SetDunningPaused(ctx context.Context, id uuid.UUID, paused bool) (contract.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDunningPaused", ctx, id, paused)
+	ret0, _ := ret[0].(contract.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDunningPaused indicates an expected call of SetDunningPaused.
+func (mr *MockCustomerServiceMockRecorder) SetDunningPaused(ctx, id, paused any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDunningPaused", reflect.TypeOf((*MockCustomerService)(nil).SetDunningPaused), ctx, id, paused)
+}
+
+// Update mocks base method.
+func (m *MockCustomerService) Update(ctx context.Context, request contract.CustomerRequest, id uuid.UUID) (contract.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, request, id)
+	ret0, _ := ret[0].(contract.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCustomerServiceMockRecorder) Update(ctx, request, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerService)(nil).Update), ctx, request, id)
+}
+
+// MockDunningService is a mock of DunningService interface.
+type MockDunningService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDunningServiceMockRecorder
+}
+
+// MockDunningServiceMockRecorder is the mock recorder for MockDunningService.
+type MockDunningServiceMockRecorder struct {
+	mock *MockDunningService
+}
+
+// NewMockDunningService creates a new mock instance.
+func NewMockDunningService(ctrl *gomock.Controller) *MockDunningService {
+	mock := &MockDunningService{ctrl: ctrl}
+	mock.recorder = &MockDunningServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDunningService) EXPECT() *MockDunningServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReminders mocks base method.
+func (m *MockDunningService) GetReminders(ctx context.Context, id string) ([]contract.ReminderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReminders", ctx, id)
+	ret0, _ := ret[0].([]contract.ReminderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReminders indicates an expected call of GetReminders.
+func (mr *MockDunningServiceMockRecorder) GetReminders(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReminders", reflect.TypeOf((*MockDunningService)(nil).GetReminders), ctx, id)
+}
+
+// MockRecurringService is a mock of RecurringService interface.
+type MockRecurringService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringServiceMockRecorder
+}
+
+// MockRecurringServiceMockRecorder is the mock recorder for MockRecurringService.
+type MockRecurringServiceMockRecorder struct {
+	mock *MockRecurringService
+}
+
+// NewMockRecurringService creates a new mock instance.
+func NewMockRecurringService(ctrl *gomock.Controller) *MockRecurringService {
+	mock := &MockRecurringService{ctrl: ctrl}
+	mock.recorder = &MockRecurringServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringService) EXPECT() *MockRecurringServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRecurringService) Create(ctx context.Context, request contract.RecurringInvoiceRequest) (contract.RecurringInvoiceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(contract.RecurringInvoiceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecurringServiceMockRecorder) Create(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecurringService)(nil).Create), ctx, request)
+}
+
+// Delete mocks base method.
+func (m *MockRecurringService) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecurringServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecurringService)(nil).Delete), ctx, id)
+}
+
+// GetDetail mocks base method.
+func (m *MockRecurringService) GetDetail(ctx context.Context, id uuid.UUID) (contract.RecurringInvoiceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, id)
+	ret0, _ := ret[0].(contract.RecurringInvoiceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockRecurringServiceMockRecorder) GetDetail(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockRecurringService)(nil).GetDetail), ctx, id)
+}
+
+// GetList mocks base method.
+func (m *MockRecurringService) GetList(ctx context.Context, params contract.GetListRecurringParam) (contract.ListRecurringInvoiceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, params)
+	ret0, _ := ret[0].(contract.ListRecurringInvoiceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockRecurringServiceMockRecorder) GetList(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockRecurringService)(nil).GetList), ctx, params)
+}
+
+// Update mocks base method.
+func (m *MockRecurringService) Update(ctx context.Context, request contract.RecurringInvoiceRequest, id uuid.UUID) (contract.RecurringInvoiceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, request, id)
+	ret0, _ := ret[0].(contract.RecurringInvoiceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringServiceMockRecorder) Update(ctx, request, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInvoiceService)(nil).Update), ctx, request, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurringService)(nil).Update), ctx, request, id)
 }
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/middleware/response"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+func CreatePaymentHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		paymentRequest, err := contract.BuildAndValidatePaymentRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.CreatePayment(r.Context(), paymentRequest, id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound,
+				errors.ErrInvoiceNotPayable:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+func GetPaymentsHandler(svc InvoiceService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetPayments(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrInvoiceIdNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
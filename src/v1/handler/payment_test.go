@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/assert"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_handler "github.com/Risuii/invoice/src/v1/handler/mock"
+)
+
+func TestHandler_CreatePayment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			request      *contract.PaymentRequest
+			statusCode   int
+			responseBody string
+		}
+
+		given struct {
+			id           string
+			payload      string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	request := contract.PaymentRequest{
+		Amount:      decimal.NewFromInt(100),
+		PaymentDate: "23-01-2023",
+		Method:      "transfer",
+		Reference:   "test-reference",
+	}
+
+	payload := `{
+		"amount": 100,
+		"payment_date": "23-01-2023",
+		"method": "Transfer",
+		"reference": "test-reference"
+	}`
+
+	testCases := []testCase{
+		{
+			name: "err bad request amount",
+			given: given{
+				id: "0001",
+				payload: `{
+					"amount": 0,
+					"payment_date": "23-01-2023",
+					"method": "transfer"
+				}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err bad request payment date",
+			given: given{
+				id: "0001",
+				payload: `{
+					"amount": 100,
+					"payment_date": "2023-01-23",
+					"method": "transfer"
+				}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err invoice id not found",
+			given: given{
+				id:           "0001",
+				payload:      payload,
+				svcErrReturn: errorss.ErrInvoiceIdNotFound,
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err internal server",
+			given: given{
+				id:           "0001",
+				payload:      payload,
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				id:      "0001",
+				payload: payload,
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   200,
+				responseBody: `{"data":{"invoice_id":"","payment_id":"00000000-0000-0000-0000-000000000000","status":"","amount_paid":0,"outstanding_balance":0},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(testCase.given.payload))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testCase.given.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			dataFromService := contract.InvoicePaymentResponse{}
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+
+			if testCase.expected.request != nil {
+				mockInvoiceSvc.EXPECT().CreatePayment(gomock.Any(), *testCase.expected.request, testCase.given.id).
+					Return(dataFromService, testCase.given.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(CreatePaymentHandler(mockInvoiceSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_GetPayments(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err invoice id not found",
+			svcErrReturn: errorss.ErrInvoiceIdNotFound,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			svcErrReturn: errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			statusCode:   200,
+			responseBody: `{"data":[],"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/just/for/testing", nil)
+			w := httptest.NewRecorder()
+
+			mockInvoiceSvc := mock_handler.NewMockInvoiceService(mockCtrl)
+			mockInvoiceSvc.EXPECT().GetPayments(gomock.Any(), "").
+				Return([]contract.PaymentResponse{}, testCase.svcErrReturn).
+				Times(1)
+
+			hf := http.HandlerFunc(GetPaymentsHandler(mockInvoiceSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
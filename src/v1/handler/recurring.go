@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/middleware/response"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+func CreateRecurringHandler(svc RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recurringRequest, err := contract.BuildAndValidateRecurringInvoiceRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Create(r.Context(), recurringRequest)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrCustomerIdNotFound,
+				errors.ErrInvoiceSeriesNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+func GetListRecurringHandler(svc RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := contract.ValidateAndBuildRecurringListRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetList(r.Context(), *params)
+		if err != nil {
+			log.Println(err)
+			response.JSONInternalErrorResponse(r.Context(), w)
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
+
+func GetDetailRecurringHandler(svc RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateRecurringIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		data, err := svc.GetDetail(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrRecurringIdNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, data)
+	}
+}
+
+func UpdateRecurringHandler(svc RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateRecurringIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		recurringRequest, err := contract.BuildAndValidateRecurringInvoiceRequest(r)
+		if err != nil {
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		res, err := svc.Update(r.Context(), recurringRequest, id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrRecurringIdNotFound,
+				errors.ErrCustomerIdNotFound,
+				errors.ErrInvoiceSeriesNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, res)
+	}
+}
+
+func DeleteRecurringHandler(svc RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := contract.ValidateRecurringIDParamRequest(r)
+		if err != nil {
+			log.Println(err)
+			response.JSONBadRequestResponse(r.Context(), w)
+			return
+		}
+
+		err = svc.Delete(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			switch err {
+			case errors.ErrRecurringIdNotFound:
+				response.JSONUnprocessableEntity(r.Context(), w, err)
+			default:
+				response.JSONInternalErrorResponse(r.Context(), w)
+			}
+			return
+		}
+
+		response.JSONSuccessResponse(r.Context(), w, nil)
+	}
+}
@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_handler "github.com/Risuii/invoice/src/v1/handler/mock"
+)
+
+func TestHandler_CreateRecurring(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	type (
+		expected struct {
+			request      *contract.RecurringInvoiceRequest
+			statusCode   int
+			responseBody string
+		}
+
+		given struct {
+			payload      string
+			svcErrReturn error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	request := contract.RecurringInvoiceRequest{
+		CustomerID: uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Subject:    "service payment",
+		TaxRate:    decimal.NewFromInt(11),
+		Interval:   "cron",
+		Cron:       "0 9 1 * *",
+		DueDays:    14,
+		StartDate:  "01-03-2024",
+		Items: []contract.RecurringItemRequest{
+			{Name: "hosting", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	payload := `{
+		"customer_id": "11111111-1111-1111-1111-111111111111",
+		"subject": " service payment ",
+		"tax_rate": 11,
+		"interval": "Cron",
+		"cron": " 0  9 1 * * ",
+		"due_days": 14,
+		"start_date": "01-03-2024",
+		"items": [{"name": "hosting", "quantity": 1, "unit_price": 100}]
+	}`
+
+	badRequest := `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`
+
+	testCases := []testCase{
+		{
+			name: "err bad request interval",
+			given: given{
+				payload: strings.Replace(payload, `"Cron"`, `"daily"`, 1),
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: badRequest,
+			},
+		},
+		{
+			name: "err bad request cron",
+			given: given{
+				payload: strings.Replace(payload, `" 0  9 1 * * "`, `"0 9 1 *"`, 1),
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: badRequest,
+			},
+		},
+		{
+			name: "err bad request end date before start date",
+			given: given{
+				payload: strings.Replace(payload, `"due_days": 14,`, `"due_days": 14, "end_date": "29-02-2024",`, 1),
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: badRequest,
+			},
+		},
+		{
+			name: "err bad request without items",
+			given: given{
+				payload: `{"customer_id": "11111111-1111-1111-1111-111111111111", "subject": "service payment", "interval": "monthly", "start_date": "01-03-2024"}`,
+			},
+			expected: expected{
+				statusCode:   400,
+				responseBody: badRequest,
+			},
+		},
+		{
+			name: "err customer id not found",
+			given: given{
+				payload:      payload,
+				svcErrReturn: errorss.ErrCustomerIdNotFound,
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   422,
+				responseBody: `{"data":null,"error":{"code":"err_customer_id_not_found","message_title":"err_customer_id_not_found_title","message":"err_customer_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "err internal server",
+			given: given{
+				payload:      payload,
+				svcErrReturn: errors.New("error internal server"),
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   500,
+				responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+			},
+		},
+		{
+			name: "success",
+			given: given{
+				payload: payload,
+			},
+			expected: expected{
+				request:      &request,
+				statusCode:   200,
+				responseBody: `{"data":{"recurring_id":"00000000-0000-0000-0000-000000000000","customer_id":"00000000-0000-0000-0000-000000000000","subject":"","tax_rate":0,"series":"","interval":"","cron":"","due_days":0,"start_date":"","end_date":"","next_run":null,"last_run_at":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},"error":null,"success":true,"metadata":{"request_id":""}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/just/for/testing", strings.NewReader(testCase.given.payload))
+			w := httptest.NewRecorder()
+
+			mockRecurringSvc := mock_handler.NewMockRecurringService(mockCtrl)
+
+			if testCase.expected.request != nil {
+				mockRecurringSvc.EXPECT().Create(gomock.Any(), *testCase.expected.request).
+					Return(contract.RecurringInvoiceResponse{}, testCase.given.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(CreateRecurringHandler(mockRecurringSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.expected.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.expected.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_GetListRecurring(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		query        string
+		params       *contract.GetListRecurringParam
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad request",
+			query:        "?page=0",
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			params:       &contract.GetListRecurringParam{Page: 1, Limit: 10},
+			svcErrReturn: errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			query:        "?page=2&limit=5",
+			params:       &contract.GetListRecurringParam{Page: 2, Limit: 5, Offset: 5},
+			statusCode:   200,
+			responseBody: `{"data":{"Data":null,"Pagination":null},"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/just/for/testing"+testCase.query, nil)
+			w := httptest.NewRecorder()
+
+			mockRecurringSvc := mock_handler.NewMockRecurringService(mockCtrl)
+
+			if testCase.params != nil {
+				mockRecurringSvc.EXPECT().GetList(gomock.Any(), *testCase.params).
+					Return(contract.ListRecurringInvoiceResponse{}, testCase.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(GetListRecurringHandler(mockRecurringSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
+
+func TestHandler_DeleteRecurring(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	id := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	testCases := []struct {
+		name         string
+		id           string
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err bad request id",
+			id:           "0001",
+			statusCode:   400,
+			responseBody: `{"data":null,"error":{"code":"err_bad_request","message_title":"Bad Request","message":"Invalid request parameters","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err recurring id not found",
+			id:           id.String(),
+			svcErrReturn: errorss.ErrRecurringIdNotFound,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_recurring_id_not_found","message_title":"Recurring Invoice Not Found","message":"The recurring invoice does not exist or was deleted","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "success",
+			id:           id.String(),
+			statusCode:   200,
+			responseBody: `{"data":null,"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodDelete, "/just/for/testing", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", testCase.id)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			mockRecurringSvc := mock_handler.NewMockRecurringService(mockCtrl)
+
+			if testCase.id == id.String() {
+				mockRecurringSvc.EXPECT().Delete(gomock.Any(), id).
+					Return(testCase.svcErrReturn).
+					Times(1)
+			}
+
+			hf := http.HandlerFunc(DeleteRecurringHandler(mockRecurringSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_handler "github.com/Risuii/invoice/src/v1/handler/mock"
+)
+
+func TestHandler_GetReminders(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sentAt := time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		svcReturn    []contract.ReminderResponse
+		svcErrReturn error
+		statusCode   int
+		responseBody string
+	}{
+		{
+			name:         "err invoice id not found",
+			svcErrReturn: errorss.ErrInvoiceIdNotFound,
+			statusCode:   422,
+			responseBody: `{"data":null,"error":{"code":"err_invoice_id_not_found","message_title":"err_invoice_id_not_found_title","message":"err_invoice_id_not_found_message","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name:         "err internal server",
+			svcErrReturn: errors.New("error internal server"),
+			statusCode:   500,
+			responseBody: `{"data":null,"error":{"code":"err_internal_server","message_title":"Server Error","message":"Failed to process request, please try again in a moment.","message_severity":"error"},"success":false,"metadata":{"request_id":""}}`,
+		},
+		{
+			name: "success",
+			svcReturn: []contract.ReminderResponse{
+				{
+					Stage:     -3,
+					Email:     "finance@customer.co.id",
+					Status:    entity.ReminderSent,
+					SentAt:    &sentAt,
+					Attempts:  1,
+					CreatedAt: sentAt,
+				},
+			},
+			statusCode:   200,
+			responseBody: `{"data":[{"stage":-3,"email":"finance@customer.co.id","status":"sent","error":"","sent_at":"2024-03-11T09:00:00Z","attempts":1,"created_at":"2024-03-11T09:00:00Z"}],"error":null,"success":true,"metadata":{"request_id":""}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/just/for/testing", nil)
+			w := httptest.NewRecorder()
+
+			mockDunningSvc := mock_handler.NewMockDunningService(mockCtrl)
+			mockDunningSvc.EXPECT().GetReminders(gomock.Any(), "").
+				Return(testCase.svcReturn, testCase.svcErrReturn).
+				Times(1)
+
+			hf := http.HandlerFunc(GetRemindersHandler(mockDunningSvc))
+			hf.ServeHTTP(w, r)
+
+			res := w.Result()
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Errorf("expected error to be nil got %v", err)
+			}
+
+			assert.Equal(t, testCase.statusCode, res.StatusCode)
+			assert.Equal(t, fmt.Sprintf("%s\n", testCase.responseBody), string(data))
+		})
+	}
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"net/http"
 
+	"github.com/Risuii/invoice/src/app"
+	"github.com/Risuii/invoice/src/middleware/idempotency"
 	"github.com/Risuii/invoice/src/v1/handler"
 	"github.com/go-chi/chi/v5"
 )
@@ -14,10 +16,51 @@ func Router(r *chi.Mux, deps *Dependency) {
 
 	// product
 
+	idempotent := idempotency.Middleware(deps.Repositories.IdempotencyRepo, app.Config().IdempotencyKeyTTL, app.Config().IdempotencyLease)
+
 	r.Route("/invoice/v1", func(v1 chi.Router) {
-		v1.Post("/", handler.CreateInvoiceHandler(deps.Services.Invoicesvc))
-		v1.Patch("/{id}", handler.UpdateInvoiceHandler(deps.Services.Invoicesvc))
+		v1.With(idempotent).Post("/", handler.CreateInvoiceHandler(deps.Services.Invoicesvc))
+		v1.With(idempotent).Post("/batch", handler.CreateInvoiceBatchHandler(deps.Services.Invoicesvc, app.Config().InvoiceBatchMaxSize))
+		v1.Post("/import", handler.ImportInvoicesHandler(deps.Services.Invoicesvc))
+		v1.Put("/{id}", handler.UpdateInvoiceHandler(deps.Services.Invoicesvc))
+		v1.Patch("/{id}", handler.PatchInvoiceHandler(deps.Services.Invoicesvc))
+		v1.Delete("/{id}", handler.DeleteInvoiceHandler(deps.Services.Invoicesvc))
 		v1.Get("/", handler.GetListInvoicesHandler(deps.Services.Invoicesvc))
+		v1.Get("/efaktur", handler.ExportEFakturHandler(deps.Services.Invoicesvc))
+		v1.Get("/export", handler.ExportInvoicesHandler(deps.Services.Invoicesvc))
+		v1.Get("/trash", handler.GetTrashInvoicesHandler(deps.Services.Invoicesvc))
 		v1.Get("/{id}", handler.GetDetailInvoicesHandler(deps.Services.Invoicesvc))
+		v1.Get("/{id}/pdf", handler.GetInvoicePDFHandler(deps.Services.Invoicesvc))
+		v1.Get("/{id}/html", handler.GetInvoiceHTMLHandler(deps.Services.Invoicesvc))
+		v1.Get("/{id}/ubl", handler.GetInvoiceUBLHandler(deps.Services.Invoicesvc))
+		v1.Put("/{id}/tax-invoice-number", handler.SetTaxInvoiceNumberHandler(deps.Services.Invoicesvc))
+		v1.Get("/templates/{name}/preview", handler.PreviewInvoiceTemplateHandler(deps.Services.Invoicesvc))
+		v1.Post("/{id}/payments", handler.CreatePaymentHandler(deps.Services.Invoicesvc))
+		v1.Get("/{id}/payments", handler.GetPaymentsHandler(deps.Services.Invoicesvc))
+		v1.Post("/{id}/credit-notes", handler.CreateCreditNoteHandler(deps.Services.Invoicesvc))
+		v1.Get("/{id}/credit-notes", handler.GetCreditNotesHandler(deps.Services.Invoicesvc))
+		v1.Get("/{id}/reminders", handler.GetRemindersHandler(deps.Services.Dunningsvc))
+		v1.Post("/{id}/issue", handler.IssueInvoiceHandler(deps.Services.Invoicesvc))
+		v1.Post("/{id}/void", handler.VoidInvoiceHandler(deps.Services.Invoicesvc))
+		v1.Post("/{id}/reopen", handler.ReopenInvoiceHandler(deps.Services.Invoicesvc))
+		v1.Post("/{id}/restore", handler.RestoreInvoiceHandler(deps.Services.Invoicesvc))
+	})
+
+	r.Route("/customer/v1", func(v1 chi.Router) {
+		v1.Post("/", handler.CreateCustomerHandler(deps.Services.Customersvc))
+		v1.Get("/", handler.GetListCustomersHandler(deps.Services.Customersvc))
+		v1.Get("/{id}", handler.GetDetailCustomerHandler(deps.Services.Customersvc))
+		v1.Put("/{id}", handler.UpdateCustomerHandler(deps.Services.Customersvc))
+		v1.Delete("/{id}", handler.DeleteCustomerHandler(deps.Services.Customersvc))
+		v1.Post("/{id}/dunning/pause", handler.PauseDunningHandler(deps.Services.Customersvc))
+		v1.Post("/{id}/dunning/resume", handler.ResumeDunningHandler(deps.Services.Customersvc))
+	})
+
+	r.Route("/recurring/v1", func(v1 chi.Router) {
+		v1.Post("/", handler.CreateRecurringHandler(deps.Services.Recurringsvc))
+		v1.Get("/", handler.GetListRecurringHandler(deps.Services.Recurringsvc))
+		v1.Get("/{id}", handler.GetDetailRecurringHandler(deps.Services.Recurringsvc))
+		v1.Put("/{id}", handler.UpdateRecurringHandler(deps.Services.Recurringsvc))
+		v1.Delete("/{id}", handler.DeleteRecurringHandler(deps.Services.Recurringsvc))
 	})
 }
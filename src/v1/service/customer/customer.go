@@ -0,0 +1,213 @@
+package Customers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+	"github.com/mariomac/gostream/stream"
+
+	frsUtils "github.com/Risuii/frs-lib/utils"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+type UUIDGenerator interface {
+	New() uuid.UUID
+}
+
+type Customerservice struct {
+	CustomerRepo CustomerRepository
+	UUIDGen      UUIDGenerator
+}
+
+func InitCustomerservice(customerRepo CustomerRepository, uuid UUIDGenerator) *Customerservice {
+	return &Customerservice{
+		CustomerRepo: customerRepo,
+		UUIDGen:      uuid,
+	}
+}
+
+func buildCustomerResponse(c entity.Customer) contract.CustomerResponse {
+	return contract.CustomerResponse{
+		CustomerID:     c.CustomerID,
+		Name:           c.Name,
+		Address:        c.Address,
+		NPWP:           c.NPWP,
+		EndpointID:     c.EndpointID,
+		EndpointScheme: c.EndpointScheme,
+		CountryCode:    c.CountryCode,
+		Email:          c.Email,
+		DunningPaused:  c.DunningPaused,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+func (cs *Customerservice) get(ctx context.Context, id uuid.UUID) (entity.Customer, error) {
+	dataCustomer, err := cs.CustomerRepo.Get(ctx, id.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return dataCustomer, errorss.ErrCustomerIdNotFound
+		}
+		log.Println(err)
+		return dataCustomer, err
+	}
+
+	return dataCustomer, nil
+}
+
+func (cs *Customerservice) Create(ctx context.Context, request contract.CustomerRequest) (contract.CustomerResponse, error) {
+	var res contract.CustomerResponse
+
+	insertDataCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID:     cs.UUIDGen.New(),
+			Name:           request.CustomerName,
+			Address:        request.Address,
+			NPWP:           request.NPWP,
+			EndpointID:     request.EndpointID,
+			EndpointScheme: request.EndpointScheme,
+			CountryCode:    request.CountryCode,
+			Email:          request.Email,
+		},
+	}
+
+	err := cs.CustomerRepo.Create(ctx, &insertDataCustomer)
+	if err != nil {
+		log.Println("create customer err: ", err)
+		return res, err
+	}
+
+	dataCustomer, err := cs.get(ctx, insertDataCustomer.CustomerID)
+	if err != nil {
+		return res, err
+	}
+
+	return buildCustomerResponse(dataCustomer), nil
+}
+
+func (cs *Customerservice) GetList(ctx context.Context, params contract.GetListCustomerParam) (contract.ListCustomerResponse, error) {
+	var response contract.ListCustomerResponse
+
+	customers, err := cs.CustomerRepo.GetList(ctx, params)
+	if err != nil {
+		log.Println("getList err: ", err)
+		return response, err
+	}
+
+	count, err := cs.CustomerRepo.GetCustomersCount(ctx, params)
+	if err != nil {
+		log.Println("CustomersCount err: ", err)
+		return response, err
+	}
+
+	pagination := frsUtils.GetPaginationData(params.Page, params.Limit, int(count))
+
+	responseCustomerList := stream.Map(stream.OfSlice(customers), func(c *entity.Customer) *contract.CustomerResponse {
+		res := buildCustomerResponse(*c)
+		return &res
+	}).ToSlice()
+
+	response = contract.ListCustomerResponse{
+		Data:       responseCustomerList,
+		Pagination: pagination,
+	}
+
+	return response, nil
+}
+
+func (cs *Customerservice) GetDetail(ctx context.Context, id uuid.UUID) (contract.CustomerResponse, error) {
+	var res contract.CustomerResponse
+
+	dataCustomer, err := cs.get(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	return buildCustomerResponse(dataCustomer), nil
+}
+
+// Update renames the customer on every invoice billed to it.
+func (cs *Customerservice) Update(ctx context.Context, request contract.CustomerRequest, id uuid.UUID) (contract.CustomerResponse, error) {
+	var res contract.CustomerResponse
+
+	dataCustomer, err := cs.get(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	dataCustomer.Name = request.CustomerName
+	dataCustomer.Address = request.Address
+	dataCustomer.NPWP = request.NPWP
+	dataCustomer.EndpointID = request.EndpointID
+	dataCustomer.EndpointScheme = request.EndpointScheme
+	dataCustomer.CountryCode = request.CountryCode
+	dataCustomer.Email = request.Email
+
+	err = cs.CustomerRepo.Update(ctx, &dataCustomer)
+	if err != nil {
+		log.Println("update customer err: ", err)
+		if err == sql.ErrNoRows {
+			return res, errorss.ErrCustomerIdNotFound
+		}
+		return res, err
+	}
+
+	return buildCustomerResponse(dataCustomer), nil
+}
+
+// SetDunningPaused stops or resumes the payment reminders of every invoice billed to the customer.
+func (cs *Customerservice) SetDunningPaused(ctx context.Context, id uuid.UUID, paused bool) (contract.CustomerResponse, error) {
+	var res contract.CustomerResponse
+
+	dataCustomer, err := cs.get(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	err = cs.CustomerRepo.UpdateDunningPaused(ctx, id.String(), paused)
+	if err != nil {
+		log.Println("update customer err: ", err)
+		if err == sql.ErrNoRows {
+			return res, errorss.ErrCustomerIdNotFound
+		}
+		return res, err
+	}
+	dataCustomer.DunningPaused = paused
+
+	return buildCustomerResponse(dataCustomer), nil
+}
+
+// Delete removes a customer that is not billed on any invoice or recurring invoice.
+func (cs *Customerservice) Delete(ctx context.Context, id uuid.UUID) error {
+	dataCustomer, err := cs.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := cs.CustomerRepo.CountInvoices(ctx, id.String())
+	if err != nil {
+		log.Println("count invoices err: ", err)
+		return err
+	}
+
+	if count > 0 {
+		log.Println("delete customer err: ", errorss.ErrCustomerHasInvoices)
+		return errorss.ErrCustomerHasInvoices
+	}
+
+	err = cs.CustomerRepo.Delete(ctx, &dataCustomer)
+	if err != nil {
+		log.Println("delete customer err: ", err)
+		if err == sql.ErrNoRows {
+			return errorss.ErrCustomerIdNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+package Customers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	frsUtils "github.com/Risuii/frs-lib/utils"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Customers "github.com/Risuii/invoice/src/v1/service/mock/customer"
+)
+
+type FixedUUIDGenerator struct{}
+
+func (g FixedUUIDGenerator) New() uuid.UUID {
+	return uuid.MustParse("00000000-0000-0000-0000-000000000000") // Use a fixed UUID for testing
+}
+
+func TestCustomerService_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	request := contract.CustomerRequest{
+		CustomerName: "test-name",
+		Address:      "test-address",
+	}
+
+	mockEntityCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID: FixedUUIDGenerator{}.New(),
+			Name:       request.CustomerName,
+			Address:    request.Address,
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		createErr error
+		expected  contract.CustomerResponse
+		err       error
+	}{
+		{
+			name:      "err duplicate customer",
+			createErr: errorss.ErrDuplicateCustomer,
+			err:       errorss.ErrDuplicateCustomer,
+		},
+		{
+			name: "success",
+			expected: contract.CustomerResponse{
+				CustomerID: mockEntityCustomer.CustomerID,
+				Name:       mockEntityCustomer.Name,
+				Address:    mockEntityCustomer.Address,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCustomerRepo := mock_Customers.NewMockCustomerRepository(mockCtrl)
+
+			insertData := mockEntityCustomer
+			mockCustomerRepo.EXPECT().Create(gomock.Any(), &insertData).
+				Return(testCase.createErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), mockEntityCustomer.CustomerID.String()).
+				Return(mockEntityCustomer, nil).
+				Times(1)
+
+			customers := InitCustomerservice(mockCustomerRepo, FixedUUIDGenerator{})
+			got, actualErr := customers.Create(context.Background(), request)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestCustomerService_GetList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	params := contract.GetListCustomerParam{Page: 1, Limit: 10, Keyword: "acme"}
+
+	mockCustomers := []*entity.Customer{
+		{
+			CustomerData: entity.CustomerData{
+				CustomerID: FixedUUIDGenerator{}.New(),
+				Name:       "acme",
+				Address:    "jakarta",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		listErr  error
+		countErr error
+		expected contract.ListCustomerResponse
+		err      error
+	}{
+		{
+			name:    "err get list",
+			listErr: errors.New("error internal server"),
+			err:     errors.New("error internal server"),
+		},
+		{
+			name:     "err get count",
+			countErr: errors.New("error internal server"),
+			err:      errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			expected: contract.ListCustomerResponse{
+				Data: []*contract.CustomerResponse{
+					{
+						CustomerID: mockCustomers[0].CustomerID,
+						Name:       "acme",
+						Address:    "jakarta",
+					},
+				},
+				Pagination: frsUtils.GetPaginationData(1, 10, 1),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCustomerRepo := mock_Customers.NewMockCustomerRepository(mockCtrl)
+
+			mockCustomerRepo.EXPECT().GetList(gomock.Any(), params).
+				Return(mockCustomers, testCase.listErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().GetCustomersCount(gomock.Any(), params).
+				Return(int64(1), testCase.countErr).
+				Times(1)
+
+			customers := InitCustomerservice(mockCustomerRepo, FixedUUIDGenerator{})
+			got, actualErr := customers.GetList(context.Background(), params)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestCustomerService_Update(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	request := contract.CustomerRequest{
+		CustomerName: "new-name",
+		Address:      "new-address",
+	}
+
+	mockEntityCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID: id,
+			Name:       "old-name",
+			Address:    "old-address",
+		},
+	}
+
+	updatedCustomer := mockEntityCustomer
+	updatedCustomer.Name = request.CustomerName
+	updatedCustomer.Address = request.Address
+
+	testCases := []struct {
+		name      string
+		getErr    error
+		updateErr error
+		expected  contract.CustomerResponse
+		err       error
+	}{
+		{
+			name:   "err customer id not found",
+			getErr: sql.ErrNoRows,
+			err:    errorss.ErrCustomerIdNotFound,
+		},
+		{
+			name:      "err duplicate customer",
+			updateErr: errorss.ErrDuplicateCustomer,
+			err:       errorss.ErrDuplicateCustomer,
+		},
+		{
+			name: "success",
+			expected: contract.CustomerResponse{
+				CustomerID: id,
+				Name:       request.CustomerName,
+				Address:    request.Address,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCustomerRepo := mock_Customers.NewMockCustomerRepository(mockCtrl)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), id.String()).
+				Return(mockEntityCustomer, testCase.getErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().Update(gomock.Any(), &updatedCustomer).
+				Return(testCase.updateErr).
+				Times(1)
+
+			customers := InitCustomerservice(mockCustomerRepo, FixedUUIDGenerator{})
+			got, actualErr := customers.Update(context.Background(), request, id)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestCustomerService_SetDunningPaused(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	mockEntityCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID: id,
+			Name:       "test-name",
+			Email:      "finance@customer.co.id",
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		getErr    error
+		updateErr error
+		expected  contract.CustomerResponse
+		err       error
+	}{
+		{
+			name:   "err customer id not found",
+			getErr: sql.ErrNoRows,
+			err:    errorss.ErrCustomerIdNotFound,
+		},
+		{
+			name:      "err update customer",
+			updateErr: errors.New("error internal server"),
+			err:       errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			expected: contract.CustomerResponse{
+				CustomerID:    id,
+				Name:          "test-name",
+				Email:         "finance@customer.co.id",
+				DunningPaused: true,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCustomerRepo := mock_Customers.NewMockCustomerRepository(mockCtrl)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), id.String()).
+				Return(mockEntityCustomer, testCase.getErr).
+				Times(1)
+
+			// only the flag is written, the other columns are left as they are stored
+			mockCustomerRepo.EXPECT().UpdateDunningPaused(gomock.Any(), id.String(), true).
+				Return(testCase.updateErr).
+				Times(1)
+
+			customers := InitCustomerservice(mockCustomerRepo, FixedUUIDGenerator{})
+			got, actualErr := customers.SetDunningPaused(context.Background(), id, true)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestCustomerService_Delete(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	mockEntityCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID: id,
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		getErr    error
+		invoices  int64
+		deleteErr error
+		err       error
+	}{
+		{
+			name:   "err customer id not found",
+			getErr: sql.ErrNoRows,
+			err:    errorss.ErrCustomerIdNotFound,
+		},
+		{
+			name:     "err customer has invoices",
+			invoices: 2,
+			err:      errorss.ErrCustomerHasInvoices,
+		},
+		{
+			name:      "err delete customer",
+			deleteErr: errors.New("error internal server"),
+			err:       errors.New("error internal server"),
+		},
+		{
+			name: "success",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockCustomerRepo := mock_Customers.NewMockCustomerRepository(mockCtrl)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), id.String()).
+				Return(mockEntityCustomer, testCase.getErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().CountInvoices(gomock.Any(), id.String()).
+				Return(testCase.invoices, nil).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().Delete(gomock.Any(), &mockEntityCustomer).
+				Return(testCase.deleteErr).
+				Times(1)
+
+			customers := InitCustomerservice(mockCustomerRepo, FixedUUIDGenerator{})
+			actualErr := customers.Delete(context.Background(), id)
+
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package Customers
+
+import (
+	"context"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+)
+
+type CustomerRepository interface {
+	Create(ctx context.Context, data *entity.Customer) error
+	Get(ctx context.Context, id string) (entity.Customer, error)
+	GetList(ctx context.Context, params contract.GetListCustomerParam) ([]*entity.Customer, error)
+	GetCustomersCount(ctx context.Context, params contract.GetListCustomerParam) (int64, error)
+	CountInvoices(ctx context.Context, id string) (int64, error)
+	Update(ctx context.Context, data *entity.Customer) error
+	UpdateDunningPaused(ctx context.Context, id string, paused bool) error
+	Delete(ctx context.Context, data *entity.Customer) error
+}
@@ -0,0 +1,233 @@
+package Dunning
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/mailer"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/mariomac/gostream/stream"
+
+	frsI18n "github.com/Risuii/frs-lib/i18n"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// sendTimeout bounds the delivery of one reminder.
+const sendTimeout = 30 * time.Second
+
+type Dunningservice struct {
+	ReminderRepo ReminderRepository
+	InvoicesRepo InvoicesRepository
+	Mailer       Mailer
+	// Stages are the days from the due date a reminder is sent on, negative before it, sorted
+	Stages []int
+	// MaxAttempts is how many times a reminder that keeps failing is sent at most
+	MaxAttempts int
+	// Language is the translation the reminders are written in
+	Language string
+}
+
+func InitDunningservice(reminderRepo ReminderRepository, invoicesRepo InvoicesRepository, mailer Mailer, stages []int, maxAttempts int, language string) *Dunningservice {
+	sorted := append([]int(nil), stages...)
+	sort.Ints(sorted)
+
+	return &Dunningservice{
+		ReminderRepo: reminderRepo,
+		InvoicesRepo: invoicesRepo,
+		Mailer:       mailer,
+		Stages:       sorted,
+		MaxAttempts:  maxAttempts,
+		Language:     language,
+	}
+}
+
+// GetReminders returns the reminder log of an invoice, earliest stage first.
+func (ds *Dunningservice) GetReminders(ctx context.Context, id string) ([]contract.ReminderResponse, error) {
+	dataInvoices, err := ds.InvoicesRepo.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return nil, errorss.ErrInvoiceIdNotFound
+		}
+		log.Println(err)
+		return nil, err
+	}
+
+	dataReminders, err := ds.ReminderRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return stream.Map(stream.OfSlice(dataReminders), func(r *entity.InvoiceReminder) contract.ReminderResponse {
+		return contract.ReminderResponse{
+			Stage:     r.Stage,
+			Email:     r.Email,
+			Status:    r.Status,
+			Error:     r.Error,
+			SentAt:    r.SentAt,
+			Attempts:  r.Attempts,
+			CreatedAt: r.CreatedAt,
+		}
+	}).ToSlice(), nil
+}
+
+// Start runs SendDue every interval until ctx is done.
+func (ds *Dunningservice) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if sent, err := ds.SendDue(ctx, time.Now()); err != nil {
+			log.Println("send due reminders err: ", err)
+		} else if sent > 0 {
+			log.Printf("sent %d invoice reminders", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends the reminder of the latest stage every unpaid invoice reached by the day of now
+// and returns how many it sent. Earlier stages that were missed are skipped, a customer gets
+// one reminder per invoice at a time. Every stage is recorded before its reminder is sent, so
+// it is sent once at most even with several instances running. A stage that failed is sent
+// again on the next runs until MaxAttempts.
+func (ds *Dunningservice) SendDue(ctx context.Context, now time.Time) (int, error) {
+	var sent int
+
+	if len(ds.Stages) == 0 {
+		return sent, nil
+	}
+
+	today := startOfDay(now.UTC())
+
+	dataInvoices, err := ds.ReminderRepo.GetDue(ctx, today.AddDate(0, 0, -ds.Stages[0]), ds.MaxAttempts)
+	if err != nil {
+		log.Println("get dunning invoices err: ", err)
+		return sent, err
+	}
+
+	for _, v := range dataInvoices {
+		stage, reached := ds.stage(daysFromDue(v.DueDate, today))
+		if !reached || (v.LastStage != nil && *v.LastStage >= stage) {
+			continue
+		}
+
+		ok, err := ds.send(ctx, *v, stage)
+		if err != nil {
+			log.Printf("send reminder of invoice %s err: %v", v.InvoiceID, err)
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// stage returns the latest stage at or before days from the due date.
+func (ds *Dunningservice) stage(days int) (int, bool) {
+	i := sort.SearchInts(ds.Stages, days+1)
+	if i == 0 {
+		return 0, false
+	}
+	return ds.Stages[i-1], true
+}
+
+// send records the reminder of stage and mails it. A reminder that could not be sent is
+// recorded with its error and tried again by a later run while it has attempts left. It
+// returns false when the stage was recorded by someone else.
+func (ds *Dunningservice) send(ctx context.Context, dataInvoice entity.DunningInvoice, stage int) (bool, error) {
+	dataReminder := entity.InvoiceReminder{
+		InvoiceReminderData: entity.InvoiceReminderData{
+			InvoiceID: dataInvoice.InvoiceID,
+			Stage:     stage,
+			Email:     dataInvoice.CustomerEmail,
+			Status:    entity.ReminderPending,
+		},
+	}
+
+	ok, err := ds.ReminderRepo.Create(ctx, &dataReminder, ds.MaxAttempts)
+	if err != nil {
+		log.Println("create invoice reminder err: ", err)
+		return false, err
+	}
+
+	if !ok {
+		return false, nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	sendErr := ds.Mailer.Send(sendCtx, ds.buildMessage(dataInvoice, stage))
+	cancel()
+
+	if sendErr != nil {
+		log.Println("send reminder err: ", sendErr)
+		dataReminder.Status = entity.ReminderFailed
+		dataReminder.Error = sendErr.Error()
+	} else {
+		sentAt := time.Now()
+		dataReminder.Status = entity.ReminderSent
+		dataReminder.SentAt = &sentAt
+	}
+
+	err = ds.ReminderRepo.Update(ctx, &dataReminder)
+	if err != nil {
+		log.Println("update invoice reminder err: ", err)
+		return sendErr == nil, err
+	}
+
+	return sendErr == nil, sendErr
+}
+
+// buildMessage writes the reminder of stage from the reminder_before, reminder_due and
+// reminder_overdue translations, the title is the subject and the message the body.
+func (ds *Dunningservice) buildMessage(dataInvoice entity.DunningInvoice, stage int) mailer.Message {
+	key := "reminder_due"
+	switch {
+	case stage < 0:
+		key = "reminder_before"
+	case stage > 0:
+		key = "reminder_overdue"
+	}
+
+	outstanding := dataInvoice.GrandTotal.Sub(dataInvoice.AmountCredited).Sub(dataInvoice.AmountPaid)
+
+	data := map[string]interface{}{
+		"InvoiceID":    dataInvoice.InvoiceID,
+		"CustomerName": dataInvoice.CustomerName,
+		"Subject":      dataInvoice.Subject,
+		"IssueDate":    dataInvoice.IssueDate.Format("02-01-2006"),
+		"DueDate":      dataInvoice.DueDate.Format("02-01-2006"),
+		"Amount":       document.FormatAmount(outstanding),
+	}
+
+	return mailer.Message{
+		To:      dataInvoice.CustomerEmail,
+		Subject: frsI18n.Title(ds.Language, key, data),
+		Body:    frsI18n.Message(ds.Language, key, data),
+	}
+}
+
+// startOfDay returns midnight UTC of the day t is in.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysFromDue returns how many days today is after the due date, negative before it.
+func daysFromDue(dueDate, today time.Time) int {
+	return int(today.Sub(startOfDay(dueDate)).Hours() / 24)
+}
@@ -0,0 +1,298 @@
+package Dunning
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/mailer"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Dunning "github.com/Risuii/invoice/src/v1/service/mock/dunning"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestDunningService_SendDue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	now := time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)
+	stages := []int{30, -3, 7, 0}
+
+	dunningInvoice := func(dueDate time.Time, email string, lastStage *int) *entity.DunningInvoice {
+		return &entity.DunningInvoice{
+			InvoicesData: entity.InvoicesData{
+				InvoiceID:    "INV/2024/02/00042",
+				IssueDate:    time.Date(2024, time.February, 13, 0, 0, 0, 0, time.UTC),
+				Subject:      "Website maintenance",
+				DueDate:      dueDate,
+				GrandTotal:   decimal.NewFromInt(8098000),
+				AmountPaid:   decimal.NewFromInt(1000000),
+				CustomerName: "PT Maju Bersama",
+			},
+			CustomerEmail: email,
+			LastStage:     lastStage,
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		invoice   *entity.DunningInvoice
+		skipped   bool
+		stage     int
+		claimed   bool
+		sendErr   error
+		status    string
+		reminders string
+		sent      int
+	}{
+		{
+			name:      "before due date",
+			invoice:   dunningInvoice(time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", nil),
+			stage:     -3,
+			claimed:   true,
+			status:    entity.ReminderSent,
+			reminders: "Reminder: invoice INV/2024/02/00042 is due on 14-03-2024",
+			sent:      1,
+		},
+		{
+			name:      "on due date",
+			invoice:   dunningInvoice(time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", intPtr(-3)),
+			stage:     0,
+			claimed:   true,
+			status:    entity.ReminderSent,
+			reminders: "Invoice INV/2024/02/00042 is due today",
+			sent:      1,
+		},
+		{
+			name:      "missed stages skipped",
+			invoice:   dunningInvoice(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", nil),
+			stage:     30,
+			claimed:   true,
+			status:    entity.ReminderSent,
+			reminders: "Invoice INV/2024/02/00042 is overdue",
+			sent:      1,
+		},
+		{
+			name:    "stage already sent",
+			invoice: dunningInvoice(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", intPtr(7)),
+			skipped: true,
+		},
+		{
+			name:    "stage claimed by another instance",
+			invoice: dunningInvoice(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", intPtr(0)),
+			stage:   7,
+		},
+		{
+			// the failed stage 7 has attempts left, so it is not the last stage
+			name:      "failed stage sent again",
+			invoice:   dunningInvoice(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", intPtr(0)),
+			stage:     7,
+			claimed:   true,
+			status:    entity.ReminderSent,
+			reminders: "Invoice INV/2024/02/00042 is overdue",
+			sent:      1,
+		},
+		{
+			name:      "err send reminder",
+			invoice:   dunningInvoice(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), "finance@customer.co.id", intPtr(0)),
+			stage:     7,
+			claimed:   true,
+			sendErr:   errors.New("smtp rcpt to: 550 no such user"),
+			status:    entity.ReminderFailed,
+			reminders: "Invoice INV/2024/02/00042 is overdue",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockReminderRepo := mock_Dunning.NewMockReminderRepository(mockCtrl)
+			mockInvoicesRepo := mock_Dunning.NewMockInvoicesRepository(mockCtrl)
+			mockMailer := mock_Dunning.NewMockMailer(mockCtrl)
+
+			mockReminderRepo.EXPECT().GetDue(gomock.Any(), time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC), 3).
+				Return([]*entity.DunningInvoice{testCase.invoice}, nil).
+				Times(1)
+
+			var claimed, updated *entity.InvoiceReminder
+			mockReminderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), 3).
+				DoAndReturn(func(ctx context.Context, data *entity.InvoiceReminder, maxAttempts int) (bool, error) {
+					claimed = &entity.InvoiceReminder{InvoiceReminderData: data.InvoiceReminderData}
+					return testCase.claimed, nil
+				}).
+				MaxTimes(1)
+
+			var msg mailer.Message
+			mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, m mailer.Message) error {
+					msg = m
+					return testCase.sendErr
+				}).
+				MaxTimes(1)
+
+			mockReminderRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, data *entity.InvoiceReminder) error {
+					updated = data
+					return nil
+				}).
+				MaxTimes(1)
+
+			dunning := InitDunningservice(mockReminderRepo, mockInvoicesRepo, mockMailer, stages, 3, "en-ID")
+			sent, err := dunning.SendDue(context.Background(), now)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.sent, sent)
+			assert.Equal(t, testCase.reminders, msg.Subject)
+
+			if testCase.skipped {
+				assert.Equal(t, (*entity.InvoiceReminder)(nil), claimed)
+				return
+			}
+
+			assert.Equal(t, testCase.stage, claimed.Stage)
+			assert.Equal(t, entity.ReminderPending, claimed.Status)
+
+			if !testCase.claimed {
+				assert.Equal(t, (*entity.InvoiceReminder)(nil), updated)
+				return
+			}
+
+			assert.Equal(t, testCase.status, updated.Status)
+			if testCase.sendErr != nil {
+				assert.Equal(t, testCase.sendErr.Error(), updated.Error)
+				assert.Equal(t, (*time.Time)(nil), updated.SentAt)
+			} else {
+				assert.Equal(t, "", updated.Error)
+				assert.NotEqual(t, (*time.Time)(nil), updated.SentAt)
+			}
+		})
+	}
+}
+
+func TestDunningService_BuildMessage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockMailer := mock_Dunning.NewMockMailer(mockCtrl)
+
+	dunning := InitDunningservice(nil, nil, mockMailer, []int{-3}, 3, "id-ID")
+	msg := dunning.buildMessage(entity.DunningInvoice{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID:      "INV/2024/02/00042",
+			IssueDate:      time.Date(2024, time.February, 13, 0, 0, 0, 0, time.UTC),
+			Subject:        "Website maintenance",
+			DueDate:        time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC),
+			GrandTotal:     decimal.NewFromInt(8098000),
+			AmountPaid:     decimal.NewFromInt(1000000),
+			AmountCredited: decimal.NewFromInt(98000),
+			CustomerName:   "PT Maju Bersama",
+		},
+		CustomerEmail: "finance@customer.co.id",
+	}, -3)
+
+	assert.Equal(t, "finance@customer.co.id", msg.To)
+	assert.Equal(t, "Pengingat: tagihan INV/2024/02/00042 jatuh tempo pada 14-03-2024", msg.Subject)
+	assert.Equal(t, "Yth. PT Maju Bersama,\n\nKami mengingatkan bahwa tagihan INV/2024/02/00042 untuk Website maintenance, diterbitkan pada 13-02-2024, jatuh tempo pada 14-03-2024. Sisa tagihan sebesar 7,000,000.00.\n\nAbaikan email ini jika pembayaran sudah dilakukan.", msg.Body)
+}
+
+func TestDunningService_SendDueNoStages(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockReminderRepo := mock_Dunning.NewMockReminderRepository(mockCtrl)
+	mockReminderRepo.EXPECT().GetDue(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	dunning := InitDunningservice(mockReminderRepo, nil, nil, nil, 3, "en-ID")
+	sent, err := dunning.SendDue(context.Background(), time.Now())
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestDunningService_GetReminders(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	sentAt := time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)
+
+	mockEntityInvoice := entity.Invoices{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID: "INV/2024/02/00042",
+		},
+	}
+
+	mockEntityReminder := &entity.InvoiceReminder{
+		ModelLogTime: entity.ModelLogTime{CreatedAt: sentAt},
+		InvoiceReminderData: entity.InvoiceReminderData{
+			InvoiceID: "INV/2024/02/00042",
+			Stage:     -3,
+			Email:     "finance@customer.co.id",
+			Status:    entity.ReminderSent,
+			SentAt:    &sentAt,
+			Attempts:  1,
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		getErr       error
+		remindersErr error
+		expected     []contract.ReminderResponse
+		err          error
+	}{
+		{
+			name:   "err invoice id not found",
+			getErr: sql.ErrNoRows,
+			err:    errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name:         "err get reminders",
+			remindersErr: errors.New("error internal server"),
+			err:          errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			expected: []contract.ReminderResponse{
+				{
+					Stage:     -3,
+					Email:     "finance@customer.co.id",
+					Status:    entity.ReminderSent,
+					SentAt:    &sentAt,
+					Attempts:  1,
+					CreatedAt: sentAt,
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockReminderRepo := mock_Dunning.NewMockReminderRepository(mockCtrl)
+			mockInvoicesRepo := mock_Dunning.NewMockInvoicesRepository(mockCtrl)
+
+			mockInvoicesRepo.EXPECT().Get(gomock.Any(), "00042").
+				Return(mockEntityInvoice, testCase.getErr).
+				Times(1)
+
+			mockReminderRepo.EXPECT().GetByInvoiceID(gomock.Any(), "INV/2024/02/00042").
+				Return([]*entity.InvoiceReminder{mockEntityReminder}, testCase.remindersErr).
+				Times(1)
+
+			dunning := InitDunningservice(mockReminderRepo, mockInvoicesRepo, nil, nil, 3, "en-ID")
+			got, actualErr := dunning.GetReminders(context.Background(), "00042")
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
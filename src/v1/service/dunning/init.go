@@ -0,0 +1,25 @@
+package Dunning
+
+import (
+	"context"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/mailer"
+)
+
+type ReminderRepository interface {
+	GetDue(ctx context.Context, dueBefore time.Time, maxAttempts int) ([]*entity.DunningInvoice, error)
+	GetByInvoiceID(ctx context.Context, invID string) ([]*entity.InvoiceReminder, error)
+	Create(ctx context.Context, data *entity.InvoiceReminder, maxAttempts int) (bool, error)
+	Update(ctx context.Context, data *entity.InvoiceReminder) error
+}
+
+type InvoicesRepository interface {
+	Get(ctx context.Context, id string) (entity.Invoices, error)
+}
+
+// Mailer delivers the reminders, mailer.SMTPMailer outside of tests.
+type Mailer interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
@@ -0,0 +1,19 @@
+package Dunning
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Risuii/invoice/src/app"
+)
+
+func TestMain(m *testing.M) {
+	os.Chdir("../../../../")
+
+	app.Init(context.Background())
+
+	exitVal := m.Run()
+
+	os.Exit(exitVal)
+}
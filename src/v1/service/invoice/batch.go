@@ -0,0 +1,94 @@
+package Invoices
+
+import (
+	"context"
+	"log"
+
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// CreateBatch creates the invoices of a batch. By default each entry is created in its own
+// transaction like Create, so a failing entry only fails itself. An atomic batch creates every
+// entry in one transaction: when one fails it keeps its error and the others are reported as
+// aborted. The error is only for failures that are not about an entry.
+func (ts *Invoiceservice) CreateBatch(ctx context.Context, request contract.BatchInvoiceRequest) ([]contract.BatchResult, error) {
+	results := make([]contract.BatchResult, len(request.Entries))
+
+	if !request.Atomic {
+		for i, entry := range request.Entries {
+			if entry.Err != nil {
+				results[i].Err = entry.Err
+				continue
+			}
+
+			res, err := ts.Create(ctx, entry.Request)
+			if err != nil {
+				log.Println("create batch entry err: ", err)
+				results[i].Err = err
+				continue
+			}
+
+			results[i].InvoiceID = res.InvoiceID
+		}
+
+		return results, nil
+	}
+
+	totals := make([]invoiceTotals, len(request.Entries))
+	for i, entry := range request.Entries {
+		if entry.Err != nil {
+			return abortBatch(results, i, entry.Err), nil
+		}
+
+		var err error
+		totals[i], err = calculateTotals(entry.Request)
+		if err != nil {
+			log.Println("calculate totals err: ", err)
+			return abortBatch(results, i, err), nil
+		}
+
+		if err := validateTotals(entry.Request, totals[i]); err != nil {
+			log.Println("validate totals err: ", err)
+			return abortBatch(results, i, err), nil
+		}
+	}
+
+	failed := -1
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		for i, entry := range request.Entries {
+			res, err := ts.insertInvoice(ctx, entry.Request, totals[i])
+			if err != nil {
+				failed = i
+				return err
+			}
+
+			results[i].InvoiceID = res.InvoiceID
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("create atomic batch err: ", err)
+		if failed < 0 {
+			return nil, err
+		}
+
+		return abortBatch(results, failed, err), nil
+	}
+
+	return results, nil
+}
+
+// abortBatch reports err on the failed entry and every other entry of the batch as aborted.
+func abortBatch(results []contract.BatchResult, failed int, err error) []contract.BatchResult {
+	for i := range results {
+		results[i] = contract.BatchResult{Err: errorss.ErrInvoiceBatchAborted}
+	}
+	results[failed].Err = err
+
+	return results
+}
@@ -0,0 +1,242 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_CreateBatch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	knownCustomerID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	unknownCustomerID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	newRequest := func(customerID uuid.UUID) contract.InvoiceRequest {
+		return contract.InvoiceRequest{
+			Subject:    "design",
+			IssueDate:  "07-03-2024",
+			DueDate:    "06-04-2024",
+			CustomerID: customerID,
+			ItemRequest: []contract.ItemRequest{
+				{Name: "logo", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+			},
+		}
+	}
+
+	mismatchRequest := newRequest(knownCustomerID)
+	mismatchRequest.GrandTotal = decimal.NewFromInt(1)
+	mismatchTotals, _ := calculateTotals(mismatchRequest)
+
+	invalidErr := &contract.InvalidEntryError{Err: errors.New("subject is required")}
+
+	series := entity.InvoiceSeries{
+		InvoiceSeriesData: entity.InvoiceSeriesData{
+			Code:        entity.DefaultInvoiceSeries,
+			Template:    "{seq:04}",
+			ResetPeriod: "never",
+		},
+	}
+
+	type mocks struct {
+		invoicesRepo  *mock_Invoices.MockInvoicesRepository
+		customerRepo  *mock_Invoices.MockCustomerRepository
+		itemRepo      *mock_Invoices.MockItemRepository
+		seriesRepo    *mock_Invoices.MockSeriesRepository
+		asession      *mock_atomic.MockAtomicSessionProvider
+		atomicSession *mock_atomic.MockAtomicSession
+	}
+
+	// expectInserts expects the inserts of the created entries, numbered from 1, and a lookup of
+	// the customer of every entry reached.
+	expectInserts := func(m mocks, created int, customerIDs ...uuid.UUID) {
+		for _, customerID := range customerIDs {
+			var err error
+			if customerID == unknownCustomerID {
+				err = sql.ErrNoRows
+			}
+
+			m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+				Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: customerID}}, err).
+				Times(1)
+		}
+
+		m.seriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+			Return(series, nil).
+			Times(created)
+
+		for i := 1; i <= created; i++ {
+			m.seriesRepo.EXPECT().NextValue(gomock.Any(), entity.DefaultInvoiceSeries, "").
+				Return(int64(i), nil).
+				Times(1)
+		}
+
+		m.invoicesRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, data *entity.Invoices) (contract.InvoiceResponseDB, error) {
+				return contract.InvoiceResponseDB{InvoiceID: data.InvoiceID}, nil
+			}).
+			Times(created)
+
+		m.itemRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(created)
+	}
+
+	testCases := []struct {
+		name     string
+		request  contract.BatchInvoiceRequest
+		setup    func(m mocks)
+		expected []contract.BatchResult
+		err      error
+	}{
+		{
+			name: "partial",
+			request: contract.BatchInvoiceRequest{
+				Entries: []contract.BatchEntry{
+					{Request: newRequest(knownCustomerID)},
+					{Err: invalidErr},
+					{Request: newRequest(unknownCustomerID)},
+				},
+			},
+			setup: func(m mocks) {
+				m.asession.EXPECT().BeginSession(gomock.Any()).
+					Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+					Times(2)
+
+				expectInserts(m, 1, knownCustomerID, unknownCustomerID)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			expected: []contract.BatchResult{
+				{InvoiceID: "0001"},
+				{Err: invalidErr},
+				{Err: errorss.ErrCustomerIdNotFound},
+			},
+		},
+		{
+			name: "atomic invalid entry",
+			request: contract.BatchInvoiceRequest{
+				Atomic: true,
+				Entries: []contract.BatchEntry{
+					{Request: newRequest(knownCustomerID)},
+					{Err: invalidErr},
+				},
+			},
+			setup: func(m mocks) {},
+			expected: []contract.BatchResult{
+				{Err: errorss.ErrInvoiceBatchAborted},
+				{Err: invalidErr},
+			},
+		},
+		{
+			name: "atomic totals mismatch",
+			request: contract.BatchInvoiceRequest{
+				Atomic: true,
+				Entries: []contract.BatchEntry{
+					{Request: mismatchRequest},
+					{Request: newRequest(knownCustomerID)},
+				},
+			},
+			setup: func(m mocks) {},
+			expected: []contract.BatchResult{
+				{Err: validateTotals(mismatchRequest, mismatchTotals)},
+				{Err: errorss.ErrInvoiceBatchAborted},
+			},
+		},
+		{
+			name: "atomic insert fails",
+			request: contract.BatchInvoiceRequest{
+				Atomic: true,
+				Entries: []contract.BatchEntry{
+					{Request: newRequest(knownCustomerID)},
+					{Request: newRequest(unknownCustomerID)},
+				},
+			},
+			setup: func(m mocks) {
+				m.asession.EXPECT().BeginSession(gomock.Any()).
+					Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+					Times(1)
+
+				expectInserts(m, 1, knownCustomerID, unknownCustomerID)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			expected: []contract.BatchResult{
+				{Err: errorss.ErrInvoiceBatchAborted},
+				{Err: errorss.ErrCustomerIdNotFound},
+			},
+		},
+		{
+			name: "atomic err begin session",
+			request: contract.BatchInvoiceRequest{
+				Atomic:  true,
+				Entries: []contract.BatchEntry{{Request: newRequest(knownCustomerID)}},
+			},
+			setup: func(m mocks) {
+				m.asession.EXPECT().BeginSession(gomock.Any()).
+					Return(nil, errors.New("error internal server")).
+					Times(1)
+			},
+			err: errors.New("error internal server"),
+		},
+		{
+			name: "atomic success",
+			request: contract.BatchInvoiceRequest{
+				Atomic: true,
+				Entries: []contract.BatchEntry{
+					{Request: newRequest(knownCustomerID)},
+					{Request: newRequest(knownCustomerID)},
+				},
+			},
+			setup: func(m mocks) {
+				m.asession.EXPECT().BeginSession(gomock.Any()).
+					Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+					Times(1)
+
+				expectInserts(m, 2, knownCustomerID, knownCustomerID)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: []contract.BatchResult{
+				{InvoiceID: "0001"},
+				{InvoiceID: "0002"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := mocks{
+				invoicesRepo:  mock_Invoices.NewMockInvoicesRepository(mockCtrl),
+				customerRepo:  mock_Invoices.NewMockCustomerRepository(mockCtrl),
+				itemRepo:      mock_Invoices.NewMockItemRepository(mockCtrl),
+				seriesRepo:    mock_Invoices.NewMockSeriesRepository(mockCtrl),
+				asession:      mock_atomic.NewMockAtomicSessionProvider(mockCtrl),
+				atomicSession: mock_atomic.NewMockAtomicSession(mockCtrl),
+			}
+
+			testCase.setup(m)
+
+			Invoices := InitInvoiceservice(m.invoicesRepo, m.customerRepo, m.itemRepo, nil, nil, m.seriesRepo, nil, nil, m.asession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.CreateBatch(context.Background(), testCase.request)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
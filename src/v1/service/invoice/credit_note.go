@@ -0,0 +1,271 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+	"github.com/mariomac/gostream/stream"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// isCreditable reports whether credit notes can be issued against an invoice in the given status.
+func isCreditable(status string) bool {
+	switch status {
+	case entity.InvoiceStatusDraft, entity.InvoiceStatusVoid, entity.InvoiceStatusCredited:
+		return false
+	default:
+		return true
+	}
+}
+
+// calculateCreditNote builds the lines and totals of a credit note against the invoice, given the
+// lines of its earlier credit notes. The result is positive, it is negated when stored.
+// The line crediting what is left of an item takes the rest of its amount and the credit note
+// crediting what is left of the invoice takes the rest of its tax, so credit notes never add up
+// to more or less than the invoice because of rounding.
+func calculateCreditNote(invoice entity.InvoicesData, items []*entity.Item, credited []*entity.CreditNoteItem, requested []contract.CreditNoteItemRequest) ([]*entity.CreditNoteItem, invoiceTotals, error) {
+	var totals invoiceTotals
+
+	creditedQuantity := map[uuid.UUID]decimal.Decimal{}
+	creditedAmount := map[uuid.UUID]decimal.Decimal{}
+	creditedSubTotal := decimal.Decimal{}
+	for _, v := range credited {
+		creditedQuantity[v.ItemID] = creditedQuantity[v.ItemID].Sub(v.Quantity)
+		creditedAmount[v.ItemID] = creditedAmount[v.ItemID].Sub(v.Amount)
+		creditedSubTotal = creditedSubTotal.Sub(v.Amount)
+	}
+
+	quantities := map[uuid.UUID]decimal.Decimal{}
+	if len(requested) == 0 {
+		for _, v := range items {
+			if left := v.Quantity.Sub(creditedQuantity[v.ItemID]); left.IsPositive() {
+				quantities[v.ItemID] = left
+			}
+		}
+
+		if len(quantities) == 0 {
+			return nil, totals, errorss.ErrInvoiceFullyCredited
+		}
+	}
+
+	itemIDs := map[uuid.UUID]bool{}
+	for _, v := range items {
+		itemIDs[v.ItemID] = true
+	}
+
+	for _, v := range requested {
+		if !itemIDs[v.ItemID] {
+			return nil, totals, errorss.ErrInvoiceItemNotFound
+		}
+		quantities[v.ItemID] = quantities[v.ItemID].Add(v.Quantity)
+	}
+
+	var lines []*entity.CreditNoteItem
+	fullyCredited := true
+
+	for _, v := range items {
+		quantity, ok := quantities[v.ItemID]
+		left := v.Quantity.Sub(creditedQuantity[v.ItemID])
+
+		if !ok {
+			fullyCredited = fullyCredited && !left.IsPositive()
+			continue
+		}
+
+		var amount decimal.Decimal
+		switch quantity.Cmp(left) {
+		case 1:
+			return nil, totals, errorss.ErrCreditNoteQuantityExceeded
+		case 0:
+			amount = v.Amount.Sub(creditedAmount[v.ItemID])
+		default:
+			amount = quantity.MulRound(v.UnitPrice, moneyPlaces, decimal.RoundHalfUp)
+			fullyCredited = false
+		}
+
+		lines = append(lines, &entity.CreditNoteItem{
+			CreditNoteItemData: entity.CreditNoteItemData{
+				ItemID:    v.ItemID,
+				Name:      v.Name,
+				Type:      v.Type,
+				Quantity:  quantity,
+				UnitPrice: v.UnitPrice,
+				Amount:    amount,
+			},
+		})
+		totals.Amounts = append(totals.Amounts, amount)
+		totals.SubTotal = totals.SubTotal.Add(amount)
+	}
+
+	if fullyCredited {
+		creditedTax := invoice.AmountCredited.Sub(creditedSubTotal)
+		totals.Tax = invoice.Tax.Sub(creditedTax)
+	} else {
+		totals.Tax = totals.SubTotal.MulPercent(invoice.TaxRate, moneyPlaces, decimal.RoundHalfUp)
+	}
+	totals.GrandTotal = totals.SubTotal.Add(totals.Tax)
+
+	return lines, totals, nil
+}
+
+// CreateCreditNote issues a credit note against an issued invoice, numbered from its own series,
+// and lowers what the customer owes on the invoice by its total.
+func (ts *Invoiceservice) CreateCreditNote(ctx context.Context, request contract.CreditNoteRequest, id string) (contract.InvoiceCreditNoteResponse, error) {
+	var res contract.InvoiceCreditNoteResponse
+
+	issueDate, _ := time.Parse("02-01-2006", request.IssueDate)
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		// lock the invoice row so concurrent credit notes never credit the same quantity twice
+		dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrInvoiceIdNotFound
+			}
+			log.Println("get invoice err: ", err)
+			return err
+		}
+
+		if !isCreditable(dataInvoices.Status) {
+			log.Println("create credit note err: ", errorss.ErrInvoiceNotCreditable)
+			return errorss.ErrInvoiceNotCreditable
+		}
+
+		dataItems, err := ts.ItemRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+		if err != nil {
+			log.Println("get items err: ", err)
+			return err
+		}
+
+		creditedItems, err := ts.CreditNoteRepo.GetItemsByInvoiceID(ctx, dataInvoices.InvoiceID)
+		if err != nil {
+			log.Println("get credit note items err: ", err)
+			return err
+		}
+
+		lines, totals, err := calculateCreditNote(dataInvoices.InvoicesData, dataItems, creditedItems, request.Items)
+		if err != nil {
+			log.Println("calculate credit note err: ", err)
+			return err
+		}
+
+		creditNoteID, err := ts.allocateInvoiceID(ctx, entity.CreditNoteSeries, issueDate)
+		if err != nil {
+			log.Println("allocate credit note id err: ", err)
+			return err
+		}
+
+		insertDataCreditNote := entity.CreditNote{
+			CreditNoteData: entity.CreditNoteData{
+				CreditNoteID: creditNoteID,
+				InvoiceID:    dataInvoices.InvoiceID,
+				IssueDate:    issueDate,
+				Reason:       request.Reason,
+				SubTotal:     totals.SubTotal.Neg(),
+				Tax:          totals.Tax.Neg(),
+				GrandTotal:   totals.GrandTotal.Neg(),
+			},
+		}
+
+		err = ts.CreditNoteRepo.Create(ctx, &insertDataCreditNote)
+		if err != nil {
+			log.Println("create credit note err: ", err)
+			return err
+		}
+
+		for _, v := range lines {
+			v.CreditNoteID = creditNoteID
+			v.Quantity = v.Quantity.Neg()
+			v.Amount = v.Amount.Neg()
+		}
+
+		err = ts.CreditNoteRepo.CreateItems(ctx, lines)
+		if err != nil {
+			log.Println("create credit note items err: ", err)
+			return err
+		}
+
+		dataInvoices.AmountCredited = dataInvoices.AmountCredited.Add(totals.GrandTotal)
+		dataInvoices.Status = deriveInvoiceStatus(dataInvoices.Status, dataInvoices.GrandTotal, dataInvoices.AmountCredited, dataInvoices.AmountPaid)
+
+		err = ts.InvoicesRepo.UpdateStatus(ctx, &dataInvoices)
+		if err != nil {
+			log.Println("update invoice status err: ", err)
+			return err
+		}
+
+		res = contract.InvoiceCreditNoteResponse{
+			InvoiceID:          dataInvoices.InvoiceID,
+			CreditNoteID:       creditNoteID,
+			Status:             dataInvoices.Status,
+			AmountCredited:     dataInvoices.AmountCredited,
+			OutstandingBalance: outstandingBalance(dataInvoices.GrandTotal, dataInvoices.AmountCredited, dataInvoices.AmountPaid),
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return contract.InvoiceCreditNoteResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (ts *Invoiceservice) GetCreditNotes(ctx context.Context, id string) ([]contract.CreditNoteResponse, error) {
+	dataInvoices, err := ts.InvoicesRepo.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return nil, errorss.ErrInvoiceIdNotFound
+		}
+		log.Println(err)
+		return nil, err
+	}
+
+	dataCreditNotes, err := ts.CreditNoteRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	dataItems, err := ts.CreditNoteRepo.GetItemsByInvoiceID(ctx, dataInvoices.InvoiceID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	items := map[string][]contract.CreditNoteItemResponse{}
+	for _, v := range dataItems {
+		items[v.CreditNoteID] = append(items[v.CreditNoteID], contract.CreditNoteItemResponse{
+			ItemID:    v.ItemID,
+			Name:      v.Name,
+			Quantity:  v.Quantity,
+			UnitPrice: v.UnitPrice,
+			Amount:    v.Amount,
+		})
+	}
+
+	return stream.Map(stream.OfSlice(dataCreditNotes), func(c *entity.CreditNote) contract.CreditNoteResponse {
+		return contract.CreditNoteResponse{
+			CreditNoteID: c.CreditNoteID,
+			InvoiceID:    c.InvoiceID,
+			IssueDate:    c.IssueDate.Format("02-01-2006"),
+			Reason:       c.Reason,
+			Items:        items[c.CreditNoteID],
+			SubTotal:     c.SubTotal,
+			Tax:          c.Tax,
+			GrandTotal:   c.GrandTotal,
+		}
+	}).ToSlice(), nil
+}
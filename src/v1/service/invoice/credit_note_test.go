@@ -0,0 +1,393 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+var (
+	creditLogoID    = uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	creditHostingID = uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+)
+
+// creditInvoice is 3 x 33.33 + 1 x 100 with 11% tax: 199.99 + 22.00 = 221.99
+func creditInvoice() (entity.InvoicesData, []*entity.Item) {
+	invoice := entity.InvoicesData{
+		InvoiceID:  "0001",
+		Status:     entity.InvoiceStatusIssued,
+		SubTotal:   decimal.RequireFromString("199.99"),
+		TaxRate:    decimal.NewFromInt(11),
+		Tax:        decimal.NewFromInt(22),
+		GrandTotal: decimal.RequireFromString("221.99"),
+	}
+
+	items := []*entity.Item{
+		{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: creditLogoID, Name: "logo", Type: "design", Quantity: decimal.NewFromInt(3), UnitPrice: decimal.RequireFromString("33.33"), Amount: decimal.RequireFromString("99.99")}},
+		{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: creditHostingID, Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(100)}},
+	}
+
+	return invoice, items
+}
+
+func creditLine(itemID uuid.UUID, name, itemType string, quantity, unitPrice, amount string) *entity.CreditNoteItem {
+	return &entity.CreditNoteItem{
+		CreditNoteItemData: entity.CreditNoteItemData{
+			ItemID:    itemID,
+			Name:      name,
+			Type:      itemType,
+			Quantity:  decimal.RequireFromString(quantity),
+			UnitPrice: decimal.RequireFromString(unitPrice),
+			Amount:    decimal.RequireFromString(amount),
+		},
+	}
+}
+
+func TestInvoiceService_CalculateCreditNote(t *testing.T) {
+	// one logo was credited before, 33.33 + 3.67 tax
+	creditedOneLogo := []*entity.CreditNoteItem{
+		creditLine(creditLogoID, "logo", "design", "-1", "33.33", "-33.33"),
+	}
+
+	testCases := []struct {
+		name           string
+		amountCredited decimal.Decimal
+		credited       []*entity.CreditNoteItem
+		requested      []contract.CreditNoteItemRequest
+		lines          []*entity.CreditNoteItem
+		subTotal       string
+		tax            string
+		grandTotal     string
+		err            error
+	}{
+		{
+			name: "partial",
+			requested: []contract.CreditNoteItemRequest{
+				{ItemID: creditLogoID, Quantity: decimal.NewFromInt(1)},
+			},
+			lines: []*entity.CreditNoteItem{
+				creditLine(creditLogoID, "logo", "design", "1", "33.33", "33.33"),
+			},
+			subTotal:   "33.33",
+			tax:        "3.67",
+			grandTotal: "37",
+		},
+		{
+			name: "partial with the same item twice",
+			requested: []contract.CreditNoteItemRequest{
+				{ItemID: creditLogoID, Quantity: decimal.NewFromInt(1)},
+				{ItemID: creditLogoID, Quantity: decimal.NewFromInt(1)},
+			},
+			lines: []*entity.CreditNoteItem{
+				creditLine(creditLogoID, "logo", "design", "2", "33.33", "66.66"),
+			},
+			subTotal:   "66.66",
+			tax:        "7.33",
+			grandTotal: "73.99",
+		},
+		{
+			name: "full",
+			lines: []*entity.CreditNoteItem{
+				creditLine(creditLogoID, "logo", "design", "3", "33.33", "99.99"),
+				creditLine(creditHostingID, "hosting", "service", "1", "100", "100"),
+			},
+			subTotal:   "199.99",
+			tax:        "22",
+			grandTotal: "221.99",
+		},
+		{
+			name:           "full after a partial credit takes the rest",
+			amountCredited: decimal.NewFromInt(37),
+			credited:       creditedOneLogo,
+			lines: []*entity.CreditNoteItem{
+				creditLine(creditLogoID, "logo", "design", "2", "33.33", "66.66"),
+				creditLine(creditHostingID, "hosting", "service", "1", "100", "100"),
+			},
+			subTotal:   "166.66",
+			tax:        "18.33",
+			grandTotal: "184.99",
+		},
+		{
+			name:           "partial crediting the rest",
+			amountCredited: decimal.NewFromInt(37),
+			credited:       creditedOneLogo,
+			requested: []contract.CreditNoteItemRequest{
+				{ItemID: creditHostingID, Quantity: decimal.NewFromInt(1)},
+				{ItemID: creditLogoID, Quantity: decimal.NewFromInt(2)},
+			},
+			lines: []*entity.CreditNoteItem{
+				creditLine(creditLogoID, "logo", "design", "2", "33.33", "66.66"),
+				creditLine(creditHostingID, "hosting", "service", "1", "100", "100"),
+			},
+			subTotal:   "166.66",
+			tax:        "18.33",
+			grandTotal: "184.99",
+		},
+		{
+			name:           "error quantity exceeded",
+			amountCredited: decimal.NewFromInt(37),
+			credited:       creditedOneLogo,
+			requested: []contract.CreditNoteItemRequest{
+				{ItemID: creditLogoID, Quantity: decimal.NewFromInt(3)},
+			},
+			err: errorss.ErrCreditNoteQuantityExceeded,
+		},
+		{
+			name: "error unknown item",
+			requested: []contract.CreditNoteItemRequest{
+				{ItemID: uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"), Quantity: decimal.NewFromInt(1)},
+			},
+			err: errorss.ErrInvoiceItemNotFound,
+		},
+		{
+			name:           "error fully credited",
+			amountCredited: decimal.RequireFromString("221.99"),
+			credited: []*entity.CreditNoteItem{
+				creditLine(creditLogoID, "logo", "design", "-3", "33.33", "-99.99"),
+				creditLine(creditHostingID, "hosting", "service", "-1", "100", "-100"),
+			},
+			err: errorss.ErrInvoiceFullyCredited,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			invoice, items := creditInvoice()
+			invoice.AmountCredited = testCase.amountCredited
+
+			lines, totals, err := calculateCreditNote(invoice, items, testCase.credited, testCase.requested)
+
+			assert.Equal(t, testCase.err, err)
+			if testCase.err != nil {
+				return
+			}
+
+			assert.Equal(t, testCase.lines, lines)
+			assert.Equal(t, decimal.RequireFromString(testCase.subTotal), totals.SubTotal)
+			assert.Equal(t, decimal.RequireFromString(testCase.tax), totals.Tax)
+			assert.Equal(t, decimal.RequireFromString(testCase.grandTotal), totals.GrandTotal)
+		})
+	}
+}
+
+func TestInvoiceService_CreateCreditNote(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	type mocks struct {
+		invoicesRepo   *mock_Invoices.MockInvoicesRepository
+		itemRepo       *mock_Invoices.MockItemRepository
+		creditNoteRepo *mock_Invoices.MockCreditNoteRepository
+		seriesRepo     *mock_Invoices.MockSeriesRepository
+		asession       *mock_atomic.MockAtomicSessionProvider
+		atomicSession  *mock_atomic.MockAtomicSession
+	}
+
+	invoice, items := creditInvoice()
+	invoice.AmountPaid = decimal.RequireFromString("184.99")
+	invoice.Status = entity.InvoiceStatusPartiallyPaid
+
+	request := contract.CreditNoteRequest{
+		IssueDate: "07-03-2024",
+		Reason:    "logo not delivered",
+		Items: []contract.CreditNoteItemRequest{
+			{ItemID: creditLogoID, Quantity: decimal.NewFromInt(1)},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		setup    func(m mocks)
+		expected contract.InvoiceCreditNoteResponse
+		err      error
+	}{
+		{
+			name: "error invoice id not found",
+			setup: func(m mocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{}, sql.ErrNoRows).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name: "error invoice not creditable",
+			setup: func(m mocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001", Status: entity.InvoiceStatusDraft}}, nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrInvoiceNotCreditable,
+		},
+		{
+			name: "error quantity exceeded",
+			setup: func(m mocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{InvoicesData: invoice}, nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().GetByInvoiceID(gomock.Any(), "0001").
+					Return(items, nil).
+					Times(1)
+
+				m.creditNoteRepo.EXPECT().GetItemsByInvoiceID(gomock.Any(), "0001").
+					Return([]*entity.CreditNoteItem{
+						creditLine(creditLogoID, "logo", "design", "-3", "33.33", "-99.99"),
+					}, nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrCreditNoteQuantityExceeded,
+		},
+		{
+			name: "success",
+			setup: func(m mocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{InvoicesData: invoice}, nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().GetByInvoiceID(gomock.Any(), "0001").
+					Return(items, nil).
+					Times(1)
+
+				m.creditNoteRepo.EXPECT().GetItemsByInvoiceID(gomock.Any(), "0001").
+					Return([]*entity.CreditNoteItem{}, nil).
+					Times(1)
+
+				m.seriesRepo.EXPECT().Get(gomock.Any(), entity.CreditNoteSeries).
+					Return(entity.InvoiceSeries{InvoiceSeriesData: entity.InvoiceSeriesData{Code: entity.CreditNoteSeries, Template: "CN/{YYYY}/{seq:05}", ResetPeriod: "yearly"}}, nil).
+					Times(1)
+
+				m.seriesRepo.EXPECT().NextValue(gomock.Any(), entity.CreditNoteSeries, "2024").
+					Return(int64(1), nil).
+					Times(1)
+
+				m.creditNoteRepo.EXPECT().Create(gomock.Any(), &entity.CreditNote{
+					CreditNoteData: entity.CreditNoteData{
+						CreditNoteID: "CN/2024/00001",
+						InvoiceID:    "0001",
+						IssueDate:    time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC),
+						Reason:       "logo not delivered",
+						SubTotal:     decimal.RequireFromString("-33.33"),
+						Tax:          decimal.RequireFromString("-3.67"),
+						GrandTotal:   decimal.NewFromInt(-37),
+					},
+				}).
+					Return(nil).
+					Times(1)
+
+				line := creditLine(creditLogoID, "logo", "design", "-1", "33.33", "-33.33")
+				line.CreditNoteID = "CN/2024/00001"
+
+				m.creditNoteRepo.EXPECT().CreateItems(gomock.Any(), []*entity.CreditNoteItem{line}).
+					Return(nil).
+					Times(1)
+
+				// 184.99 paid settles the 221.99 - 37 still owed
+				updatedInvoice := entity.Invoices{InvoicesData: invoice}
+				updatedInvoice.AmountCredited = decimal.NewFromInt(37)
+				updatedInvoice.Status = entity.InvoiceStatusPaid
+
+				m.invoicesRepo.EXPECT().UpdateStatus(gomock.Any(), &updatedInvoice).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: contract.InvoiceCreditNoteResponse{
+				InvoiceID:          "0001",
+				CreditNoteID:       "CN/2024/00001",
+				Status:             entity.InvoiceStatusPaid,
+				AmountCredited:     decimal.NewFromInt(37),
+				OutstandingBalance: decimal.NewFromInt(0),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := mocks{
+				invoicesRepo:   mock_Invoices.NewMockInvoicesRepository(mockCtrl),
+				itemRepo:       mock_Invoices.NewMockItemRepository(mockCtrl),
+				creditNoteRepo: mock_Invoices.NewMockCreditNoteRepository(mockCtrl),
+				seriesRepo:     mock_Invoices.NewMockSeriesRepository(mockCtrl),
+				asession:       mock_atomic.NewMockAtomicSessionProvider(mockCtrl),
+				atomicSession:  mock_atomic.NewMockAtomicSession(mockCtrl),
+			}
+
+			m.asession.EXPECT().BeginSession(gomock.Any()).
+				Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+				Times(1)
+
+			testCase.setup(m)
+
+			Invoices := InitInvoiceservice(m.invoicesRepo, nil, m.itemRepo, nil, m.creditNoteRepo, m.seriesRepo, nil, nil, m.asession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.CreateCreditNote(context.Background(), request, "0001")
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_GetCreditNotes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+	mockCreditNoteRepo := mock_Invoices.NewMockCreditNoteRepository(mockCtrl)
+
+	mockInvoicesRepo.EXPECT().Get(gomock.Any(), "0001").
+		Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001"}}, nil).
+		Times(1)
+
+	mockCreditNoteRepo.EXPECT().GetByInvoiceID(gomock.Any(), "0001").
+		Return([]*entity.CreditNote{
+			{CreditNoteData: entity.CreditNoteData{CreditNoteID: "CN/2024/00001", InvoiceID: "0001", IssueDate: time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC), Reason: "logo not delivered", SubTotal: decimal.RequireFromString("-33.33"), Tax: decimal.RequireFromString("-3.67"), GrandTotal: decimal.NewFromInt(-37)}},
+		}, nil).
+		Times(1)
+
+	line := creditLine(creditLogoID, "logo", "design", "-1", "33.33", "-33.33")
+	line.CreditNoteID = "CN/2024/00001"
+
+	mockCreditNoteRepo.EXPECT().GetItemsByInvoiceID(gomock.Any(), "0001").
+		Return([]*entity.CreditNoteItem{line}, nil).
+		Times(1)
+
+	Invoices := InitInvoiceservice(mockInvoicesRepo, nil, nil, nil, mockCreditNoteRepo, nil, nil, nil, nil, FixedUUIDGenerator{})
+	got, err := Invoices.GetCreditNotes(context.Background(), "0001")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []contract.CreditNoteResponse{
+		{
+			CreditNoteID: "CN/2024/00001",
+			InvoiceID:    "0001",
+			IssueDate:    "07-03-2024",
+			Reason:       "logo not delivered",
+			Items: []contract.CreditNoteItemResponse{
+				{ItemID: creditLogoID, Name: "logo", Quantity: decimal.NewFromInt(-1), UnitPrice: decimal.RequireFromString("33.33"), Amount: decimal.RequireFromString("-33.33")},
+			},
+			SubTotal:   decimal.RequireFromString("-33.33"),
+			Tax:        decimal.RequireFromString("-3.67"),
+			GrandTotal: decimal.NewFromInt(-37),
+		},
+	}, got)
+}
@@ -0,0 +1,54 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// resolveCustomer returns the customer the invoice is billed to. An explicit customer_id must
+// exist, otherwise the customer is looked up by its normalized name and address and created
+// when it is new.
+func (ts *Invoiceservice) resolveCustomer(ctx context.Context, request contract.InvoiceRequest) (uuid.UUID, error) {
+	if request.CustomerID != uuid.Nil {
+		dataCustomer, err := ts.CustomerRepo.Get(ctx, request.CustomerID.String())
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return uuid.Nil, errorss.ErrCustomerIdNotFound
+			}
+			log.Println(err)
+			return uuid.Nil, err
+		}
+
+		return dataCustomer.CustomerID, nil
+	}
+
+	if request.CustomerRequest == nil {
+		return uuid.Nil, errorss.ErrCustomerIdNotFound
+	}
+
+	dataCustomer, err := ts.CustomerRepo.FindOrCreate(ctx, &entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID:     ts.UUIDGen.New(),
+			Name:           request.CustomerRequest.CustomerName,
+			Address:        request.CustomerRequest.Address,
+			NPWP:           request.CustomerRequest.NPWP,
+			EndpointID:     request.CustomerRequest.EndpointID,
+			EndpointScheme: request.CustomerRequest.EndpointScheme,
+			CountryCode:    request.CustomerRequest.CountryCode,
+		},
+	})
+	if err != nil {
+		log.Println("find or create customer err: ", err)
+		return uuid.Nil, err
+	}
+
+	return dataCustomer.CustomerID, nil
+}
@@ -0,0 +1,216 @@
+package Invoices
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/document/efaktur"
+	"github.com/Risuii/invoice/src/document/export"
+	"github.com/Risuii/invoice/src/document/html"
+	"github.com/Risuii/invoice/src/document/pdf"
+	"github.com/Risuii/invoice/src/document/ubl"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// RenderPDF renders the invoice detail with the named PDF template, the default one when
+// templateName is empty.
+func (ts *Invoiceservice) RenderPDF(ctx context.Context, id, templateName string, labels document.Labels) ([]byte, error) {
+	invoice, err := ts.GetDetail(ctx, id)
+	if err != nil {
+		log.Println("get detail err: ", err)
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Render(&buf, templateName, pdf.Data{Invoice: invoice, Labels: labels}); err != nil {
+		log.Println("render pdf err: ", err)
+		if errors.Is(err, pdf.ErrUnknownTemplate) {
+			return nil, errorss.ErrDocumentTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderHTML renders the invoice detail with the named HTML template of the deployment.
+func (ts *Invoiceservice) RenderHTML(ctx context.Context, id, templateName string, labels document.Labels) ([]byte, error) {
+	invoice, err := ts.GetDetail(ctx, id)
+	if err != nil {
+		log.Println("get detail err: ", err)
+		return nil, err
+	}
+
+	return ts.renderHTML(templateName, invoice, labels)
+}
+
+// PreviewHTML renders the named HTML template with document.SampleInvoice, to check a
+// template and the branding before using them on real invoices.
+func (ts *Invoiceservice) PreviewHTML(ctx context.Context, templateName string, labels document.Labels) ([]byte, error) {
+	return ts.renderHTML(templateName, document.SampleInvoice(), labels)
+}
+
+func (ts *Invoiceservice) renderHTML(templateName string, invoice contract.InvoiceResponse, labels document.Labels) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ts.HTMLRenderer.Render(&buf, templateName, invoice, labels); err != nil {
+		log.Println("render html err: ", err)
+		if errors.Is(err, html.ErrUnknownTemplate) {
+			return nil, errorss.ErrDocumentTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportUBL encodes the invoice as a UBL e-invoice. Drafts are not final yet and void
+// invoices are no longer owed, so neither is exported.
+func (ts *Invoiceservice) ExportUBL(ctx context.Context, id string) ([]byte, error) {
+	dataInvoices, err := ts.InvoicesRepo.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return nil, errorss.ErrInvoiceIdNotFound
+		}
+		log.Println(err)
+		return nil, err
+	}
+
+	if dataInvoices.Status == entity.InvoiceStatusDraft || dataInvoices.Status == entity.InvoiceStatusVoid {
+		return nil, errorss.ErrInvoiceNotExportable
+	}
+
+	dataCustomer, err := ts.CustomerRepo.Get(ctx, dataInvoices.CustomerID.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return nil, errorss.ErrCustomerIdNotFound
+		}
+		log.Println(err)
+		return nil, err
+	}
+
+	dataItems, err := ts.ItemRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := ts.UBLEncoder.Encode(&buf, dataInvoices, dataCustomer, dataItems); err != nil {
+		log.Println("encode ubl err: ", err)
+		if errors.Is(err, ubl.ErrCustomerEndpoint) {
+			return nil, errorss.ErrCustomerEndpointMissing
+		}
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportEFaktur writes the e-Faktur import CSV of the invoices issued in the range that have a
+// tax invoice number.
+func (ts *Invoiceservice) ExportEFaktur(ctx context.Context, params contract.EFakturParam) ([]byte, error) {
+	dataInvoices, err := ts.InvoicesRepo.GetForTaxExport(ctx, params.From, params.To)
+	if err != nil {
+		log.Println("get invoices for tax export err: ", err)
+		return nil, err
+	}
+
+	invoices := make([]efaktur.Invoice, 0, len(dataInvoices))
+	for _, dataInvoice := range dataInvoices {
+		dataCustomer, err := ts.CustomerRepo.Get(ctx, dataInvoice.CustomerID.String())
+		if err != nil {
+			log.Println("get customer err: ", err)
+			return nil, err
+		}
+
+		dataItems, err := ts.ItemRepo.GetByInvoiceID(ctx, dataInvoice.InvoiceID)
+		if err != nil {
+			log.Println("get items err: ", err)
+			return nil, err
+		}
+
+		invoices = append(invoices, efaktur.Invoice{Invoice: *dataInvoice, Customer: dataCustomer, Items: dataItems})
+	}
+
+	var buf bytes.Buffer
+	if err := efaktur.Write(&buf, invoices); err != nil {
+		log.Println("write efaktur err: ", err)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SetTaxInvoiceNumber records the tax invoice number DJP assigned to an issued invoice, or
+// corrects it. Drafts take it on update and void invoices are not reported.
+func (ts *Invoiceservice) SetTaxInvoiceNumber(ctx context.Context, request contract.TaxInvoiceNumberRequest, id string) (contract.TaxInvoiceNumberResponse, error) {
+	var res contract.TaxInvoiceNumberResponse
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrInvoiceIdNotFound
+			}
+			log.Println("get invoice err: ", err)
+			return err
+		}
+
+		switch dataInvoices.Status {
+		case entity.InvoiceStatusDraft, entity.InvoiceStatusVoid:
+			log.Printf("set tax invoice number of %s invoice %s err: %v", dataInvoices.Status, dataInvoices.InvoiceID, errorss.ErrInvoiceTaxNumberNotAssignable)
+			return errorss.ErrInvoiceTaxNumberNotAssignable
+		}
+
+		dataInvoices.TaxInvoiceNumber = request.TaxInvoiceNumber
+
+		err = ts.InvoicesRepo.UpdateTaxInvoiceNumber(ctx, &dataInvoices)
+		if err != nil {
+			log.Println("update tax invoice number err: ", err)
+			return err
+		}
+
+		res = contract.TaxInvoiceNumberResponse{
+			InvoiceID:        dataInvoices.InvoiceID,
+			TaxInvoiceNumber: dataInvoices.TaxInvoiceNumber,
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return contract.TaxInvoiceNumberResponse{}, err
+	}
+
+	return res, nil
+}
+
+// Export writes every invoice matching the list filters to w in the requested format.
+func (ts *Invoiceservice) Export(ctx context.Context, params contract.GetListParam, exportParam contract.ExportParam, w io.Writer) error {
+	writer, err := export.NewWriter(w, exportParam.Format, exportParam.IncludeItems)
+	if err != nil {
+		log.Println("new export writer err: ", err)
+		return err
+	}
+
+	if err := ts.InvoicesRepo.ExportList(ctx, params, exportParam.IncludeItems, writer.Write); err != nil {
+		log.Println("export list err: ", err)
+		writer.Abort()
+		return err
+	}
+
+	return writer.Close()
+}
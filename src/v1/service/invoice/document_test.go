@@ -0,0 +1,505 @@
+package Invoices
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/document"
+	"github.com/Risuii/invoice/src/document/export"
+	"github.com/Risuii/invoice/src/document/html"
+	"github.com/Risuii/invoice/src/document/ubl"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_RenderPDF(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockInvoice := entity.Invoices{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID:  "0001",
+			CustomerID: uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+			Status:     entity.InvoiceStatusIssued,
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		getErr    error
+		template  string
+		expectPDF bool
+		err       error
+	}{
+		{
+			name:   "err invoice id not found",
+			getErr: sql.ErrNoRows,
+			err:    errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name:     "err unknown template",
+			template: "fancy",
+			err:      errorss.ErrDocumentTemplateNotFound,
+		},
+		{
+			name:      "success default template",
+			expectPDF: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
+			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+
+			mockInvoicesRepo.EXPECT().Get(gomock.Any(), mockInvoice.InvoiceID).
+				Return(mockInvoice, testCase.getErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), mockInvoice.CustomerID.String()).
+				Return(entity.Customer{}, nil).
+				Times(1)
+
+			mockItemRepo.EXPECT().GetByInvoiceID(gomock.Any(), mockInvoice.InvoiceID).
+				Return(nil, nil).
+				Times(1)
+
+			mockPaymentRepo.EXPECT().GetByInvoiceID(gomock.Any(), mockInvoice.InvoiceID).
+				Return(nil, nil).
+				Times(1)
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.RenderPDF(context.Background(), mockInvoice.InvoiceID, testCase.template, document.Labels{})
+
+			assert.Equal(t, testCase.err, actualErr)
+			assert.Equal(t, testCase.expectPDF, bytes.HasPrefix(got, []byte("%PDF-")))
+		})
+	}
+}
+
+func TestInvoiceService_PreviewHTML(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	testCases := []struct {
+		name      string
+		renderErr error
+		expected  []byte
+		err       error
+	}{
+		{
+			name:      "err unknown template",
+			renderErr: fmt.Errorf("%w: %q", html.ErrUnknownTemplate, "fancy"),
+			err:       errorss.ErrDocumentTemplateNotFound,
+		},
+		{
+			name:      "err render",
+			renderErr: errors.New("template: default.html: executing"),
+			err:       errors.New("template: default.html: executing"),
+		},
+		{
+			name:     "success",
+			expected: []byte("rendered"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+
+			mockHTMLRenderer.EXPECT().Render(gomock.Any(), "fancy", document.SampleInvoice(), document.Labels{}).
+				DoAndReturn(func(w io.Writer, name string, invoice contract.InvoiceResponse, labels document.Labels) error {
+					if testCase.renderErr != nil {
+						return testCase.renderErr
+					}
+					_, err := w.Write([]byte("rendered"))
+					return err
+				}).
+				Times(1)
+
+			Invoices := InitInvoiceservice(nil, nil, nil, nil, nil, nil, mockHTMLRenderer, nil, nil, FixedUUIDGenerator{})
+			got, actualErr := Invoices.PreviewHTML(context.Background(), "fancy", document.Labels{})
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_ExportUBL(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID: uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+			EndpointID: "9876543210987",
+		},
+	}
+
+	mockItems := []*entity.Item{
+		{ItemData: entity.ItemData{InvoiceID: "0001", Name: "design"}},
+	}
+
+	testCases := []struct {
+		name      string
+		getErr    error
+		status    string
+		encodeErr error
+		expected  []byte
+		err       error
+	}{
+		{
+			name:   "err invoice id not found",
+			getErr: sql.ErrNoRows,
+			err:    errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name:   "err draft invoice",
+			status: entity.InvoiceStatusDraft,
+			err:    errorss.ErrInvoiceNotExportable,
+		},
+		{
+			name:   "err void invoice",
+			status: entity.InvoiceStatusVoid,
+			err:    errorss.ErrInvoiceNotExportable,
+		},
+		{
+			name:      "err customer endpoint missing",
+			status:    entity.InvoiceStatusIssued,
+			encodeErr: ubl.ErrCustomerEndpoint,
+			err:       errorss.ErrCustomerEndpointMissing,
+		},
+		{
+			name:      "err supplier endpoint missing",
+			status:    entity.InvoiceStatusIssued,
+			encodeErr: ubl.ErrSupplierEndpoint,
+			err:       ubl.ErrSupplierEndpoint,
+		},
+		{
+			name:     "success",
+			status:   entity.InvoiceStatusPaid,
+			expected: []byte("<Invoice/>"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
+
+			mockInvoice := entity.Invoices{
+				InvoicesData: entity.InvoicesData{
+					InvoiceID:  "0001",
+					CustomerID: mockCustomer.CustomerID,
+					Status:     testCase.status,
+				},
+			}
+
+			mockInvoicesRepo.EXPECT().Get(gomock.Any(), mockInvoice.InvoiceID).
+				Return(mockInvoice, testCase.getErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), mockCustomer.CustomerID.String()).
+				Return(mockCustomer, nil).
+				Times(1)
+
+			mockItemRepo.EXPECT().GetByInvoiceID(gomock.Any(), mockInvoice.InvoiceID).
+				Return(mockItems, nil).
+				Times(1)
+
+			mockUBLEncoder.EXPECT().Encode(gomock.Any(), mockInvoice, mockCustomer, mockItems).
+				DoAndReturn(func(w io.Writer, invoice entity.Invoices, customer entity.Customer, items []*entity.Item) error {
+					if testCase.encodeErr != nil {
+						return testCase.encodeErr
+					}
+					_, err := w.Write([]byte("<Invoice/>"))
+					return err
+				}).
+				Times(1)
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, nil, nil, nil, nil, mockUBLEncoder, nil, FixedUUIDGenerator{})
+			got, actualErr := Invoices.ExportUBL(context.Background(), mockInvoice.InvoiceID)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_ExportEFaktur(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	params := contract.EFakturParam{
+		From: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockInvoices := []*entity.Invoices{
+		{
+			InvoicesData: entity.InvoicesData{
+				InvoiceID:        "0001",
+				IssueDate:        time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+				CustomerID:       uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+				SubTotal:         decimal.NewFromInt(300),
+				TaxRate:          decimal.NewFromInt(11),
+				Tax:              decimal.NewFromInt(33),
+				TaxInvoiceNumber: "0002400000001",
+			},
+		},
+	}
+
+	mockCustomer := entity.Customer{
+		CustomerData: entity.CustomerData{
+			CustomerID: mockInvoices[0].CustomerID,
+			Name:       "acme",
+			Address:    "jakarta",
+			NPWP:       "012345678901000",
+		},
+	}
+
+	mockItems := []*entity.Item{
+		{ItemData: entity.ItemData{InvoiceID: "0001", Name: "design", Quantity: decimal.NewFromInt(3), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(300)}},
+	}
+
+	testCases := []struct {
+		name        string
+		listErr     error
+		customerErr error
+		expected    []string
+		err         error
+	}{
+		{
+			name:    "err get invoices",
+			listErr: errors.New("error internal server"),
+			err:     errors.New("error internal server"),
+		},
+		{
+			name:        "err get customer",
+			customerErr: errors.New("error internal server"),
+			err:         errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			expected: []string{
+				"FK,01,0,0002400000001,3,2024,07/03/2024,012345678901000,acme,jakarta,300,33,0,,0,0,0,0,0001,",
+				"LT,012345678901000,acme,jakarta,,,,,,,,,,",
+				"OF,,design,100,3,300,0,300,33,0,0",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+
+			mockInvoicesRepo.EXPECT().GetForTaxExport(gomock.Any(), params.From, params.To).
+				Return(mockInvoices, testCase.listErr).
+				Times(1)
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), mockCustomer.CustomerID.String()).
+				Return(mockCustomer, testCase.customerErr).
+				Times(1)
+
+			mockItemRepo.EXPECT().GetByInvoiceID(gomock.Any(), "0001").
+				Return(mockItems, nil).
+				Times(1)
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, nil, nil, nil, nil, nil, nil, FixedUUIDGenerator{})
+			got, actualErr := Invoices.ExportEFaktur(context.Background(), params)
+
+			assert.Equal(t, testCase.err, actualErr)
+
+			// the first three lines are the column headers
+			lines := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+			if testCase.expected != nil {
+				assert.Equal(t, testCase.expected, lines[3:])
+			} else {
+				assert.Equal(t, 0, len(got))
+			}
+		})
+	}
+}
+
+func TestInvoiceService_SetTaxInvoiceNumber(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	newInvoice := func(status string) entity.Invoices {
+		return entity.Invoices{
+			InvoicesData: entity.InvoicesData{
+				InvoiceID: "0001",
+				Status:    status,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		invoice         entity.Invoices
+		getForUpdateErr error
+		updateErr       error
+		expected        contract.TaxInvoiceNumberResponse
+		err             error
+	}{
+		{
+			name:            "err invoice id not found",
+			getForUpdateErr: sql.ErrNoRows,
+			err:             errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name:    "err draft invoice",
+			invoice: newInvoice(entity.InvoiceStatusDraft),
+			err:     errorss.ErrInvoiceTaxNumberNotAssignable,
+		},
+		{
+			name:    "err void invoice",
+			invoice: newInvoice(entity.InvoiceStatusVoid),
+			err:     errorss.ErrInvoiceTaxNumberNotAssignable,
+		},
+		{
+			name:      "err duplicate tax invoice number",
+			invoice:   newInvoice(entity.InvoiceStatusIssued),
+			updateErr: errorss.ErrDuplicateTaxInvoiceNumber,
+			err:       errorss.ErrDuplicateTaxInvoiceNumber,
+		},
+		{
+			name:    "success paid invoice",
+			invoice: newInvoice(entity.InvoiceStatusPaid),
+			expected: contract.TaxInvoiceNumberResponse{
+				InvoiceID:        "0001",
+				TaxInvoiceNumber: "0022400000042",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+			mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
+
+			mockAsession.EXPECT().BeginSession(gomock.Any()).
+				Return(atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession), nil).
+				Times(1)
+
+			mockInvoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+				Return(testCase.invoice, testCase.getForUpdateErr).
+				Times(1)
+
+			updatedInvoice := testCase.invoice
+			updatedInvoice.TaxInvoiceNumber = "0022400000042"
+
+			mockInvoicesRepo.EXPECT().UpdateTaxInvoiceNumber(gomock.Any(), &updatedInvoice).
+				Return(testCase.updateErr).
+				Times(1)
+
+			mockAtomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			mockAtomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, nil, nil, nil, nil, nil, nil, nil, mockAsession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.SetTaxInvoiceNumber(context.Background(), contract.TaxInvoiceNumberRequest{TaxInvoiceNumber: "0022400000042"}, "0001")
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_Export(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	params := contract.GetListParam{Page: 1, Limit: 10, Statuses: []string{"Paid"}}
+	errInternal := errors.New("error internal server")
+
+	mockInvoice := &entity.Invoices{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID:  "0001",
+			IssueDate:  time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+			DueDate:    time.Date(2024, time.April, 6, 0, 0, 0, 0, time.UTC),
+			Subject:    "design",
+			Status:     "Paid",
+			SubTotal:   decimal.NewFromInt(300),
+			TaxRate:    decimal.NewFromInt(11),
+			Tax:        decimal.NewFromInt(33),
+			GrandTotal: decimal.NewFromInt(333),
+			AmountPaid: decimal.NewFromInt(333),
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		exportParam contract.ExportParam
+		listErr     error
+		expected    string
+		err         error
+	}{
+		{
+			name:        "err unknown format",
+			exportParam: contract.ExportParam{Format: "pdf"},
+			err:         export.ErrUnknownFormat,
+		},
+		{
+			name:        "err export list",
+			exportParam: contract.ExportParam{Format: export.FormatCSV},
+			listErr:     errInternal,
+			err:         errInternal,
+		},
+		{
+			name:        "success csv",
+			exportParam: contract.ExportParam{Format: export.FormatCSV},
+			expected: "invoice_id,issue_date,due_date,subject,customer_name,status,total_items,sub_total,tax_rate,tax,grand_total,amount_paid,amount_credited\n" +
+				"0001,07-03-2024,06-04-2024,design,,Paid,0,300,11,33,333,333,0\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+
+			mockInvoicesRepo.EXPECT().ExportList(gomock.Any(), params, testCase.exportParam.IncludeItems, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, params contract.GetListParam, includeItems bool, fn func(invoice *entity.Invoices, item *entity.Item) error) error {
+					if testCase.listErr != nil {
+						return testCase.listErr
+					}
+					return fn(mockInvoice, nil)
+				}).
+				Times(1)
+
+			var buf bytes.Buffer
+			Invoices := InitInvoiceservice(mockInvoicesRepo, nil, nil, nil, nil, nil, nil, nil, nil, FixedUUIDGenerator{})
+			actualErr := Invoices.Export(context.Background(), params, testCase.exportParam, &buf)
+
+			assert.Equal(t, true, errors.Is(actualErr, testCase.err))
+			assert.Equal(t, testCase.expected, buf.String())
+		})
+	}
+}
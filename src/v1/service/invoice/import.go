@@ -0,0 +1,104 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// Import creates the invoices of an import file. Each invoice is created on its own like
+// Create does, so one failing invoice does not hold back the others. A dry run creates nothing
+// and only checks what can be checked without writing.
+func (ts *Invoiceservice) Import(ctx context.Context, request contract.ImportRequest) (contract.ImportResponse, error) {
+	response := contract.ImportResponse{
+		DryRun:   request.DryRun,
+		Total:    len(request.Invoices),
+		Invoices: []contract.ImportResult{},
+		Errors:   append([]contract.ImportError{}, request.Errors...),
+	}
+
+	for _, invoice := range request.Invoices {
+		if err := ctx.Err(); err != nil {
+			log.Println("import canceled err: ", err)
+			return response, err
+		}
+
+		if len(invoice.Errors) > 0 {
+			response.Failed++
+			response.Errors = append(response.Errors, invoice.Errors...)
+			continue
+		}
+
+		result := contract.ImportResult{
+			Reference: invoice.Reference,
+			Rows:      invoice.Rows,
+		}
+
+		var err error
+		if request.DryRun {
+			err = ts.checkImport(ctx, invoice.Request)
+		} else {
+			var res contract.InvcResponse
+			res, err = ts.Create(ctx, invoice.Request)
+			result.InvoiceID = res.InvoiceID
+		}
+
+		if err != nil {
+			log.Println("import invoice err: ", err)
+			response.Failed++
+			response.Errors = append(response.Errors, contract.ImportError{
+				Row:       invoice.Rows[0],
+				Reference: invoice.Reference,
+				Message:   err.Error(),
+			})
+			continue
+		}
+
+		response.Imported++
+		response.Invoices = append(response.Invoices, result)
+	}
+
+	return response, nil
+}
+
+// checkImport runs the checks of Create that only read: the totals, the customer and the series.
+// Duplicate tax invoice numbers are only found by the insert.
+func (ts *Invoiceservice) checkImport(ctx context.Context, request contract.InvoiceRequest) error {
+	totals, err := calculateTotals(request)
+	if err != nil {
+		return err
+	}
+
+	if err := validateTotals(request, totals); err != nil {
+		return err
+	}
+
+	if request.CustomerID != uuid.Nil {
+		if _, err := ts.CustomerRepo.Get(ctx, request.CustomerID.String()); err != nil {
+			if err == sql.ErrNoRows {
+				return errorss.ErrCustomerIdNotFound
+			}
+			return err
+		}
+	}
+
+	seriesCode := request.Series
+	if seriesCode == "" {
+		seriesCode = entity.DefaultInvoiceSeries
+	}
+
+	if _, err := ts.SeriesRepo.Get(ctx, seriesCode); err != nil {
+		if err == sql.ErrNoRows {
+			return errorss.ErrInvoiceSeriesNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_Import(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	customerID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	invoiceRequest := contract.InvoiceRequest{
+		Subject:    "design",
+		IssueDate:  "07-03-2024",
+		DueDate:    "06-04-2024",
+		CustomerID: customerID,
+		ItemRequest: []contract.ItemRequest{
+			{Name: "logo", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	rowErrors := []contract.ImportError{{Row: 4, Reference: "B-1", Message: "quantity: gt=0"}}
+
+	request := contract.ImportRequest{
+		Invoices: []contract.ImportInvoice{
+			{Reference: "A-1", Rows: []int{2, 3}, Request: invoiceRequest},
+			{Reference: "B-1", Rows: []int{4}, Errors: rowErrors},
+		},
+		Errors: []contract.ImportError{{Row: 5, Message: "reference is required"}},
+	}
+
+	series := entity.InvoiceSeries{
+		InvoiceSeriesData: entity.InvoiceSeriesData{
+			Code:        entity.DefaultInvoiceSeries,
+			Template:    "{seq:04}",
+			ResetPeriod: "never",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		dryRun      bool
+		customerErr error
+		beginErr    error
+		expected    contract.ImportResponse
+	}{
+		{
+			name:        "dry run customer not found",
+			dryRun:      true,
+			customerErr: sql.ErrNoRows,
+			expected: contract.ImportResponse{
+				DryRun:   true,
+				Total:    2,
+				Failed:   2,
+				Invoices: []contract.ImportResult{},
+				Errors: []contract.ImportError{
+					{Row: 5, Message: "reference is required"},
+					{Row: 2, Reference: "A-1", Message: errorss.ErrCustomerIdNotFound.Error()},
+					rowErrors[0],
+				},
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			expected: contract.ImportResponse{
+				DryRun:   true,
+				Total:    2,
+				Imported: 1,
+				Failed:   1,
+				Invoices: []contract.ImportResult{{Reference: "A-1", Rows: []int{2, 3}}},
+				Errors:   []contract.ImportError{{Row: 5, Message: "reference is required"}, rowErrors[0]},
+			},
+		},
+		{
+			name:     "err create invoice",
+			beginErr: errors.New("error internal server"),
+			expected: contract.ImportResponse{
+				Total:    2,
+				Failed:   2,
+				Invoices: []contract.ImportResult{},
+				Errors: []contract.ImportError{
+					{Row: 5, Message: "reference is required"},
+					{Row: 2, Reference: "A-1", Message: "error internal server"},
+					rowErrors[0],
+				},
+			},
+		},
+		{
+			name: "success",
+			expected: contract.ImportResponse{
+				Total:    2,
+				Imported: 1,
+				Failed:   1,
+				Invoices: []contract.ImportResult{{Reference: "A-1", Rows: []int{2, 3}, InvoiceID: "0001"}},
+				Errors:   []contract.ImportError{{Row: 5, Message: "reference is required"}, rowErrors[0]},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+
+			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+			mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
+			mockAtomicSessionCtx := atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession)
+
+			if !testCase.dryRun {
+				mockAsession.EXPECT().BeginSession(gomock.Any()).
+					Return(mockAtomicSessionCtx, testCase.beginErr).
+					Times(1)
+
+				mockSeriesRepo.EXPECT().NextValue(gomock.Any(), entity.DefaultInvoiceSeries, "").
+					Return(int64(1), nil).
+					Times(1)
+
+				mockInvoicesRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, data *entity.Invoices) (contract.InvoiceResponseDB, error) {
+						assert.Equal(t, time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC), data.IssueDate)
+						return contract.InvoiceResponseDB{InvoiceID: data.InvoiceID}, nil
+					}).
+					Times(1)
+
+				mockItemRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil).
+					Times(1)
+
+				mockAtomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			}
+
+			mockCustomerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+				Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: customerID}}, testCase.customerErr).
+				Times(1)
+
+			mockSeriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+				Return(series, nil).
+				Times(1)
+
+			importRequest := request
+			importRequest.DryRun = testCase.dryRun
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, nil, nil, mockSeriesRepo, nil, nil, mockAsession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.Import(context.Background(), importRequest)
+
+			assert.Equal(t, nil, actualErr)
+			assert.Equal(t, testCase.expected, got)
+		})
+	}
+}
@@ -2,7 +2,11 @@ package Invoices
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/document"
 	"github.com/Risuii/invoice/src/entity"
 	"github.com/Risuii/invoice/src/v1/contract"
 	"github.com/google/uuid"
@@ -13,19 +17,59 @@ type InvoicesRepository interface {
 	GetList(ctx context.Context, params contract.GetListParam) ([]*entity.Invoices, error)
 	GetInvoicesCount(ctx context.Context, param contract.GetListParam) (int64, error)
 	Get(ctx context.Context, id string) (entity.Invoices, error)
-	GetLatestInvoiceID(ctx context.Context) (string, error)
 	Update(ctx context.Context, data *entity.Invoices) error
+	GetForUpdate(ctx context.Context, id string) (entity.Invoices, error)
+	UpdateStatus(ctx context.Context, data *entity.Invoices) error
+	CreateStatusHistory(ctx context.Context, data *entity.InvoiceStatusHistory) error
+	UpdateTaxInvoiceNumber(ctx context.Context, data *entity.Invoices) error
+	GetForTaxExport(ctx context.Context, from, to time.Time) ([]*entity.Invoices, error)
+	GetPastDue(ctx context.Context, dueBefore time.Time, includeOverdue bool) ([]*entity.Invoices, error)
+	ExportList(ctx context.Context, params contract.GetListParam, includeItems bool, fn func(invoice *entity.Invoices, item *entity.Item) error) error
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	GetDeletedForUpdate(ctx context.Context, id string) (entity.Invoices, error)
+	Restore(ctx context.Context, id string) error
+	GetTrash(ctx context.Context, params contract.GetTrashListParam) ([]*entity.Invoices, error)
+	GetTrashCount(ctx context.Context) (int64, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type CustomerRepository interface {
-	Create(ctx context.Context, data *entity.Customer) error
 	Get(ctx context.Context, id string) (entity.Customer, error)
-	Update(ctx context.Context, data *entity.Customer) error
+	FindOrCreate(ctx context.Context, data *entity.Customer) (entity.Customer, error)
 }
 
 type ItemRepository interface {
 	Create(ctx context.Context, data []*entity.Item) error
 	GetByInvoiceID(ctx context.Context, invID string) ([]*entity.Item, error)
+	GetByInvoiceIDForUpdate(ctx context.Context, invID string) ([]*entity.Item, error)
 	Update(ctx context.Context, data []*entity.Item) error
 	Delete(ctx context.Context, ids []uuid.UUID) error
+	DeleteByInvoiceID(ctx context.Context, invID string, deletedAt time.Time) error
+	RestoreByInvoiceID(ctx context.Context, invID string, deletedAt time.Time) error
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, data *entity.Payment) error
+	GetByInvoiceID(ctx context.Context, invID string) ([]*entity.Payment, error)
+	GetTotalByInvoiceID(ctx context.Context, invID string) (decimal.Decimal, error)
+}
+
+type CreditNoteRepository interface {
+	Create(ctx context.Context, data *entity.CreditNote) error
+	CreateItems(ctx context.Context, data []*entity.CreditNoteItem) error
+	GetByInvoiceID(ctx context.Context, invID string) ([]*entity.CreditNote, error)
+	GetItemsByInvoiceID(ctx context.Context, invID string) ([]*entity.CreditNoteItem, error)
+}
+
+type SeriesRepository interface {
+	Get(ctx context.Context, code string) (entity.InvoiceSeries, error)
+	NextValue(ctx context.Context, code, periodKey string) (int64, error)
+}
+
+type HTMLRenderer interface {
+	Render(w io.Writer, name string, invoice contract.InvoiceResponse, labels document.Labels) error
+}
+
+type UBLEncoder interface {
+	Encode(w io.Writer, invoice entity.Invoices, customer entity.Customer, items []*entity.Item) error
 }
@@ -3,7 +3,6 @@ package Invoices
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"log"
 	"time"
 
@@ -28,168 +27,189 @@ func (g DefaultUUIDGenerator) New() uuid.UUID {
 }
 
 type Invoiceservice struct {
-	InvoicesRepo  InvoicesRepository
-	CustomerRepo  CustomerRepository
-	ItemRepo      ItemRepository
-	AtomicSession frsAtomic.AtomicSessionProvider
-	UUIDGen       UUIDGenerator
+	InvoicesRepo   InvoicesRepository
+	CustomerRepo   CustomerRepository
+	ItemRepo       ItemRepository
+	PaymentRepo    PaymentRepository
+	CreditNoteRepo CreditNoteRepository
+	SeriesRepo     SeriesRepository
+	HTMLRenderer   HTMLRenderer
+	UBLEncoder     UBLEncoder
+	AtomicSession  frsAtomic.AtomicSessionProvider
+	UUIDGen        UUIDGenerator
+
+	// Now is the clock days_overdue is counted with
+	Now func() time.Time
 }
 
-func InitInvoiceservice(InvoicesRepo InvoicesRepository, customerRepo CustomerRepository, item ItemRepository, aSession frsAtomic.AtomicSessionProvider, uuid UUIDGenerator) *Invoiceservice {
+func InitInvoiceservice(InvoicesRepo InvoicesRepository, customerRepo CustomerRepository, item ItemRepository, payment PaymentRepository, creditNote CreditNoteRepository, series SeriesRepository, htmlRenderer HTMLRenderer, ublEncoder UBLEncoder, aSession frsAtomic.AtomicSessionProvider, uuid UUIDGenerator) *Invoiceservice {
 	return &Invoiceservice{
-		InvoicesRepo:  InvoicesRepo,
-		CustomerRepo:  customerRepo,
-		ItemRepo:      item,
-		AtomicSession: aSession,
-		UUIDGen:       uuid,
+		InvoicesRepo:   InvoicesRepo,
+		CustomerRepo:   customerRepo,
+		ItemRepo:       item,
+		PaymentRepo:    payment,
+		CreditNoteRepo: creditNote,
+		SeriesRepo:     series,
+		HTMLRenderer:   htmlRenderer,
+		UBLEncoder:     ublEncoder,
+		AtomicSession:  aSession,
+		UUIDGen:        uuid,
+		Now:            time.Now,
 	}
 }
 
-func incrementInvoiceID(lastInvoiceID string) string {
-	var lastID int
-	_, err := fmt.Sscanf(lastInvoiceID, "%d", &lastID)
+func (ts *Invoiceservice) Create(ctx context.Context, request contract.InvoiceRequest) (contract.InvcResponse, error) {
+	var res contract.InvcResponse
+
+	totals, err := calculateTotals(request)
 	if err != nil {
-		log.Fatal(err)
+		log.Println("calculate totals err: ", err)
+		return res, err
 	}
 
-	newID := lastID + 1
-
-	return fmt.Sprintf("%04d", newID)
-}
+	if err := validateTotals(request, totals); err != nil {
+		log.Println("validate totals err: ", err)
+		return res, err
+	}
 
-func findItemID(arr1, arr2 []uuid.UUID) []uuid.UUID {
-	// Membuat map untuk menyimpan frekuensi UUID pada array pertama
-	uuidCount := make(map[uuid.UUID]int)
+	err = frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		var err error
+		res, err = ts.insertInvoice(ctx, request, totals)
+		return err
+	})
 
-	// Menghitung frekuensi UUID pada array pertama
-	for _, id := range arr1 {
-		uuidCount[id]++
+	if err != nil {
+		log.Println("err: ", err)
+		return res, err
 	}
 
-	// Mengurangkan frekuensi UUID pada array kedua
-	for _, id := range arr2 {
-		uuidCount[id]--
-	}
+	return res, nil
+}
 
-	// Membuat slice untuk menyimpan UUID yang tidak ada di kedua array
-	missingUUIDs := make([]uuid.UUID, 0)
+// CreateInTransaction creates a draft invoice like Create, in the transaction of ctx. The
+// invoice is only kept when the caller commits.
+func (ts *Invoiceservice) CreateInTransaction(ctx context.Context, request contract.InvoiceRequest) (contract.InvcResponse, error) {
+	totals, err := calculateTotals(request)
+	if err != nil {
+		log.Println("calculate totals err: ", err)
+		return contract.InvcResponse{}, err
+	}
 
-	// Menemukan UUID yang frekuensinya tidak nol (artinya hanya ada di salah satu array)
-	for id, count := range uuidCount {
-		if count != 0 {
-			missingUUIDs = append(missingUUIDs, id)
-		}
+	if err := validateTotals(request, totals); err != nil {
+		log.Println("validate totals err: ", err)
+		return contract.InvcResponse{}, err
 	}
 
-	return missingUUIDs
+	return ts.insertInvoice(ctx, request, totals)
 }
 
-func (ts *Invoiceservice) Create(ctx context.Context, request contract.InvoiceRequest) (contract.InvcResponse, error) {
+// insertInvoice writes a new draft invoice with its items. It has to run inside a transaction,
+// see allocateInvoiceID.
+func (ts *Invoiceservice) insertInvoice(ctx context.Context, request contract.InvoiceRequest, totals invoiceTotals) (contract.InvcResponse, error) {
 	var res contract.InvcResponse
-	var newInvoiceID string
-
-	invoiceID, _ := ts.InvoicesRepo.GetLatestInvoiceID(ctx)
-
-	if invoiceID == "" {
-		newInvoiceID = incrementInvoiceID("0000")
-	} else {
-		newInvoiceID = incrementInvoiceID(invoiceID)
-	}
 
 	layout := "02-01-2006"
-	uuidForCustomer := ts.UUIDGen.New()
 	newIssueDate, _ := time.Parse(layout, request.IssueDate)
 
 	newDueDate, _ := time.Parse(layout, request.DueDate)
 
-	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
-
-		insertDataCustomer := entity.Customer{
-			CustomerData: entity.CustomerData{
-				CustomerID: uuidForCustomer,
-				Name:       request.CustomerRequest.CustomerName,
-				Address:    request.CustomerRequest.Address,
-			},
-		}
-
-		insertDataInvoice := entity.Invoices{
-			InvoicesData: entity.InvoicesData{
-				InvoiceID:  newInvoiceID,
-				IssueDate:  newIssueDate,
-				Subject:    request.Subject,
-				TotalItems: len(request.ItemRequest),
-				CustomerID: insertDataCustomer.CustomerID,
-				DueDate:    newDueDate,
-				SubTotal:   request.SubTotal,
-				Tax:        request.Tax,
-				GrandTotal: request.GrandTotal,
-				Status:     "Unpaid",
-			},
-		}
-
-		items := stream.Map(stream.OfSlice(request.ItemRequest), func(i contract.ItemRequest) *entity.Item {
-			return &entity.Item{
-				ItemData: entity.ItemData{
-					InvoiceID: insertDataInvoice.InvoiceID,
-					ItemID:    ts.UUIDGen.New(),
-					Name:      i.Name,
-					Type:      i.Type,
-					Quantity:  i.Quantity,
-					UnitPrice: i.UnitPrice,
-					Amount:    i.Amount,
-				},
-			}
-		}).ToSlice()
-
-		err := ts.CustomerRepo.Create(ctx, &insertDataCustomer)
-		if err != nil {
-			log.Println("create customer err: ", err)
-			return err
-		}
+	customerID, err := ts.resolveCustomer(ctx, request)
+	if err != nil {
+		log.Println("resolve customer err: ", err)
+		return res, err
+	}
 
-		invoiceData, err := ts.InvoicesRepo.Create(ctx, &insertDataInvoice)
-		if err != nil {
-			log.Println("create invoice err: ", err)
-			return err
-		}
+	newInvoiceID, err := ts.allocateInvoiceID(ctx, request.Series, newIssueDate)
+	if err != nil {
+		log.Println("allocate invoice id err: ", err)
+		return res, err
+	}
 
-		err = ts.ItemRepo.Create(ctx, items)
-		if err != nil {
-			log.Println("create item err: ", err)
-			return err
-		}
+	insertDataInvoice := entity.Invoices{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID:  newInvoiceID,
+			IssueDate:  newIssueDate,
+			Subject:    request.Subject,
+			TotalItems: len(request.ItemRequest),
+			CustomerID: customerID,
+			DueDate:    newDueDate,
+			SubTotal:   totals.SubTotal,
+			TaxRate:    request.TaxRate,
+			Tax:        totals.Tax,
+			GrandTotal: totals.GrandTotal,
+			Status:     entity.InvoiceStatusDraft,
+
+			TaxInvoiceNumber: request.TaxInvoiceNumber,
+		},
+	}
 
-		res = contract.InvcResponse{
-			InvoiceID: invoiceData.InvoiceID,
-		}
+	items := make([]*entity.Item, 0, len(request.ItemRequest))
+	for i, v := range request.ItemRequest {
+		items = append(items, &entity.Item{
+			ItemData: entity.ItemData{
+				InvoiceID: insertDataInvoice.InvoiceID,
+				ItemID:    ts.UUIDGen.New(),
+				Name:      v.Name,
+				Type:      v.Type,
+				Quantity:  v.Quantity,
+				UnitPrice: v.UnitPrice,
+				Amount:    totals.Amounts[i],
+			},
+		})
+	}
 
-		return nil
-	})
+	invoiceData, err := ts.InvoicesRepo.Create(ctx, &insertDataInvoice)
+	if err != nil {
+		log.Println("create invoice err: ", err)
+		return res, err
+	}
 
+	err = ts.ItemRepo.Create(ctx, items)
 	if err != nil {
-		log.Println("err: ", err)
+		log.Println("create item err: ", err)
 		return res, err
 	}
 
+	res = contract.InvcResponse{
+		InvoiceID: invoiceData.InvoiceID,
+	}
+
 	return res, nil
 }
 
 func (ts *Invoiceservice) GetList(ctx context.Context, params contract.GetListParam) (contract.ListInvoiceResponse, error) {
 	var response contract.ListInvoiceResponse
 
-	Invoices, err := ts.InvoicesRepo.GetList(ctx, params)
-	if err != nil {
-		log.Println("getList err: ", err)
-		return response, err
+	listParams := params
+	if params.CursorMode {
+		// one more row tells whether there is a next page without counting the whole table
+		listParams.Limit++
 	}
 
-	count, err := ts.InvoicesRepo.GetInvoicesCount(ctx, params)
+	Invoices, err := ts.InvoicesRepo.GetList(ctx, listParams)
 	if err != nil {
-		log.Println("InvoicesCount err: ", err)
+		log.Println("getList err: ", err)
 		return response, err
 	}
 
-	pagination := frsUtils.GetPaginationData(params.Page, params.Limit, int(count))
+	var pagination *frsUtils.Pagination
+	var nextCursor string
+
+	if params.CursorMode {
+		if len(Invoices) > params.Limit {
+			Invoices = Invoices[:params.Limit]
+			last := Invoices[len(Invoices)-1]
+			nextCursor = contract.EncodeCursor(contract.Cursor{CreatedAt: last.CreatedAt, ID: last.Id})
+		}
+	} else {
+		count, err := ts.InvoicesRepo.GetInvoicesCount(ctx, params)
+		if err != nil {
+			log.Println("InvoicesCount err: ", err)
+			return response, err
+		}
+
+		pagination = frsUtils.GetPaginationData(params.Page, params.Limit, int(count))
+	}
 
 	responseInvoicesList := stream.Map(stream.OfSlice(Invoices), func(t *entity.Invoices) *contract.Invoice {
 		return &contract.Invoice{
@@ -203,6 +223,7 @@ func (ts *Invoiceservice) GetList(ctx context.Context, params contract.GetListPa
 			SubTotal:     t.SubTotal,
 			Tax:          t.Tax,
 			GrandTotal:   t.GrandTotal,
+			DaysOverdue:  ts.daysOverdue(t.InvoicesData),
 			CreatedAt:    t.CreatedAt,
 			UpdatedAt:    t.UpdatedAt,
 		}
@@ -211,6 +232,7 @@ func (ts *Invoiceservice) GetList(ctx context.Context, params contract.GetListPa
 	response = contract.ListInvoiceResponse{
 		Data:       responseInvoicesList,
 		Pagination: pagination,
+		NextCursor: nextCursor,
 	}
 
 	return response, nil
@@ -251,6 +273,12 @@ func (ts *Invoiceservice) GetDetail(ctx context.Context, id string) (contract.In
 		return res, err
 	}
 
+	dataPayments, err := ts.PaymentRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+	if err != nil {
+		log.Println(err)
+		return res, err
+	}
+
 	items := stream.Map(stream.OfSlice(dataItems), func(i *entity.Item) contract.ItemResponse {
 		return contract.ItemResponse{
 			ItemID:    i.ItemID,
@@ -262,141 +290,209 @@ func (ts *Invoiceservice) GetDetail(ctx context.Context, id string) (contract.In
 	}).ToSlice()
 
 	res = contract.InvoiceResponse{
-		InvoiceID:    dataInvoices.InvoiceID,
-		IssueDate:    dataInvoices.IssueDate.Format("02-01-2006"),
-		Subject:      dataInvoices.Subject,
-		TotalItem:    dataInvoices.TotalItems,
-		Items:        items,
-		CustomerName: dataCustomer.Name,
-		DueDate:      dataInvoices.DueDate.Format("02-01-2006"),
-		Status:       dataInvoices.Status,
-		SubTotal:     dataInvoices.SubTotal,
-		Tax:          dataInvoices.Tax,
-		GrandTotal:   dataInvoices.GrandTotal,
+		InvoiceID:          dataInvoices.InvoiceID,
+		IssueDate:          dataInvoices.IssueDate.Format("02-01-2006"),
+		Subject:            dataInvoices.Subject,
+		TotalItem:          dataInvoices.TotalItems,
+		Items:              items,
+		CustomerName:       dataCustomer.Name,
+		CustomerAddress:    dataCustomer.Address,
+		DueDate:            dataInvoices.DueDate.Format("02-01-2006"),
+		Status:             dataInvoices.Status,
+		DaysOverdue:        ts.daysOverdue(dataInvoices.InvoicesData),
+		SubTotal:           dataInvoices.SubTotal,
+		TaxRate:            dataInvoices.TaxRate,
+		Tax:                dataInvoices.Tax,
+		GrandTotal:         dataInvoices.GrandTotal,
+		AmountCredited:     dataInvoices.AmountCredited,
+		AmountPaid:         dataInvoices.AmountPaid,
+		OutstandingBalance: outstandingBalance(dataInvoices.GrandTotal, dataInvoices.AmountCredited, dataInvoices.AmountPaid),
+		Payments:           buildPaymentResponses(dataPayments),
+		TaxInvoiceNumber:   dataInvoices.TaxInvoiceNumber,
+		Version:            dataInvoices.Version,
 	}
 
 	return res, nil
 }
 
-func (ts *Invoiceservice) Update(ctx context.Context, request contract.InvoiceRequest, id string) (contract.InvcResponse, error) {
+// Update replaces a draft invoice with the request. version is the version the client last read,
+// the update is rejected with ErrInvoiceVersionMismatch when the invoice changed since;
+// contract.AnyVersion overwrites whatever is stored.
+func (ts *Invoiceservice) Update(ctx context.Context, request contract.InvoiceRequest, id string, version int64) (contract.InvcResponse, error) {
 	var res contract.InvcResponse
 
-	dataInvoices, err := ts.InvoicesRepo.Get(ctx, id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Println(err)
-			return res, errorss.ErrInvoiceIdNotFound
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, dataItems, err := ts.getEditableInvoice(ctx, id, version)
+		if err != nil {
+			return err
 		}
-		log.Println(err)
-		return res, err
+
+		res, err = ts.replaceInvoice(ctx, dataInvoices, dataItems, request)
+		return err
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return contract.InvcResponse{}, err
 	}
 
-	customerID := dataInvoices.CustomerID.String()
+	return res, nil
+}
 
-	dataCustomer, err := ts.CustomerRepo.Get(ctx, customerID)
+// getEditableInvoice returns a draft invoice with its items when it is still at version. It
+// locks the invoice, so it is called in the transaction of the change and sees the stored
+// version rather than a cached one.
+func (ts *Invoiceservice) getEditableInvoice(ctx context.Context, id string, version int64) (entity.Invoices, []*entity.Item, error) {
+	dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println(err)
-			return res, errorss.ErrCustomerIdNotFound
+			return dataInvoices, nil, errorss.ErrInvoiceIdNotFound
 		}
 		log.Println(err)
-		return res, err
+		return dataInvoices, nil, err
 	}
 
-	dataItems, err := ts.ItemRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+	// only drafts can be edited, issued invoices are changed through their lifecycle endpoints
+	if dataInvoices.Status != entity.InvoiceStatusDraft {
+		log.Println("update invoice err: ", errorss.ErrInvoiceNotEditable)
+		return dataInvoices, nil, errorss.ErrInvoiceNotEditable
+	}
+
+	if version != contract.AnyVersion && version != dataInvoices.Version {
+		log.Println("update invoice err: ", errorss.ErrInvoiceVersionMismatch)
+		return dataInvoices, nil, errorss.ErrInvoiceVersionMismatch
+	}
+
+	dataItems, err := ts.ItemRepo.GetByInvoiceIDForUpdate(ctx, dataInvoices.InvoiceID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println(err)
-			return res, errorss.ErrInvoiceIdNotFound
+			return dataInvoices, nil, errorss.ErrInvoiceIdNotFound
 		}
 		log.Println(err)
+		return dataInvoices, nil, err
+	}
+
+	return dataInvoices, dataItems, nil
+}
+
+// replaceInvoice writes the request over a stored invoice in the transaction of ctx. Items are
+// matched by item_id: a stored item missing from the request is deleted and an item without
+// item_id is added.
+func (ts *Invoiceservice) replaceInvoice(ctx context.Context, dataInvoices entity.Invoices, dataItems []*entity.Item, request contract.InvoiceRequest) (contract.InvcResponse, error) {
+	var res contract.InvcResponse
+
+	totals, err := calculateTotals(request)
+	if err != nil {
+		log.Println("calculate totals err: ", err)
 		return res, err
 	}
 
-	layout := "02-01-2006"
-	newIssueDate, _ := time.Parse(layout, request.IssueDate)
-	newDueDate, _ := time.Parse(layout, request.DueDate)
+	if err := validateTotals(request, totals); err != nil {
+		log.Println("validate totals err: ", err)
+		return res, err
+	}
 
-	err = frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+	storedItems := make(map[uuid.UUID]bool, len(dataItems))
+	for _, v := range dataItems {
+		storedItems[v.ItemID] = true
+	}
 
-		// invoice
-		dataInvoices.IssueDate = newIssueDate
-		dataInvoices.Subject = request.Subject
-		dataInvoices.TotalItems = len(request.ItemRequest)
-		dataInvoices.DueDate = newDueDate
-		dataInvoices.SubTotal = request.SubTotal
-		dataInvoices.Tax = request.Tax
-		dataInvoices.GrandTotal = request.GrandTotal
-
-		// customer
-		dataCustomer.Name = request.CustomerRequest.CustomerName
-		dataCustomer.Address = request.CustomerRequest.Address
-
-		var data []uuid.UUID
-		var dataRequest []uuid.UUID
-		for _, v := range dataItems {
-			data = append(data, v.ItemID)
-		}
-		for _, v := range request.ItemRequest {
-			dataRequest = append(dataRequest, v.ItemID)
+	newItems := []*entity.Item{}
+	updatedItems := []*entity.Item{}
+	for i, v := range request.ItemRequest {
+		item := &entity.Item{
+			ItemData: entity.ItemData{
+				InvoiceID: dataInvoices.InvoiceID,
+				ItemID:    v.ItemID,
+				Name:      v.Name,
+				Type:      v.Type,
+				Quantity:  v.Quantity,
+				UnitPrice: v.UnitPrice,
+				Amount:    totals.Amounts[i],
+			},
 		}
 
-		var newID []uuid.UUID
-		if len(data) > len(dataRequest) {
-			missID := findItemID(data, dataRequest)
-			newID = append(newID, missID...)
+		if v.ItemID == uuid.Nil {
+			item.ItemID = ts.UUIDGen.New()
+			newItems = append(newItems, item)
+			continue
 		}
 
-		err := ts.ItemRepo.Delete(ctx, newID)
-		if err != nil {
-			log.Println("delete id err: ", err)
-			return err
+		// also rejects an item_id sent twice
+		if !storedItems[v.ItemID] {
+			log.Println("update invoice err: ", errorss.ErrInvoiceItemNotFound)
+			return res, errorss.ErrInvoiceItemNotFound
 		}
+		delete(storedItems, v.ItemID)
+		updatedItems = append(updatedItems, item)
+	}
 
-		// item
-		for i, v := range request.ItemRequest {
-			dataItems[i] = &entity.Item{
-				ItemData: entity.ItemData{
-					InvoiceID: dataInvoices.InvoiceID,
-					ItemID:    data[i],
-					Name:      v.Name,
-					Type:      v.Type,
-					Quantity:  v.Quantity,
-					UnitPrice: v.UnitPrice,
-					Amount:    v.Amount,
-				},
-			}
+	var deletedItems []uuid.UUID
+	for _, v := range dataItems {
+		if storedItems[v.ItemID] {
+			deletedItems = append(deletedItems, v.ItemID)
 		}
+	}
 
-		err = ts.CustomerRepo.Update(ctx, &dataCustomer)
-		if err != nil {
-			log.Println("update customer err: ", err)
-			return err
-		}
+	layout := "02-01-2006"
+	newIssueDate, _ := time.Parse(layout, request.IssueDate)
+	newDueDate, _ := time.Parse(layout, request.DueDate)
 
-		err = ts.InvoicesRepo.Update(ctx, &dataInvoices)
-		if err != nil {
-			log.Println("update invoice err: ", err)
-			return err
-		}
+	// invoice
+	dataInvoices.IssueDate = newIssueDate
+	dataInvoices.Subject = request.Subject
+	dataInvoices.TotalItems = len(request.ItemRequest)
+	dataInvoices.DueDate = newDueDate
+	dataInvoices.SubTotal = totals.SubTotal
+	dataInvoices.TaxRate = request.TaxRate
+	dataInvoices.Tax = totals.Tax
+	dataInvoices.GrandTotal = totals.GrandTotal
+	dataInvoices.TaxInvoiceNumber = request.TaxInvoiceNumber
+
+	// customer, shared with other invoices so it is switched instead of renamed
+	customerID, err := ts.resolveCustomer(ctx, request)
+	if err != nil {
+		log.Println("resolve customer err: ", err)
+		return res, err
+	}
+	dataInvoices.CustomerID = customerID
 
-		err = ts.ItemRepo.Update(ctx, dataItems)
-		if err != nil {
-			log.Println("update item err: ", err)
-			return err
-		}
+	err = ts.ItemRepo.Delete(ctx, deletedItems)
+	if err != nil {
+		log.Println("delete id err: ", err)
+		return res, err
+	}
 
-		res = contract.InvcResponse{
-			InvoiceID: dataInvoices.InvoiceID,
+	// the update only matches the version read above, no row means someone else updated it first
+	err = ts.InvoicesRepo.Update(ctx, &dataInvoices)
+	if err != nil {
+		log.Println("update invoice err: ", err)
+		if err == sql.ErrNoRows {
+			return res, errorss.ErrInvoiceVersionMismatch
 		}
+		return res, err
+	}
 
-		return nil
-	})
-
+	err = ts.ItemRepo.Update(ctx, updatedItems)
 	if err != nil {
-		log.Println("err: ", err)
+		log.Println("update item err: ", err)
 		return res, err
 	}
 
+	if len(newItems) > 0 {
+		err = ts.ItemRepo.Create(ctx, newItems)
+		if err != nil {
+			log.Println("create item err: ", err)
+			return res, err
+		}
+	}
+
+	res = contract.InvcResponse{
+		InvoiceID: dataInvoices.InvoiceID,
+		Version:   dataInvoices.Version + 1,
+	}
+
 	return res, nil
 }
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
 	"github.com/Risuii/invoice/src/entity"
 	errorss "github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/numbering"
 	"github.com/Risuii/invoice/src/v1/contract"
 	"github.com/go-faker/faker/v4"
 	"github.com/go-playground/assert"
@@ -71,12 +73,22 @@ func TestInvoiceService_GetList(t *testing.T) {
 		Limit: 10,
 	}
 
+	mockCursorParams := contract.GetListParam{
+		Limit:      3,
+		CursorMode: true,
+	}
+
+	mockLastCursorParams := contract.GetListParam{
+		Limit:      5,
+		CursorMode: true,
+	}
+
 	sizeDataSet := 5
 
 	for i := 0; i < sizeDataSet; i++ {
 		mockInvoices = append(mockInvoices, &entity.Invoices{
 			ModelID: entity.ModelID{
-				Id: 1,
+				Id: int64(i + 1),
 			},
 			ModelLogTime: entity.ModelLogTime{
 				CreatedAt: currentDate,
@@ -141,6 +153,35 @@ func TestInvoiceService_GetList(t *testing.T) {
 				err: errors.New("error"),
 			},
 		},
+		{
+			name: "success cursor mode with next page",
+			given: given{
+				params: mockCursorParams,
+				getListInvoices: getListInvoices{
+					Invoices: mockInvoices,
+				},
+			},
+			expected: expected{
+				res: contract.ListInvoiceResponse{
+					Data:       mockInvoicesResp[:mockCursorParams.Limit],
+					NextCursor: contract.EncodeCursor(contract.Cursor{CreatedAt: currentDate, ID: 3}),
+				},
+			},
+		},
+		{
+			name: "success cursor mode last page",
+			given: given{
+				params: mockLastCursorParams,
+				getListInvoices: getListInvoices{
+					Invoices: mockInvoices,
+				},
+			},
+			expected: expected{
+				res: contract.ListInvoiceResponse{
+					Data: mockInvoicesResp,
+				},
+			},
+		},
 		{
 			name: "success",
 			given: given{
@@ -172,21 +213,30 @@ func TestInvoiceService_GetList(t *testing.T) {
 		mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
 		mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
 		mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+		mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+		mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+		mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+		mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
 		mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
 
 		func() {
-			mockInvoicesRepo.EXPECT().GetList(gomock.Any(), testCase.given.params).
+			listParams := testCase.given.params
+			if listParams.CursorMode {
+				listParams.Limit++
+			}
+
+			mockInvoicesRepo.EXPECT().GetList(gomock.Any(), listParams).
 				Return(testCase.given.getListInvoices.Invoices, testCase.given.getListInvoices.err).
 				Times(1)
 
-			if testCase.given.getListInvoices.err == nil {
+			if testCase.given.getListInvoices.err == nil && !testCase.given.params.CursorMode {
 				mockInvoicesRepo.EXPECT().GetInvoicesCount(gomock.Any(), testCase.given.params).
 					Return(testCase.given.getInvoicesCount.count, testCase.given.getInvoicesCount.err).
 					Times(1)
 			}
 		}()
 
-		Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockAsession, FixedUUIDGenerator{})
+		Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
 		got, actualErr := Invoices.GetList(context.Background(), testCase.given.params)
 		assert.Equal(t, testCase.expected.res, got)
 		assert.Equal(t, testCase.expected.err, actualErr)
@@ -213,12 +263,18 @@ func TestInvoiceService_GetDetail(t *testing.T) {
 			err   error
 		}
 
+		getPayments struct {
+			payments []*entity.Payment
+			err      error
+		}
+
 		given struct {
 			id          string
 			uuid        uuid.UUID
 			getInvoices getInvoices
 			getCustomer getCustomer
 			getItems    getItems
+			getPayments getPayments
 		}
 
 		expected struct {
@@ -252,9 +308,9 @@ func TestInvoiceService_GetDetail(t *testing.T) {
 				InvoiceID: faker.Name(),
 				Name:      faker.Name(),
 				Type:      faker.Name(),
-				Quantity:  5,
-				UnitPrice: 5,
-				Amount:    5,
+				Quantity:  decimal.NewFromInt(5),
+				UnitPrice: decimal.NewFromInt(5),
+				Amount:    decimal.NewFromInt(5),
 			},
 		})
 	}
@@ -409,6 +465,7 @@ func TestInvoiceService_GetDetail(t *testing.T) {
 					IssueDate: "01-01-0001",
 					Items:     mockItems,
 					DueDate:   "01-01-0001",
+					Payments:  []contract.PaymentResponse{},
 				},
 				err: nil,
 			},
@@ -419,6 +476,10 @@ func TestInvoiceService_GetDetail(t *testing.T) {
 		mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
 		mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
 		mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+		mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+		mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+		mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+		mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
 		mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
 
 		func() {
@@ -434,11 +495,17 @@ func TestInvoiceService_GetDetail(t *testing.T) {
 					mockItemRepo.EXPECT().GetByInvoiceID(gomock.Any(), testCase.given.getInvoices.Invoices.InvoiceID).
 						Return(testCase.given.getItems.items, testCase.given.getItems.err).
 						Times(1)
+
+					if testCase.given.getItems.err == nil {
+						mockPaymentRepo.EXPECT().GetByInvoiceID(gomock.Any(), testCase.given.getInvoices.Invoices.InvoiceID).
+							Return(testCase.given.getPayments.payments, testCase.given.getPayments.err).
+							Times(1)
+					}
 				}
 			}
 		}()
 
-		Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockAsession, FixedUUIDGenerator{})
+		Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
 		got, actualErr := Invoices.GetDetail(context.Background(), testCase.given.id)
 		log.Println(got)
 		assert.Equal(t, testCase.expected.res, got)
@@ -451,13 +518,24 @@ func TestInvoiceService_Create(t *testing.T) {
 	mockCtrl.Finish()
 
 	type (
-		getLatestInvoiceID struct {
-			invoiceID string
-			err       error
+		getSeries struct {
+			series entity.InvoiceSeries
+			err    error
 		}
 
-		createCustomer struct {
-			err error
+		nextValue struct {
+			value int64
+			err   error
+		}
+
+		getCustomer struct {
+			customer entity.Customer
+			err      error
+		}
+
+		findOrCreateCustomer struct {
+			customer entity.Customer
+			err      error
 		}
 
 		createInvoice struct {
@@ -470,14 +548,16 @@ func TestInvoiceService_Create(t *testing.T) {
 		}
 
 		given struct {
-			req                contract.InvoiceRequest
-			dataCustomer       entity.Customer
-			dataInvoices       entity.Invoices
-			dataItem           []*entity.Item
-			getLatestInvoiceID getLatestInvoiceID
-			createCustomer     createCustomer
-			createInvoice      createInvoice
-			createItem         createItem
+			req                  contract.InvoiceRequest
+			dataCustomer         entity.Customer
+			dataInvoices         entity.Invoices
+			dataItem             []*entity.Item
+			getSeries            getSeries
+			nextValue            nextValue
+			getCustomer          getCustomer
+			findOrCreateCustomer findOrCreateCustomer
+			createInvoice        createInvoice
+			createItem           createItem
 		}
 
 		expected struct {
@@ -496,16 +576,46 @@ func TestInvoiceService_Create(t *testing.T) {
 
 	mockInvoiceRequest := contract.InvoiceRequest{
 		Subject:    faker.Name(),
-		SubTotal:   1,
-		Tax:        1,
-		GrandTotal: 1,
-		CustomerRequest: contract.CustomerRequest{
+		SubTotal:   decimal.NewFromInt(300),
+		TaxRate:    decimal.NewFromInt(10),
+		GrandTotal: decimal.NewFromInt(330),
+		CustomerRequest: &contract.CustomerRequest{
 			CustomerName: faker.Name(),
 			Address:      faker.Name(),
 		},
-		ItemRequest: []contract.ItemRequest{},
+		ItemRequest: []contract.ItemRequest{
+			{
+				Name:      faker.Name(),
+				Type:      faker.Name(),
+				Quantity:  decimal.NewFromInt(2),
+				UnitPrice: decimal.NewFromInt(100),
+			},
+			{
+				Name:      faker.Name(),
+				Type:      faker.Name(),
+				Quantity:  decimal.RequireFromString("0.5"),
+				UnitPrice: decimal.NewFromInt(200),
+				Amount:    decimal.NewFromInt(100),
+			},
+		},
+	}
+
+	mockExistingCustomerRequest := mockInvoiceRequest
+	mockExistingCustomerRequest.CustomerID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	mockExistingCustomerRequest.CustomerRequest = nil
+
+	mockSeries := entity.InvoiceSeries{
+		InvoiceSeriesData: entity.InvoiceSeriesData{
+			Code:        entity.DefaultInvoiceSeries,
+			Template:    "{seq:04}",
+			ResetPeriod: numbering.ResetNever,
+		},
 	}
 
+	mockMismatchRequest := mockInvoiceRequest
+	mockMismatchRequest.Tax = decimal.NewFromInt(10)
+	mockMismatchRequest.GrandTotal = decimal.NewFromInt(310)
+
 	mockInsertDataCustomer := entity.Customer{
 		CustomerData: entity.CustomerData{
 			CustomerID: mockID,
@@ -522,31 +632,96 @@ func TestInvoiceService_Create(t *testing.T) {
 			Subject:    mockInvoiceRequest.Subject,
 			TotalItems: len(mockInvoiceRequest.ItemRequest),
 			CustomerID: mockInsertDataCustomer.CustomerID,
-			SubTotal:   mockInvoiceRequest.SubTotal,
-			Tax:        mockInvoiceRequest.Tax,
-			GrandTotal: mockInvoiceRequest.GrandTotal,
-			Status:     "Unpaid",
+			SubTotal:   decimal.NewFromInt(300),
+			TaxRate:    decimal.NewFromInt(10),
+			Tax:        decimal.NewFromInt(30),
+			GrandTotal: decimal.NewFromInt(330),
+			Status:     entity.InvoiceStatusDraft,
 		},
 	}
 
 	mockItemResp := stream.Map(stream.OfSlice(mockInvoiceRequest.ItemRequest), func(t contract.ItemRequest) *entity.Item {
 		return &entity.Item{
 			ItemData: entity.ItemData{
+				InvoiceID: "0001",
 				ItemID:    mockID,
 				Name:      t.Name,
 				Type:      t.Type,
 				Quantity:  t.Quantity,
 				UnitPrice: t.UnitPrice,
-				Amount:    t.Amount,
+				Amount:    t.Quantity.Mul(t.UnitPrice),
 			},
 		}
 	}).ToSlice()
 
 	testCases := []testCase{
 		{
-			name: "err create customer",
+			name: "err find or create customer",
+			given: given{
+				req:          mockInvoiceRequest,
+				dataCustomer: mockInsertDataCustomer,
+				findOrCreateCustomer: findOrCreateCustomer{
+					err: errors.New("error internal server"),
+				},
+			},
+			expected: expected{
+				err: errors.New("error internal server"),
+			},
+		},
+		{
+			name: "err customer id not found",
+			given: given{
+				req: mockExistingCustomerRequest,
+				getCustomer: getCustomer{
+					err: sql.ErrNoRows,
+				},
+			},
+			expected: expected{
+				err: errorss.ErrCustomerIdNotFound,
+			},
+		},
+		{
+			name: "err totals mismatch",
+			given: given{
+				req: mockMismatchRequest,
+			},
+			expected: expected{
+				err: &errorss.TotalsMismatchError{
+					Mismatches: []errorss.TotalMismatch{
+						{Field: "tax", Expected: decimal.NewFromInt(30), Actual: decimal.NewFromInt(10)},
+						{Field: "grand_total", Expected: decimal.NewFromInt(330), Actual: decimal.NewFromInt(310)},
+					},
+				},
+			},
+		},
+		{
+			name: "err invoice series not found",
+			given: given{
+				req:          mockInvoiceRequest,
+				dataCustomer: mockInsertDataCustomer,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockInsertDataCustomer,
+				},
+				getSeries: getSeries{
+					err: sql.ErrNoRows,
+				},
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceSeriesNotFound,
+			},
+		},
+		{
+			name: "err next invoice number",
 			given: given{
-				createCustomer: createCustomer{
+				req:          mockInvoiceRequest,
+				dataCustomer: mockInsertDataCustomer,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockInsertDataCustomer,
+				},
+				getSeries: getSeries{
+					series: mockSeries,
+				},
+				nextValue: nextValue{
 					err: errors.New("error internal server"),
 				},
 			},
@@ -560,8 +735,14 @@ func TestInvoiceService_Create(t *testing.T) {
 				req:          mockInvoiceRequest,
 				dataInvoices: mockInsertDataInvoice,
 				dataCustomer: mockInsertDataCustomer,
-				createCustomer: createCustomer{
-					err: nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockInsertDataCustomer,
+				},
+				getSeries: getSeries{
+					series: mockSeries,
+				},
+				nextValue: nextValue{
+					value: 1,
 				},
 				createInvoice: createInvoice{
 					err: errors.New("error internal server"),
@@ -578,8 +759,14 @@ func TestInvoiceService_Create(t *testing.T) {
 				dataInvoices: mockInsertDataInvoice,
 				dataItem:     mockItemResp,
 				dataCustomer: mockInsertDataCustomer,
-				createCustomer: createCustomer{
-					err: nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockInsertDataCustomer,
+				},
+				getSeries: getSeries{
+					series: mockSeries,
+				},
+				nextValue: nextValue{
+					value: 1,
 				},
 				createInvoice: createInvoice{
 					invoice: contract.InvoiceResponseDB{
@@ -603,8 +790,14 @@ func TestInvoiceService_Create(t *testing.T) {
 				dataInvoices: mockInsertDataInvoice,
 				dataItem:     mockItemResp,
 				dataCustomer: mockInsertDataCustomer,
-				createCustomer: createCustomer{
-					err: nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockInsertDataCustomer,
+				},
+				getSeries: getSeries{
+					series: mockSeries,
+				},
+				nextValue: nextValue{
+					value: 1,
 				},
 				createInvoice: createInvoice{
 					invoice: contract.InvoiceResponseDB{
@@ -624,6 +817,41 @@ func TestInvoiceService_Create(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "success with existing customer",
+			given: given{
+				req: mockExistingCustomerRequest,
+				dataInvoices: func() entity.Invoices {
+					data := mockInsertDataInvoice
+					data.CustomerID = mockExistingCustomerRequest.CustomerID
+					return data
+				}(),
+				dataItem: mockItemResp,
+				getCustomer: getCustomer{
+					customer: entity.Customer{
+						CustomerData: entity.CustomerData{
+							CustomerID: mockExistingCustomerRequest.CustomerID,
+						},
+					},
+				},
+				getSeries: getSeries{
+					series: mockSeries,
+				},
+				nextValue: nextValue{
+					value: 1,
+				},
+				createInvoice: createInvoice{
+					invoice: contract.InvoiceResponseDB{
+						InvoiceID: "test-id",
+					},
+				},
+			},
+			expected: expected{
+				res: contract.InvcResponse{
+					InvoiceID: "test-id",
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -631,25 +859,37 @@ func TestInvoiceService_Create(t *testing.T) {
 			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
 			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
 			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
 
 			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
 			mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
 			mockAtomicSessionCtx := atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession)
 
 			func() {
-				mockInvoicesRepo.EXPECT().GetLatestInvoiceID(gomock.Any()).
-					Return(testCase.given.getLatestInvoiceID.invoiceID, testCase.given.getLatestInvoiceID.err).
-					Times(1)
-
 				mockAsession.EXPECT().BeginSession(gomock.Any()).
 					Return(mockAtomicSessionCtx, nil).
 					Times(1)
 
-				mockCustomerRepo.EXPECT().Create(gomock.Any(), &testCase.given.dataCustomer).
-					Return(testCase.given.createCustomer.err).
+				mockCustomerRepo.EXPECT().Get(gomock.Any(), testCase.given.req.CustomerID.String()).
+					Return(testCase.given.getCustomer.customer, testCase.given.getCustomer.err).
+					Times(1)
+
+				mockCustomerRepo.EXPECT().FindOrCreate(gomock.Any(), &testCase.given.dataCustomer).
+					Return(testCase.given.findOrCreateCustomer.customer, testCase.given.findOrCreateCustomer.err).
+					Times(1)
+
+				mockSeriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+					Return(testCase.given.getSeries.series, testCase.given.getSeries.err).
+					Times(1)
+
+				mockSeriesRepo.EXPECT().NextValue(gomock.Any(), entity.DefaultInvoiceSeries, "").
+					Return(testCase.given.nextValue.value, testCase.given.nextValue.err).
 					Times(1)
 
-				testCase.given.dataInvoices.Status = "Unpaid"
+				testCase.given.dataInvoices.Status = entity.InvoiceStatusDraft
 
 				mockInvoicesRepo.EXPECT().Create(gomock.Any(), &testCase.given.dataInvoices).
 					Return(testCase.given.createInvoice.invoice, testCase.given.createInvoice.err).
@@ -664,7 +904,7 @@ func TestInvoiceService_Create(t *testing.T) {
 				mockAtomicSession.EXPECT().Commit(gomock.Any()).Times(1)
 			}()
 
-			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockAsession, FixedUUIDGenerator{})
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
 			got, actualErr := Invoices.Create(context.Background(), testCase.given.req)
 			log.Println(got)
 			assert.Equal(t, testCase.expected.res, got)
@@ -683,9 +923,9 @@ func TestInvoiceService_Update(t *testing.T) {
 			err         error
 		}
 
-		getDataCustomer struct {
-			dataCustomer entity.Customer
-			err          error
+		findOrCreateCustomer struct {
+			customer entity.Customer
+			err      error
 		}
 
 		getDataItems struct {
@@ -697,10 +937,6 @@ func TestInvoiceService_Update(t *testing.T) {
 			err error
 		}
 
-		updateCustomer struct {
-			err error
-		}
-
 		updateInvoice struct {
 			err error
 		}
@@ -710,15 +946,15 @@ func TestInvoiceService_Update(t *testing.T) {
 		}
 
 		given struct {
-			req             contract.InvoiceRequest
-			id              string
-			getDataInvoice  getDataInvoice
-			getDataCustomer getDataCustomer
-			getDataItems    getDataItems
-			deleteDataItems deleteDataItems
-			updateCustomer  updateCustomer
-			updateInvoice   updateInvoice
-			updateItem      updateItem
+			req                  contract.InvoiceRequest
+			id                   string
+			version              int64
+			getDataInvoice       getDataInvoice
+			findOrCreateCustomer findOrCreateCustomer
+			getDataItems         getDataItems
+			deleteDataItems      deleteDataItems
+			updateInvoice        updateInvoice
+			updateItem           updateItem
 		}
 
 		expected struct {
@@ -738,10 +974,10 @@ func TestInvoiceService_Update(t *testing.T) {
 
 	mockInvoiceRequest := contract.InvoiceRequest{
 		Subject:    "test-subject",
-		SubTotal:   0,
-		Tax:        0,
-		GrandTotal: 0,
-		CustomerRequest: contract.CustomerRequest{
+		SubTotal:   decimal.NewFromInt(0),
+		Tax:        decimal.NewFromInt(0),
+		GrandTotal: decimal.NewFromInt(0),
+		CustomerRequest: &contract.CustomerRequest{
 			CustomerName: "test-name",
 			Address:      "test-address",
 		},
@@ -756,10 +992,11 @@ func TestInvoiceService_Update(t *testing.T) {
 			Subject:    "test-subject",
 			TotalItems: len(mockInvoiceRequest.ItemRequest),
 			CustomerID: mockID,
-			SubTotal:   0,
-			Tax:        0,
-			GrandTotal: 0,
-			Status:     "Unpaid",
+			SubTotal:   decimal.NewFromInt(0),
+			Tax:        decimal.NewFromInt(0),
+			GrandTotal: decimal.NewFromInt(0),
+			Status:     entity.InvoiceStatusDraft,
+			Version:    3,
 		},
 	}
 
@@ -803,25 +1040,43 @@ func TestInvoiceService_Update(t *testing.T) {
 			},
 		},
 		{
-			name: "error customer id not found",
+			name: "error invoice not editable",
 			given: given{
 				id:  "test-id",
 				req: mockInvoiceRequest,
+				getDataInvoice: getDataInvoice{
+					dataInvoice: entity.Invoices{
+						InvoicesData: entity.InvoicesData{
+							InvoiceID: "0001",
+							Status:    entity.InvoiceStatusIssued,
+						},
+					},
+					err: nil,
+				},
+			},
+
+			expected: expected{
+				err: errorss.ErrInvoiceNotEditable,
+			},
+		},
+		{
+			name: "error version mismatch",
+			given: given{
+				id:      "test-id",
+				req:     mockInvoiceRequest,
+				version: 2,
 				getDataInvoice: getDataInvoice{
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					err: sql.ErrNoRows,
-				},
 			},
 
 			expected: expected{
-				err: errorss.ErrCustomerIdNotFound,
+				err: errorss.ErrInvoiceVersionMismatch,
 			},
 		},
 		{
-			name: "error get customer data",
+			name: "error find or create customer",
 			given: given{
 				id:  "test-id",
 				req: mockInvoiceRequest,
@@ -829,7 +1084,7 @@ func TestInvoiceService_Update(t *testing.T) {
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
+				findOrCreateCustomer: findOrCreateCustomer{
 					err: errors.New("error internal server"),
 				},
 			},
@@ -847,9 +1102,8 @@ func TestInvoiceService_Update(t *testing.T) {
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					err: sql.ErrNoRows,
@@ -869,9 +1123,8 @@ func TestInvoiceService_Update(t *testing.T) {
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					err: errors.New("error internal server"),
@@ -891,9 +1144,8 @@ func TestInvoiceService_Update(t *testing.T) {
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					dataItem: mockEntityItem,
@@ -909,7 +1161,7 @@ func TestInvoiceService_Update(t *testing.T) {
 			},
 		},
 		{
-			name: "error update customer",
+			name: "error update invoice",
 			given: given{
 				id:  "test-id",
 				req: mockInvoiceRequest,
@@ -917,9 +1169,8 @@ func TestInvoiceService_Update(t *testing.T) {
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					dataItem: mockEntityItem,
@@ -928,7 +1179,7 @@ func TestInvoiceService_Update(t *testing.T) {
 				deleteDataItems: deleteDataItems{
 					err: nil,
 				},
-				updateCustomer: updateCustomer{
+				updateInvoice: updateInvoice{
 					err: errors.New("error internal server"),
 				},
 			},
@@ -938,17 +1189,17 @@ func TestInvoiceService_Update(t *testing.T) {
 			},
 		},
 		{
-			name: "error update invoice",
+			name: "error invoice updated concurrently",
 			given: given{
-				id:  "test-id",
-				req: mockInvoiceRequest,
+				id:      "test-id",
+				req:     mockInvoiceRequest,
+				version: 3,
 				getDataInvoice: getDataInvoice{
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					dataItem: mockEntityItem,
@@ -957,16 +1208,13 @@ func TestInvoiceService_Update(t *testing.T) {
 				deleteDataItems: deleteDataItems{
 					err: nil,
 				},
-				updateCustomer: updateCustomer{
-					err: nil,
-				},
 				updateInvoice: updateInvoice{
-					err: errors.New("error internal server"),
+					err: sql.ErrNoRows,
 				},
 			},
 
 			expected: expected{
-				err: errors.New("error internal server"),
+				err: errorss.ErrInvoiceVersionMismatch,
 			},
 		},
 		{
@@ -978,9 +1226,8 @@ func TestInvoiceService_Update(t *testing.T) {
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					dataItem: mockEntityItem,
@@ -989,9 +1236,6 @@ func TestInvoiceService_Update(t *testing.T) {
 				deleteDataItems: deleteDataItems{
 					err: nil,
 				},
-				updateCustomer: updateCustomer{
-					err: nil,
-				},
 				updateInvoice: updateInvoice{
 					err: nil,
 				},
@@ -1007,15 +1251,15 @@ func TestInvoiceService_Update(t *testing.T) {
 		{
 			name: "success",
 			given: given{
-				id:  "test-id",
-				req: mockInvoiceRequest,
+				id:      "test-id",
+				req:     mockInvoiceRequest,
+				version: 3,
 				getDataInvoice: getDataInvoice{
 					dataInvoice: mockEntityInvoice,
 					err:         nil,
 				},
-				getDataCustomer: getDataCustomer{
-					dataCustomer: mockEntityCustomer,
-					err:          nil,
+				findOrCreateCustomer: findOrCreateCustomer{
+					customer: mockEntityCustomer,
 				},
 				getDataItems: getDataItems{
 					dataItem: mockEntityItem,
@@ -1024,9 +1268,6 @@ func TestInvoiceService_Update(t *testing.T) {
 				deleteDataItems: deleteDataItems{
 					err: nil,
 				},
-				updateCustomer: updateCustomer{
-					err: nil,
-				},
 				updateInvoice: updateInvoice{
 					err: nil,
 				},
@@ -1038,6 +1279,7 @@ func TestInvoiceService_Update(t *testing.T) {
 			expected: expected{
 				res: contract.InvcResponse{
 					InvoiceID: mockEntityInvoice.InvoiceID,
+					Version:   4,
 				},
 				err: nil,
 			},
@@ -1049,36 +1291,34 @@ func TestInvoiceService_Update(t *testing.T) {
 			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
 			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
 			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
 
 			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
 			mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
 			mockAtomicSessionCtx := atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession)
 
 			func() {
-				mockInvoicesRepo.EXPECT().Get(gomock.Any(), testCase.given.id).
-					Return(testCase.given.getDataInvoice.dataInvoice, testCase.given.getDataInvoice.err).
+				mockAsession.EXPECT().BeginSession(gomock.Any()).
+					Return(mockAtomicSessionCtx, nil).
 					Times(1)
 
-				customerID := testCase.given.getDataInvoice.dataInvoice.CustomerID.String()
-
-				mockCustomerRepo.EXPECT().Get(gomock.Any(), customerID).
-					Return(testCase.given.getDataCustomer.dataCustomer, testCase.given.getDataCustomer.err).
+				mockInvoicesRepo.EXPECT().GetForUpdate(gomock.Any(), testCase.given.id).
+					Return(testCase.given.getDataInvoice.dataInvoice, testCase.given.getDataInvoice.err).
 					Times(1)
 
-				mockItemRepo.EXPECT().GetByInvoiceID(gomock.Any(), testCase.given.getDataInvoice.dataInvoice.InvoiceID).
+				mockItemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), testCase.given.getDataInvoice.dataInvoice.InvoiceID).
 					Return(testCase.given.getDataItems.dataItem, testCase.given.getDataItems.err).
 					Times(1)
 
-				mockAsession.EXPECT().BeginSession(gomock.Any()).
-					Return(mockAtomicSessionCtx, nil).
-					Times(1)
-
 				mockItemRepo.EXPECT().Delete(gomock.Any(), mockUUID).
 					Return(testCase.given.deleteDataItems.err).
 					Times(1)
 
-				mockCustomerRepo.EXPECT().Update(gomock.Any(), &mockEntityCustomer).
-					Return(testCase.given.updateCustomer.err).
+				mockCustomerRepo.EXPECT().FindOrCreate(gomock.Any(), &mockEntityCustomer).
+					Return(testCase.given.findOrCreateCustomer.customer, testCase.given.findOrCreateCustomer.err).
 					Times(1)
 
 				mockInvoicesRepo.EXPECT().Update(gomock.Any(), &mockEntityInvoice).
@@ -1095,11 +1335,73 @@ func TestInvoiceService_Update(t *testing.T) {
 
 			}()
 
-			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockAsession, FixedUUIDGenerator{})
-			got, actualErr := Invoices.Update(context.Background(), testCase.given.req, testCase.given.id)
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.Update(context.Background(), testCase.given.req, testCase.given.id, testCase.given.version)
 			log.Println(got)
 			assert.Equal(t, testCase.expected.res, got)
 			assert.Equal(t, testCase.expected.err, actualErr)
 		})
 	}
 }
+
+func TestInvoiceService_UpdateFailedKeepsItems(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	customerID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	logoID := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	hostingID := uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+
+	mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+	mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+	mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+	mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+	mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
+	mockAtomicSessionCtx := atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession)
+
+	mockAsession.EXPECT().BeginSession(gomock.Any()).
+		Return(mockAtomicSessionCtx, nil).
+		Times(1)
+
+	mockInvoicesRepo.EXPECT().GetForUpdate(mockAtomicSessionCtx, "0001").
+		Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001", CustomerID: customerID, Status: entity.InvoiceStatusDraft, Version: 1}}, nil).
+		Times(1)
+
+	mockItemRepo.EXPECT().GetByInvoiceIDForUpdate(mockAtomicSessionCtx, "0001").
+		Return([]*entity.Item{
+			{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: logoID, Name: "logo", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(100)}},
+			{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: hostingID, Name: "hosting", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(50), Amount: decimal.NewFromInt(50)}},
+		}, nil).
+		Times(1)
+
+	mockCustomerRepo.EXPECT().Get(mockAtomicSessionCtx, customerID.String()).
+		Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: customerID}}, nil).
+		Times(1)
+
+	// the removed item is deleted in the transaction of the update, so the rollback restores it
+	mockItemRepo.EXPECT().Delete(mockAtomicSessionCtx, []uuid.UUID{hostingID}).
+		Return(nil).
+		Times(1)
+
+	mockInvoicesRepo.EXPECT().Update(mockAtomicSessionCtx, gomock.Any()).
+		Return(errors.New("error internal server")).
+		Times(1)
+
+	mockAtomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+
+	request := contract.InvoiceRequest{
+		Subject:    "design",
+		IssueDate:  "07-03-2024",
+		DueDate:    "06-04-2024",
+		CustomerID: customerID,
+		ItemRequest: []contract.ItemRequest{
+			{ItemID: logoID, Name: "logo", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, nil, nil, nil, nil, nil, mockAsession, FixedUUIDGenerator{})
+	got, actualErr := Invoices.Update(context.Background(), request, "0001", 1)
+
+	assert.Equal(t, contract.InvcResponse{}, got)
+	assert.Equal(t, errors.New("error internal server"), actualErr)
+}
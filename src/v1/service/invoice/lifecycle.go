@@ -0,0 +1,138 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+type invoiceTransition struct {
+	from []string
+	to   string
+	// unpaidOnly rejects the transition once any payment or credit note was recorded on the invoice
+	unpaidOnly bool
+}
+
+var (
+	issueTransition = invoiceTransition{
+		from: []string{entity.InvoiceStatusDraft},
+		to:   entity.InvoiceStatusIssued,
+	}
+
+	voidTransition = invoiceTransition{
+		from:       []string{entity.InvoiceStatusDraft, entity.InvoiceStatusIssued, entity.InvoiceStatusOverdue},
+		to:         entity.InvoiceStatusVoid,
+		unpaidOnly: true,
+	}
+
+	reopenTransition = invoiceTransition{
+		from:       []string{entity.InvoiceStatusIssued, entity.InvoiceStatusOverdue, entity.InvoiceStatusVoid},
+		to:         entity.InvoiceStatusDraft,
+		unpaidOnly: true,
+	}
+)
+
+func (it invoiceTransition) allowedFrom(status string) bool {
+	for _, v := range it.from {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isPayable reports whether payments can be recorded against an invoice in the given status.
+func isPayable(status string) bool {
+	switch status {
+	case entity.InvoiceStatusDraft, entity.InvoiceStatusVoid, entity.InvoiceStatusCredited:
+		return false
+	default:
+		return true
+	}
+}
+
+func (ts *Invoiceservice) Issue(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	return ts.transition(ctx, issueTransition, request, id)
+}
+
+func (ts *Invoiceservice) Void(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	return ts.transition(ctx, voidTransition, request, id)
+}
+
+func (ts *Invoiceservice) Reopen(ctx context.Context, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	return ts.transition(ctx, reopenTransition, request, id)
+}
+
+func (ts *Invoiceservice) transition(ctx context.Context, it invoiceTransition, request contract.InvoiceTransitionRequest, id string) (contract.InvoiceStatusResponse, error) {
+	var res contract.InvoiceStatusResponse
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrInvoiceIdNotFound
+			}
+			log.Println("get invoice err: ", err)
+			return err
+		}
+
+		if !it.allowedFrom(dataInvoices.Status) {
+			log.Printf("transition invoice %s from %s to %s err: %v", dataInvoices.InvoiceID, dataInvoices.Status, it.to, errorss.ErrInvoiceInvalidStatusTransition)
+			return errorss.ErrInvoiceInvalidStatusTransition
+		}
+
+		if it.unpaidOnly && !dataInvoices.AmountPaid.IsZero() {
+			log.Println("transition invoice err: ", errorss.ErrInvoiceHasPayments)
+			return errorss.ErrInvoiceHasPayments
+		}
+
+		if it.unpaidOnly && !dataInvoices.AmountCredited.IsZero() {
+			log.Println("transition invoice err: ", errorss.ErrInvoiceHasCreditNotes)
+			return errorss.ErrInvoiceHasCreditNotes
+		}
+
+		history := entity.InvoiceStatusHistory{
+			InvoiceStatusHistoryData: entity.InvoiceStatusHistoryData{
+				InvoiceID:  dataInvoices.InvoiceID,
+				FromStatus: dataInvoices.Status,
+				ToStatus:   it.to,
+				Reason:     request.Reason,
+			},
+		}
+
+		dataInvoices.Status = it.to
+
+		err = ts.InvoicesRepo.UpdateStatus(ctx, &dataInvoices)
+		if err != nil {
+			log.Println("update invoice status err: ", err)
+			return err
+		}
+
+		err = ts.InvoicesRepo.CreateStatusHistory(ctx, &history)
+		if err != nil {
+			log.Println("create invoice status history err: ", err)
+			return err
+		}
+
+		res = contract.InvoiceStatusResponse{
+			InvoiceID: dataInvoices.InvoiceID,
+			Status:    dataInvoices.Status,
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return contract.InvoiceStatusResponse{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,254 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	errorss "github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_Transition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	type (
+		given struct {
+			action          string
+			invoice         entity.Invoices
+			getForUpdateErr error
+			updateStatusErr error
+			createLogErr    error
+		}
+
+		expected struct {
+			res contract.InvoiceStatusResponse
+			err error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	newInvoice := func(status string, amountPaid int64) entity.Invoices {
+		return entity.Invoices{
+			InvoicesData: entity.InvoicesData{
+				InvoiceID:  "0001",
+				Status:     status,
+				GrandTotal: decimal.NewFromInt(300),
+				AmountPaid: decimal.NewFromInt(amountPaid),
+			},
+		}
+	}
+
+	testCases := []testCase{
+		{
+			name: "error invoice id not found",
+			given: given{
+				action:          "issue",
+				getForUpdateErr: sql.ErrNoRows,
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceIdNotFound,
+			},
+		},
+		{
+			name: "error issue already issued invoice",
+			given: given{
+				action:  "issue",
+				invoice: newInvoice(entity.InvoiceStatusIssued, 0),
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceInvalidStatusTransition,
+			},
+		},
+		{
+			name: "error void paid invoice",
+			given: given{
+				action:  "void",
+				invoice: newInvoice(entity.InvoiceStatusPaid, 300),
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceInvalidStatusTransition,
+			},
+		},
+		{
+			name: "error void overdue invoice with payments",
+			given: given{
+				action:  "void",
+				invoice: newInvoice(entity.InvoiceStatusOverdue, 100),
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceHasPayments,
+			},
+		},
+		{
+			name: "error reopen invoice with credit notes",
+			given: given{
+				action: "reopen",
+				invoice: func() entity.Invoices {
+					invoice := newInvoice(entity.InvoiceStatusIssued, 0)
+					invoice.AmountCredited = decimal.NewFromInt(100)
+					return invoice
+				}(),
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceHasCreditNotes,
+			},
+		},
+		{
+			name: "error reopen draft invoice",
+			given: given{
+				action:  "reopen",
+				invoice: newInvoice(entity.InvoiceStatusDraft, 0),
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceInvalidStatusTransition,
+			},
+		},
+		{
+			name: "error update status",
+			given: given{
+				action:          "issue",
+				invoice:         newInvoice(entity.InvoiceStatusDraft, 0),
+				updateStatusErr: errors.New("error internal server"),
+			},
+			expected: expected{
+				err: errors.New("error internal server"),
+			},
+		},
+		{
+			name: "error create status history",
+			given: given{
+				action:       "issue",
+				invoice:      newInvoice(entity.InvoiceStatusDraft, 0),
+				createLogErr: errors.New("error internal server"),
+			},
+			expected: expected{
+				err: errors.New("error internal server"),
+			},
+		},
+		{
+			name: "success issue",
+			given: given{
+				action:  "issue",
+				invoice: newInvoice(entity.InvoiceStatusDraft, 0),
+			},
+			expected: expected{
+				res: contract.InvoiceStatusResponse{
+					InvoiceID: "0001",
+					Status:    entity.InvoiceStatusIssued,
+				},
+			},
+		},
+		{
+			name: "success void",
+			given: given{
+				action:  "void",
+				invoice: newInvoice(entity.InvoiceStatusIssued, 0),
+			},
+			expected: expected{
+				res: contract.InvoiceStatusResponse{
+					InvoiceID: "0001",
+					Status:    entity.InvoiceStatusVoid,
+				},
+			},
+		},
+		{
+			name: "success reopen",
+			given: given{
+				action:  "reopen",
+				invoice: newInvoice(entity.InvoiceStatusVoid, 0),
+			},
+			expected: expected{
+				res: contract.InvoiceStatusResponse{
+					InvoiceID: "0001",
+					Status:    entity.InvoiceStatusDraft,
+				},
+			},
+		},
+	}
+
+	request := contract.InvoiceTransitionRequest{
+		Reason: "test-reason",
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
+
+			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+			mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
+			mockAtomicSessionCtx := atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession)
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
+
+			transitions := map[string]func(context.Context, contract.InvoiceTransitionRequest, string) (contract.InvoiceStatusResponse, error){
+				"issue":  Invoices.Issue,
+				"void":   Invoices.Void,
+				"reopen": Invoices.Reopen,
+			}
+			targets := map[string]string{
+				"issue":  entity.InvoiceStatusIssued,
+				"void":   entity.InvoiceStatusVoid,
+				"reopen": entity.InvoiceStatusDraft,
+			}
+
+			func() {
+				mockAsession.EXPECT().BeginSession(gomock.Any()).
+					Return(mockAtomicSessionCtx, nil).
+					Times(1)
+
+				mockInvoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(testCase.given.invoice, testCase.given.getForUpdateErr).
+					Times(1)
+
+				updatedInvoice := testCase.given.invoice
+				updatedInvoice.Status = targets[testCase.given.action]
+
+				mockInvoicesRepo.EXPECT().UpdateStatus(gomock.Any(), &updatedInvoice).
+					Return(testCase.given.updateStatusErr).
+					Times(1)
+
+				mockInvoicesRepo.EXPECT().CreateStatusHistory(gomock.Any(), &entity.InvoiceStatusHistory{
+					InvoiceStatusHistoryData: entity.InvoiceStatusHistoryData{
+						InvoiceID:  "0001",
+						FromStatus: testCase.given.invoice.Status,
+						ToStatus:   targets[testCase.given.action],
+						Reason:     "test-reason",
+					},
+				}).
+					Return(testCase.given.createLogErr).
+					Times(1)
+
+				mockAtomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+
+				mockAtomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			}()
+
+			got, actualErr := transitions[testCase.given.action](context.Background(), request, "0001")
+			assert.Equal(t, testCase.expected.res, got)
+			assert.Equal(t, testCase.expected.err, actualErr)
+		})
+	}
+}
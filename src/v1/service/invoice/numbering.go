@@ -0,0 +1,40 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/numbering"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// allocateInvoiceID takes the next number of the series. It has to run inside the create
+// transaction: the counter row stays locked until commit and a rollback hands the number back,
+// so concurrent creates never collide and no number is skipped.
+func (ts *Invoiceservice) allocateInvoiceID(ctx context.Context, seriesCode string, issueDate time.Time) (string, error) {
+	if seriesCode == "" {
+		seriesCode = entity.DefaultInvoiceSeries
+	}
+
+	series, err := ts.SeriesRepo.Get(ctx, seriesCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return "", errorss.ErrInvoiceSeriesNotFound
+		}
+		log.Println(err)
+		return "", err
+	}
+
+	seq, err := ts.SeriesRepo.NextValue(ctx, series.Code, numbering.PeriodKey(series.ResetPeriod, issueDate))
+	if err != nil {
+		log.Println("next value err: ", err)
+		return "", err
+	}
+
+	return numbering.Format(series.Template, issueDate, seq)
+}
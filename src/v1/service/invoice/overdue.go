@@ -0,0 +1,290 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+)
+
+const (
+	LateFeeNone       = "none"
+	LateFeeFlat       = "flat"
+	LateFeePercentage = "percentage"
+
+	overdueReason = "past due date"
+	lateFeeName   = "Late fee "
+)
+
+// LateFeePolicy is how late fees are charged on overdue invoices.
+type LateFeePolicy struct {
+	Type string
+	// Amount is the fee of one charge, a percent of the outstanding balance for LateFeePercentage
+	Amount decimal.Decimal
+	// GraceDays is how many days past the due date go by before the first charge
+	GraceDays int
+	// IntervalDays charges the fee again every IntervalDays, zero charges it once
+	IntervalDays int
+	// MaxAmount caps the late fees of one invoice, zero is no cap
+	MaxAmount decimal.Decimal
+}
+
+func (p LateFeePolicy) enabled() bool {
+	return (p.Type == LateFeeFlat || p.Type == LateFeePercentage) && p.Amount.IsPositive()
+}
+
+// charges returns how many fees an invoice daysOverdue days past its due date owes in total.
+func (p LateFeePolicy) charges(daysOverdue int) int {
+	days := daysOverdue - p.GraceDays
+	if days <= 0 {
+		return 0
+	}
+
+	if p.IntervalDays <= 0 {
+		return 1
+	}
+
+	return 1 + (days-1)/p.IntervalDays
+}
+
+// chargeDate returns the day the nth charge, counted from zero, became due.
+func (p LateFeePolicy) chargeDate(dueDate time.Time, n int) time.Time {
+	return dueDate.AddDate(0, 0, p.GraceDays+1+n*p.IntervalDays)
+}
+
+// startOfDay returns midnight UTC of the day t is in.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysPastDue returns how many whole days the day of now is after the due date, zero until then.
+func daysPastDue(dueDate, now time.Time) int {
+	days := int(startOfDay(now.UTC()).Sub(startOfDay(dueDate)).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// daysOverdue is the days_overdue of the responses, only an overdue invoice is counted.
+func (ts *Invoiceservice) daysOverdue(data entity.InvoicesData) int {
+	if data.Status != entity.InvoiceStatusOverdue {
+		return 0
+	}
+	return daysPastDue(data.DueDate, ts.Now())
+}
+
+// StartOverdueCheck runs MarkOverdue every interval until ctx is done.
+func (ts *Invoiceservice) StartOverdueCheck(ctx context.Context, interval time.Duration, policy LateFeePolicy) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if updated, err := ts.MarkOverdue(ctx, time.Now(), policy); err != nil {
+			log.Println("mark overdue invoices err: ", err)
+		} else if updated > 0 {
+			log.Printf("updated %d overdue invoices", updated)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// MarkOverdue marks the unpaid issued invoices whose due date passed before the day of now as
+// overdue and charges the late fees they owe under policy. It returns how many invoices it
+// changed. Every invoice is changed under its row lock and only by what is still missing, so
+// running it again or on several instances at once changes nothing twice.
+func (ts *Invoiceservice) MarkOverdue(ctx context.Context, now time.Time, policy LateFeePolicy) (int, error) {
+	var updated int
+
+	dataInvoices, err := ts.InvoicesRepo.GetPastDue(ctx, startOfDay(now.UTC()), policy.enabled())
+	if err != nil {
+		log.Println("get past due invoices err: ", err)
+		return updated, err
+	}
+
+	for _, v := range dataInvoices {
+		ok, err := ts.markOverdue(ctx, v.InvoiceID, now, policy)
+		if err != nil {
+			log.Printf("mark invoice %s overdue err: %v", v.InvoiceID, err)
+			continue
+		}
+
+		if ok {
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// markOverdue marks one invoice overdue and adds its missing late fees. It returns false when
+// there was nothing to change, the invoice was settled, deleted or already up to date.
+func (ts *Invoiceservice) markOverdue(ctx context.Context, id string, now time.Time, policy LateFeePolicy) (bool, error) {
+	var updated bool
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			log.Println("get invoice err: ", err)
+			return err
+		}
+
+		switch dataInvoices.Status {
+		case entity.InvoiceStatusIssued, entity.InvoiceStatusPartiallyPaid, entity.InvoiceStatusOverdue:
+		default:
+			return nil
+		}
+
+		days := daysPastDue(dataInvoices.DueDate, now)
+		if days == 0 {
+			return nil
+		}
+
+		var fees []*entity.Item
+		if policy.enabled() {
+			// the fees already charged are read under the lock too, a cached list could miss one
+			dataItems, err := ts.ItemRepo.GetByInvoiceIDForUpdate(ctx, dataInvoices.InvoiceID)
+			if err != nil {
+				log.Println("get items err: ", err)
+				return err
+			}
+
+			fees, err = ts.lateFees(policy, dataInvoices.InvoicesData, dataItems, days)
+			if err != nil {
+				log.Println("late fees err: ", err)
+				return err
+			}
+		}
+
+		fromStatus := dataInvoices.Status
+		if fromStatus == entity.InvoiceStatusOverdue && len(fees) == 0 {
+			return nil
+		}
+
+		dataInvoices.Status = entity.InvoiceStatusOverdue
+
+		if len(fees) > 0 {
+			err = ts.ItemRepo.Create(ctx, fees)
+			if err != nil {
+				log.Println("create late fee items err: ", err)
+				return err
+			}
+
+			for _, v := range fees {
+				dataInvoices.SubTotal = dataInvoices.SubTotal.Add(v.Amount)
+			}
+			dataInvoices.TotalItems += len(fees)
+			dataInvoices.Tax = dataInvoices.SubTotal.MulPercent(dataInvoices.TaxRate, moneyPlaces, decimal.RoundHalfUp)
+			dataInvoices.GrandTotal = dataInvoices.SubTotal.Add(dataInvoices.Tax)
+
+			err = ts.InvoicesRepo.Update(ctx, &dataInvoices)
+			if err != nil {
+				log.Println("update invoice err: ", err)
+				return err
+			}
+		} else {
+			err = ts.InvoicesRepo.UpdateStatus(ctx, &dataInvoices)
+			if err != nil {
+				log.Println("update invoice status err: ", err)
+				return err
+			}
+		}
+
+		if fromStatus != entity.InvoiceStatusOverdue {
+			err = ts.InvoicesRepo.CreateStatusHistory(ctx, &entity.InvoiceStatusHistory{
+				InvoiceStatusHistoryData: entity.InvoiceStatusHistoryData{
+					InvoiceID:  dataInvoices.InvoiceID,
+					FromStatus: fromStatus,
+					ToStatus:   entity.InvoiceStatusOverdue,
+					Reason:     overdueReason,
+				},
+			})
+			if err != nil {
+				log.Println("create invoice status history err: ", err)
+				return err
+			}
+		}
+
+		updated = true
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return false, err
+	}
+
+	return updated, nil
+}
+
+// lateFees returns the late fee items an invoice daysOverdue days past due is missing. A
+// percentage is taken of what is still owed of the invoice itself, earlier fees are not
+// charged fees on. The charges stop at the cap of the policy. A fee too large for a Decimal is
+// an error.
+func (ts *Invoiceservice) lateFees(policy LateFeePolicy, dataInvoices entity.InvoicesData, dataItems []*entity.Item, daysOverdue int) ([]*entity.Item, error) {
+	var fees []*entity.Item
+	var charged, subTotal decimal.Decimal
+	var count int
+
+	for _, v := range dataItems {
+		if v.Type == entity.ItemTypeLateFee {
+			charged = charged.Add(v.Amount)
+			count++
+			continue
+		}
+		subTotal = subTotal.Add(v.Amount)
+	}
+
+	amount := policy.Amount
+	if policy.Type == LateFeePercentage {
+		grandTotal := subTotal.Add(subTotal.MulPercent(dataInvoices.TaxRate, moneyPlaces, decimal.RoundHalfUp))
+		owed := outstandingBalance(grandTotal, dataInvoices.AmountCredited, dataInvoices.AmountPaid)
+		var err error
+		amount, err = owed.MulPercentChecked(policy.Amount, moneyPlaces, decimal.RoundHalfUp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for n := count; n < policy.charges(daysOverdue); n++ {
+		fee := amount
+		if policy.MaxAmount.IsPositive() {
+			if left := policy.MaxAmount.Sub(charged); fee.GreaterThan(left) {
+				fee = left
+			}
+		}
+
+		if !fee.IsPositive() {
+			break
+		}
+		charged = charged.Add(fee)
+
+		fees = append(fees, &entity.Item{
+			ItemData: entity.ItemData{
+				InvoiceID: dataInvoices.InvoiceID,
+				ItemID:    ts.UUIDGen.New(),
+				Name:      lateFeeName + policy.chargeDate(dataInvoices.DueDate, n).Format("02-01-2006"),
+				Type:      entity.ItemTypeLateFee,
+				Quantity:  decimal.NewFromInt(1),
+				UnitPrice: fee,
+				Amount:    fee,
+			},
+		})
+	}
+
+	return fees, nil
+}
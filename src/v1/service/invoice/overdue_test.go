@@ -0,0 +1,357 @@
+package Invoices
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/go-playground/assert"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_LateFeeCharges(t *testing.T) {
+	dueDate := time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		now          time.Time
+		graceDays    int
+		intervalDays int
+		days         int
+		charges      int
+	}{
+		{
+			name: "on the due date",
+			now:  time.Date(2024, time.March, 14, 23, 59, 0, 0, time.UTC),
+		},
+		{
+			name:    "day after the due date",
+			now:     time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC),
+			days:    1,
+			charges: 1,
+		},
+		{
+			name:      "within the grace period",
+			now:       time.Date(2024, time.March, 19, 0, 0, 0, 0, time.UTC),
+			graceDays: 5,
+			days:      5,
+		},
+		{
+			name:      "once after the grace period",
+			now:       time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC),
+			graceDays: 5,
+			days:      47,
+			charges:   1,
+		},
+		{
+			name:         "every interval after the grace period",
+			now:          time.Date(2024, time.April, 3, 0, 0, 0, 0, time.UTC),
+			graceDays:    5,
+			intervalDays: 7,
+			days:         20,
+			charges:      3,
+		},
+		{
+			name:         "other timezone",
+			now:          time.Date(2024, time.March, 15, 6, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+			intervalDays: 7,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policy := LateFeePolicy{GraceDays: testCase.graceDays, IntervalDays: testCase.intervalDays}
+
+			days := daysPastDue(dueDate, testCase.now)
+
+			assert.Equal(t, testCase.days, days)
+			assert.Equal(t, testCase.charges, policy.charges(days))
+		})
+	}
+}
+
+func TestInvoiceService_MarkOverdue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	type mocks struct {
+		invoicesRepo  *mock_Invoices.MockInvoicesRepository
+		itemRepo      *mock_Invoices.MockItemRepository
+		asession      *mock_atomic.MockAtomicSessionProvider
+		atomicSession *mock_atomic.MockAtomicSession
+	}
+
+	now := time.Date(2024, time.April, 3, 10, 0, 0, 0, time.UTC)
+	today := time.Date(2024, time.April, 3, 0, 0, 0, 0, time.UTC)
+
+	newInvoice := func(status string) entity.Invoices {
+		return entity.Invoices{
+			InvoicesData: entity.InvoicesData{
+				InvoiceID:  "0001",
+				TotalItems: 1,
+				DueDate:    time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC),
+				Status:     status,
+				SubTotal:   decimal.NewFromInt(100),
+				TaxRate:    decimal.NewFromInt(11),
+				Tax:        decimal.NewFromInt(11),
+				GrandTotal: decimal.NewFromInt(111),
+				AmountPaid: decimal.NewFromInt(11),
+				Version:    3,
+			},
+		}
+	}
+
+	newItem := func(name, itemType string, amount decimal.Decimal) *entity.Item {
+		return &entity.Item{
+			ItemData: entity.ItemData{
+				InvoiceID: "0001",
+				ItemID:    FixedUUIDGenerator{}.New(),
+				Name:      name,
+				Type:      itemType,
+				Quantity:  decimal.NewFromInt(1),
+				UnitPrice: amount,
+				Amount:    amount,
+			},
+		}
+	}
+
+	hosting := newItem("hosting", "service", decimal.NewFromInt(100))
+
+	// lock expects the invoice to be read under its row lock
+	lock := func(m mocks, invoice entity.Invoices) {
+		m.invoicesRepo.EXPECT().GetPastDue(gomock.Any(), today, gomock.Any()).
+			Return([]*entity.Invoices{&invoice}, nil).
+			Times(1)
+
+		m.asession.EXPECT().BeginSession(gomock.Any()).
+			Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+			Times(1)
+
+		m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+			Return(invoice, nil).
+			Times(1)
+	}
+
+	history := &entity.InvoiceStatusHistory{
+		InvoiceStatusHistoryData: entity.InvoiceStatusHistoryData{
+			InvoiceID:  "0001",
+			FromStatus: entity.InvoiceStatusPartiallyPaid,
+			ToStatus:   entity.InvoiceStatusOverdue,
+			Reason:     "past due date",
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		policy   LateFeePolicy
+		setup    func(m mocks)
+		expected int
+		err      error
+	}{
+		{
+			name: "err get past due",
+			setup: func(m mocks) {
+				m.invoicesRepo.EXPECT().GetPastDue(gomock.Any(), today, false).
+					Return(nil, errors.New("error internal server")).
+					Times(1)
+			},
+			err: errors.New("error internal server"),
+		},
+		{
+			name: "invoice paid in the meantime",
+			setup: func(m mocks) {
+				lock(m, newInvoice(entity.InvoiceStatusPaid))
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name: "err update status",
+			setup: func(m mocks) {
+				lock(m, newInvoice(entity.InvoiceStatusPartiallyPaid))
+
+				m.invoicesRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).
+					Return(errors.New("error internal server")).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name: "success without late fees",
+			setup: func(m mocks) {
+				lock(m, newInvoice(entity.InvoiceStatusPartiallyPaid))
+
+				updatedInvoice := newInvoice(entity.InvoiceStatusOverdue)
+				m.invoicesRepo.EXPECT().UpdateStatus(gomock.Any(), &updatedInvoice).
+					Return(nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().CreateStatusHistory(gomock.Any(), history).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: 1,
+		},
+		{
+			name: "success percentage late fee",
+			policy: LateFeePolicy{
+				Type:   LateFeePercentage,
+				Amount: decimal.NewFromInt(2),
+			},
+			setup: func(m mocks) {
+				lock(m, newInvoice(entity.InvoiceStatusPartiallyPaid))
+
+				m.itemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), "0001").
+					Return([]*entity.Item{hosting}, nil).
+					Times(1)
+
+				// 2% of the 100 still owed
+				fee := newItem("Late fee 15-03-2024", entity.ItemTypeLateFee, decimal.NewFromInt(2))
+				m.itemRepo.EXPECT().Create(gomock.Any(), []*entity.Item{fee}).
+					Return(nil).
+					Times(1)
+
+				updatedInvoice := newInvoice(entity.InvoiceStatusOverdue)
+				updatedInvoice.TotalItems = 2
+				updatedInvoice.SubTotal = decimal.NewFromInt(102)
+				updatedInvoice.Tax = decimal.RequireFromString("11.22")
+				updatedInvoice.GrandTotal = decimal.RequireFromString("113.22")
+				m.invoicesRepo.EXPECT().Update(gomock.Any(), &updatedInvoice).
+					Return(nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().CreateStatusHistory(gomock.Any(), history).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: 1,
+		},
+		{
+			name: "success flat late fees up to the cap",
+			policy: LateFeePolicy{
+				Type:         LateFeeFlat,
+				Amount:       decimal.NewFromInt(10),
+				GraceDays:    5,
+				IntervalDays: 7,
+				MaxAmount:    decimal.NewFromInt(25),
+			},
+			setup: func(m mocks) {
+				invoice := newInvoice(entity.InvoiceStatusOverdue)
+				invoice.TotalItems = 2
+				invoice.SubTotal = decimal.NewFromInt(110)
+				invoice.Tax = decimal.RequireFromString("12.1")
+				invoice.GrandTotal = decimal.RequireFromString("122.1")
+				lock(m, invoice)
+
+				m.itemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), "0001").
+					Return([]*entity.Item{hosting, newItem("Late fee 20-03-2024", entity.ItemTypeLateFee, decimal.NewFromInt(10))}, nil).
+					Times(1)
+
+				// 20 days past due owes 3 charges, the last one is cut to the 5 left under the cap
+				m.itemRepo.EXPECT().Create(gomock.Any(), []*entity.Item{
+					newItem("Late fee 27-03-2024", entity.ItemTypeLateFee, decimal.NewFromInt(10)),
+					newItem("Late fee 03-04-2024", entity.ItemTypeLateFee, decimal.NewFromInt(5)),
+				}).
+					Return(nil).
+					Times(1)
+
+				updatedInvoice := invoice
+				updatedInvoice.TotalItems = 4
+				updatedInvoice.SubTotal = decimal.NewFromInt(125)
+				updatedInvoice.Tax = decimal.RequireFromString("13.75")
+				updatedInvoice.GrandTotal = decimal.RequireFromString("138.75")
+				m.invoicesRepo.EXPECT().Update(gomock.Any(), &updatedInvoice).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: 1,
+		},
+		{
+			name: "overdue invoice at the cap",
+			policy: LateFeePolicy{
+				Type:         LateFeeFlat,
+				Amount:       decimal.NewFromInt(10),
+				IntervalDays: 1,
+				MaxAmount:    decimal.NewFromInt(10),
+			},
+			setup: func(m mocks) {
+				lock(m, newInvoice(entity.InvoiceStatusOverdue))
+
+				m.itemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), "0001").
+					Return([]*entity.Item{hosting, newItem("Late fee 15-03-2024", entity.ItemTypeLateFee, decimal.NewFromInt(10))}, nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name: "fee missing from cached items",
+			policy: LateFeePolicy{
+				Type:   LateFeeFlat,
+				Amount: decimal.NewFromInt(10),
+			},
+			setup: func(m mocks) {
+				invoice := newInvoice(entity.InvoiceStatusOverdue)
+				invoice.TotalItems = 2
+				lock(m, invoice)
+
+				// a cached item list from before the fee was charged is not used
+				m.itemRepo.EXPECT().GetByInvoiceID(gomock.Any(), "0001").
+					Return([]*entity.Item{hosting}, nil).
+					AnyTimes()
+
+				m.itemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), "0001").
+					Return([]*entity.Item{hosting, newItem("Late fee 15-03-2024", entity.ItemTypeLateFee, decimal.NewFromInt(10))}, nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := mocks{
+				invoicesRepo:  mock_Invoices.NewMockInvoicesRepository(mockCtrl),
+				itemRepo:      mock_Invoices.NewMockItemRepository(mockCtrl),
+				asession:      mock_atomic.NewMockAtomicSessionProvider(mockCtrl),
+				atomicSession: mock_atomic.NewMockAtomicSession(mockCtrl),
+			}
+
+			testCase.setup(m)
+
+			Invoices := InitInvoiceservice(m.invoicesRepo, nil, m.itemRepo, nil, nil, nil, nil, nil, m.asession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.MarkOverdue(context.Background(), now, testCase.policy)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_DaysOverdue(t *testing.T) {
+	Invoices := InitInvoiceservice(nil, nil, nil, nil, nil, nil, nil, nil, nil, FixedUUIDGenerator{})
+	Invoices.Now = func() time.Time {
+		return time.Date(2024, time.April, 3, 10, 0, 0, 0, time.UTC)
+	}
+
+	dueDate := time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 20, Invoices.daysOverdue(entity.InvoicesData{Status: entity.InvoiceStatusOverdue, DueDate: dueDate}))
+	// a partially paid invoice the overdue check did not reach yet is not counted
+	assert.Equal(t, 0, Invoices.daysOverdue(entity.InvoicesData{Status: entity.InvoiceStatusPartiallyPaid, DueDate: dueDate}))
+}
@@ -0,0 +1,39 @@
+package Invoices
+
+import (
+	"context"
+	"log"
+
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+)
+
+// Patch applies an RFC 7386 merge patch to a draft invoice, see contract.ApplyInvoicePatch.
+// version is checked like in Update.
+func (ts *Invoiceservice) Patch(ctx context.Context, patch []byte, id string, version int64) (contract.InvcResponse, error) {
+	var res contract.InvcResponse
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, dataItems, err := ts.getEditableInvoice(ctx, id, version)
+		if err != nil {
+			return err
+		}
+
+		request, err := contract.ApplyInvoicePatch(dataInvoices.InvoicesData, dataItems, patch)
+		if err != nil {
+			log.Println("apply patch err: ", err)
+			return err
+		}
+
+		res, err = ts.replaceInvoice(ctx, dataInvoices, dataItems, request)
+		return err
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return contract.InvcResponse{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,273 @@
+package Invoices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_Patch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	customerID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	logoID := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	hostingID := uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+
+	storedInvoice := entity.Invoices{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID:  "0001",
+			IssueDate:  time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC),
+			Subject:    "design",
+			TotalItems: 2,
+			CustomerID: customerID,
+			DueDate:    time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC),
+			Status:     entity.InvoiceStatusDraft,
+			SubTotal:   decimal.NewFromInt(200),
+			TaxRate:    decimal.NewFromInt(10),
+			Tax:        decimal.NewFromInt(20),
+			GrandTotal: decimal.NewFromInt(220),
+			Version:    3,
+		},
+	}
+
+	storedItems := func() []*entity.Item {
+		return []*entity.Item{
+			{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: logoID, Name: "logo", Type: "design", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(100)}},
+			{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: hostingID, Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(2), UnitPrice: decimal.NewFromInt(50), Amount: decimal.NewFromInt(100)}},
+		}
+	}
+
+	patchedInvoice := storedInvoice
+	patchedInvoice.DueDate = time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+	patchedInvoice.SubTotal = decimal.NewFromInt(220)
+	patchedInvoice.Tax = decimal.NewFromInt(22)
+	patchedInvoice.GrandTotal = decimal.NewFromInt(242)
+
+	newCustomerID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	rebilledInvoice := storedInvoice
+	rebilledInvoice.CustomerID = newCustomerID
+
+	type mocks struct {
+		invoicesRepo  *mock_Invoices.MockInvoicesRepository
+		customerRepo  *mock_Invoices.MockCustomerRepository
+		itemRepo      *mock_Invoices.MockItemRepository
+		asession      *mock_atomic.MockAtomicSessionProvider
+		atomicSession *mock_atomic.MockAtomicSession
+	}
+
+	expectLocked := func(m mocks) {
+		m.asession.EXPECT().BeginSession(gomock.Any()).
+			Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+			Times(1)
+
+		m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+			Return(storedInvoice, nil).
+			Times(1)
+	}
+
+	expectStored := func(m mocks) {
+		expectLocked(m)
+
+		m.itemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), "0001").
+			Return(storedItems(), nil).
+			Times(1)
+	}
+
+	expectRejected := func(m mocks) {
+		expectStored(m)
+
+		m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+	}
+
+	testCases := []struct {
+		name     string
+		patch    string
+		version  int64
+		setup    func(m mocks)
+		expected contract.InvcResponse
+		err      error
+	}{
+		{
+			name:    "error version mismatch",
+			patch:   `{"subject":"logo"}`,
+			version: 2,
+			setup: func(m mocks) {
+				expectLocked(m)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrInvoiceVersionMismatch,
+		},
+		{
+			name:    "error required field removed",
+			patch:   `{"subject":null}`,
+			version: 3,
+			setup:   expectRejected,
+			err:     errorss.ErrInvoicePatchInvalid,
+		},
+		{
+			name:    "error unknown field",
+			patch:   `{"item_request":[]}`,
+			version: 3,
+			setup:   expectRejected,
+			err:     errorss.ErrInvoicePatchInvalid,
+		},
+		{
+			name:    "error invalid item",
+			patch:   `{"items":{"new":{"name":"domain"}}}`,
+			version: 3,
+			setup:   expectRejected,
+			err:     errorss.ErrInvoicePatchInvalid,
+		},
+		{
+			name:    "error customer id and customer request",
+			patch:   `{"customer_id":"22222222-2222-2222-2222-222222222222","customer_request":{"customer_name":"PT Baru","address":"Jakarta"}}`,
+			version: 3,
+			setup:   expectRejected,
+			err:     errorss.ErrInvoicePatchInvalid,
+		},
+		{
+			name:    "success customer request replaces stored customer",
+			patch:   `{"customer_request":{"customer_name":"pt baru","address":"Jakarta"}}`,
+			version: 3,
+			setup: func(m mocks) {
+				expectStored(m)
+
+				m.customerRepo.EXPECT().FindOrCreate(gomock.Any(), &entity.Customer{
+					CustomerData: entity.CustomerData{CustomerID: FixedUUIDGenerator{}.New(), Name: "pt baru", Address: "Jakarta"},
+				}).
+					Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: newCustomerID}}, nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().Delete(gomock.Any(), gomock.Nil()).
+					Return(nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().Update(gomock.Any(), &rebilledInvoice).
+					Return(nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().Update(gomock.Any(), storedItems()).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: contract.InvcResponse{InvoiceID: "0001", Version: 4},
+		},
+		{
+			name: "success",
+			patch: `{
+				"due_date": "30-04-2024",
+				"items": {
+					"aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa": {"quantity": 2},
+					"bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb": null,
+					"new": {"name": "domain", "type": "service", "quantity": 1, "unit_price": 20}
+				}
+			}`,
+			version: 3,
+			setup: func(m mocks) {
+				expectStored(m)
+
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: customerID}}, nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().Delete(gomock.Any(), []uuid.UUID{hostingID}).
+					Return(nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().Update(gomock.Any(), &patchedInvoice).
+					Return(nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().Update(gomock.Any(), []*entity.Item{
+					{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: logoID, Name: "logo", Type: "design", Quantity: decimal.NewFromInt(2), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(200)}},
+				}).
+					Return(nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().Create(gomock.Any(), []*entity.Item{
+					{ItemData: entity.ItemData{InvoiceID: "0001", ItemID: FixedUUIDGenerator{}.New(), Name: "domain", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(20), Amount: decimal.NewFromInt(20)}},
+				}).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: contract.InvcResponse{InvoiceID: "0001", Version: 4},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := mocks{
+				invoicesRepo:  mock_Invoices.NewMockInvoicesRepository(mockCtrl),
+				customerRepo:  mock_Invoices.NewMockCustomerRepository(mockCtrl),
+				itemRepo:      mock_Invoices.NewMockItemRepository(mockCtrl),
+				asession:      mock_atomic.NewMockAtomicSessionProvider(mockCtrl),
+				atomicSession: mock_atomic.NewMockAtomicSession(mockCtrl),
+			}
+
+			testCase.setup(m)
+
+			Invoices := InitInvoiceservice(m.invoicesRepo, m.customerRepo, m.itemRepo, nil, nil, nil, nil, nil, m.asession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.Patch(context.Background(), []byte(testCase.patch), "0001", testCase.version)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_UpdateUnknownItem(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+	mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+	mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+	mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
+
+	mockAsession.EXPECT().BeginSession(gomock.Any()).
+		Return(atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession), nil).
+		Times(1)
+
+	mockInvoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+		Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001", Status: entity.InvoiceStatusDraft, Version: 1}}, nil).
+		Times(1)
+
+	mockItemRepo.EXPECT().GetByInvoiceIDForUpdate(gomock.Any(), "0001").
+		Return([]*entity.Item{}, nil).
+		Times(1)
+
+	mockAtomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+
+	request := contract.InvoiceRequest{
+		Subject:   "design",
+		IssueDate: "07-03-2024",
+		DueDate:   "06-04-2024",
+		ItemRequest: []contract.ItemRequest{
+			{ItemID: uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"), Name: "logo", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	Invoices := InitInvoiceservice(mockInvoicesRepo, nil, mockItemRepo, nil, nil, nil, nil, nil, mockAsession, FixedUUIDGenerator{})
+	got, actualErr := Invoices.Update(context.Background(), request, "0001", 1)
+
+	assert.Equal(t, contract.InvcResponse{}, got)
+	assert.Equal(t, errorss.ErrInvoiceItemNotFound, actualErr)
+}
@@ -0,0 +1,148 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/mariomac/gostream/stream"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// deriveInvoiceStatus returns the status of an issued invoice after its paid or credited amount
+// changed. Credit notes lower what is owed, an invoice credited in full without any payment is
+// credited. An overdue invoice stays overdue until it is settled in full.
+func deriveInvoiceStatus(currentStatus string, grandTotal, amountCredited, amountPaid decimal.Decimal) string {
+	owed := grandTotal.Sub(amountCredited)
+
+	switch cmp := amountPaid.Cmp(owed); {
+	case amountCredited.IsPositive() && owed.IsZero() && !amountPaid.IsPositive():
+		return entity.InvoiceStatusCredited
+	case cmp < 0 && currentStatus == entity.InvoiceStatusOverdue:
+		return entity.InvoiceStatusOverdue
+	case !amountPaid.IsPositive():
+		return entity.InvoiceStatusIssued
+	case cmp < 0:
+		return entity.InvoiceStatusPartiallyPaid
+	case cmp == 0:
+		return entity.InvoiceStatusPaid
+	default:
+		return entity.InvoiceStatusOverpaid
+	}
+}
+
+// outstandingBalance is what the customer still owes, negative when a refund is due.
+func outstandingBalance(grandTotal, amountCredited, amountPaid decimal.Decimal) decimal.Decimal {
+	return grandTotal.Sub(amountCredited).Sub(amountPaid)
+}
+
+func buildPaymentResponses(payments []*entity.Payment) []contract.PaymentResponse {
+	return stream.Map(stream.OfSlice(payments), func(p *entity.Payment) contract.PaymentResponse {
+		return contract.PaymentResponse{
+			PaymentID:   p.PaymentID,
+			Amount:      p.Amount,
+			PaymentDate: p.PaymentDate.Format("02-01-2006"),
+			Method:      p.Method,
+			Reference:   p.Reference,
+		}
+	}).ToSlice()
+}
+
+func (ts *Invoiceservice) CreatePayment(ctx context.Context, request contract.PaymentRequest, id string) (contract.InvoicePaymentResponse, error) {
+	var res contract.InvoicePaymentResponse
+
+	newPaymentDate, _ := time.Parse("02-01-2006", request.PaymentDate)
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		// lock the invoice row so concurrent payments are applied one after another
+		dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrInvoiceIdNotFound
+			}
+			log.Println("get invoice err: ", err)
+			return err
+		}
+
+		if !isPayable(dataInvoices.Status) {
+			log.Println("create payment err: ", errorss.ErrInvoiceNotPayable)
+			return errorss.ErrInvoiceNotPayable
+		}
+
+		insertDataPayment := entity.Payment{
+			PaymentData: entity.PaymentData{
+				PaymentID:   ts.UUIDGen.New(),
+				InvoiceID:   dataInvoices.InvoiceID,
+				Amount:      request.Amount,
+				PaymentDate: newPaymentDate,
+				Method:      request.Method,
+				Reference:   request.Reference,
+			},
+		}
+
+		err = ts.PaymentRepo.Create(ctx, &insertDataPayment)
+		if err != nil {
+			log.Println("create payment err: ", err)
+			return err
+		}
+
+		amountPaid, err := ts.PaymentRepo.GetTotalByInvoiceID(ctx, dataInvoices.InvoiceID)
+		if err != nil {
+			log.Println("get total payment err: ", err)
+			return err
+		}
+
+		dataInvoices.AmountPaid = amountPaid
+		dataInvoices.Status = deriveInvoiceStatus(dataInvoices.Status, dataInvoices.GrandTotal, dataInvoices.AmountCredited, amountPaid)
+
+		err = ts.InvoicesRepo.UpdateStatus(ctx, &dataInvoices)
+		if err != nil {
+			log.Println("update invoice status err: ", err)
+			return err
+		}
+
+		res = contract.InvoicePaymentResponse{
+			InvoiceID:          dataInvoices.InvoiceID,
+			PaymentID:          insertDataPayment.PaymentID,
+			Status:             dataInvoices.Status,
+			AmountPaid:         dataInvoices.AmountPaid,
+			OutstandingBalance: outstandingBalance(dataInvoices.GrandTotal, dataInvoices.AmountCredited, dataInvoices.AmountPaid),
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return contract.InvoicePaymentResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (ts *Invoiceservice) GetPayments(ctx context.Context, id string) ([]contract.PaymentResponse, error) {
+	dataInvoices, err := ts.InvoicesRepo.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println(err)
+			return nil, errorss.ErrInvoiceIdNotFound
+		}
+		log.Println(err)
+		return nil, err
+	}
+
+	dataPayments, err := ts.PaymentRepo.GetByInvoiceID(ctx, dataInvoices.InvoiceID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return buildPaymentResponses(dataPayments), nil
+}
@@ -0,0 +1,385 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	errorss "github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+func TestInvoiceService_DeriveInvoiceStatus(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         string
+		grandTotal     decimal.Decimal
+		amountCredited decimal.Decimal
+		amountPaid     decimal.Decimal
+		expected       string
+	}{
+		{name: "no payment", status: entity.InvoiceStatusIssued, grandTotal: decimal.NewFromInt(300), amountPaid: decimal.NewFromInt(0), expected: entity.InvoiceStatusIssued},
+		{name: "partial payment", status: entity.InvoiceStatusIssued, grandTotal: decimal.NewFromInt(300), amountPaid: decimal.NewFromInt(100), expected: entity.InvoiceStatusPartiallyPaid},
+		{name: "full payment", status: entity.InvoiceStatusPartiallyPaid, grandTotal: decimal.NewFromInt(300), amountPaid: decimal.NewFromInt(300), expected: entity.InvoiceStatusPaid},
+		{name: "full payment summed from fractions", status: entity.InvoiceStatusIssued, grandTotal: decimal.RequireFromString("0.3"), amountPaid: decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2")), expected: entity.InvoiceStatusPaid},
+		{name: "overpayment", status: entity.InvoiceStatusPaid, grandTotal: decimal.NewFromInt(300), amountPaid: decimal.RequireFromString("300.01"), expected: entity.InvoiceStatusOverpaid},
+		{name: "partial payment on overdue invoice", status: entity.InvoiceStatusOverdue, grandTotal: decimal.NewFromInt(300), amountPaid: decimal.NewFromInt(100), expected: entity.InvoiceStatusOverdue},
+		{name: "full payment on overdue invoice", status: entity.InvoiceStatusOverdue, grandTotal: decimal.NewFromInt(300), amountPaid: decimal.NewFromInt(300), expected: entity.InvoiceStatusPaid},
+		{name: "partial credit", status: entity.InvoiceStatusIssued, grandTotal: decimal.NewFromInt(300), amountCredited: decimal.NewFromInt(100), expected: entity.InvoiceStatusIssued},
+		{name: "partial credit settles payment", status: entity.InvoiceStatusPartiallyPaid, grandTotal: decimal.NewFromInt(300), amountCredited: decimal.NewFromInt(100), amountPaid: decimal.NewFromInt(200), expected: entity.InvoiceStatusPaid},
+		{name: "full credit", status: entity.InvoiceStatusOverdue, grandTotal: decimal.NewFromInt(300), amountCredited: decimal.NewFromInt(300), expected: entity.InvoiceStatusCredited},
+		{name: "full credit after payment", status: entity.InvoiceStatusPaid, grandTotal: decimal.NewFromInt(300), amountCredited: decimal.NewFromInt(300), amountPaid: decimal.NewFromInt(300), expected: entity.InvoiceStatusOverpaid},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, deriveInvoiceStatus(testCase.status, testCase.grandTotal, testCase.amountCredited, testCase.amountPaid))
+		})
+	}
+}
+
+func TestInvoiceService_CreatePayment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	type (
+		getForUpdate struct {
+			invoice entity.Invoices
+			err     error
+		}
+
+		createPayment struct {
+			err error
+		}
+
+		getTotal struct {
+			total decimal.Decimal
+			err   error
+		}
+
+		updateStatus struct {
+			err error
+		}
+
+		given struct {
+			id            string
+			req           contract.PaymentRequest
+			getForUpdate  getForUpdate
+			createPayment createPayment
+			getTotal      getTotal
+			updateStatus  updateStatus
+		}
+
+		expected struct {
+			res contract.InvoicePaymentResponse
+			err error
+		}
+
+		testCase struct {
+			name     string
+			given    given
+			expected expected
+		}
+	)
+
+	mockID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	paymentDate := time.Date(2023, time.January, 23, 0, 0, 0, 0, time.UTC)
+
+	mockRequest := contract.PaymentRequest{
+		Amount:      decimal.NewFromInt(100),
+		PaymentDate: "23-01-2023",
+		Method:      "transfer",
+		Reference:   "test-reference",
+	}
+
+	mockInvoice := entity.Invoices{
+		InvoicesData: entity.InvoicesData{
+			InvoiceID:  "0001",
+			GrandTotal: decimal.NewFromInt(300),
+			Status:     entity.InvoiceStatusIssued,
+		},
+	}
+
+	mockPayment := entity.Payment{
+		PaymentData: entity.PaymentData{
+			PaymentID:   mockID,
+			InvoiceID:   "0001",
+			Amount:      decimal.NewFromInt(100),
+			PaymentDate: paymentDate,
+			Method:      "transfer",
+			Reference:   "test-reference",
+		},
+	}
+
+	testCases := []testCase{
+		{
+			name: "error invoice id not found",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					err: sql.ErrNoRows,
+				},
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceIdNotFound,
+			},
+		},
+		{
+			name: "error invoice not payable",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					invoice: entity.Invoices{
+						InvoicesData: entity.InvoicesData{
+							InvoiceID: "0001",
+							Status:    entity.InvoiceStatusDraft,
+						},
+					},
+				},
+			},
+			expected: expected{
+				err: errorss.ErrInvoiceNotPayable,
+			},
+		},
+		{
+			name: "error create payment",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					invoice: mockInvoice,
+				},
+				createPayment: createPayment{
+					err: errors.New("error internal server"),
+				},
+			},
+			expected: expected{
+				err: errors.New("error internal server"),
+			},
+		},
+		{
+			name: "error get total payment",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					invoice: mockInvoice,
+				},
+				getTotal: getTotal{
+					err: errors.New("error internal server"),
+				},
+			},
+			expected: expected{
+				err: errors.New("error internal server"),
+			},
+		},
+		{
+			name: "error update invoice status",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					invoice: mockInvoice,
+				},
+				getTotal: getTotal{
+					total: decimal.NewFromInt(100),
+				},
+				updateStatus: updateStatus{
+					err: errors.New("error internal server"),
+				},
+			},
+			expected: expected{
+				err: errors.New("error internal server"),
+			},
+		},
+		{
+			name: "success partially paid",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					invoice: mockInvoice,
+				},
+				getTotal: getTotal{
+					total: decimal.NewFromInt(100),
+				},
+			},
+			expected: expected{
+				res: contract.InvoicePaymentResponse{
+					InvoiceID:          "0001",
+					PaymentID:          mockID,
+					Status:             entity.InvoiceStatusPartiallyPaid,
+					AmountPaid:         decimal.NewFromInt(100),
+					OutstandingBalance: decimal.NewFromInt(200),
+				},
+			},
+		},
+		{
+			name: "success paid",
+			given: given{
+				id:  "0001",
+				req: mockRequest,
+				getForUpdate: getForUpdate{
+					invoice: mockInvoice,
+				},
+				getTotal: getTotal{
+					total: decimal.NewFromInt(300),
+				},
+			},
+			expected: expected{
+				res: contract.InvoicePaymentResponse{
+					InvoiceID:          "0001",
+					PaymentID:          mockID,
+					Status:             entity.InvoiceStatusPaid,
+					AmountPaid:         decimal.NewFromInt(300),
+					OutstandingBalance: decimal.NewFromInt(0),
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
+
+			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+			mockAtomicSession := mock_atomic.NewMockAtomicSession(mockCtrl)
+			mockAtomicSessionCtx := atomic.NewAtomicSessionContext(context.Background(), mockAtomicSession)
+
+			func() {
+				mockAsession.EXPECT().BeginSession(gomock.Any()).
+					Return(mockAtomicSessionCtx, nil).
+					Times(1)
+
+				mockInvoicesRepo.EXPECT().GetForUpdate(gomock.Any(), testCase.given.id).
+					Return(testCase.given.getForUpdate.invoice, testCase.given.getForUpdate.err).
+					Times(1)
+
+				mockPaymentRepo.EXPECT().Create(gomock.Any(), &mockPayment).
+					Return(testCase.given.createPayment.err).
+					Times(1)
+
+				mockPaymentRepo.EXPECT().GetTotalByInvoiceID(gomock.Any(), mockInvoice.InvoiceID).
+					Return(testCase.given.getTotal.total, testCase.given.getTotal.err).
+					Times(1)
+
+				updatedInvoice := mockInvoice
+				updatedInvoice.AmountPaid = testCase.given.getTotal.total
+				updatedInvoice.Status = deriveInvoiceStatus(mockInvoice.Status, mockInvoice.GrandTotal, mockInvoice.AmountCredited, testCase.given.getTotal.total)
+
+				mockInvoicesRepo.EXPECT().UpdateStatus(gomock.Any(), &updatedInvoice).
+					Return(testCase.given.updateStatus.err).
+					Times(1)
+
+				mockAtomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+
+				mockAtomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			}()
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.CreatePayment(context.Background(), testCase.given.req, testCase.given.id)
+			log.Println(got)
+			assert.Equal(t, testCase.expected.res, got)
+			assert.Equal(t, testCase.expected.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_GetPayments(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	mockID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	paymentDate := time.Date(2023, time.January, 23, 0, 0, 0, 0, time.UTC)
+
+	mockPayments := []*entity.Payment{
+		{
+			PaymentData: entity.PaymentData{
+				PaymentID:   mockID,
+				InvoiceID:   "0001",
+				Amount:      decimal.NewFromInt(100),
+				PaymentDate: paymentDate,
+				Method:      "transfer",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		getInvoice  error
+		getPayments error
+		expectedRes []contract.PaymentResponse
+		expectedErr error
+	}{
+		{
+			name:        "error invoice id not found",
+			getInvoice:  sql.ErrNoRows,
+			expectedErr: errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name:        "error get payments",
+			getPayments: errors.New("error internal server"),
+			expectedErr: errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			expectedRes: []contract.PaymentResponse{
+				{
+					PaymentID:   mockID,
+					Amount:      decimal.NewFromInt(100),
+					PaymentDate: "23-01-2023",
+					Method:      "transfer",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mockInvoicesRepo := mock_Invoices.NewMockInvoicesRepository(mockCtrl)
+			mockCustomerRepo := mock_Invoices.NewMockCustomerRepository(mockCtrl)
+			mockItemRepo := mock_Invoices.NewMockItemRepository(mockCtrl)
+			mockPaymentRepo := mock_Invoices.NewMockPaymentRepository(mockCtrl)
+			mockSeriesRepo := mock_Invoices.NewMockSeriesRepository(mockCtrl)
+			mockHTMLRenderer := mock_Invoices.NewMockHTMLRenderer(mockCtrl)
+			mockUBLEncoder := mock_Invoices.NewMockUBLEncoder(mockCtrl)
+			mockAsession := mock_atomic.NewMockAtomicSessionProvider(mockCtrl)
+
+			mockInvoicesRepo.EXPECT().Get(gomock.Any(), "0001").
+				Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001"}}, testCase.getInvoice).
+				Times(1)
+
+			if testCase.getInvoice == nil {
+				mockPaymentRepo.EXPECT().GetByInvoiceID(gomock.Any(), "0001").
+					Return(mockPayments, testCase.getPayments).
+					Times(1)
+			}
+
+			Invoices := InitInvoiceservice(mockInvoicesRepo, mockCustomerRepo, mockItemRepo, mockPaymentRepo, nil, mockSeriesRepo, mockHTMLRenderer, mockUBLEncoder, mockAsession, FixedUUIDGenerator{})
+			got, actualErr := Invoices.GetPayments(context.Background(), "0001")
+			assert.Equal(t, testCase.expectedRes, got)
+			assert.Equal(t, testCase.expectedErr, actualErr)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package Invoices
+
+import (
+	"fmt"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/v1/contract"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// moneyPlaces is the number of fractional digits invoice amounts are rounded to.
+const moneyPlaces = 2
+
+type invoiceTotals struct {
+	Amounts    []decimal.Decimal
+	SubTotal   decimal.Decimal
+	Tax        decimal.Decimal
+	GrandTotal decimal.Decimal
+}
+
+// calculateTotals computes every line amount and the invoice totals from quantities, unit prices
+// and the tax rate. Client supplied amounts are never trusted. It returns
+// ErrInvoiceAmountOutOfRange when an amount does not fit a Decimal.
+func calculateTotals(request contract.InvoiceRequest) (invoiceTotals, error) {
+	var totals invoiceTotals
+
+	for _, v := range request.ItemRequest {
+		amount, err := v.Quantity.MulRoundChecked(v.UnitPrice, moneyPlaces, decimal.RoundHalfUp)
+		if err != nil {
+			return invoiceTotals{}, errorss.ErrInvoiceAmountOutOfRange
+		}
+		totals.Amounts = append(totals.Amounts, amount)
+
+		totals.SubTotal, err = totals.SubTotal.AddChecked(amount)
+		if err != nil {
+			return invoiceTotals{}, errorss.ErrInvoiceAmountOutOfRange
+		}
+	}
+
+	var err error
+	totals.Tax, err = totals.SubTotal.MulPercentChecked(request.TaxRate, moneyPlaces, decimal.RoundHalfUp)
+	if err != nil {
+		return invoiceTotals{}, errorss.ErrInvoiceAmountOutOfRange
+	}
+
+	totals.GrandTotal, err = totals.SubTotal.AddChecked(totals.Tax)
+	if err != nil {
+		return invoiceTotals{}, errorss.ErrInvoiceAmountOutOfRange
+	}
+
+	return totals, nil
+}
+
+// validateTotals compares the optional client supplied values with the calculated ones.
+// A zero value means the client left the field out and is not compared.
+func validateTotals(request contract.InvoiceRequest, totals invoiceTotals) error {
+	var mismatches []errorss.TotalMismatch
+
+	check := func(field string, expected, actual decimal.Decimal) {
+		if !actual.IsZero() && !expected.Equal(actual.Round(moneyPlaces, decimal.RoundHalfUp)) {
+			mismatches = append(mismatches, errorss.TotalMismatch{
+				Field:    field,
+				Expected: expected,
+				Actual:   actual,
+			})
+		}
+	}
+
+	for i, v := range request.ItemRequest {
+		check(fmt.Sprintf("item_request[%d].amount", i), totals.Amounts[i], v.Amount)
+	}
+
+	check("sub_total", totals.SubTotal, request.SubTotal)
+	check("tax", totals.Tax, request.Tax)
+	check("grand_total", totals.GrandTotal, request.GrandTotal)
+
+	if len(mismatches) > 0 {
+		return &errorss.TotalsMismatchError{Mismatches: mismatches}
+	}
+
+	return nil
+}
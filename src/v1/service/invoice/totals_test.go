@@ -0,0 +1,160 @@
+package Invoices
+
+import (
+	"testing"
+
+	"github.com/Risuii/invoice/src/decimal"
+	errorss "github.com/Risuii/invoice/src/errors"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+)
+
+func TestInvoiceService_CalculateTotals(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  contract.InvoiceRequest
+		expected invoiceTotals
+		err      error
+	}{
+		{
+			name:     "no items",
+			request:  contract.InvoiceRequest{TaxRate: decimal.NewFromInt(11)},
+			expected: invoiceTotals{},
+		},
+		{
+			name: "items with tax",
+			request: contract.InvoiceRequest{
+				TaxRate: decimal.NewFromInt(11),
+				ItemRequest: []contract.ItemRequest{
+					{Quantity: decimal.NewFromInt(3), UnitPrice: decimal.NewFromInt(300)},
+					{Quantity: decimal.RequireFromString("1.5"), UnitPrice: decimal.RequireFromString("99.99")},
+				},
+			},
+			expected: invoiceTotals{
+				Amounts:    []decimal.Decimal{decimal.NewFromInt(900), decimal.RequireFromString("149.99")},
+				SubTotal:   decimal.RequireFromString("1049.99"),
+				Tax:        decimal.RequireFromString("115.5"),
+				GrandTotal: decimal.RequireFromString("1165.49"),
+			},
+		},
+		{
+			name: "half cent rounds up",
+			request: contract.InvoiceRequest{
+				TaxRate: decimal.NewFromInt(10),
+				ItemRequest: []contract.ItemRequest{
+					{Quantity: decimal.NewFromInt(1), UnitPrice: decimal.RequireFromString("0.05")},
+				},
+			},
+			expected: invoiceTotals{
+				Amounts:    []decimal.Decimal{decimal.RequireFromString("0.05")},
+				SubTotal:   decimal.RequireFromString("0.05"),
+				Tax:        decimal.RequireFromString("0.01"),
+				GrandTotal: decimal.RequireFromString("0.06"),
+			},
+		},
+		{
+			name: "no float noise",
+			request: contract.InvoiceRequest{
+				ItemRequest: []contract.ItemRequest{
+					{Quantity: decimal.NewFromInt(3), UnitPrice: decimal.RequireFromString("0.1")},
+				},
+			},
+			expected: invoiceTotals{
+				Amounts:    []decimal.Decimal{decimal.RequireFromString("0.3")},
+				SubTotal:   decimal.RequireFromString("0.3"),
+				GrandTotal: decimal.RequireFromString("0.3"),
+			},
+		},
+		{
+			name: "line amount out of range",
+			request: contract.InvoiceRequest{
+				ItemRequest: []contract.ItemRequest{
+					{Quantity: decimal.NewFromInt(99999999999), UnitPrice: decimal.NewFromInt(99999999999)},
+				},
+			},
+			err: errorss.ErrInvoiceAmountOutOfRange,
+		},
+		{
+			name: "sub total out of range",
+			request: contract.InvoiceRequest{
+				ItemRequest: []contract.ItemRequest{
+					{Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(900000000000000)},
+					{Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(900000000000000)},
+				},
+			},
+			err: errorss.ErrInvoiceAmountOutOfRange,
+		},
+		{
+			name: "grand total out of range",
+			request: contract.InvoiceRequest{
+				TaxRate: decimal.NewFromInt(100),
+				ItemRequest: []contract.ItemRequest{
+					{Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(900000000000000)},
+				},
+			},
+			err: errorss.ErrInvoiceAmountOutOfRange,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := calculateTotals(testCase.request)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, err)
+		})
+	}
+}
+
+func TestInvoiceService_ValidateTotals(t *testing.T) {
+	request := contract.InvoiceRequest{
+		TaxRate: decimal.NewFromInt(10),
+		ItemRequest: []contract.ItemRequest{
+			{Quantity: decimal.NewFromInt(2), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(r *contract.InvoiceRequest)
+		expected error
+	}{
+		{
+			name:     "nothing sent by client",
+			modify:   func(r *contract.InvoiceRequest) {},
+			expected: nil,
+		},
+		{
+			name: "matching values",
+			modify: func(r *contract.InvoiceRequest) {
+				r.ItemRequest = []contract.ItemRequest{{Quantity: decimal.NewFromInt(2), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(200)}}
+				r.SubTotal, r.Tax, r.GrandTotal = decimal.NewFromInt(200), decimal.NewFromInt(20), decimal.NewFromInt(220)
+			},
+			expected: nil,
+		},
+		{
+			name: "mismatching amount and grand total",
+			modify: func(r *contract.InvoiceRequest) {
+				r.ItemRequest = []contract.ItemRequest{{Quantity: decimal.NewFromInt(2), UnitPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(150)}}
+				r.GrandTotal = decimal.NewFromInt(200)
+			},
+			expected: &errorss.TotalsMismatchError{
+				Mismatches: []errorss.TotalMismatch{
+					{Field: "item_request[0].amount", Expected: decimal.NewFromInt(200), Actual: decimal.NewFromInt(150)},
+					{Field: "grand_total", Expected: decimal.NewFromInt(220), Actual: decimal.NewFromInt(200)},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := request
+			testCase.modify(&req)
+
+			totals, err := calculateTotals(req)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expected, validateTotals(req, totals))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/mariomac/gostream/stream"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	frsUtils "github.com/Risuii/frs-lib/utils"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// Delete moves a draft invoice and its items to the trash. Issued and paid invoices are part of
+// the books and are never deleted, an unpaid one can be reopened to a draft first.
+func (ts *Invoiceservice) Delete(ctx context.Context, id string) error {
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, err := ts.InvoicesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrInvoiceIdNotFound
+			}
+			log.Println(err)
+			return err
+		}
+
+		if dataInvoices.Status != entity.InvoiceStatusDraft {
+			log.Println("delete invoice err: ", errorss.ErrInvoiceNotDeletable)
+			return errorss.ErrInvoiceNotDeletable
+		}
+
+		deletedAt := time.Now()
+
+		err = ts.InvoicesRepo.Delete(ctx, dataInvoices.InvoiceID, deletedAt)
+		if err != nil {
+			log.Println("delete invoice err: ", err)
+			return err
+		}
+
+		err = ts.ItemRepo.DeleteByInvoiceID(ctx, dataInvoices.InvoiceID, deletedAt)
+		if err != nil {
+			log.Println("delete items err: ", err)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Restore takes an invoice out of the trash with the items it was deleted with.
+func (ts *Invoiceservice) Restore(ctx context.Context, id string) (contract.InvcResponse, error) {
+	var res contract.InvcResponse
+
+	err := frsAtomic.Atomic(ctx, ts.AtomicSession, func(ctx context.Context) error {
+		dataInvoices, err := ts.InvoicesRepo.GetDeletedForUpdate(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrInvoiceIdNotFound
+			}
+			log.Println(err)
+			return err
+		}
+
+		// the customer may have been deleted while the invoice was in the trash
+		_, err = ts.CustomerRepo.Get(ctx, dataInvoices.CustomerID.String())
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Println(err)
+				return errorss.ErrCustomerIdNotFound
+			}
+			log.Println(err)
+			return err
+		}
+
+		err = ts.InvoicesRepo.Restore(ctx, dataInvoices.InvoiceID)
+		if err != nil {
+			log.Println("restore invoice err: ", err)
+			return err
+		}
+
+		err = ts.ItemRepo.RestoreByInvoiceID(ctx, dataInvoices.InvoiceID, *dataInvoices.DeletedAt)
+		if err != nil {
+			log.Println("restore items err: ", err)
+			return err
+		}
+
+		res = contract.InvcResponse{
+			InvoiceID: dataInvoices.InvoiceID,
+			Version:   dataInvoices.Version + 1,
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (ts *Invoiceservice) GetTrash(ctx context.Context, params contract.GetTrashListParam) (contract.ListTrashResponse, error) {
+	var response contract.ListTrashResponse
+
+	Invoices, err := ts.InvoicesRepo.GetTrash(ctx, params)
+	if err != nil {
+		log.Println("get trash err: ", err)
+		return response, err
+	}
+
+	count, err := ts.InvoicesRepo.GetTrashCount(ctx)
+	if err != nil {
+		log.Println("trash count err: ", err)
+		return response, err
+	}
+
+	response = contract.ListTrashResponse{
+		Data: stream.Map(stream.OfSlice(Invoices), func(t *entity.Invoices) *contract.TrashedInvoice {
+			return &contract.TrashedInvoice{
+				Invoice: contract.Invoice{
+					InvoiceID:    t.InvoiceID,
+					IssueDate:    t.IssueDate.Format("02-01-2006"),
+					Subject:      t.Subject,
+					TotalItem:    t.TotalItems,
+					CustomerName: t.CustomerName,
+					DueDate:      t.DueDate.Format("02-01-2006"),
+					Status:       t.Status,
+					SubTotal:     t.SubTotal,
+					Tax:          t.Tax,
+					GrandTotal:   t.GrandTotal,
+					CreatedAt:    t.CreatedAt,
+					UpdatedAt:    t.UpdatedAt,
+				},
+				DeletedAt: *t.DeletedAt,
+			}
+		}).ToSlice(),
+		Pagination: frsUtils.GetPaginationData(params.Page, params.Limit, int(count)),
+	}
+
+	return response, nil
+}
+
+// Purge voids the draft invoices that have been in the trash for longer than retention and deletes their items for good.
+func (ts *Invoiceservice) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := ts.InvoicesRepo.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.Println("purge invoices err: ", err)
+		return 0, err
+	}
+
+	return purged, nil
+}
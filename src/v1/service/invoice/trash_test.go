@@ -0,0 +1,315 @@
+package Invoices
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	frsUtils "github.com/Risuii/frs-lib/utils"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Invoices "github.com/Risuii/invoice/src/v1/service/mock/invoice"
+)
+
+type trashMocks struct {
+	invoicesRepo  *mock_Invoices.MockInvoicesRepository
+	customerRepo  *mock_Invoices.MockCustomerRepository
+	itemRepo      *mock_Invoices.MockItemRepository
+	asession      *mock_atomic.MockAtomicSessionProvider
+	atomicSession *mock_atomic.MockAtomicSession
+}
+
+func newTrashMocks(mockCtrl *gomock.Controller) trashMocks {
+	m := trashMocks{
+		invoicesRepo:  mock_Invoices.NewMockInvoicesRepository(mockCtrl),
+		customerRepo:  mock_Invoices.NewMockCustomerRepository(mockCtrl),
+		itemRepo:      mock_Invoices.NewMockItemRepository(mockCtrl),
+		asession:      mock_atomic.NewMockAtomicSessionProvider(mockCtrl),
+		atomicSession: mock_atomic.NewMockAtomicSession(mockCtrl),
+	}
+
+	m.asession.EXPECT().BeginSession(gomock.Any()).
+		Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+		AnyTimes()
+
+	return m
+}
+
+func (m trashMocks) service() *Invoiceservice {
+	return InitInvoiceservice(m.invoicesRepo, m.customerRepo, m.itemRepo, nil, nil, nil, nil, nil, m.asession, FixedUUIDGenerator{})
+}
+
+func TestInvoiceService_Delete(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	testCases := []struct {
+		name  string
+		setup func(m trashMocks)
+		err   error
+	}{
+		{
+			name: "error invoice id not found",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{}, sql.ErrNoRows).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name: "error invoice not deletable",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001", Status: entity.InvoiceStatusIssued}}, nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrInvoiceNotDeletable,
+		},
+		{
+			name: "error delete items",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001", Status: entity.InvoiceStatusDraft}}, nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().Delete(gomock.Any(), "0001", gomock.Any()).
+					Return(nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().DeleteByInvoiceID(gomock.Any(), "0001", gomock.Any()).
+					Return(errors.New("error internal server")).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			setup: func(m trashMocks) {
+				var invoiceDeletedAt time.Time
+
+				m.invoicesRepo.EXPECT().GetForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{InvoicesData: entity.InvoicesData{InvoiceID: "0001", Status: entity.InvoiceStatusDraft}}, nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().Delete(gomock.Any(), "0001", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id string, deletedAt time.Time) error {
+						invoiceDeletedAt = deletedAt
+						return nil
+					}).
+					Times(1)
+
+				// the items share the deleted_at of their invoice
+				m.itemRepo.EXPECT().DeleteByInvoiceID(gomock.Any(), "0001", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, invID string, deletedAt time.Time) error {
+						assert.Equal(t, invoiceDeletedAt, deletedAt)
+						return nil
+					}).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := newTrashMocks(mockCtrl)
+			testCase.setup(m)
+
+			actualErr := m.service().Delete(context.Background(), "0001")
+
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_Restore(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	customerID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	deletedAt := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+
+	deletedInvoice := entity.Invoices{
+		ModelLogTime: entity.ModelLogTime{DeletedAt: &deletedAt},
+		InvoicesData: entity.InvoicesData{InvoiceID: "0001", CustomerID: customerID, Status: entity.InvoiceStatusDraft, Version: 2},
+	}
+
+	testCases := []struct {
+		name     string
+		setup    func(m trashMocks)
+		expected contract.InvcResponse
+		err      error
+	}{
+		{
+			name: "error invoice not in trash",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetDeletedForUpdate(gomock.Any(), "0001").
+					Return(entity.Invoices{}, sql.ErrNoRows).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrInvoiceIdNotFound,
+		},
+		{
+			name: "error customer deleted",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetDeletedForUpdate(gomock.Any(), "0001").
+					Return(deletedInvoice, nil).
+					Times(1)
+
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{}, sql.ErrNoRows).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrCustomerIdNotFound,
+		},
+		{
+			name: "error duplicate tax invoice number",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetDeletedForUpdate(gomock.Any(), "0001").
+					Return(deletedInvoice, nil).
+					Times(1)
+
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: customerID}}, nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().Restore(gomock.Any(), "0001").
+					Return(errorss.ErrDuplicateTaxInvoiceNumber).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrDuplicateTaxInvoiceNumber,
+		},
+		{
+			name: "success",
+			setup: func(m trashMocks) {
+				m.invoicesRepo.EXPECT().GetDeletedForUpdate(gomock.Any(), "0001").
+					Return(deletedInvoice, nil).
+					Times(1)
+
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{CustomerData: entity.CustomerData{CustomerID: customerID}}, nil).
+					Times(1)
+
+				m.invoicesRepo.EXPECT().Restore(gomock.Any(), "0001").
+					Return(nil).
+					Times(1)
+
+				m.itemRepo.EXPECT().RestoreByInvoiceID(gomock.Any(), "0001", deletedAt).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: contract.InvcResponse{InvoiceID: "0001", Version: 3},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := newTrashMocks(mockCtrl)
+			testCase.setup(m)
+
+			got, actualErr := m.service().Restore(context.Background(), "0001")
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestInvoiceService_GetTrash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	deletedAt := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	params := contract.GetTrashListParam{Page: 1, Limit: 10}
+
+	m := newTrashMocks(mockCtrl)
+
+	m.invoicesRepo.EXPECT().GetTrash(gomock.Any(), params).
+		Return([]*entity.Invoices{
+			{
+				ModelLogTime: entity.ModelLogTime{DeletedAt: &deletedAt},
+				InvoicesData: entity.InvoicesData{
+					InvoiceID:    "0001",
+					IssueDate:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+					DueDate:      time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+					Subject:      "design",
+					CustomerName: "acme",
+					Status:       entity.InvoiceStatusDraft,
+					GrandTotal:   decimal.NewFromInt(100),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	m.invoicesRepo.EXPECT().GetTrashCount(gomock.Any()).
+		Return(int64(1), nil).
+		Times(1)
+
+	got, err := m.service().GetTrash(context.Background(), params)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, contract.ListTrashResponse{
+		Data: []*contract.TrashedInvoice{
+			{
+				Invoice: contract.Invoice{
+					InvoiceID:    "0001",
+					IssueDate:    "01-03-2024",
+					DueDate:      "31-03-2024",
+					Subject:      "design",
+					CustomerName: "acme",
+					Status:       entity.InvoiceStatusDraft,
+					GrandTotal:   decimal.NewFromInt(100),
+				},
+				DeletedAt: deletedAt,
+			},
+		},
+		Pagination: frsUtils.GetPaginationData(1, 10, 1),
+	}, got)
+}
+
+func TestInvoiceService_Purge(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	m := newTrashMocks(mockCtrl)
+
+	m.invoicesRepo.EXPECT().Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+			// invoices deleted in the last 30 days are kept
+			assert.Equal(t, true, time.Since(before) >= 30*24*time.Hour)
+			assert.Equal(t, true, time.Since(before) < 31*24*time.Hour)
+			return 2, nil
+		}).
+		Times(1)
+
+	purged, err := m.service().Purge(context.Background(), 30*24*time.Hour)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), purged)
+}
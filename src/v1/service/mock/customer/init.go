@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: customer/init.go
+//
+// Generated by this command:
+//
+//	mockgen -source=customer/init.go -destination=mock/customer/init.go
+//
+// Package mock_Customers is a generated GoMock package.
+package mock_Customers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/Risuii/invoice/src/entity"
+	contract "github.com/Risuii/invoice/src/v1/contract"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCustomerRepository is a mock of CustomerRepository interface.
+type MockCustomerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerRepositoryMockRecorder
+}
+
+// MockCustomerRepositoryMockRecorder is the mock recorder for MockCustomerRepository.
+type MockCustomerRepositoryMockRecorder struct {
+	mock *MockCustomerRepository
+}
+
+// NewMockCustomerRepository creates a new mock instance.
+func NewMockCustomerRepository(ctrl *gomock.Controller) *MockCustomerRepository {
+	mock := &MockCustomerRepository{ctrl: ctrl}
+	mock.recorder = &MockCustomerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerRepository) EXPECT() *MockCustomerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountInvoices mocks base method.
+func (m *MockCustomerRepository) CountInvoices(ctx context.Context, id string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountInvoices", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountInvoices indicates an expected call of CountInvoices.
+func (mr *MockCustomerRepositoryMockRecorder) CountInvoices(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountInvoices", reflect.TypeOf((*MockCustomerRepository)(nil).CountInvoices), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockCustomerRepository) Create(ctx context.Context, data *entity.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCustomerRepositoryMockRecorder) Create(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerRepository)(nil).Create), ctx, data)
+}
+
+// Delete mocks base method.
+func (m *MockCustomerRepository) Delete(ctx context.Context, data *entity.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCustomerRepositoryMockRecorder) Delete(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerRepository)(nil).Delete), ctx, data)
+}
+
+// Get mocks base method.
+func (m *MockCustomerRepository) Get(ctx context.Context, id string) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCustomerRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCustomerRepository)(nil).Get), ctx, id)
+}
+
+// GetCustomersCount mocks base method.
+func (m *MockCustomerRepository) GetCustomersCount(ctx context.Context, params contract.GetListCustomerParam) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomersCount", ctx, params)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomersCount indicates an expected call of GetCustomersCount.
+func (mr *MockCustomerRepositoryMockRecorder) GetCustomersCount(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomersCount", reflect.TypeOf((*MockCustomerRepository)(nil).GetCustomersCount), ctx, params)
+}
+
+// GetList mocks base method.
+func (m *MockCustomerRepository) GetList(ctx context.Context, params contract.GetListCustomerParam) ([]*entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, params)
+	ret0, _ := ret[0].([]*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockCustomerRepositoryMockRecorder) GetList(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockCustomerRepository)(nil).GetList), ctx, params)
+}
+
+// Update mocks base method.
+func (m *MockCustomerRepository) Update(ctx context.Context, data *entity.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCustomerRepositoryMockRecorder) Update(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), ctx, data)
+}
+
+// UpdateDunningPaused mocks base method.
+func (m *MockCustomerRepository) UpdateDunningPaused(ctx context.Context, id string, paused bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDunningPaused", ctx, id, paused)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDunningPaused indicates an expected call of UpdateDunningPaused.
+func (mr *MockCustomerRepositoryMockRecorder) UpdateDunningPaused(ctx, id, paused any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDunningPaused", reflect.TypeOf((*MockCustomerRepository)(nil).UpdateDunningPaused), ctx, id, paused)
+}
@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dunning/init.go
+//
+// Generated by this command:
+//
+//	mockgen -source=dunning/init.go -destination=mock/dunning/init.go
+//
+// Package mock_Dunning is a generated GoMock package.
+package mock_Dunning
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/Risuii/invoice/src/entity"
+	mailer "github.com/Risuii/invoice/src/mailer"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReminderRepository is a mock of ReminderRepository interface.
+type MockReminderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReminderRepositoryMockRecorder
+}
+
+// MockReminderRepositoryMockRecorder is the mock recorder for MockReminderRepository.
+type MockReminderRepositoryMockRecorder struct {
+	mock *MockReminderRepository
+}
+
+// NewMockReminderRepository creates a new mock instance.
+func NewMockReminderRepository(ctrl *gomock.Controller) *MockReminderRepository {
+	mock := &MockReminderRepository{ctrl: ctrl}
+	mock.recorder = &MockReminderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReminderRepository) EXPECT() *MockReminderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReminderRepository) Create(ctx context.Context, data *entity.InvoiceReminder, maxAttempts int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, data, maxAttempts)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReminderRepositoryMockRecorder) Create(ctx, data, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReminderRepository)(nil).Create), ctx, data, maxAttempts)
+}
+
+// GetByInvoiceID mocks base method.
+func (m *MockReminderRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*entity.InvoiceReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByInvoiceID", ctx, invID)
+	ret0, _ := ret[0].([]*entity.InvoiceReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByInvoiceID indicates an expected call of GetByInvoiceID.
+func (mr *MockReminderRepositoryMockRecorder) GetByInvoiceID(ctx, invID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInvoiceID", reflect.TypeOf((*MockReminderRepository)(nil).GetByInvoiceID), ctx, invID)
+}
+
+// GetDue mocks base method.
+func (m *MockReminderRepository) GetDue(ctx context.Context, dueBefore time.Time, maxAttempts int) ([]*entity.DunningInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDue", ctx, dueBefore, maxAttempts)
+	ret0, _ := ret[0].([]*entity.DunningInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDue indicates an expected call of GetDue.
+func (mr *MockReminderRepositoryMockRecorder) GetDue(ctx, dueBefore, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDue", reflect.TypeOf((*MockReminderRepository)(nil).GetDue), ctx, dueBefore, maxAttempts)
+}
+
+// Update mocks base method.
+func (m *MockReminderRepository) Update(ctx context.Context, data *entity.InvoiceReminder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReminderRepositoryMockRecorder) Update(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReminderRepository)(nil).Update), ctx, data)
+}
+
+// MockInvoicesRepository is a mock of InvoicesRepository interface.
+type MockInvoicesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvoicesRepositoryMockRecorder
+}
+
+// MockInvoicesRepositoryMockRecorder is the mock recorder for MockInvoicesRepository.
+type MockInvoicesRepositoryMockRecorder struct {
+	mock *MockInvoicesRepository
+}
+
+// NewMockInvoicesRepository creates a new mock instance.
+func NewMockInvoicesRepository(ctrl *gomock.Controller) *MockInvoicesRepository {
+	mock := &MockInvoicesRepository{ctrl: ctrl}
+	mock.recorder = &MockInvoicesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvoicesRepository) EXPECT() *MockInvoicesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockInvoicesRepository) Get(ctx context.Context, id string) (entity.Invoices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Invoices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockInvoicesRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInvoicesRepository)(nil).Get), ctx, id)
+}
+
+// MockMailer is a mock of Mailer interface.
+type MockMailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailerMockRecorder
+}
+
+// MockMailerMockRecorder is the mock recorder for MockMailer.
+type MockMailerMockRecorder struct {
+	mock *MockMailer
+}
+
+// NewMockMailer creates a new mock instance.
+func NewMockMailer(ctrl *gomock.Controller) *MockMailer {
+	mock := &MockMailer{ctrl: ctrl}
+	mock.recorder = &MockMailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailer) EXPECT() *MockMailerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMailerMockRecorder) Send(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMailer)(nil).Send), ctx, msg)
+}
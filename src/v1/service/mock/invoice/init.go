@@ -10,8 +10,12 @@ package mock_Invoices
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
+	decimal "github.com/Risuii/invoice/src/decimal"
+	document "github.com/Risuii/invoice/src/document"
 	entity "github.com/Risuii/invoice/src/entity"
 	contract "github.com/Risuii/invoice/src/v1/contract"
 	uuid "github.com/google/uuid"
@@ -56,6 +60,48 @@ func (mr *MockInvoicesRepositoryMockRecorder) Create(ctx, data any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvoicesRepository)(nil).Create), ctx, data)
 }
 
+// CreateStatusHistory mocks base method.
+func (m *MockInvoicesRepository) CreateStatusHistory(ctx context.Context, data *entity.InvoiceStatusHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatusHistory", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStatusHistory indicates an expected call of CreateStatusHistory.
+func (mr *MockInvoicesRepositoryMockRecorder) CreateStatusHistory(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatusHistory", reflect.TypeOf((*MockInvoicesRepository)(nil).CreateStatusHistory), ctx, data)
+}
+
+// Delete mocks base method.
+func (m *MockInvoicesRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockInvoicesRepositoryMockRecorder) Delete(ctx, id, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInvoicesRepository)(nil).Delete), ctx, id, deletedAt)
+}
+
+// ExportList mocks base method.
+func (m *MockInvoicesRepository) ExportList(ctx context.Context, params contract.GetListParam, includeItems bool, fn func(*entity.Invoices, *entity.Item) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportList", ctx, params, includeItems, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportList indicates an expected call of ExportList.
+func (mr *MockInvoicesRepositoryMockRecorder) ExportList(ctx, params, includeItems, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportList", reflect.TypeOf((*MockInvoicesRepository)(nil).ExportList), ctx, params, includeItems, fn)
+}
+
 // Get mocks base method.
 func (m *MockInvoicesRepository) Get(ctx context.Context, id string) (entity.Invoices, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +117,51 @@ func (mr *MockInvoicesRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockInvoicesRepository)(nil).Get), ctx, id)
 }
 
+// GetDeletedForUpdate mocks base method.
+func (m *MockInvoicesRepository) GetDeletedForUpdate(ctx context.Context, id string) (entity.Invoices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedForUpdate", ctx, id)
+	ret0, _ := ret[0].(entity.Invoices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedForUpdate indicates an expected call of GetDeletedForUpdate.
+func (mr *MockInvoicesRepositoryMockRecorder) GetDeletedForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedForUpdate", reflect.TypeOf((*MockInvoicesRepository)(nil).GetDeletedForUpdate), ctx, id)
+}
+
+// GetForTaxExport mocks base method.
+func (m *MockInvoicesRepository) GetForTaxExport(ctx context.Context, from, to time.Time) ([]*entity.Invoices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForTaxExport", ctx, from, to)
+	ret0, _ := ret[0].([]*entity.Invoices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForTaxExport indicates an expected call of GetForTaxExport.
+func (mr *MockInvoicesRepositoryMockRecorder) GetForTaxExport(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForTaxExport", reflect.TypeOf((*MockInvoicesRepository)(nil).GetForTaxExport), ctx, from, to)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockInvoicesRepository) GetForUpdate(ctx context.Context, id string) (entity.Invoices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(entity.Invoices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockInvoicesRepositoryMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockInvoicesRepository)(nil).GetForUpdate), ctx, id)
+}
+
 // GetInvoicesCount mocks base method.
 func (m *MockInvoicesRepository) GetInvoicesCount(ctx context.Context, param contract.GetListParam) (int64, error) {
 	m.ctrl.T.Helper()
@@ -86,34 +177,93 @@ func (mr *MockInvoicesRepositoryMockRecorder) GetInvoicesCount(ctx, param any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoicesCount", reflect.TypeOf((*MockInvoicesRepository)(nil).GetInvoicesCount), ctx, param)
 }
 
-// GetLatestInvoiceID mocks base method.
-func (m *MockInvoicesRepository) GetLatestInvoiceID(ctx context.Context) (string, error) {
+// GetList mocks base method.
+func (m *MockInvoicesRepository) GetList(ctx context.Context, params contract.GetListParam) ([]*entity.Invoices, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestInvoiceID", ctx)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetList", ctx, params)
+	ret0, _ := ret[0].([]*entity.Invoices)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLatestInvoiceID indicates an expected call of GetLatestInvoiceID.
-func (mr *MockInvoicesRepositoryMockRecorder) GetLatestInvoiceID(ctx any) *gomock.Call {
+// GetList indicates an expected call of GetList.
+func (mr *MockInvoicesRepositoryMockRecorder) GetList(ctx, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestInvoiceID", reflect.TypeOf((*MockInvoicesRepository)(nil).GetLatestInvoiceID), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInvoicesRepository)(nil).GetList), ctx, params)
 }
 
-// GetList mocks base method.
-func (m *MockInvoicesRepository) GetList(ctx context.Context, params contract.GetListParam) ([]*entity.Invoices, error) {
+// GetPastDue mocks base method.
+func (m *MockInvoicesRepository) GetPastDue(ctx context.Context, dueBefore time.Time, includeOverdue bool) ([]*entity.Invoices, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetList", ctx, params)
+	ret := m.ctrl.Call(m, "GetPastDue", ctx, dueBefore, includeOverdue)
 	ret0, _ := ret[0].([]*entity.Invoices)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetList indicates an expected call of GetList.
-func (mr *MockInvoicesRepositoryMockRecorder) GetList(ctx, params any) *gomock.Call {
+// GetPastDue indicates an expected call of GetPastDue.
+func (mr *MockInvoicesRepositoryMockRecorder) GetPastDue(ctx, dueBefore, includeOverdue any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockInvoicesRepository)(nil).GetList), ctx, params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPastDue", reflect.TypeOf((*MockInvoicesRepository)(nil).GetPastDue), ctx, dueBefore, includeOverdue)
+}
+
+// GetTrash mocks base method.
+func (m *MockInvoicesRepository) GetTrash(ctx context.Context, params contract.GetTrashListParam) ([]*entity.Invoices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, params)
+	ret0, _ := ret[0].([]*entity.Invoices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockInvoicesRepositoryMockRecorder) GetTrash(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockInvoicesRepository)(nil).GetTrash), ctx, params)
+}
+
+// GetTrashCount mocks base method.
+func (m *MockInvoicesRepository) GetTrashCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrashCount indicates an expected call of GetTrashCount.
+func (mr *MockInvoicesRepositoryMockRecorder) GetTrashCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashCount", reflect.TypeOf((*MockInvoicesRepository)(nil).GetTrashCount), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockInvoicesRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockInvoicesRepositoryMockRecorder) Purge(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockInvoicesRepository)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockInvoicesRepository) Restore(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockInvoicesRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockInvoicesRepository)(nil).Restore), ctx, id)
 }
 
 // Update mocks base method.
@@ -130,6 +280,34 @@ func (mr *MockInvoicesRepositoryMockRecorder) Update(ctx, data any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInvoicesRepository)(nil).Update), ctx, data)
 }
 
+// UpdateStatus mocks base method.
+func (m *MockInvoicesRepository) UpdateStatus(ctx context.Context, data *entity.Invoices) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockInvoicesRepositoryMockRecorder) UpdateStatus(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockInvoicesRepository)(nil).UpdateStatus), ctx, data)
+}
+
+// UpdateTaxInvoiceNumber mocks base method.
+func (m *MockInvoicesRepository) UpdateTaxInvoiceNumber(ctx context.Context, data *entity.Invoices) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaxInvoiceNumber", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTaxInvoiceNumber indicates an expected call of UpdateTaxInvoiceNumber.
+func (mr *MockInvoicesRepositoryMockRecorder) UpdateTaxInvoiceNumber(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaxInvoiceNumber", reflect.TypeOf((*MockInvoicesRepository)(nil).UpdateTaxInvoiceNumber), ctx, data)
+}
+
 // MockCustomerRepository is a mock of CustomerRepository interface.
 type MockCustomerRepository struct {
 	ctrl     *gomock.Controller
@@ -153,18 +331,19 @@ func (m *MockCustomerRepository) EXPECT() *MockCustomerRepositoryMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockCustomerRepository) Create(ctx context.Context, data *entity.Customer) error {
+// FindOrCreate mocks base method.
+func (m *MockCustomerRepository) FindOrCreate(ctx context.Context, data *entity.Customer) (entity.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, data)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FindOrCreate", ctx, data)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockCustomerRepositoryMockRecorder) Create(ctx, data any) *gomock.Call {
+// FindOrCreate indicates an expected call of FindOrCreate.
+func (mr *MockCustomerRepositoryMockRecorder) FindOrCreate(ctx, data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerRepository)(nil).Create), ctx, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreate", reflect.TypeOf((*MockCustomerRepository)(nil).FindOrCreate), ctx, data)
 }
 
 // Get mocks base method.
@@ -182,20 +361,6 @@ func (mr *MockCustomerRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCustomerRepository)(nil).Get), ctx, id)
 }
 
-// Update mocks base method.
-func (m *MockCustomerRepository) Update(ctx context.Context, data *entity.Customer) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, data)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Update indicates an expected call of Update.
-func (mr *MockCustomerRepositoryMockRecorder) Update(ctx, data any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), ctx, data)
-}
-
 // MockItemRepository is a mock of ItemRepository interface.
 type MockItemRepository struct {
 	ctrl     *gomock.Controller
@@ -234,17 +399,31 @@ func (mr *MockItemRepositoryMockRecorder) Create(ctx, data any) *gomock.Call {
 }
 
 // Delete mocks base method.
-func (m *MockItemRepository) Delete(ctx context.Context, id []uuid.UUID) error {
+func (m *MockItemRepository) Delete(ctx context.Context, ids []uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, ids)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockItemRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockItemRepositoryMockRecorder) Delete(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockItemRepository)(nil).Delete), ctx, ids)
+}
+
+// DeleteByInvoiceID mocks base method.
+func (m *MockItemRepository) DeleteByInvoiceID(ctx context.Context, invID string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByInvoiceID", ctx, invID, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByInvoiceID indicates an expected call of DeleteByInvoiceID.
+func (mr *MockItemRepositoryMockRecorder) DeleteByInvoiceID(ctx, invID, deletedAt any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockItemRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByInvoiceID", reflect.TypeOf((*MockItemRepository)(nil).DeleteByInvoiceID), ctx, invID, deletedAt)
 }
 
 // GetByInvoiceID mocks base method.
@@ -262,6 +441,35 @@ func (mr *MockItemRepositoryMockRecorder) GetByInvoiceID(ctx, invID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInvoiceID", reflect.TypeOf((*MockItemRepository)(nil).GetByInvoiceID), ctx, invID)
 }
 
+// GetByInvoiceIDForUpdate mocks base method.
+func (m *MockItemRepository) GetByInvoiceIDForUpdate(ctx context.Context, invID string) ([]*entity.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByInvoiceIDForUpdate", ctx, invID)
+	ret0, _ := ret[0].([]*entity.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByInvoiceIDForUpdate indicates an expected call of GetByInvoiceIDForUpdate.
+func (mr *MockItemRepositoryMockRecorder) GetByInvoiceIDForUpdate(ctx, invID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInvoiceIDForUpdate", reflect.TypeOf((*MockItemRepository)(nil).GetByInvoiceIDForUpdate), ctx, invID)
+}
+
+// RestoreByInvoiceID mocks base method.
+func (m *MockItemRepository) RestoreByInvoiceID(ctx context.Context, invID string, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreByInvoiceID", ctx, invID, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreByInvoiceID indicates an expected call of RestoreByInvoiceID.
+func (mr *MockItemRepositoryMockRecorder) RestoreByInvoiceID(ctx, invID, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreByInvoiceID", reflect.TypeOf((*MockItemRepository)(nil).RestoreByInvoiceID), ctx, invID, deletedAt)
+}
+
 // Update mocks base method.
 func (m *MockItemRepository) Update(ctx context.Context, data []*entity.Item) error {
 	m.ctrl.T.Helper()
@@ -275,3 +483,278 @@ func (mr *MockItemRepositoryMockRecorder) Update(ctx, data any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockItemRepository)(nil).Update), ctx, data)
 }
+
+// MockPaymentRepository is a mock of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+}
+
+// MockPaymentRepositoryMockRecorder is the mock recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mock instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPaymentRepository) Create(ctx context.Context, data *entity.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentRepositoryMockRecorder) Create(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentRepository)(nil).Create), ctx, data)
+}
+
+// GetByInvoiceID mocks base method.
+func (m *MockPaymentRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByInvoiceID", ctx, invID)
+	ret0, _ := ret[0].([]*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByInvoiceID indicates an expected call of GetByInvoiceID.
+func (mr *MockPaymentRepositoryMockRecorder) GetByInvoiceID(ctx, invID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInvoiceID", reflect.TypeOf((*MockPaymentRepository)(nil).GetByInvoiceID), ctx, invID)
+}
+
+// GetTotalByInvoiceID mocks base method.
+func (m *MockPaymentRepository) GetTotalByInvoiceID(ctx context.Context, invID string) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalByInvoiceID", ctx, invID)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalByInvoiceID indicates an expected call of GetTotalByInvoiceID.
+func (mr *MockPaymentRepositoryMockRecorder) GetTotalByInvoiceID(ctx, invID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalByInvoiceID", reflect.TypeOf((*MockPaymentRepository)(nil).GetTotalByInvoiceID), ctx, invID)
+}
+
+// MockCreditNoteRepository is a mock of CreditNoteRepository interface.
+type MockCreditNoteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreditNoteRepositoryMockRecorder
+}
+
+// MockCreditNoteRepositoryMockRecorder is the mock recorder for MockCreditNoteRepository.
+type MockCreditNoteRepositoryMockRecorder struct {
+	mock *MockCreditNoteRepository
+}
+
+// NewMockCreditNoteRepository creates a new mock instance.
+func NewMockCreditNoteRepository(ctrl *gomock.Controller) *MockCreditNoteRepository {
+	mock := &MockCreditNoteRepository{ctrl: ctrl}
+	mock.recorder = &MockCreditNoteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreditNoteRepository) EXPECT() *MockCreditNoteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCreditNoteRepository) Create(ctx context.Context, data *entity.CreditNote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCreditNoteRepositoryMockRecorder) Create(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCreditNoteRepository)(nil).Create), ctx, data)
+}
+
+// CreateItems mocks base method.
+func (m *MockCreditNoteRepository) CreateItems(ctx context.Context, data []*entity.CreditNoteItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItems", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateItems indicates an expected call of CreateItems.
+func (mr *MockCreditNoteRepositoryMockRecorder) CreateItems(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItems", reflect.TypeOf((*MockCreditNoteRepository)(nil).CreateItems), ctx, data)
+}
+
+// GetByInvoiceID mocks base method.
+func (m *MockCreditNoteRepository) GetByInvoiceID(ctx context.Context, invID string) ([]*entity.CreditNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByInvoiceID", ctx, invID)
+	ret0, _ := ret[0].([]*entity.CreditNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByInvoiceID indicates an expected call of GetByInvoiceID.
+func (mr *MockCreditNoteRepositoryMockRecorder) GetByInvoiceID(ctx, invID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInvoiceID", reflect.TypeOf((*MockCreditNoteRepository)(nil).GetByInvoiceID), ctx, invID)
+}
+
+// GetItemsByInvoiceID mocks base method.
+func (m *MockCreditNoteRepository) GetItemsByInvoiceID(ctx context.Context, invID string) ([]*entity.CreditNoteItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemsByInvoiceID", ctx, invID)
+	ret0, _ := ret[0].([]*entity.CreditNoteItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemsByInvoiceID indicates an expected call of GetItemsByInvoiceID.
+func (mr *MockCreditNoteRepositoryMockRecorder) GetItemsByInvoiceID(ctx, invID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsByInvoiceID", reflect.TypeOf((*MockCreditNoteRepository)(nil).GetItemsByInvoiceID), ctx, invID)
+}
+
+// MockSeriesRepository is a mock of SeriesRepository interface.
+type MockSeriesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesRepositoryMockRecorder
+}
+
+// MockSeriesRepositoryMockRecorder is the mock recorder for MockSeriesRepository.
+type MockSeriesRepositoryMockRecorder struct {
+	mock *MockSeriesRepository
+}
+
+// NewMockSeriesRepository creates a new mock instance.
+func NewMockSeriesRepository(ctrl *gomock.Controller) *MockSeriesRepository {
+	mock := &MockSeriesRepository{ctrl: ctrl}
+	mock.recorder = &MockSeriesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesRepository) EXPECT() *MockSeriesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockSeriesRepository) Get(ctx context.Context, code string) (entity.InvoiceSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, code)
+	ret0, _ := ret[0].(entity.InvoiceSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSeriesRepositoryMockRecorder) Get(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSeriesRepository)(nil).Get), ctx, code)
+}
+
+// NextValue mocks base method.
+func (m *MockSeriesRepository) NextValue(ctx context.Context, code, periodKey string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextValue", ctx, code, periodKey)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextValue indicates an expected call of NextValue.
+func (mr *MockSeriesRepositoryMockRecorder) NextValue(ctx, code, periodKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextValue", reflect.TypeOf((*MockSeriesRepository)(nil).NextValue), ctx, code, periodKey)
+}
+
+// MockHTMLRenderer is a mock of HTMLRenderer interface.
+type MockHTMLRenderer struct {
+	ctrl     *gomock.Controller
+	recorder *MockHTMLRendererMockRecorder
+}
+
+// MockHTMLRendererMockRecorder is the mock recorder for MockHTMLRenderer.
+type MockHTMLRendererMockRecorder struct {
+	mock *MockHTMLRenderer
+}
+
+// NewMockHTMLRenderer creates a new mock instance.
+func NewMockHTMLRenderer(ctrl *gomock.Controller) *MockHTMLRenderer {
+	mock := &MockHTMLRenderer{ctrl: ctrl}
+	mock.recorder = &MockHTMLRendererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHTMLRenderer) EXPECT() *MockHTMLRendererMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method.
+func (m *MockHTMLRenderer) Render(w io.Writer, name string, invoice contract.InvoiceResponse, labels document.Labels) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", w, name, invoice, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockHTMLRendererMockRecorder) Render(w, name, invoice, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockHTMLRenderer)(nil).Render), w, name, invoice, labels)
+}
+
+// MockUBLEncoder is a mock of UBLEncoder interface.
+type MockUBLEncoder struct {
+	ctrl     *gomock.Controller
+	recorder *MockUBLEncoderMockRecorder
+}
+
+// MockUBLEncoderMockRecorder is the mock recorder for MockUBLEncoder.
+type MockUBLEncoderMockRecorder struct {
+	mock *MockUBLEncoder
+}
+
+// NewMockUBLEncoder creates a new mock instance.
+func NewMockUBLEncoder(ctrl *gomock.Controller) *MockUBLEncoder {
+	mock := &MockUBLEncoder{ctrl: ctrl}
+	mock.recorder = &MockUBLEncoderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUBLEncoder) EXPECT() *MockUBLEncoderMockRecorder {
+	return m.recorder
+}
+
+// Encode mocks base method.
+func (m *MockUBLEncoder) Encode(w io.Writer, invoice entity.Invoices, customer entity.Customer, items []*entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", w, invoice, customer, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockUBLEncoderMockRecorder) Encode(w, invoice, customer, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockUBLEncoder)(nil).Encode), w, invoice, customer, items)
+}
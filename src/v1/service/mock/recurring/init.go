@@ -0,0 +1,375 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recurring/init.go
+//
+// Generated by this command:
+//
+//	mockgen -source=recurring/init.go -destination=mock/recurring/init.go
+//
+// Package mock_Recurring is a generated GoMock package.
+package mock_Recurring
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/Risuii/invoice/src/entity"
+	contract "github.com/Risuii/invoice/src/v1/contract"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecurringRepository is a mock of RecurringRepository interface.
+type MockRecurringRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringRepositoryMockRecorder
+}
+
+// MockRecurringRepositoryMockRecorder is the mock recorder for MockRecurringRepository.
+type MockRecurringRepositoryMockRecorder struct {
+	mock *MockRecurringRepository
+}
+
+// NewMockRecurringRepository creates a new mock instance.
+func NewMockRecurringRepository(ctrl *gomock.Controller) *MockRecurringRepository {
+	mock := &MockRecurringRepository{ctrl: ctrl}
+	mock.recorder = &MockRecurringRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringRepository) EXPECT() *MockRecurringRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Advance mocks base method.
+func (m *MockRecurringRepository) Advance(ctx context.Context, id uuid.UUID, runAt time.Time, nextRun *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Advance", ctx, id, runAt, nextRun)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Advance indicates an expected call of Advance.
+func (mr *MockRecurringRepositoryMockRecorder) Advance(ctx, id, runAt, nextRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Advance", reflect.TypeOf((*MockRecurringRepository)(nil).Advance), ctx, id, runAt, nextRun)
+}
+
+// Create mocks base method.
+func (m *MockRecurringRepository) Create(ctx context.Context, data *entity.RecurringInvoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecurringRepositoryMockRecorder) Create(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecurringRepository)(nil).Create), ctx, data)
+}
+
+// CreateItems mocks base method.
+func (m *MockRecurringRepository) CreateItems(ctx context.Context, data []*entity.RecurringInvoiceItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItems", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateItems indicates an expected call of CreateItems.
+func (mr *MockRecurringRepositoryMockRecorder) CreateItems(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItems", reflect.TypeOf((*MockRecurringRepository)(nil).CreateItems), ctx, data)
+}
+
+// CreateRun mocks base method.
+func (m *MockRecurringRepository) CreateRun(ctx context.Context, data *entity.RecurringInvoiceRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRun", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRun indicates an expected call of CreateRun.
+func (mr *MockRecurringRepositoryMockRecorder) CreateRun(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRun", reflect.TypeOf((*MockRecurringRepository)(nil).CreateRun), ctx, data)
+}
+
+// Delete mocks base method.
+func (m *MockRecurringRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecurringRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecurringRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteItems mocks base method.
+func (m *MockRecurringRepository) DeleteItems(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItems", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItems indicates an expected call of DeleteItems.
+func (mr *MockRecurringRepositoryMockRecorder) DeleteItems(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItems", reflect.TypeOf((*MockRecurringRepository)(nil).DeleteItems), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockRecurringRepository) Get(ctx context.Context, id string) (entity.RecurringInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.RecurringInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRecurringRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRecurringRepository)(nil).Get), ctx, id)
+}
+
+// GetDue mocks base method.
+func (m *MockRecurringRepository) GetDue(ctx context.Context, now time.Time) ([]*entity.RecurringInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDue", ctx, now)
+	ret0, _ := ret[0].([]*entity.RecurringInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDue indicates an expected call of GetDue.
+func (mr *MockRecurringRepositoryMockRecorder) GetDue(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDue", reflect.TypeOf((*MockRecurringRepository)(nil).GetDue), ctx, now)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockRecurringRepository) GetForUpdate(ctx context.Context, id string) (entity.RecurringInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(entity.RecurringInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockRecurringRepositoryMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockRecurringRepository)(nil).GetForUpdate), ctx, id)
+}
+
+// GetItems mocks base method.
+func (m *MockRecurringRepository) GetItems(ctx context.Context, id string) ([]*entity.RecurringInvoiceItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, id)
+	ret0, _ := ret[0].([]*entity.RecurringInvoiceItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockRecurringRepositoryMockRecorder) GetItems(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockRecurringRepository)(nil).GetItems), ctx, id)
+}
+
+// GetList mocks base method.
+func (m *MockRecurringRepository) GetList(ctx context.Context, params contract.GetListRecurringParam) ([]*entity.RecurringInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, params)
+	ret0, _ := ret[0].([]*entity.RecurringInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockRecurringRepositoryMockRecorder) GetList(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockRecurringRepository)(nil).GetList), ctx, params)
+}
+
+// GetRecurringCount mocks base method.
+func (m *MockRecurringRepository) GetRecurringCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringCount indicates an expected call of GetRecurringCount.
+func (mr *MockRecurringRepositoryMockRecorder) GetRecurringCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringCount", reflect.TypeOf((*MockRecurringRepository)(nil).GetRecurringCount), ctx)
+}
+
+// GetRuns mocks base method.
+func (m *MockRecurringRepository) GetRuns(ctx context.Context, id string, limit int) ([]*entity.RecurringInvoiceRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuns", ctx, id, limit)
+	ret0, _ := ret[0].([]*entity.RecurringInvoiceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuns indicates an expected call of GetRuns.
+func (mr *MockRecurringRepositoryMockRecorder) GetRuns(ctx, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuns", reflect.TypeOf((*MockRecurringRepository)(nil).GetRuns), ctx, id, limit)
+}
+
+// RunLocked mocks base method.
+func (m *MockRecurringRepository) RunLocked(ctx context.Context, fn func(context.Context) error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunLocked", ctx, fn)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunLocked indicates an expected call of RunLocked.
+func (mr *MockRecurringRepositoryMockRecorder) RunLocked(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunLocked", reflect.TypeOf((*MockRecurringRepository)(nil).RunLocked), ctx, fn)
+}
+
+// Update mocks base method.
+func (m *MockRecurringRepository) Update(ctx context.Context, data *entity.RecurringInvoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRecurringRepositoryMockRecorder) Update(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRecurringRepository)(nil).Update), ctx, data)
+}
+
+// MockCustomerRepository is a mock of CustomerRepository interface.
+type MockCustomerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerRepositoryMockRecorder
+}
+
+// MockCustomerRepositoryMockRecorder is the mock recorder for MockCustomerRepository.
+type MockCustomerRepositoryMockRecorder struct {
+	mock *MockCustomerRepository
+}
+
+// NewMockCustomerRepository creates a new mock instance.
+func NewMockCustomerRepository(ctrl *gomock.Controller) *MockCustomerRepository {
+	mock := &MockCustomerRepository{ctrl: ctrl}
+	mock.recorder = &MockCustomerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerRepository) EXPECT() *MockCustomerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockCustomerRepository) Get(ctx context.Context, id string) (entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCustomerRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCustomerRepository)(nil).Get), ctx, id)
+}
+
+// MockSeriesRepository is a mock of SeriesRepository interface.
+type MockSeriesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesRepositoryMockRecorder
+}
+
+// MockSeriesRepositoryMockRecorder is the mock recorder for MockSeriesRepository.
+type MockSeriesRepositoryMockRecorder struct {
+	mock *MockSeriesRepository
+}
+
+// NewMockSeriesRepository creates a new mock instance.
+func NewMockSeriesRepository(ctrl *gomock.Controller) *MockSeriesRepository {
+	mock := &MockSeriesRepository{ctrl: ctrl}
+	mock.recorder = &MockSeriesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesRepository) EXPECT() *MockSeriesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockSeriesRepository) Get(ctx context.Context, code string) (entity.InvoiceSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, code)
+	ret0, _ := ret[0].(entity.InvoiceSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSeriesRepositoryMockRecorder) Get(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSeriesRepository)(nil).Get), ctx, code)
+}
+
+// MockInvoiceCreator is a mock of InvoiceCreator interface.
+type MockInvoiceCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvoiceCreatorMockRecorder
+}
+
+// MockInvoiceCreatorMockRecorder is the mock recorder for MockInvoiceCreator.
+type MockInvoiceCreatorMockRecorder struct {
+	mock *MockInvoiceCreator
+}
+
+// NewMockInvoiceCreator creates a new mock instance.
+func NewMockInvoiceCreator(ctrl *gomock.Controller) *MockInvoiceCreator {
+	mock := &MockInvoiceCreator{ctrl: ctrl}
+	mock.recorder = &MockInvoiceCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvoiceCreator) EXPECT() *MockInvoiceCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateInTransaction mocks base method.
+func (m *MockInvoiceCreator) CreateInTransaction(ctx context.Context, request contract.InvoiceRequest) (contract.InvcResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInTransaction", ctx, request)
+	ret0, _ := ret[0].(contract.InvcResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInTransaction indicates an expected call of CreateInTransaction.
+func (mr *MockInvoiceCreatorMockRecorder) CreateInTransaction(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInTransaction", reflect.TypeOf((*MockInvoiceCreator)(nil).CreateInTransaction), ctx, request)
+}
@@ -0,0 +1,42 @@
+package Recurring
+
+import (
+	"context"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+)
+
+type RecurringRepository interface {
+	Create(ctx context.Context, data *entity.RecurringInvoice) error
+	CreateItems(ctx context.Context, data []*entity.RecurringInvoiceItem) error
+	Get(ctx context.Context, id string) (entity.RecurringInvoice, error)
+	GetForUpdate(ctx context.Context, id string) (entity.RecurringInvoice, error)
+	GetItems(ctx context.Context, id string) ([]*entity.RecurringInvoiceItem, error)
+	GetRuns(ctx context.Context, id string, limit int) ([]*entity.RecurringInvoiceRun, error)
+	GetList(ctx context.Context, params contract.GetListRecurringParam) ([]*entity.RecurringInvoice, error)
+	GetRecurringCount(ctx context.Context) (int64, error)
+	GetDue(ctx context.Context, now time.Time) ([]*entity.RecurringInvoice, error)
+	Update(ctx context.Context, data *entity.RecurringInvoice) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteItems(ctx context.Context, id uuid.UUID) error
+	Advance(ctx context.Context, id uuid.UUID, runAt time.Time, nextRun *time.Time) error
+	CreateRun(ctx context.Context, data *entity.RecurringInvoiceRun) error
+	RunLocked(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+}
+
+type CustomerRepository interface {
+	Get(ctx context.Context, id string) (entity.Customer, error)
+}
+
+type SeriesRepository interface {
+	Get(ctx context.Context, code string) (entity.InvoiceSeries, error)
+}
+
+// InvoiceCreator creates the invoice of a period in the transaction claiming it, the invoice
+// service.
+type InvoiceCreator interface {
+	CreateInTransaction(ctx context.Context, request contract.InvoiceRequest) (contract.InvcResponse, error)
+}
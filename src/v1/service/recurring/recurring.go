@@ -0,0 +1,367 @@
+package Recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/schedule"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/google/uuid"
+	"github.com/mariomac/gostream/stream"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+	frsUtils "github.com/Risuii/frs-lib/utils"
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+// detailRuns is how many of the latest runs the detail shows.
+const detailRuns = 10
+
+type UUIDGenerator interface {
+	New() uuid.UUID
+}
+
+type Recurringservice struct {
+	RecurringRepo  RecurringRepository
+	CustomerRepo   CustomerRepository
+	SeriesRepo     SeriesRepository
+	InvoiceCreator InvoiceCreator
+	AtomicSession  frsAtomic.AtomicSessionProvider
+	UUIDGen        UUIDGenerator
+}
+
+func InitRecurringservice(recurringRepo RecurringRepository, customerRepo CustomerRepository, series SeriesRepository, invoiceCreator InvoiceCreator, aSession frsAtomic.AtomicSessionProvider, uuid UUIDGenerator) *Recurringservice {
+	return &Recurringservice{
+		RecurringRepo:  recurringRepo,
+		CustomerRepo:   customerRepo,
+		SeriesRepo:     series,
+		InvoiceCreator: invoiceCreator,
+		AtomicSession:  aSession,
+		UUIDGen:        uuid,
+	}
+}
+
+func buildRecurringResponse(r entity.RecurringInvoice) contract.RecurringInvoiceResponse {
+	res := contract.RecurringInvoiceResponse{
+		RecurringID: r.RecurringID,
+		CustomerID:  r.CustomerID,
+		Subject:     r.Subject,
+		TaxRate:     r.TaxRate,
+		Series:      r.Series,
+		Interval:    r.Interval,
+		Cron:        r.Cron,
+		DueDays:     r.DueDays,
+		StartDate:   r.StartDate.Format("02-01-2006"),
+		NextRun:     r.NextRun,
+		LastRunAt:   r.LastRunAt,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+
+	if r.EndDate != nil {
+		res.EndDate = r.EndDate.Format("02-01-2006")
+	}
+
+	return res
+}
+
+func buildRecurringData(request contract.RecurringInvoiceRequest) entity.RecurringInvoiceData {
+	layout := "02-01-2006"
+	startDate, _ := time.Parse(layout, request.StartDate)
+
+	data := entity.RecurringInvoiceData{
+		CustomerID: request.CustomerID,
+		Subject:    request.Subject,
+		TaxRate:    request.TaxRate,
+		Series:     request.Series,
+		Interval:   request.Interval,
+		Cron:       request.Cron,
+		DueDays:    request.DueDays,
+		StartDate:  startDate,
+	}
+
+	if request.EndDate != "" {
+		endDate, _ := time.Parse(layout, request.EndDate)
+		data.EndDate = &endDate
+	}
+
+	return data
+}
+
+func buildRecurringItems(id uuid.UUID, request []contract.RecurringItemRequest) []*entity.RecurringInvoiceItem {
+	return stream.Map(stream.OfSlice(request), func(v contract.RecurringItemRequest) *entity.RecurringInvoiceItem {
+		return &entity.RecurringInvoiceItem{
+			RecurringInvoiceItemData: entity.RecurringInvoiceItemData{
+				RecurringID: id,
+				Name:        v.Name,
+				Type:        v.Type,
+				Quantity:    v.Quantity,
+				UnitPrice:   v.UnitPrice,
+			},
+		}
+	}).ToSlice()
+}
+
+// nextRun returns the first run of the schedule after the given run, or its first run when
+// after is nil. It returns nil when the schedule has no run left, the end date is the last
+// day with a run.
+func nextRun(data entity.RecurringInvoiceData, after *time.Time) (*time.Time, error) {
+	var next time.Time
+	var err error
+
+	if after == nil {
+		next, err = schedule.First(data.Interval, data.Cron, data.StartDate)
+	} else {
+		next, err = schedule.Next(data.Interval, data.Cron, data.StartDate, *after)
+	}
+
+	if errors.Is(err, schedule.ErrNoOccurrence) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if data.EndDate != nil && !next.Before(data.EndDate.AddDate(0, 0, 1)) {
+		return nil, nil
+	}
+
+	return &next, nil
+}
+
+// validateReferences checks the customer and the series the invoices are created with.
+func (rs *Recurringservice) validateReferences(ctx context.Context, request contract.RecurringInvoiceRequest) error {
+	if _, err := rs.CustomerRepo.Get(ctx, request.CustomerID.String()); err != nil {
+		log.Println("get customer err: ", err)
+		if err == sql.ErrNoRows {
+			return errorss.ErrCustomerIdNotFound
+		}
+		return err
+	}
+
+	seriesCode := request.Series
+	if seriesCode == "" {
+		seriesCode = entity.DefaultInvoiceSeries
+	}
+
+	if _, err := rs.SeriesRepo.Get(ctx, seriesCode); err != nil {
+		log.Println("get series err: ", err)
+		if err == sql.ErrNoRows {
+			return errorss.ErrInvoiceSeriesNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (rs *Recurringservice) get(ctx context.Context, id uuid.UUID) (entity.RecurringInvoice, error) {
+	dataRecurring, err := rs.RecurringRepo.Get(ctx, id.String())
+	if err != nil {
+		log.Println("get recurring invoice err: ", err)
+		if err == sql.ErrNoRows {
+			return dataRecurring, errorss.ErrRecurringIdNotFound
+		}
+		return dataRecurring, err
+	}
+
+	return dataRecurring, nil
+}
+
+func (rs *Recurringservice) Create(ctx context.Context, request contract.RecurringInvoiceRequest) (contract.RecurringInvoiceResponse, error) {
+	var res contract.RecurringInvoiceResponse
+
+	if err := rs.validateReferences(ctx, request); err != nil {
+		return res, err
+	}
+
+	insertDataRecurring := entity.RecurringInvoice{
+		RecurringInvoiceData: buildRecurringData(request),
+	}
+	insertDataRecurring.RecurringID = rs.UUIDGen.New()
+
+	next, err := nextRun(insertDataRecurring.RecurringInvoiceData, nil)
+	if err != nil {
+		log.Println("next run err: ", err)
+		return res, err
+	}
+	insertDataRecurring.NextRun = next
+
+	err = frsAtomic.Atomic(ctx, rs.AtomicSession, func(ctx context.Context) error {
+		err := rs.RecurringRepo.Create(ctx, &insertDataRecurring)
+		if err != nil {
+			log.Println("create recurring invoice err: ", err)
+			return err
+		}
+
+		err = rs.RecurringRepo.CreateItems(ctx, buildRecurringItems(insertDataRecurring.RecurringID, request.Items))
+		if err != nil {
+			log.Println("create recurring invoice items err: ", err)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return res, err
+	}
+
+	return rs.GetDetail(ctx, insertDataRecurring.RecurringID)
+}
+
+func (rs *Recurringservice) GetList(ctx context.Context, params contract.GetListRecurringParam) (contract.ListRecurringInvoiceResponse, error) {
+	var response contract.ListRecurringInvoiceResponse
+
+	recurring, err := rs.RecurringRepo.GetList(ctx, params)
+	if err != nil {
+		log.Println("getList err: ", err)
+		return response, err
+	}
+
+	count, err := rs.RecurringRepo.GetRecurringCount(ctx)
+	if err != nil {
+		log.Println("RecurringCount err: ", err)
+		return response, err
+	}
+
+	pagination := frsUtils.GetPaginationData(params.Page, params.Limit, int(count))
+
+	responseRecurringList := stream.Map(stream.OfSlice(recurring), func(r *entity.RecurringInvoice) *contract.RecurringInvoiceResponse {
+		res := buildRecurringResponse(*r)
+		return &res
+	}).ToSlice()
+
+	response = contract.ListRecurringInvoiceResponse{
+		Data:       responseRecurringList,
+		Pagination: pagination,
+	}
+
+	return response, nil
+}
+
+// GetDetail returns the schedule with its items and latest runs.
+func (rs *Recurringservice) GetDetail(ctx context.Context, id uuid.UUID) (contract.RecurringInvoiceResponse, error) {
+	var res contract.RecurringInvoiceResponse
+
+	dataRecurring, err := rs.get(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	dataItems, err := rs.RecurringRepo.GetItems(ctx, id.String())
+	if err != nil {
+		log.Println("get recurring invoice items err: ", err)
+		return res, err
+	}
+
+	dataRuns, err := rs.RecurringRepo.GetRuns(ctx, id.String(), detailRuns)
+	if err != nil {
+		log.Println("get recurring invoice runs err: ", err)
+		return res, err
+	}
+
+	res = buildRecurringResponse(dataRecurring)
+
+	res.Items = stream.Map(stream.OfSlice(dataItems), func(v *entity.RecurringInvoiceItem) contract.RecurringItemResponse {
+		return contract.RecurringItemResponse{
+			Name:      v.Name,
+			Type:      v.Type,
+			Quantity:  v.Quantity,
+			UnitPrice: v.UnitPrice,
+		}
+	}).ToSlice()
+
+	res.Runs = stream.Map(stream.OfSlice(dataRuns), func(v *entity.RecurringInvoiceRun) contract.RecurringRunResponse {
+		return contract.RecurringRunResponse{
+			RunAt:     v.RunAt,
+			InvoiceID: v.InvoiceID,
+			Status:    v.Status,
+			Error:     v.Error,
+		}
+	}).ToSlice()
+
+	return res, nil
+}
+
+// Update replaces the template and its items. The next run is worked out again from the last
+// run, so periods already invoiced are not invoiced again.
+func (rs *Recurringservice) Update(ctx context.Context, request contract.RecurringInvoiceRequest, id uuid.UUID) (contract.RecurringInvoiceResponse, error) {
+	var res contract.RecurringInvoiceResponse
+
+	if err := rs.validateReferences(ctx, request); err != nil {
+		return res, err
+	}
+
+	err := frsAtomic.Atomic(ctx, rs.AtomicSession, func(ctx context.Context) error {
+		// lock the row so a run claimed meanwhile is seen in last_run_at
+		dataRecurring, err := rs.RecurringRepo.GetForUpdate(ctx, id.String())
+		if err != nil {
+			log.Println("get recurring invoice err: ", err)
+			if err == sql.ErrNoRows {
+				return errorss.ErrRecurringIdNotFound
+			}
+			return err
+		}
+
+		lastRunAt := dataRecurring.LastRunAt
+		dataRecurring.RecurringInvoiceData = buildRecurringData(request)
+		dataRecurring.RecurringID = id
+		dataRecurring.LastRunAt = lastRunAt
+
+		dataRecurring.NextRun, err = nextRun(dataRecurring.RecurringInvoiceData, lastRunAt)
+		if err != nil {
+			log.Println("next run err: ", err)
+			return err
+		}
+
+		err = rs.RecurringRepo.Update(ctx, &dataRecurring)
+		if err != nil {
+			log.Println("update recurring invoice err: ", err)
+			if err == sql.ErrNoRows {
+				return errorss.ErrRecurringIdNotFound
+			}
+			return err
+		}
+
+		err = rs.RecurringRepo.DeleteItems(ctx, id)
+		if err != nil {
+			log.Println("delete recurring invoice items err: ", err)
+			return err
+		}
+
+		err = rs.RecurringRepo.CreateItems(ctx, buildRecurringItems(id, request.Items))
+		if err != nil {
+			log.Println("create recurring invoice items err: ", err)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Println("err: ", err)
+		return res, err
+	}
+
+	return rs.GetDetail(ctx, id)
+}
+
+// Delete stops the schedule, the invoices it created are kept.
+func (rs *Recurringservice) Delete(ctx context.Context, id uuid.UUID) error {
+	err := rs.RecurringRepo.Delete(ctx, id)
+	if err != nil {
+		log.Println("delete recurring invoice err: ", err)
+		if err == sql.ErrNoRows {
+			return errorss.ErrRecurringIdNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+package Recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	atomic "github.com/Risuii/frs-lib/atomic"
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	mock_atomic "github.com/Risuii/frs-lib/atomic/mock"
+	errorss "github.com/Risuii/invoice/src/errors"
+	mock_Recurring "github.com/Risuii/invoice/src/v1/service/mock/recurring"
+)
+
+type FixedUUIDGenerator struct{}
+
+func (g FixedUUIDGenerator) New() uuid.UUID {
+	return uuid.MustParse("00000000-0000-0000-0000-000000000000") // Use a fixed UUID for testing
+}
+
+type recurringMocks struct {
+	recurringRepo  *mock_Recurring.MockRecurringRepository
+	customerRepo   *mock_Recurring.MockCustomerRepository
+	seriesRepo     *mock_Recurring.MockSeriesRepository
+	invoiceCreator *mock_Recurring.MockInvoiceCreator
+	asession       *mock_atomic.MockAtomicSessionProvider
+	atomicSession  *mock_atomic.MockAtomicSession
+}
+
+func newRecurringMocks(mockCtrl *gomock.Controller) recurringMocks {
+	m := recurringMocks{
+		recurringRepo:  mock_Recurring.NewMockRecurringRepository(mockCtrl),
+		customerRepo:   mock_Recurring.NewMockCustomerRepository(mockCtrl),
+		seriesRepo:     mock_Recurring.NewMockSeriesRepository(mockCtrl),
+		invoiceCreator: mock_Recurring.NewMockInvoiceCreator(mockCtrl),
+		asession:       mock_atomic.NewMockAtomicSessionProvider(mockCtrl),
+		atomicSession:  mock_atomic.NewMockAtomicSession(mockCtrl),
+	}
+
+	m.asession.EXPECT().BeginSession(gomock.Any()).
+		Return(atomic.NewAtomicSessionContext(context.Background(), m.atomicSession), nil).
+		AnyTimes()
+
+	return m
+}
+
+func (m recurringMocks) service() *Recurringservice {
+	return InitRecurringservice(m.recurringRepo, m.customerRepo, m.seriesRepo, m.invoiceCreator, m.asession, FixedUUIDGenerator{})
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+var (
+	customerID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	startDate  = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func recurringRequest() contract.RecurringInvoiceRequest {
+	return contract.RecurringInvoiceRequest{
+		CustomerID: customerID,
+		Subject:    "service payment",
+		TaxRate:    decimal.NewFromInt(11),
+		Interval:   "monthly",
+		DueDays:    14,
+		StartDate:  "31-01-2024",
+		EndDate:    "31-12-2024",
+		Items: []contract.RecurringItemRequest{
+			{Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+}
+
+func TestRecurring_NextRun(t *testing.T) {
+	data := entity.RecurringInvoiceData{
+		Interval:  "monthly",
+		StartDate: startDate,
+		EndDate:   timePtr(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	testCases := []struct {
+		name     string
+		data     entity.RecurringInvoiceData
+		after    *time.Time
+		expected *time.Time
+	}{
+		{name: "first run", data: data, expected: timePtr(startDate)},
+		{name: "next run", data: data, after: timePtr(startDate), expected: timePtr(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC))},
+		{name: "run on the end date", data: data, after: timePtr(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)), expected: timePtr(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC))},
+		{name: "past the end date", data: data, after: timePtr(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC))},
+		{name: "cron never runs", data: entity.RecurringInvoiceData{Interval: "cron", Cron: "0 0 30 2 *", StartDate: startDate}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := nextRun(testCase.data, testCase.after)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expected, got)
+		})
+	}
+}
+
+func TestRecurringService_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	request := recurringRequest()
+
+	insertData := entity.RecurringInvoice{
+		RecurringInvoiceData: entity.RecurringInvoiceData{
+			RecurringID: FixedUUIDGenerator{}.New(),
+			CustomerID:  customerID,
+			Subject:     "service payment",
+			TaxRate:     decimal.NewFromInt(11),
+			Interval:    "monthly",
+			DueDays:     14,
+			StartDate:   startDate,
+			EndDate:     timePtr(time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)),
+			NextRun:     timePtr(startDate),
+		},
+	}
+
+	items := []*entity.RecurringInvoiceItem{
+		{RecurringInvoiceItemData: entity.RecurringInvoiceItemData{RecurringID: FixedUUIDGenerator{}.New(), Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)}},
+	}
+
+	testCases := []struct {
+		name     string
+		setup    func(m recurringMocks)
+		expected contract.RecurringInvoiceResponse
+		err      error
+	}{
+		{
+			name: "err customer not found",
+			setup: func(m recurringMocks) {
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{}, sql.ErrNoRows).
+					Times(1)
+			},
+			err: errorss.ErrCustomerIdNotFound,
+		},
+		{
+			name: "err series not found",
+			setup: func(m recurringMocks) {
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{}, nil).
+					Times(1)
+
+				m.seriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+					Return(entity.InvoiceSeries{}, sql.ErrNoRows).
+					Times(1)
+			},
+			err: errorss.ErrInvoiceSeriesNotFound,
+		},
+		{
+			name: "err create items",
+			setup: func(m recurringMocks) {
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{}, nil).
+					Times(1)
+
+				m.seriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+					Return(entity.InvoiceSeries{}, nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().Create(gomock.Any(), &insertData).
+					Return(nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().CreateItems(gomock.Any(), items).
+					Return(errors.New("error internal server")).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errors.New("error internal server"),
+		},
+		{
+			name: "success",
+			setup: func(m recurringMocks) {
+				m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+					Return(entity.Customer{}, nil).
+					Times(1)
+
+				m.seriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+					Return(entity.InvoiceSeries{}, nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().Create(gomock.Any(), &insertData).
+					Return(nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().CreateItems(gomock.Any(), items).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+
+				m.recurringRepo.EXPECT().Get(gomock.Any(), insertData.RecurringID.String()).
+					Return(insertData, nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().GetItems(gomock.Any(), insertData.RecurringID.String()).
+					Return(items, nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().GetRuns(gomock.Any(), insertData.RecurringID.String(), detailRuns).
+					Return(nil, nil).
+					Times(1)
+			},
+			expected: contract.RecurringInvoiceResponse{
+				RecurringID: insertData.RecurringID,
+				CustomerID:  customerID,
+				Subject:     "service payment",
+				TaxRate:     decimal.NewFromInt(11),
+				Interval:    "monthly",
+				DueDays:     14,
+				StartDate:   "31-01-2024",
+				EndDate:     "31-12-2024",
+				NextRun:     timePtr(startDate),
+				Items: []contract.RecurringItemResponse{
+					{Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+				},
+				Runs: []contract.RecurringRunResponse{},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := newRecurringMocks(mockCtrl)
+			testCase.setup(m)
+
+			got, actualErr := m.service().Create(context.Background(), request)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestRecurringService_Update(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	request := recurringRequest()
+	request.Interval = "quarterly"
+
+	lastRunAt := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		setup func(m recurringMocks)
+		err   error
+	}{
+		{
+			name: "err recurring id not found",
+			setup: func(m recurringMocks) {
+				m.recurringRepo.EXPECT().GetForUpdate(gomock.Any(), id.String()).
+					Return(entity.RecurringInvoice{}, sql.ErrNoRows).
+					Times(1)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+			err: errorss.ErrRecurringIdNotFound,
+		},
+		{
+			name: "success",
+			setup: func(m recurringMocks) {
+				m.recurringRepo.EXPECT().GetForUpdate(gomock.Any(), id.String()).
+					Return(entity.RecurringInvoice{
+						RecurringInvoiceData: entity.RecurringInvoiceData{
+							RecurringID: id,
+							Interval:    "monthly",
+							StartDate:   startDate,
+							NextRun:     timePtr(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)),
+							LastRunAt:   &lastRunAt,
+						},
+					}, nil).
+					Times(1)
+
+				// the next quarter after the last run, periods already invoiced are skipped
+				m.recurringRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, data *entity.RecurringInvoice) error {
+						assert.Equal(t, timePtr(time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC)), data.NextRun)
+						assert.Equal(t, &lastRunAt, data.LastRunAt)
+						assert.Equal(t, "quarterly", data.Interval)
+						return nil
+					}).
+					Times(1)
+
+				m.recurringRepo.EXPECT().DeleteItems(gomock.Any(), id).
+					Return(nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().CreateItems(gomock.Any(), gomock.Any()).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+
+				m.recurringRepo.EXPECT().Get(gomock.Any(), id.String()).
+					Return(entity.RecurringInvoice{}, nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().GetItems(gomock.Any(), id.String()).
+					Return(nil, nil).
+					Times(1)
+
+				m.recurringRepo.EXPECT().GetRuns(gomock.Any(), id.String(), detailRuns).
+					Return(nil, nil).
+					Times(1)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := newRecurringMocks(mockCtrl)
+
+			m.customerRepo.EXPECT().Get(gomock.Any(), customerID.String()).
+				Return(entity.Customer{}, nil).
+				Times(1)
+
+			m.seriesRepo.EXPECT().Get(gomock.Any(), entity.DefaultInvoiceSeries).
+				Return(entity.InvoiceSeries{}, nil).
+				Times(1)
+
+			testCase.setup(m)
+
+			_, actualErr := m.service().Update(context.Background(), request, id)
+
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestRecurringService_Delete(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	testCases := []struct {
+		name      string
+		deleteErr error
+		err       error
+	}{
+		{
+			name:      "err recurring id not found",
+			deleteErr: sql.ErrNoRows,
+			err:       errorss.ErrRecurringIdNotFound,
+		},
+		{
+			name: "success",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := newRecurringMocks(mockCtrl)
+
+			m.recurringRepo.EXPECT().Delete(gomock.Any(), id).
+				Return(testCase.deleteErr).
+				Times(1)
+
+			actualErr := m.service().Delete(context.Background(), id)
+
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package Recurring
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/mariomac/gostream/stream"
+
+	frsAtomic "github.com/Risuii/frs-lib/atomic"
+)
+
+// Start runs RunDue every interval until ctx is done.
+func (rs *Recurringservice) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if created, err := rs.RunDue(ctx, time.Now()); err != nil {
+			log.Println("run due recurring invoices err: ", err)
+		} else if created > 0 {
+			log.Printf("created %d recurring invoices", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue creates the invoice of every schedule due at now and returns how many it created.
+// Only the instance holding the scheduler lock runs it, the others skip the tick. A schedule
+// behind by several periods catches up one period per tick.
+func (rs *Recurringservice) RunDue(ctx context.Context, now time.Time) (int, error) {
+	var created int
+
+	locked, err := rs.RecurringRepo.RunLocked(ctx, func(ctx context.Context) error {
+		dataRecurring, err := rs.RecurringRepo.GetDue(ctx, now)
+		if err != nil {
+			log.Println("get due recurring invoices err: ", err)
+			return err
+		}
+
+		for _, v := range dataRecurring {
+			ok, err := rs.run(ctx, *v)
+			if err != nil {
+				log.Printf("run recurring invoice %s err: %v", v.RecurringID, err)
+				continue
+			}
+
+			if ok {
+				created++
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return created, err
+	}
+
+	if !locked {
+		log.Println("recurring invoices are run by another instance")
+	}
+
+	return created, nil
+}
+
+// run creates the invoice of the period the schedule is waiting for. Moving the schedule to
+// its next run, creating the invoice and recording the run happen in one transaction, so a
+// period is invoiced exactly once. A period whose invoice failed stays due and is tried again
+// on the next tick, its run keeps the last error. It returns false when the period was claimed
+// by someone else or failed.
+func (rs *Recurringservice) run(ctx context.Context, dataRecurring entity.RecurringInvoice) (bool, error) {
+	runAt := dataRecurring.NextRun.UTC()
+
+	next, err := nextRun(dataRecurring.RecurringInvoiceData, &runAt)
+	if err != nil {
+		log.Println("next run err: ", err)
+		return false, err
+	}
+
+	dataItems, err := rs.RecurringRepo.GetItems(ctx, dataRecurring.RecurringID.String())
+	if err != nil {
+		log.Println("get recurring invoice items err: ", err)
+		return false, err
+	}
+
+	dataRun := entity.RecurringInvoiceRun{
+		RecurringInvoiceRunData: entity.RecurringInvoiceRunData{
+			RecurringID: dataRecurring.RecurringID,
+			RunAt:       runAt,
+			Status:      entity.RecurringRunCreated,
+		},
+	}
+
+	claimed := true
+	err = frsAtomic.Atomic(ctx, rs.AtomicSession, func(ctx context.Context) error {
+		err := rs.RecurringRepo.Advance(ctx, dataRecurring.RecurringID, runAt, next)
+		if err == sql.ErrNoRows {
+			claimed = false
+			return err
+		}
+		if err != nil {
+			log.Println("advance recurring invoice err: ", err)
+			return err
+		}
+
+		res, err := rs.InvoiceCreator.CreateInTransaction(ctx, buildInvoiceRequest(dataRecurring, dataItems, runAt))
+		if err != nil {
+			log.Println("create recurring invoice err: ", err)
+			return err
+		}
+		dataRun.InvoiceID = res.InvoiceID
+
+		err = rs.RecurringRepo.CreateRun(ctx, &dataRun)
+		if err != nil {
+			log.Println("create recurring invoice run err: ", err)
+			return err
+		}
+
+		return nil
+	})
+
+	if !claimed {
+		return false, nil
+	}
+
+	if err != nil {
+		log.Println("err: ", err)
+
+		dataRun.Status = entity.RecurringRunFailed
+		dataRun.Error = err.Error()
+		if runErr := rs.RecurringRepo.CreateRun(ctx, &dataRun); runErr != nil {
+			log.Println("create failed recurring invoice run err: ", runErr)
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// buildInvoiceRequest builds the draft invoice of the period starting at runAt, issued on that
+// day and due DueDays later.
+func buildInvoiceRequest(dataRecurring entity.RecurringInvoice, dataItems []*entity.RecurringInvoiceItem, runAt time.Time) contract.InvoiceRequest {
+	layout := "02-01-2006"
+
+	return contract.InvoiceRequest{
+		Subject:    dataRecurring.Subject,
+		IssueDate:  runAt.Format(layout),
+		DueDate:    runAt.AddDate(0, 0, dataRecurring.DueDays).Format(layout),
+		TaxRate:    dataRecurring.TaxRate,
+		Series:     dataRecurring.Series,
+		CustomerID: dataRecurring.CustomerID,
+		ItemRequest: stream.Map(stream.OfSlice(dataItems), func(v *entity.RecurringInvoiceItem) contract.ItemRequest {
+			return contract.ItemRequest{
+				Name:      v.Name,
+				Type:      v.Type,
+				Quantity:  v.Quantity,
+				UnitPrice: v.UnitPrice,
+			}
+		}).ToSlice(),
+	}
+}
@@ -0,0 +1,244 @@
+package Recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Risuii/invoice/src/decimal"
+	"github.com/Risuii/invoice/src/entity"
+	"github.com/Risuii/invoice/src/v1/contract"
+	"github.com/go-playground/assert"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	errorss "github.com/Risuii/invoice/src/errors"
+)
+
+func TestRecurringService_RunDue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	now := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	runAt := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	dueRecurring := []*entity.RecurringInvoice{
+		{
+			RecurringInvoiceData: entity.RecurringInvoiceData{
+				RecurringID: id,
+				CustomerID:  customerID,
+				Subject:     "service payment",
+				TaxRate:     decimal.NewFromInt(11),
+				Interval:    "monthly",
+				DueDays:     14,
+				StartDate:   startDate,
+				NextRun:     &runAt,
+			},
+		},
+	}
+
+	items := []*entity.RecurringInvoiceItem{
+		{RecurringInvoiceItemData: entity.RecurringInvoiceItemData{RecurringID: id, Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)}},
+	}
+
+	invoiceRequest := contract.InvoiceRequest{
+		Subject:    "service payment",
+		IssueDate:  "29-02-2024",
+		DueDate:    "14-03-2024",
+		TaxRate:    decimal.NewFromInt(11),
+		CustomerID: customerID,
+		ItemRequest: []contract.ItemRequest{
+			{Name: "hosting", Type: "service", Quantity: decimal.NewFromInt(1), UnitPrice: decimal.NewFromInt(100)},
+		},
+	}
+
+	// claim expects the period to be claimed, with advanceErr as the result of moving the schedule
+	claim := func(m recurringMocks, advanceErr error) {
+		m.recurringRepo.EXPECT().GetItems(gomock.Any(), id.String()).
+			Return(items, nil).
+			Times(1)
+
+		m.recurringRepo.EXPECT().Advance(gomock.Any(), id, runAt, &nextRunAt).
+			Return(advanceErr).
+			Times(1)
+	}
+
+	testCases := []struct {
+		name     string
+		setup    func(m recurringMocks)
+		locked   bool
+		expected int
+		err      error
+	}{
+		{
+			name: "lock held by another instance",
+		},
+		{
+			name:   "err get due",
+			locked: true,
+			setup: func(m recurringMocks) {
+				m.recurringRepo.EXPECT().GetDue(gomock.Any(), now).
+					Return(nil, errors.New("error internal server")).
+					Times(1)
+			},
+			err: errors.New("error internal server"),
+		},
+		{
+			name:   "period claimed by another run",
+			locked: true,
+			setup: func(m recurringMocks) {
+				m.recurringRepo.EXPECT().GetDue(gomock.Any(), now).
+					Return(dueRecurring, nil).
+					Times(1)
+
+				claim(m, sql.ErrNoRows)
+
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:   "invoice not created",
+			locked: true,
+			setup: func(m recurringMocks) {
+				m.recurringRepo.EXPECT().GetDue(gomock.Any(), now).
+					Return(dueRecurring, nil).
+					Times(1)
+
+				claim(m, nil)
+
+				m.invoiceCreator.EXPECT().CreateInTransaction(gomock.Any(), invoiceRequest).
+					Return(contract.InvcResponse{}, errorss.ErrCustomerIdNotFound).
+					Times(1)
+
+				// the claim is rolled back with the invoice, the period stays due for the next tick
+				m.atomicSession.EXPECT().Rollback(gomock.Any()).Times(1)
+
+				failedRun := entity.RecurringInvoiceRun{
+					RecurringInvoiceRunData: entity.RecurringInvoiceRunData{
+						RecurringID: id,
+						RunAt:       runAt,
+						Status:      entity.RecurringRunFailed,
+						Error:       errorss.ErrCustomerIdNotFound.Error(),
+					},
+				}
+				m.recurringRepo.EXPECT().CreateRun(gomock.Any(), &failedRun).
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			name:   "success",
+			locked: true,
+			setup: func(m recurringMocks) {
+				m.recurringRepo.EXPECT().GetDue(gomock.Any(), now).
+					Return(dueRecurring, nil).
+					Times(1)
+
+				claim(m, nil)
+
+				m.invoiceCreator.EXPECT().CreateInTransaction(gomock.Any(), invoiceRequest).
+					Return(contract.InvcResponse{InvoiceID: "INV/2024/02/00001"}, nil).
+					Times(1)
+
+				createdRun := entity.RecurringInvoiceRun{
+					RecurringInvoiceRunData: entity.RecurringInvoiceRunData{
+						RecurringID: id,
+						RunAt:       runAt,
+						InvoiceID:   "INV/2024/02/00001",
+						Status:      entity.RecurringRunCreated,
+					},
+				}
+				m.recurringRepo.EXPECT().CreateRun(gomock.Any(), &createdRun).
+					Return(nil).
+					Times(1)
+
+				m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+			},
+			expected: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := newRecurringMocks(mockCtrl)
+
+			m.recurringRepo.EXPECT().RunLocked(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+					if !testCase.locked {
+						return false, nil
+					}
+					return true, fn(ctx)
+				}).
+				Times(1)
+
+			if testCase.setup != nil {
+				testCase.setup(m)
+			}
+
+			got, actualErr := m.service().RunDue(context.Background(), now)
+
+			assert.Equal(t, testCase.expected, got)
+			assert.Equal(t, testCase.err, actualErr)
+		})
+	}
+}
+
+func TestRecurringService_RunDueLastPeriod(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCtrl.Finish()
+
+	id := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	runAt := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	m := newRecurringMocks(mockCtrl)
+
+	m.recurringRepo.EXPECT().RunLocked(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+			return true, fn(ctx)
+		}).
+		Times(1)
+
+	m.recurringRepo.EXPECT().GetDue(gomock.Any(), gomock.Any()).
+		Return([]*entity.RecurringInvoice{
+			{
+				RecurringInvoiceData: entity.RecurringInvoiceData{
+					RecurringID: id,
+					Interval:    "monthly",
+					StartDate:   startDate,
+					EndDate:     &endDate,
+					NextRun:     &runAt,
+				},
+			},
+		}, nil).
+		Times(1)
+
+	m.recurringRepo.EXPECT().GetItems(gomock.Any(), id.String()).
+		Return(nil, nil).
+		Times(1)
+
+	// the run of March 31 is past the end date, the schedule stops
+	var nextRunAt *time.Time
+	m.recurringRepo.EXPECT().Advance(gomock.Any(), id, runAt, nextRunAt).
+		Return(nil).
+		Times(1)
+
+	m.invoiceCreator.EXPECT().CreateInTransaction(gomock.Any(), gomock.Any()).
+		Return(contract.InvcResponse{InvoiceID: "0001"}, nil).
+		Times(1)
+
+	m.recurringRepo.EXPECT().CreateRun(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	m.atomicSession.EXPECT().Commit(gomock.Any()).Times(1)
+
+	got, err := m.service().RunDue(context.Background(), time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, got)
+}